@@ -122,6 +122,23 @@ func (r *LibraryCourseRepository) Update(ctx context.Context, course *entities.L
 	return course, nil
 }
 
+// SaveLessonQuiz replaces the extended quiz of one lesson in the course's lessons JSON
+func (r *LibraryCourseRepository) SaveLessonQuiz(ctx context.Context, courseID string, lessonPath []int, quiz *entities.ExtendedQuiz) error {
+	course, err := r.GetByID(ctx, courseID)
+	if err != nil {
+		return err
+	}
+
+	lesson, err := course.LessonAt(lessonPath)
+	if err != nil {
+		return err
+	}
+	lesson.ExtendedQuiz = quiz
+
+	_, err = r.Update(ctx, course)
+	return err
+}
+
 // Delete removes a library course by ID
 func (r *LibraryCourseRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM library_courses WHERE id = ?`
@@ -438,12 +455,17 @@ func (r *UserCourseRepository) Create(ctx context.Context, userCourse *entities.
 		return nil, err
 	}
 
-	query := `INSERT INTO user_courses (id, user_id, library_course_id, progress, current_lesson_index, completed_lessons, started_at, updated_at, completed_at)
-			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	completionReasonsJSON, err := json.Marshal(userCourse.CompletionReasons)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `INSERT INTO user_courses (id, user_id, library_course_id, progress, current_lesson_index, completed_lessons, completion_reasons, started_at, updated_at, completed_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	_, err = r.db.DB().ExecContext(ctx, query,
 		userCourse.ID, userCourse.UserID, userCourse.LibraryCourseID,
-		userCourse.Progress, userCourse.CurrentLessonIndex, string(completedLessonsJSON),
+		userCourse.Progress, userCourse.CurrentLessonIndex, string(completedLessonsJSON), string(completionReasonsJSON),
 		userCourse.StartedAt, userCourse.UpdatedAt, userCourse.CompletedAt)
 	if err != nil {
 		return nil, err
@@ -454,16 +476,17 @@ func (r *UserCourseRepository) Create(ctx context.Context, userCourse *entities.
 
 // GetByID retrieves a user course by ID
 func (r *UserCourseRepository) GetByID(ctx context.Context, id string) (*entities.UserCourse, error) {
-	query := `SELECT id, user_id, library_course_id, progress, current_lesson_index, completed_lessons, started_at, updated_at, completed_at
+	query := `SELECT id, user_id, library_course_id, progress, current_lesson_index, completed_lessons, completion_reasons, started_at, updated_at, completed_at
 			  FROM user_courses WHERE id = ?`
 
 	userCourse := &entities.UserCourse{}
 	var completedAt sql.NullTime
 	var completedLessonsJSON sql.NullString
+	var completionReasonsJSON sql.NullString
 
 	err := r.db.DB().QueryRowContext(ctx, query, id).Scan(
 		&userCourse.ID, &userCourse.UserID, &userCourse.LibraryCourseID,
-		&userCourse.Progress, &userCourse.CurrentLessonIndex, &completedLessonsJSON,
+		&userCourse.Progress, &userCourse.CurrentLessonIndex, &completedLessonsJSON, &completionReasonsJSON,
 		&userCourse.StartedAt, &userCourse.UpdatedAt, &completedAt)
 
 	if err == sql.ErrNoRows {
@@ -486,21 +509,26 @@ func (r *UserCourseRepository) GetByID(ctx context.Context, id string) (*entitie
 		userCourse.CompletedLessons = []int{}
 	}
 
+	if userCourse.CompletionReasons, err = parseCompletionReasons(completionReasonsJSON); err != nil {
+		return nil, err
+	}
+
 	return userCourse, nil
 }
 
 // GetByUserAndCourse retrieves a user course by user ID and library course ID
 func (r *UserCourseRepository) GetByUserAndCourse(ctx context.Context, userID, libraryCourseID string) (*entities.UserCourse, error) {
-	query := `SELECT id, user_id, library_course_id, progress, current_lesson_index, completed_lessons, started_at, updated_at, completed_at
+	query := `SELECT id, user_id, library_course_id, progress, current_lesson_index, completed_lessons, completion_reasons, started_at, updated_at, completed_at
 			  FROM user_courses WHERE user_id = ? AND library_course_id = ?`
 
 	userCourse := &entities.UserCourse{}
 	var completedAt sql.NullTime
 	var completedLessonsJSON sql.NullString
+	var completionReasonsJSON sql.NullString
 
 	err := r.db.DB().QueryRowContext(ctx, query, userID, libraryCourseID).Scan(
 		&userCourse.ID, &userCourse.UserID, &userCourse.LibraryCourseID,
-		&userCourse.Progress, &userCourse.CurrentLessonIndex, &completedLessonsJSON,
+		&userCourse.Progress, &userCourse.CurrentLessonIndex, &completedLessonsJSON, &completionReasonsJSON,
 		&userCourse.StartedAt, &userCourse.UpdatedAt, &completedAt)
 
 	if err == sql.ErrNoRows {
@@ -523,6 +551,10 @@ func (r *UserCourseRepository) GetByUserAndCourse(ctx context.Context, userID, l
 		userCourse.CompletedLessons = []int{}
 	}
 
+	if userCourse.CompletionReasons, err = parseCompletionReasons(completionReasonsJSON); err != nil {
+		return nil, err
+	}
+
 	return userCourse, nil
 }
 
@@ -535,10 +567,15 @@ func (r *UserCourseRepository) Update(ctx context.Context, userCourse *entities.
 		return nil, err
 	}
 
-	query := `UPDATE user_courses SET progress = ?, current_lesson_index = ?, completed_lessons = ?, updated_at = ?, completed_at = ? WHERE id = ?`
+	completionReasonsJSON, err := json.Marshal(userCourse.CompletionReasons)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `UPDATE user_courses SET progress = ?, current_lesson_index = ?, completed_lessons = ?, completion_reasons = ?, updated_at = ?, completed_at = ? WHERE id = ?`
 
 	result, err := r.db.DB().ExecContext(ctx, query,
-		userCourse.Progress, userCourse.CurrentLessonIndex, string(completedLessonsJSON),
+		userCourse.Progress, userCourse.CurrentLessonIndex, string(completedLessonsJSON), string(completionReasonsJSON),
 		userCourse.UpdatedAt, userCourse.CompletedAt, userCourse.ID)
 	if err != nil {
 		return nil, err
@@ -585,7 +622,7 @@ func (r *UserCourseRepository) ListByUser(ctx context.Context, userID string, li
 	}
 
 	// Get paginated user courses
-	query := `SELECT id, user_id, library_course_id, progress, current_lesson_index, completed_lessons, started_at, updated_at, completed_at
+	query := `SELECT id, user_id, library_course_id, progress, current_lesson_index, completed_lessons, completion_reasons, started_at, updated_at, completed_at
 			  FROM user_courses WHERE user_id = ? ORDER BY started_at DESC LIMIT ? OFFSET ?`
 
 	rows, err := r.db.DB().QueryContext(ctx, query, userID, limit, offset)
@@ -599,9 +636,10 @@ func (r *UserCourseRepository) ListByUser(ctx context.Context, userID string, li
 		uc := &entities.UserCourse{}
 		var completedAt sql.NullTime
 		var completedLessonsJSON sql.NullString
+		var completionReasonsJSON sql.NullString
 
 		if err := rows.Scan(&uc.ID, &uc.UserID, &uc.LibraryCourseID,
-			&uc.Progress, &uc.CurrentLessonIndex, &completedLessonsJSON,
+			&uc.Progress, &uc.CurrentLessonIndex, &completedLessonsJSON, &completionReasonsJSON,
 			&uc.StartedAt, &uc.UpdatedAt, &completedAt); err != nil {
 			return nil, 0, err
 		}
@@ -619,6 +657,10 @@ func (r *UserCourseRepository) ListByUser(ctx context.Context, userID string, li
 			uc.CompletedLessons = []int{}
 		}
 
+		if uc.CompletionReasons, err = parseCompletionReasons(completionReasonsJSON); err != nil {
+			return nil, 0, err
+		}
+
 		userCourses = append(userCourses, uc)
 	}
 
@@ -635,7 +677,7 @@ func (r *UserCourseRepository) ListCompleted(ctx context.Context, userID string,
 	}
 
 	// Get paginated completed courses
-	query := `SELECT id, user_id, library_course_id, progress, current_lesson_index, completed_lessons, started_at, updated_at, completed_at
+	query := `SELECT id, user_id, library_course_id, progress, current_lesson_index, completed_lessons, completion_reasons, started_at, updated_at, completed_at
 			  FROM user_courses WHERE user_id = ? AND completed_at IS NOT NULL ORDER BY completed_at DESC LIMIT ? OFFSET ?`
 
 	rows, err := r.db.DB().QueryContext(ctx, query, userID, limit, offset)
@@ -649,9 +691,10 @@ func (r *UserCourseRepository) ListCompleted(ctx context.Context, userID string,
 		uc := &entities.UserCourse{}
 		var completedAt sql.NullTime
 		var completedLessonsJSON sql.NullString
+		var completionReasonsJSON sql.NullString
 
 		if err := rows.Scan(&uc.ID, &uc.UserID, &uc.LibraryCourseID,
-			&uc.Progress, &uc.CurrentLessonIndex, &completedLessonsJSON,
+			&uc.Progress, &uc.CurrentLessonIndex, &completedLessonsJSON, &completionReasonsJSON,
 			&uc.StartedAt, &uc.UpdatedAt, &completedAt); err != nil {
 			return nil, 0, err
 		}
@@ -669,6 +712,10 @@ func (r *UserCourseRepository) ListCompleted(ctx context.Context, userID string,
 			uc.CompletedLessons = []int{}
 		}
 
+		if uc.CompletionReasons, err = parseCompletionReasons(completionReasonsJSON); err != nil {
+			return nil, 0, err
+		}
+
 		userCourses = append(userCourses, uc)
 	}
 
@@ -685,7 +732,7 @@ func (r *UserCourseRepository) ListInProgress(ctx context.Context, userID string
 	}
 
 	// Get paginated in-progress courses
-	query := `SELECT id, user_id, library_course_id, progress, current_lesson_index, completed_lessons, started_at, updated_at, completed_at
+	query := `SELECT id, user_id, library_course_id, progress, current_lesson_index, completed_lessons, completion_reasons, started_at, updated_at, completed_at
 			  FROM user_courses WHERE user_id = ? AND completed_at IS NULL ORDER BY updated_at DESC LIMIT ? OFFSET ?`
 
 	rows, err := r.db.DB().QueryContext(ctx, query, userID, limit, offset)
@@ -699,9 +746,10 @@ func (r *UserCourseRepository) ListInProgress(ctx context.Context, userID string
 		uc := &entities.UserCourse{}
 		var completedAt sql.NullTime
 		var completedLessonsJSON sql.NullString
+		var completionReasonsJSON sql.NullString
 
 		if err := rows.Scan(&uc.ID, &uc.UserID, &uc.LibraryCourseID,
-			&uc.Progress, &uc.CurrentLessonIndex, &completedLessonsJSON,
+			&uc.Progress, &uc.CurrentLessonIndex, &completedLessonsJSON, &completionReasonsJSON,
 			&uc.StartedAt, &uc.UpdatedAt, &completedAt); err != nil {
 			return nil, 0, err
 		}
@@ -719,8 +767,27 @@ func (r *UserCourseRepository) ListInProgress(ctx context.Context, userID string
 			uc.CompletedLessons = []int{}
 		}
 
+		if uc.CompletionReasons, err = parseCompletionReasons(completionReasonsJSON); err != nil {
+			return nil, 0, err
+		}
+
 		userCourses = append(userCourses, uc)
 	}
 
 	return userCourses, total, rows.Err()
 }
+
+// parseCompletionReasons decodes the completion_reasons column, which is keyed by lesson index
+func parseCompletionReasons(data sql.NullString) (map[int]entities.CompletionReason, error) {
+	reasons := map[int]entities.CompletionReason{}
+	if !data.Valid || data.String == "" {
+		return reasons, nil
+	}
+	if err := json.Unmarshal([]byte(data.String), &reasons); err != nil {
+		return nil, err
+	}
+	if reasons == nil {
+		reasons = map[int]entities.CompletionReason{} // Rows written with a nil map store "null"
+	}
+	return reasons, nil
+}
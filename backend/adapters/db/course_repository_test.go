@@ -147,6 +147,44 @@ func TestLibraryCourseRepository_Update(t *testing.T) {
 	}
 }
 
+func TestLibraryCourseRepository_SaveLessonQuiz(t *testing.T) {
+	db, cleanup := setupTestCourseDB(t)
+	defer cleanup()
+
+	repo := NewLibraryCourseRepository(db)
+	ctx := context.Background()
+
+	lessons := []entities.Lesson{{Title: "Intro", Content: "Welcome", Order: 0, Sublessons: []entities.Lesson{{Title: "Ports", Content: "Ports"}}}}
+	course, _ := entities.NewLibraryCourse("Quiz Course", "Desc", lessons, "Author", "user-123", []string{}, entities.DifficultyBeginner, 5)
+	created, _ := repo.Create(ctx, course)
+
+	quiz := &entities.ExtendedQuiz{
+		Version: entities.ExtendedQuizVersion,
+		Questions: []entities.ExtendedQuizQuestion{
+			{ID: "q1", Type: entities.QuestionTypeShortAnswer, Difficulty: 2, Question: "Name the port", AcceptedAnswers: []string{"repository"}},
+		},
+	}
+	if err := repo.SaveLessonQuiz(ctx, created.ID, []int{0, 0}, quiz); err != nil {
+		t.Fatalf("failed to save lesson quiz: %v", err)
+	}
+
+	retrieved, err := repo.GetByID(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("failed to get course: %v", err)
+	}
+	saved := retrieved.Lessons[0].Sublessons[0].ExtendedQuiz
+	if saved == nil || len(saved.Questions) != 1 || saved.Questions[0].AcceptedAnswers[0] != "repository" {
+		t.Errorf("expected the quiz to be stored on the sublesson, got %+v", saved)
+	}
+	if retrieved.Lessons[0].ExtendedQuiz != nil {
+		t.Error("expected the chapter quiz to be left alone")
+	}
+
+	if err := repo.SaveLessonQuiz(ctx, created.ID, []int{3}, quiz); err != entities.ErrInvalidLessonIndex {
+		t.Errorf("expected ErrInvalidLessonIndex, got %v", err)
+	}
+}
+
 func TestLibraryCourseRepository_Delete(t *testing.T) {
 	db, cleanup := setupTestCourseDB(t)
 	defer cleanup()
@@ -352,6 +390,44 @@ func TestUserCourseRepository_Update(t *testing.T) {
 	}
 }
 
+func TestUserCourseRepository_CompletionReasons(t *testing.T) {
+	db, cleanup := setupTestCourseDB(t)
+	defer cleanup()
+
+	libRepo := NewLibraryCourseRepository(db)
+	userCourseRepo := NewUserCourseRepository(db)
+	ctx := context.Background()
+
+	// Setup
+	lessons := []entities.Lesson{{Title: "Intro", Content: "Welcome", Order: 0}, {Title: "Next", Content: "More", Order: 1}}
+	libCourse, _ := entities.NewLibraryCourse("Test", "Desc", lessons, "Author", "user-123", []string{}, entities.DifficultyBeginner, 5)
+	createdLib, _ := libRepo.Create(ctx, libCourse)
+
+	userCourse, _ := entities.NewUserCourse("user-123", createdLib.ID)
+	created, _ := userCourseRepo.Create(ctx, userCourse)
+
+	created.MarkLessonCompleted(0, 2)
+	created.MarkLessonTestedOut(1, 2)
+	if _, err := userCourseRepo.Update(ctx, created); err != nil {
+		t.Fatalf("failed to update user course: %v", err)
+	}
+
+	found, err := userCourseRepo.GetByUserAndCourse(ctx, "user-123", createdLib.ID)
+	if err != nil {
+		t.Fatalf("failed to get user course: %v", err)
+	}
+
+	if len(found.CompletedLessons) != 2 {
+		t.Errorf("expected 2 completed lessons, got %v", found.CompletedLessons)
+	}
+	if found.CompletionReasons[1] != entities.CompletionReasonTestedOut {
+		t.Errorf("expected lesson 1 to be tested out, got %q", found.CompletionReasons[1])
+	}
+	if _, ok := found.CompletionReasons[0]; ok {
+		t.Error("expected studied lesson to have no completion reason")
+	}
+}
+
 func TestUserCourseRepository_ListByUser(t *testing.T) {
 	db, cleanup := setupTestCourseDB(t)
 	defer cleanup()
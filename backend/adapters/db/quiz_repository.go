@@ -3,6 +3,8 @@ package db
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,21 +13,31 @@ import (
 
 // QuizRepository handles quiz data persistence
 type QuizRepository struct {
-	db *sql.DB
+	db *SQLiteDB
 }
 
 // NewQuizRepository creates a new quiz repository
-func NewQuizRepository(db *sql.DB) *QuizRepository {
+func NewQuizRepository(db *SQLiteDB) *QuizRepository {
 	return &QuizRepository{db: db}
 }
 
+// execer runs statements on the database or within a transaction
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
 // SaveAttempt saves a quiz attempt
 func (r *QuizRepository) SaveAttempt(ctx context.Context, attempt *entities.QuizAttempt) (*entities.QuizAttempt, error) {
+	return saveAttempt(ctx, r.db.DB(), attempt)
+}
+
+func saveAttempt(ctx context.Context, db execer, attempt *entities.QuizAttempt) (*entities.QuizAttempt, error) {
 	if attempt.ID == "" {
 		attempt.ID = uuid.New().String()
 	}
 
-	_, err := r.db.ExecContext(ctx, `
+	_, err := db.ExecContext(ctx, `
 		INSERT INTO quiz_attempts (
 			id, user_id, course_id, quiz_type, quiz_id,
 			score, max_score, total_questions, correct_count,
@@ -43,7 +55,7 @@ func (r *QuizRepository) SaveAttempt(ctx context.Context, attempt *entities.Quiz
 		attempt.CorrectCount,
 		attempt.Percentage,
 		attempt.MasteryLevel,
-		attempt.CompletedAt,
+		attempt.CompletedAt.UTC(),
 	)
 
 	if err != nil {
@@ -55,15 +67,24 @@ func (r *QuizRepository) SaveAttempt(ctx context.Context, attempt *entities.Quiz
 
 // SaveResponse saves a quiz response
 func (r *QuizRepository) SaveResponse(ctx context.Context, response *entities.QuizResponse) (*entities.QuizResponse, error) {
+	return saveResponse(ctx, r.db.DB(), response)
+}
+
+func saveResponse(ctx context.Context, db execer, response *entities.QuizResponse) (*entities.QuizResponse, error) {
 	if response.ID == "" {
 		response.ID = uuid.New().String()
 	}
 
-	_, err := r.db.ExecContext(ctx, `
+	testResultsJSON, err := json.Marshal(response.TestResults)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.ExecContext(ctx, `
 		INSERT INTO quiz_responses (
 			id, attempt_id, question_id, user_answer, is_correct,
-			points_earned, points_possible, confidence, time_taken_seconds
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			points_earned, points_possible, confidence, time_taken_seconds, concept, test_results
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		response.ID,
 		response.AttemptID,
@@ -74,6 +95,8 @@ func (r *QuizRepository) SaveResponse(ctx context.Context, response *entities.Qu
 		response.PointsPossible,
 		response.Confidence,
 		response.TimeTakenSec,
+		response.Concept,
+		string(testResultsJSON),
 	)
 
 	if err != nil {
@@ -83,9 +106,66 @@ func (r *QuizRepository) SaveResponse(ctx context.Context, response *entities.Qu
 	return response, nil
 }
 
+// SaveGradedAttempt closes the attempt's exam session, if any, and stores the attempt with
+// its responses in one transaction, so either all of them are stored or none is
+func (r *QuizRepository) SaveGradedAttempt(ctx context.Context, sessionID string, submittedAt time.Time, attempt *entities.QuizAttempt, responses []entities.QuizResponse) (*entities.QuizAttempt, error) {
+	tx, err := r.db.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Closing the session first means a session submitted twice at once is only graded once
+	if sessionID != "" {
+		if err := closeQuizSession(ctx, tx, sessionID, submittedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	saved, err := saveAttempt(ctx, tx, attempt)
+	if err != nil {
+		return nil, err
+	}
+	for i := range responses {
+		responses[i].AttemptID = saved.ID
+		if _, err := saveResponse(ctx, tx, &responses[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+// GetAttempt returns an attempt by ID
+func (r *QuizRepository) GetAttempt(ctx context.Context, id string) (*entities.QuizAttempt, error) {
+	a := &entities.QuizAttempt{}
+	err := r.db.DB().QueryRowContext(ctx, `
+		SELECT id, user_id, course_id, quiz_type, quiz_id,
+			   score, max_score, total_questions, correct_count,
+			   percentage, mastery_level, completed_at
+		FROM quiz_attempts
+		WHERE id = ?
+	`, id).Scan(
+		&a.ID, &a.UserID, &a.CourseID, &a.QuizType, &a.QuizID,
+		&a.Score, &a.MaxScore, &a.TotalQuestions, &a.CorrectCount,
+		&a.Percentage, &a.MasteryLevel, &a.CompletedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, entities.ErrQuizAttemptNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
 // GetAttemptsByQuiz returns all attempts for a specific quiz
 func (r *QuizRepository) GetAttemptsByQuiz(ctx context.Context, userID, courseID, quizID string) ([]entities.QuizAttempt, error) {
-	rows, err := r.db.QueryContext(ctx, `
+	rows, err := r.db.DB().QueryContext(ctx, `
 		SELECT id, user_id, course_id, quiz_type, quiz_id,
 			   score, max_score, total_questions, correct_count,
 			   percentage, mastery_level, completed_at
@@ -153,7 +233,7 @@ func (r *QuizRepository) GetQuizStats(ctx context.Context, userID, courseID, qui
 // GetCourseQuizSummary returns quiz summary for entire course
 func (r *QuizRepository) GetCourseQuizSummary(ctx context.Context, userID, courseID string) (*entities.CourseQuizSummary, error) {
 	// Get all unique quiz IDs for this course
-	rows, err := r.db.QueryContext(ctx, `
+	rows, err := r.db.DB().QueryContext(ctx, `
 		SELECT DISTINCT quiz_id, quiz_type
 		FROM quiz_attempts
 		WHERE user_id = ? AND course_id = ?
@@ -196,15 +276,16 @@ func (r *QuizRepository) GetCourseQuizSummary(ctx context.Context, userID, cours
 		averageScore = totalScore / float64(quizCount)
 	}
 
-	// Get weak and strong concepts
-	weakConcepts, strongConcepts, err := r.getConceptStrengths(ctx, userID, courseID)
+	// Get weak, strong and confidently wrong concepts
+	outcomes, err := r.conceptOutcomes(ctx, userID, courseID)
 	if err != nil {
 		return nil, err
 	}
+	weakConcepts, strongConcepts := entities.WeakAndStrongConcepts(entities.ComputeConceptStrengths(outcomes))
 
 	// Get review queue size
 	var reviewQueueSize int
-	err = r.db.QueryRowContext(ctx, `
+	err = r.db.DB().QueryRowContext(ctx, `
 		SELECT COUNT(*) FROM review_queue
 		WHERE user_id = ? AND course_id = ? AND next_review <= ?
 	`, userID, courseID, time.Now()).Scan(&reviewQueueSize)
@@ -223,32 +304,112 @@ func (r *QuizRepository) GetCourseQuizSummary(ctx context.Context, userID, cours
 		WeakConcepts:     weakConcepts,
 		StrongConcepts:   strongConcepts,
 		ReviewQueueSize:  reviewQueueSize,
+
+		ConfidentlyWrongConcepts: entities.ConfidentlyWrongConcepts(outcomes),
 	}, nil
 }
 
-// getConceptStrengths analyzes responses to identify weak and strong concepts
-func (r *QuizRepository) getConceptStrengths(ctx context.Context, userID, courseID string) (weak, strong []string, err error) {
-	// This would require storing concepts in responses
-	// For now, return empty slices - can be enhanced later
-	return []string{}, []string{}, nil
+// GetConceptStrengths computes per-concept accuracy and trend from a user's responses in a course
+// Responses recorded before concepts were stored have no concept and are skipped
+func (r *QuizRepository) GetConceptStrengths(ctx context.Context, userID, courseID string) ([]entities.ConceptStrength, error) {
+	outcomes, err := r.conceptOutcomes(ctx, userID, courseID)
+	if err != nil {
+		return nil, err
+	}
+	return entities.ComputeConceptStrengths(outcomes), nil
+}
+
+// GetCalibration breaks a user's accuracy down by reported confidence
+// An empty courseID covers every course the user has answered questions in
+func (r *QuizRepository) GetCalibration(ctx context.Context, userID, courseID string) (*entities.CalibrationReport, error) {
+	outcomes, err := r.conceptOutcomes(ctx, userID, courseID)
+	if err != nil {
+		return nil, err
+	}
+	return entities.ComputeCalibration(courseID, outcomes), nil
+}
+
+// conceptOutcomes returns every graded response of a user, oldest first
+// An empty courseID returns responses from all courses
+func (r *QuizRepository) conceptOutcomes(ctx context.Context, userID, courseID string) ([]entities.ConceptOutcome, error) {
+	query := `
+		SELECT qr.concept, qr.is_correct, qr.confidence, qa.completed_at
+		FROM quiz_responses qr
+		JOIN quiz_attempts qa ON qa.id = qr.attempt_id
+		WHERE qa.user_id = ?`
+	args := []interface{}{userID}
+	if courseID != "" {
+		query += ` AND qa.course_id = ?`
+		args = append(args, courseID)
+	}
+	query += ` ORDER BY qa.completed_at ASC`
+
+	rows, err := r.db.DB().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var outcomes []entities.ConceptOutcome
+	for rows.Next() {
+		var o entities.ConceptOutcome
+		var confidence sql.NullString
+		if err := rows.Scan(&o.Concept, &o.IsCorrect, &confidence, &o.AnsweredAt); err != nil {
+			return nil, err
+		}
+		o.Confidence = entities.ConfidenceLevel(confidence.String)
+		outcomes = append(outcomes, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return outcomes, nil
+}
+
+// reviewQueueColumns lists the review_queue columns read by scanReviewQueueItem
+const reviewQueueColumns = `id, user_id, course_id, quiz_id, question_id, concept,
+			   wrong_count, last_attempt, next_review, stability, last_reviewed, priority`
+
+// scanReviewQueueItem reads a review_queue row selected with reviewQueueColumns
+func scanReviewQueueItem(row interface{ Scan(dest ...any) error }) (*entities.ReviewQueueItem, error) {
+	var item entities.ReviewQueueItem
+	var lastReviewed sql.NullTime
+	err := row.Scan(
+		&item.ID, &item.UserID, &item.CourseID, &item.QuizID,
+		&item.QuestionID, &item.Concept, &item.WrongCount,
+		&item.LastAttempt, &item.NextReview, &item.Stability, &lastReviewed, &item.Priority,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if lastReviewed.Valid {
+		item.LastReviewed = &lastReviewed.Time
+	}
+
+	return &item, nil
 }
 
 // AddToReviewQueue adds a question to the spaced repetition review queue
+// If the question is already queued its schedule is replaced by the item's
 func (r *QuizRepository) AddToReviewQueue(ctx context.Context, item *entities.ReviewQueueItem) error {
 	if item.ID == "" {
 		item.ID = uuid.New().String()
 	}
 
-	_, err := r.db.ExecContext(ctx, `
+	_, err := r.db.DB().ExecContext(ctx, `
 		INSERT INTO review_queue (
 			id, user_id, course_id, quiz_id, question_id, concept,
-			wrong_count, last_attempt, next_review, stability
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			wrong_count, last_attempt, next_review, stability, last_reviewed, priority
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(user_id, course_id, question_id) DO UPDATE SET
-			wrong_count = wrong_count + 1,
+			wrong_count = excluded.wrong_count,
 			last_attempt = excluded.last_attempt,
 			next_review = excluded.next_review,
-			stability = excluded.stability * 0.8
+			stability = excluded.stability,
+			last_reviewed = excluded.last_reviewed,
+			priority = excluded.priority
 	`,
 		item.ID,
 		item.UserID,
@@ -260,19 +421,20 @@ func (r *QuizRepository) AddToReviewQueue(ctx context.Context, item *entities.Re
 		item.LastAttempt,
 		item.NextReview,
 		item.Stability,
+		item.LastReviewed,
+		item.Priority,
 	)
 
 	return err
 }
 
-// GetReviewQueue returns questions due for review
+// GetReviewQueue returns questions due for review, highest priority first
 func (r *QuizRepository) GetReviewQueue(ctx context.Context, userID, courseID string, limit int) ([]entities.ReviewQueueItem, error) {
-	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, user_id, course_id, quiz_id, question_id, concept,
-			   wrong_count, last_attempt, next_review, stability
+	rows, err := r.db.DB().QueryContext(ctx, `
+		SELECT `+reviewQueueColumns+`
 		FROM review_queue
 		WHERE user_id = ? AND course_id = ? AND next_review <= ?
-		ORDER BY next_review ASC
+		ORDER BY priority DESC, next_review ASC
 		LIMIT ?
 	`, userID, courseID, time.Now(), limit)
 
@@ -283,24 +445,84 @@ func (r *QuizRepository) GetReviewQueue(ctx context.Context, userID, courseID st
 
 	var items []entities.ReviewQueueItem
 	for rows.Next() {
-		var item entities.ReviewQueueItem
-		err := rows.Scan(
-			&item.ID, &item.UserID, &item.CourseID, &item.QuizID,
-			&item.QuestionID, &item.Concept, &item.WrongCount,
-			&item.LastAttempt, &item.NextReview, &item.Stability,
-		)
+		item, err := scanReviewQueueItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *item)
+	}
+
+	return items, rows.Err()
+}
+
+// GetDueReviewItems returns every question due for review in the given courses, highest priority first
+func (r *QuizRepository) GetDueReviewItems(ctx context.Context, userID string, courseIDs []string) ([]entities.ReviewQueueItem, error) {
+	if len(courseIDs) == 0 {
+		return []entities.ReviewQueueItem{}, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(courseIDs)), ", ")
+	args := []interface{}{userID, time.Now()}
+	for _, id := range courseIDs {
+		args = append(args, id)
+	}
+
+	rows, err := r.db.DB().QueryContext(ctx, `
+		SELECT `+reviewQueueColumns+`
+		FROM review_queue
+		WHERE user_id = ? AND next_review <= ? AND course_id IN (`+placeholders+`)
+		ORDER BY priority DESC, next_review ASC
+	`, args...)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []entities.ReviewQueueItem{}
+	for rows.Next() {
+		item, err := scanReviewQueueItem(rows)
 		if err != nil {
 			return nil, err
 		}
-		items = append(items, item)
+		items = append(items, *item)
 	}
 
 	return items, rows.Err()
 }
 
-// RemoveFromReviewQueue removes a question from review queue (when answered correctly)
+// CountReviewedSince returns how many queued questions the user has reviewed since the given time
+func (r *QuizRepository) CountReviewedSince(ctx context.Context, userID string, since time.Time) (int, error) {
+	var count int
+	err := r.db.DB().QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM review_queue
+		WHERE user_id = ? AND last_reviewed >= ?
+	`, userID, since).Scan(&count)
+	return count, err
+}
+
+// GetReviewItem returns a single queued question
+func (r *QuizRepository) GetReviewItem(ctx context.Context, userID, courseID, questionID string) (*entities.ReviewQueueItem, error) {
+	row := r.db.DB().QueryRowContext(ctx, `
+		SELECT `+reviewQueueColumns+`
+		FROM review_queue
+		WHERE user_id = ? AND course_id = ? AND question_id = ?
+	`, userID, courseID, questionID)
+
+	item, err := scanReviewQueueItem(row)
+	if err == sql.ErrNoRows {
+		return nil, entities.ErrReviewItemNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// RemoveFromReviewQueue removes a question from the review queue
 func (r *QuizRepository) RemoveFromReviewQueue(ctx context.Context, userID, courseID, questionID string) error {
-	_, err := r.db.ExecContext(ctx, `
+	_, err := r.db.DB().ExecContext(ctx, `
 		DELETE FROM review_queue
 		WHERE user_id = ? AND course_id = ? AND question_id = ?
 	`, userID, courseID, questionID)
@@ -310,19 +532,11 @@ func (r *QuizRepository) RemoveFromReviewQueue(ctx context.Context, userID, cour
 // GetDashboardQuizStats returns aggregated quiz stats for the dashboard
 func (r *QuizRepository) GetDashboardQuizStats(ctx context.Context, userID string, fromDate, toDate *time.Time) (*entities.DashboardQuizStats, error) {
 	// Build date filter
-	dateFilter := ""
-	args := []interface{}{userID}
-	if fromDate != nil {
-		dateFilter += " AND completed_at >= ?"
-		args = append(args, *fromDate)
-	}
-	if toDate != nil {
-		dateFilter += " AND completed_at <= ?"
-		args = append(args, *toDate)
-	}
+	dateFilter, dateArgs := completedAtFilter(fromDate, toDate)
+	args := append([]interface{}{userID}, dateArgs...)
 
 	// Get all course IDs with quiz attempts
-	rows, err := r.db.QueryContext(ctx, `
+	rows, err := r.db.DB().QueryContext(ctx, `
 		SELECT DISTINCT course_id
 		FROM quiz_attempts
 		WHERE user_id = ?`+dateFilter,
@@ -374,26 +588,15 @@ func (r *QuizRepository) GetDashboardQuizStats(ctx context.Context, userID strin
 	}
 
 	// Get recent attempts (last 10)
-	recentArgs := []interface{}{userID}
-	recentDateFilter := ""
-	if fromDate != nil {
-		recentDateFilter += " AND completed_at >= ?"
-		recentArgs = append(recentArgs, *fromDate)
-	}
-	if toDate != nil {
-		recentDateFilter += " AND completed_at <= ?"
-		recentArgs = append(recentArgs, *toDate)
-	}
-
-	recentRows, err := r.db.QueryContext(ctx, `
+	recentRows, err := r.db.DB().QueryContext(ctx, `
 		SELECT id, user_id, course_id, quiz_type, quiz_id,
 			   score, max_score, total_questions, correct_count,
 			   percentage, mastery_level, completed_at
 		FROM quiz_attempts
-		WHERE user_id = ?`+recentDateFilter+`
+		WHERE user_id = ?`+dateFilter+`
 		ORDER BY completed_at DESC
 		LIMIT 10`,
-		recentArgs...)
+		args...)
 	if err != nil {
 		return nil, err
 	}
@@ -413,24 +616,13 @@ func (r *QuizRepository) GetDashboardQuizStats(ctx context.Context, userID strin
 	}
 
 	// Get score history (for charts)
-	historyArgs := []interface{}{userID}
-	historyDateFilter := ""
-	if fromDate != nil {
-		historyDateFilter += " AND completed_at >= ?"
-		historyArgs = append(historyArgs, *fromDate)
-	}
-	if toDate != nil {
-		historyDateFilter += " AND completed_at <= ?"
-		historyArgs = append(historyArgs, *toDate)
-	}
-
-	historyRows, err := r.db.QueryContext(ctx, `
+	historyRows, err := r.db.DB().QueryContext(ctx, `
 		SELECT course_id, date(completed_at) as date, AVG(percentage) as avg_score
 		FROM quiz_attempts
-		WHERE user_id = ?`+historyDateFilter+`
+		WHERE user_id = ?`+dateFilter+`
 		GROUP BY course_id, date(completed_at)
 		ORDER BY date(completed_at) ASC`,
-		historyArgs...)
+		args...)
 	if err != nil {
 		return nil, err
 	}
@@ -449,11 +641,12 @@ func (r *QuizRepository) GetDashboardQuizStats(ctx context.Context, userID strin
 
 // GetResponsesByAttempt returns all responses for an attempt
 func (r *QuizRepository) GetResponsesByAttempt(ctx context.Context, attemptID string) ([]entities.QuizResponse, error) {
-	rows, err := r.db.QueryContext(ctx, `
+	rows, err := r.db.DB().QueryContext(ctx, `
 		SELECT id, attempt_id, question_id, user_answer, is_correct,
-			   points_earned, points_possible, confidence, time_taken_seconds
+			   points_earned, points_possible, confidence, time_taken_seconds, concept, test_results
 		FROM quiz_responses
 		WHERE attempt_id = ?
+		ORDER BY rowid
 	`, attemptID)
 
 	if err != nil {
@@ -466,12 +659,98 @@ func (r *QuizRepository) GetResponsesByAttempt(ctx context.Context, attemptID st
 		var resp entities.QuizResponse
 		var confidence sql.NullString
 		var timeTaken sql.NullInt64
+		var testResultsJSON string
+
+		err := rows.Scan(
+			&resp.ID, &resp.AttemptID, &resp.QuestionID,
+			&resp.UserAnswer, &resp.IsCorrect,
+			&resp.PointsEarned, &resp.PointsPossible,
+			&confidence, &timeTaken, &resp.Concept, &testResultsJSON,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal([]byte(testResultsJSON), &resp.TestResults); err != nil {
+			return nil, err
+		}
+
+		if confidence.Valid {
+			resp.Confidence = entities.ConfidenceLevel(confidence.String)
+		}
+		if timeTaken.Valid {
+			resp.TimeTakenSec = int(timeTaken.Int64)
+		}
+
+		responses = append(responses, resp)
+	}
+
+	return responses, rows.Err()
+}
+
+// GetItemResponses returns every learner's responses to a quiz, with each attempt's percentage
+func (r *QuizRepository) GetItemResponses(ctx context.Context, courseID, quizID string) ([]entities.ItemResponse, error) {
+	rows, err := r.db.DB().QueryContext(ctx, `
+		SELECT qr.id, qr.attempt_id, qr.question_id, qr.user_answer, qr.is_correct,
+			   qr.points_earned, qr.points_possible, qr.confidence, qr.time_taken_seconds, qr.concept,
+			   a.course_id, a.percentage
+		FROM quiz_responses qr
+		JOIN quiz_attempts a ON a.id = qr.attempt_id
+		WHERE a.course_id = ? AND a.quiz_id = ?
+		ORDER BY a.completed_at, qr.id
+	`, courseID, quizID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanItemResponses(rows)
+}
+
+// GetQuestionResponses returns every learner's responses to the given questions, whichever
+// course they were answered in, with each attempt's course and percentage
+func (r *QuizRepository) GetQuestionResponses(ctx context.Context, questionIDs []string) ([]entities.ItemResponse, error) {
+	if len(questionIDs) == 0 {
+		return []entities.ItemResponse{}, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(questionIDs)), ", ")
+	var args []interface{}
+	for _, id := range questionIDs {
+		args = append(args, id)
+	}
+
+	rows, err := r.db.DB().QueryContext(ctx, `
+		SELECT qr.id, qr.attempt_id, qr.question_id, qr.user_answer, qr.is_correct,
+			   qr.points_earned, qr.points_possible, qr.confidence, qr.time_taken_seconds, qr.concept,
+			   a.course_id, a.percentage
+		FROM quiz_responses qr
+		JOIN quiz_attempts a ON a.id = qr.attempt_id
+		WHERE qr.question_id IN (`+placeholders+`)
+		ORDER BY a.completed_at, qr.id
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanItemResponses(rows)
+}
+
+// scanItemResponses reads responses joined to their attempt's course and percentage
+func scanItemResponses(rows *sql.Rows) ([]entities.ItemResponse, error) {
+	var responses []entities.ItemResponse
+	for rows.Next() {
+		var resp entities.ItemResponse
+		var confidence sql.NullString
+		var timeTaken sql.NullInt64
 
 		err := rows.Scan(
 			&resp.ID, &resp.AttemptID, &resp.QuestionID,
 			&resp.UserAnswer, &resp.IsCorrect,
 			&resp.PointsEarned, &resp.PointsPossible,
-			&confidence, &timeTaken,
+			&confidence, &timeTaken, &resp.Concept,
+			&resp.CourseID, &resp.AttemptPercentage,
 		)
 		if err != nil {
 			return nil, err
@@ -489,3 +768,471 @@ func (r *QuizRepository) GetResponsesByAttempt(ctx context.Context, attemptID st
 
 	return responses, rows.Err()
 }
+
+// GetAnsweredQuestions returns the question IDs a user has answered in a course, keyed by quiz ID
+func (r *QuizRepository) GetAnsweredQuestions(ctx context.Context, userID, courseID string) (map[string][]string, error) {
+	rows, err := r.db.DB().QueryContext(ctx, `
+		SELECT DISTINCT a.quiz_id, qr.question_id
+		FROM quiz_responses qr
+		JOIN quiz_attempts a ON a.id = qr.attempt_id
+		WHERE a.user_id = ? AND a.course_id = ? AND qr.user_answer != 'null'
+	`, userID, courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	answered := make(map[string][]string)
+	for rows.Next() {
+		var quizID, questionID string
+		if err := rows.Scan(&quizID, &questionID); err != nil {
+			return nil, err
+		}
+		answered[quizID] = append(answered[quizID], questionID)
+	}
+
+	return answered, rows.Err()
+}
+
+// SaveQuizInstance stores a generated quiz's seed and question selection
+func (r *QuizRepository) SaveQuizInstance(ctx context.Context, instance *entities.QuizInstance) (*entities.QuizInstance, error) {
+	if instance.ID == "" {
+		instance.ID = uuid.New().String()
+	}
+
+	questionIDsJSON, err := json.Marshal(instance.QuestionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = r.db.DB().ExecContext(ctx, `
+		INSERT INTO quiz_instances (
+			id, user_id, course_id, quiz_id, include_sublessons, seed, question_ids, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		instance.ID,
+		instance.UserID,
+		instance.CourseID,
+		instance.QuizID,
+		instance.IncludeSublessons,
+		instance.Seed,
+		string(questionIDsJSON),
+		instance.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return instance, nil
+}
+
+// GetQuizInstance retrieves a generated quiz by ID
+func (r *QuizRepository) GetQuizInstance(ctx context.Context, id string) (*entities.QuizInstance, error) {
+	instance := &entities.QuizInstance{}
+	var questionIDsJSON string
+
+	err := r.db.DB().QueryRowContext(ctx, `
+		SELECT id, user_id, course_id, quiz_id, include_sublessons, seed, question_ids, created_at
+		FROM quiz_instances
+		WHERE id = ?
+	`, id).Scan(
+		&instance.ID,
+		&instance.UserID,
+		&instance.CourseID,
+		&instance.QuizID,
+		&instance.IncludeSublessons,
+		&instance.Seed,
+		&questionIDsJSON,
+		&instance.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, entities.ErrQuizInstanceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(questionIDsJSON), &instance.QuestionIDs); err != nil {
+		return nil, err
+	}
+
+	return instance, nil
+}
+
+// SaveQuizSession stores a newly started exam session
+func (r *QuizRepository) SaveQuizSession(ctx context.Context, session *entities.QuizSession) (*entities.QuizSession, error) {
+	if session.ID == "" {
+		session.ID = uuid.New().String()
+	}
+
+	var expiresAt interface{}
+	if session.ExpiresAt != nil {
+		expiresAt = session.ExpiresAt.UTC()
+	}
+
+	_, err := r.db.DB().ExecContext(ctx, `
+		INSERT INTO quiz_sessions (id, user_id, course_id, quiz_id, started_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`,
+		session.ID,
+		session.UserID,
+		session.CourseID,
+		session.QuizID,
+		session.StartedAt.UTC(),
+		expiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// GetQuizSession retrieves an exam session by ID
+func (r *QuizRepository) GetQuizSession(ctx context.Context, id string) (*entities.QuizSession, error) {
+	row := r.db.DB().QueryRowContext(ctx, `
+		SELECT id, user_id, course_id, quiz_id, started_at, expires_at, submitted_at
+		FROM quiz_sessions
+		WHERE id = ?
+	`, id)
+
+	session, err := scanQuizSession(row)
+	if err == sql.ErrNoRows {
+		return nil, entities.ErrQuizSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// GetQuizSessions retrieves a user's sessions for a quiz, newest first
+func (r *QuizRepository) GetQuizSessions(ctx context.Context, userID, courseID, quizID string) ([]entities.QuizSession, error) {
+	rows, err := r.db.DB().QueryContext(ctx, `
+		SELECT id, user_id, course_id, quiz_id, started_at, expires_at, submitted_at
+		FROM quiz_sessions
+		WHERE user_id = ? AND course_id = ? AND quiz_id = ?
+		ORDER BY started_at DESC
+	`, userID, courseID, quizID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []entities.QuizSession
+	for rows.Next() {
+		session, err := scanQuizSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *session)
+	}
+
+	return sessions, rows.Err()
+}
+
+// CloseQuizSession marks a session as submitted unless it already was
+func (r *QuizRepository) CloseQuizSession(ctx context.Context, id string, submittedAt time.Time) error {
+	return closeQuizSession(ctx, r.db.DB(), id, submittedAt)
+}
+
+func closeQuizSession(ctx context.Context, db execer, id string, submittedAt time.Time) error {
+	result, err := db.ExecContext(ctx, `
+		UPDATE quiz_sessions SET submitted_at = ?
+		WHERE id = ? AND submitted_at IS NULL
+	`, submittedAt.UTC(), id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		var exists int
+		err := db.QueryRowContext(ctx, `SELECT 1 FROM quiz_sessions WHERE id = ?`, id).Scan(&exists)
+		if err == sql.ErrNoRows {
+			return entities.ErrQuizSessionNotFound
+		}
+		if err != nil {
+			return err
+		}
+		return entities.ErrQuizSessionClosed
+	}
+
+	return nil
+}
+
+// scanQuizSession reads a quiz_sessions row
+func scanQuizSession(row interface{ Scan(dest ...any) error }) (*entities.QuizSession, error) {
+	session := &entities.QuizSession{}
+	var expiresAt, submittedAt sql.NullTime
+
+	err := row.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.CourseID,
+		&session.QuizID,
+		&session.StartedAt,
+		&expiresAt,
+		&submittedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if expiresAt.Valid {
+		session.ExpiresAt = &expiresAt.Time
+	}
+	if submittedAt.Valid {
+		session.SubmittedAt = &submittedAt.Time
+	}
+	return session, nil
+}
+
+// SaveQuizDraft stores a newly started draft attempt
+func (r *QuizRepository) SaveQuizDraft(ctx context.Context, draft *entities.QuizDraft) (*entities.QuizDraft, error) {
+	if draft.ID == "" {
+		draft.ID = uuid.New().String()
+	}
+
+	var deadline interface{}
+	if draft.Deadline != nil {
+		deadline = draft.Deadline.UTC()
+	}
+
+	_, err := r.db.DB().ExecContext(ctx, `
+		INSERT INTO quiz_drafts (
+			id, user_id, course_id, quiz_id, instance_id, session_id,
+			started_at, updated_at, expires_at, deadline
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		draft.ID,
+		draft.UserID,
+		draft.CourseID,
+		draft.QuizID,
+		draft.InstanceID,
+		draft.SessionID,
+		draft.StartedAt.UTC(),
+		draft.UpdatedAt.UTC(),
+		draft.ExpiresAt.UTC(),
+		deadline,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, answer := range draft.Answers {
+		if err := r.saveDraftAnswer(ctx, draft.ID, answer); err != nil {
+			return nil, err
+		}
+	}
+
+	return draft, nil
+}
+
+// GetQuizDraft retrieves a draft attempt with its saved answers
+func (r *QuizRepository) GetQuizDraft(ctx context.Context, id string) (*entities.QuizDraft, error) {
+	row := r.db.DB().QueryRowContext(ctx, `
+		SELECT id, user_id, course_id, quiz_id, instance_id, session_id,
+			   started_at, updated_at, expires_at, deadline
+		FROM quiz_drafts
+		WHERE id = ?
+	`, id)
+
+	draft, err := scanQuizDraft(row)
+	if err == sql.ErrNoRows {
+		return nil, entities.ErrQuizDraftNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if draft.Answers, err = r.getDraftAnswers(ctx, draft.ID); err != nil {
+		return nil, err
+	}
+
+	return draft, nil
+}
+
+// GetOpenQuizDrafts retrieves a user's drafts that have not expired, most recently saved first
+func (r *QuizRepository) GetOpenQuizDrafts(ctx context.Context, userID string, now time.Time) ([]entities.QuizDraft, error) {
+	rows, err := r.db.DB().QueryContext(ctx, `
+		SELECT id, user_id, course_id, quiz_id, instance_id, session_id,
+			   started_at, updated_at, expires_at, deadline
+		FROM quiz_drafts
+		WHERE user_id = ? AND expires_at > ?
+		ORDER BY updated_at DESC
+	`, userID, now.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var drafts []entities.QuizDraft
+	for rows.Next() {
+		draft, err := scanQuizDraft(rows)
+		if err != nil {
+			return nil, err
+		}
+		drafts = append(drafts, *draft)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range drafts {
+		if drafts[i].Answers, err = r.getDraftAnswers(ctx, drafts[i].ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return drafts, nil
+}
+
+// SaveQuizDraftAnswer stores an answer, replacing any earlier answer to the same question,
+// and records the draft's new last-saved time and expiry
+func (r *QuizRepository) SaveQuizDraftAnswer(ctx context.Context, draft *entities.QuizDraft, answer entities.QuizAnswer) error {
+	if err := r.saveDraftAnswer(ctx, draft.ID, answer); err != nil {
+		return err
+	}
+
+	_, err := r.db.DB().ExecContext(ctx, `
+		UPDATE quiz_drafts SET updated_at = ?, expires_at = ?
+		WHERE id = ?
+	`, draft.UpdatedAt.UTC(), draft.ExpiresAt.UTC(), draft.ID)
+	return err
+}
+
+// DeleteQuizDraft removes a draft and its answers
+func (r *QuizRepository) DeleteQuizDraft(ctx context.Context, id string) error {
+	if _, err := r.db.DB().ExecContext(ctx, `DELETE FROM quiz_draft_answers WHERE draft_id = ?`, id); err != nil {
+		return err
+	}
+	_, err := r.db.DB().ExecContext(ctx, `DELETE FROM quiz_drafts WHERE id = ?`, id)
+	return err
+}
+
+// DeleteExpiredQuizDrafts removes every draft that expired before now, returning how many
+func (r *QuizRepository) DeleteExpiredQuizDrafts(ctx context.Context, now time.Time) (int, error) {
+	_, err := r.db.DB().ExecContext(ctx, `
+		DELETE FROM quiz_draft_answers
+		WHERE draft_id IN (SELECT id FROM quiz_drafts WHERE expires_at <= ?)
+	`, now.UTC())
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.db.DB().ExecContext(ctx, `DELETE FROM quiz_drafts WHERE expires_at <= ?`, now.UTC())
+	if err != nil {
+		return 0, err
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(deleted), nil
+}
+
+// saveDraftAnswer upserts one answer of a draft
+// Replacing an answer keeps its row, so answers stay in the order they were first given
+func (r *QuizRepository) saveDraftAnswer(ctx context.Context, draftID string, answer entities.QuizAnswer) error {
+	_, err := r.db.DB().ExecContext(ctx, `
+		INSERT INTO quiz_draft_answers (draft_id, question_id, answer, confidence, time_taken_seconds)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(draft_id, question_id) DO UPDATE SET
+			answer = excluded.answer,
+			confidence = excluded.confidence,
+			time_taken_seconds = excluded.time_taken_seconds
+	`,
+		draftID,
+		answer.QuestionID,
+		string(answer.Answer),
+		answer.Confidence,
+		answer.TimeTakenSec,
+	)
+	return err
+}
+
+// getDraftAnswers retrieves a draft's answers in the order they were first given
+func (r *QuizRepository) getDraftAnswers(ctx context.Context, draftID string) ([]entities.QuizAnswer, error) {
+	rows, err := r.db.DB().QueryContext(ctx, `
+		SELECT question_id, answer, confidence, time_taken_seconds
+		FROM quiz_draft_answers
+		WHERE draft_id = ?
+		ORDER BY rowid
+	`, draftID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	answers := []entities.QuizAnswer{}
+	for rows.Next() {
+		var answer entities.QuizAnswer
+		var rawAnswer string
+		var confidence sql.NullString
+		var timeTaken sql.NullInt64
+
+		if err := rows.Scan(&answer.QuestionID, &rawAnswer, &confidence, &timeTaken); err != nil {
+			return nil, err
+		}
+
+		answer.Answer = json.RawMessage(rawAnswer)
+		if confidence.Valid {
+			answer.Confidence = entities.ConfidenceLevel(confidence.String)
+		}
+		if timeTaken.Valid {
+			answer.TimeTakenSec = int(timeTaken.Int64)
+		}
+		answers = append(answers, answer)
+	}
+
+	return answers, rows.Err()
+}
+
+// scanQuizDraft reads a quiz_drafts row, without its answers
+func scanQuizDraft(row interface{ Scan(dest ...any) error }) (*entities.QuizDraft, error) {
+	draft := &entities.QuizDraft{}
+	var deadline sql.NullTime
+
+	err := row.Scan(
+		&draft.ID,
+		&draft.UserID,
+		&draft.CourseID,
+		&draft.QuizID,
+		&draft.InstanceID,
+		&draft.SessionID,
+		&draft.StartedAt,
+		&draft.UpdatedAt,
+		&draft.ExpiresAt,
+		&deadline,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if deadline.Valid {
+		draft.Deadline = &deadline.Time
+	}
+	return draft, nil
+}
+
+// completedAtFilter builds the SQL condition and arguments restricting attempts to a date range
+// Times are compared in UTC, the zone attempts are stored in
+func completedAtFilter(fromDate, toDate *time.Time) (string, []interface{}) {
+	filter := ""
+	var args []interface{}
+	if fromDate != nil {
+		filter += " AND completed_at >= ?"
+		args = append(args, fromDate.UTC())
+	}
+	if toDate != nil {
+		filter += " AND completed_at <= ?"
+		args = append(args, toDate.UTC())
+	}
+	return filter, args
+}
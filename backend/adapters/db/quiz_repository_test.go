@@ -0,0 +1,725 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/project/backend/domain/entities"
+)
+
+// createTestQuizUser stores a user, since quiz attempts and review items reference users
+func createTestQuizUser(t *testing.T, db *SQLiteDB) string {
+	t.Helper()
+
+	user, err := entities.NewUser("learner@example.com", "Learner", "password123")
+	if err != nil {
+		t.Fatalf("failed to create user entity: %v", err)
+	}
+	created, err := NewUserRepository(db).Create(context.Background(), user)
+	if err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	return created.ID
+}
+
+// saveTestAttempt stores an attempt completed at the given time
+func saveTestAttempt(t *testing.T, repo *QuizRepository, userID, courseID, quizID string, score, maxScore int, completedAt time.Time) *entities.QuizAttempt {
+	t.Helper()
+
+	attempt := entities.NewQuizAttempt(userID, courseID, entities.QuizTypeForLessonPath([]int{0, 0}), quizID, score, maxScore, maxScore, score)
+	attempt.CompletedAt = completedAt
+	saved, err := repo.SaveAttempt(context.Background(), attempt)
+	if err != nil {
+		t.Fatalf("failed to save attempt: %v", err)
+	}
+	return saved
+}
+
+func TestQuizRepository_SaveAttemptAndResponses(t *testing.T) {
+	db, cleanup := setupTestCourseDB(t)
+	defer cleanup()
+
+	repo := NewQuizRepository(db)
+	ctx := context.Background()
+	userID := createTestQuizUser(t, db)
+
+	attempt := saveTestAttempt(t, repo, userID, "course-1", "lesson-00-sub-00", 3, 4, time.Now())
+	if attempt.ID == "" {
+		t.Fatal("expected attempt ID to be set")
+	}
+
+	stored, err := repo.GetAttempt(ctx, attempt.ID)
+	if err != nil {
+		t.Fatalf("failed to get attempt: %v", err)
+	}
+	if stored.UserID != userID || stored.QuizID != "lesson-00-sub-00" || stored.Score != 3 {
+		t.Errorf("expected attempt to round-trip, got %+v", stored)
+	}
+	if _, err := repo.GetAttempt(ctx, "missing"); err != entities.ErrQuizAttemptNotFound {
+		t.Errorf("expected ErrQuizAttemptNotFound, got %v", err)
+	}
+
+	_, err = repo.SaveResponse(ctx, &entities.QuizResponse{
+		AttemptID:      attempt.ID,
+		QuestionID:     "q1",
+		UserAnswer:     json.RawMessage(`1`),
+		IsCorrect:      true,
+		PointsEarned:   3,
+		PointsPossible: 3,
+		Confidence:     entities.ConfidenceHigh,
+		TimeTakenSec:   12,
+		Concept:        "loops",
+		TestResults:    []entities.CodeTestResult{{Name: "TestLoop", Passed: false, Output: "want 3"}},
+	})
+	if err != nil {
+		t.Fatalf("failed to save response: %v", err)
+	}
+
+	responses, err := repo.GetResponsesByAttempt(ctx, attempt.ID)
+	if err != nil {
+		t.Fatalf("failed to get responses: %v", err)
+	}
+	if len(responses) != 1 {
+		t.Fatalf("expected 1 response, got %d", len(responses))
+	}
+	r := responses[0]
+	if string(r.UserAnswer) != "1" || !r.IsCorrect || r.Confidence != entities.ConfidenceHigh || r.TimeTakenSec != 12 || r.Concept != "loops" {
+		t.Errorf("expected response to round-trip, got %+v", r)
+	}
+	if len(r.TestResults) != 1 || r.TestResults[0].Name != "TestLoop" || r.TestResults[0].Output != "want 3" {
+		t.Errorf("expected test results to round-trip, got %+v", r.TestResults)
+	}
+
+	answered, err := repo.GetAnsweredQuestions(ctx, userID, "course-1")
+	if err != nil {
+		t.Fatalf("failed to get answered questions: %v", err)
+	}
+	if ids := answered["lesson-00-sub-00"]; len(ids) != 1 || ids[0] != "q1" {
+		t.Errorf("expected q1 to be answered, got %v", answered)
+	}
+}
+
+func TestQuizRepository_GetItemResponses(t *testing.T) {
+	db, cleanup := setupTestCourseDB(t)
+	defer cleanup()
+
+	repo := NewQuizRepository(db)
+	ctx := context.Background()
+	userID := createTestQuizUser(t, db)
+	now := time.Now()
+
+	first := saveTestAttempt(t, repo, userID, "course-1", "lesson-00", 1, 4, now.Add(-time.Hour))
+	second := saveTestAttempt(t, repo, userID, "course-1", "lesson-00", 3, 4, now)
+	other := saveTestAttempt(t, repo, userID, "course-1", "lesson-01", 4, 4, now)
+	for _, attempt := range []*entities.QuizAttempt{first, second, other} {
+		_, err := repo.SaveResponse(ctx, &entities.QuizResponse{
+			AttemptID: attempt.ID, QuestionID: "q1", UserAnswer: json.RawMessage(`0`), PointsPossible: 1,
+		})
+		if err != nil {
+			t.Fatalf("failed to save response: %v", err)
+		}
+	}
+
+	responses, err := repo.GetItemResponses(ctx, "course-1", "lesson-00")
+	if err != nil {
+		t.Fatalf("failed to get item responses: %v", err)
+	}
+	if len(responses) != 2 {
+		t.Fatalf("expected 2 responses for lesson-00, got %d", len(responses))
+	}
+	if responses[0].AttemptID != first.ID || responses[0].AttemptPercentage != 25 || responses[1].AttemptPercentage != 75 {
+		t.Errorf("expected responses with their attempt percentages in attempt order, got %+v", responses)
+	}
+}
+
+func TestQuizRepository_GetQuestionResponses(t *testing.T) {
+	db, cleanup := setupTestCourseDB(t)
+	defer cleanup()
+
+	repo := NewQuizRepository(db)
+	ctx := context.Background()
+	userID := createTestQuizUser(t, db)
+	now := time.Now()
+
+	first := saveTestAttempt(t, repo, userID, "course-1", "lesson-00", 1, 2, now.Add(-time.Hour))
+	second := saveTestAttempt(t, repo, userID, "course-2", "lesson-03", 2, 2, now)
+	for _, attempt := range []*entities.QuizAttempt{first, second} {
+		for _, questionID := range []string{"go-basics.q1", "own"} {
+			_, err := repo.SaveResponse(ctx, &entities.QuizResponse{
+				AttemptID: attempt.ID, QuestionID: questionID, UserAnswer: json.RawMessage(`0`), PointsPossible: 1,
+			})
+			if err != nil {
+				t.Fatalf("failed to save response: %v", err)
+			}
+		}
+	}
+
+	responses, err := repo.GetQuestionResponses(ctx, []string{"go-basics.q1", "go-basics.q2"})
+	if err != nil {
+		t.Fatalf("failed to get question responses: %v", err)
+	}
+	if len(responses) != 2 {
+		t.Fatalf("expected the bank question's responses from both courses, got %d", len(responses))
+	}
+	if responses[0].CourseID != "course-1" || responses[1].CourseID != "course-2" || responses[1].AttemptPercentage != 100 {
+		t.Errorf("expected responses with their attempt's course and percentage, got %+v", responses)
+	}
+}
+
+func TestQuizRepository_GetQuizStats(t *testing.T) {
+	db, cleanup := setupTestCourseDB(t)
+	defer cleanup()
+
+	repo := NewQuizRepository(db)
+	ctx := context.Background()
+	userID := createTestQuizUser(t, db)
+	now := time.Now()
+
+	saveTestAttempt(t, repo, userID, "course-1", "lesson-00", 2, 4, now.Add(-2*time.Hour))
+	saveTestAttempt(t, repo, userID, "course-1", "lesson-00", 4, 4, now.Add(-time.Hour))
+	saveTestAttempt(t, repo, userID, "course-1", "lesson-00", 3, 4, now)
+	saveTestAttempt(t, repo, userID, "course-1", "lesson-01", 1, 4, now)
+
+	stats, err := repo.GetQuizStats(ctx, userID, "course-1", "lesson-00")
+	if err != nil {
+		t.Fatalf("failed to get quiz stats: %v", err)
+	}
+
+	if stats.AttemptCount != 3 {
+		t.Errorf("expected 3 attempts, got %d", stats.AttemptCount)
+	}
+	if stats.LatestScore == nil || *stats.LatestScore != 75 {
+		t.Errorf("expected latest score 75, got %v", stats.LatestScore)
+	}
+	if stats.BestScore == nil || *stats.BestScore != 100 {
+		t.Errorf("expected best score 100, got %v", stats.BestScore)
+	}
+	if stats.BestMastery != entities.MasteryExpert {
+		t.Errorf("expected best mastery expert, got %s", stats.BestMastery)
+	}
+	if len(stats.History) != 3 || stats.History[0].Score != 3 {
+		t.Errorf("expected history newest first, got %+v", stats.History)
+	}
+
+	empty, err := repo.GetQuizStats(ctx, userID, "course-1", "lesson-09")
+	if err != nil {
+		t.Fatalf("failed to get quiz stats: %v", err)
+	}
+	if empty.AttemptCount != 0 || empty.LatestScore != nil || empty.BestScore != nil {
+		t.Errorf("expected no stats for an untaken quiz, got %+v", empty)
+	}
+}
+
+func TestQuizRepository_GetCourseQuizSummary(t *testing.T) {
+	db, cleanup := setupTestCourseDB(t)
+	defer cleanup()
+
+	repo := NewQuizRepository(db)
+	ctx := context.Background()
+	userID := createTestQuizUser(t, db)
+	now := time.Now()
+
+	chapter := entities.NewQuizAttempt(userID, "course-1", entities.QuizTypeForLessonPath([]int{0}), "lesson-00", 1, 2, 2, 1)
+	if _, err := repo.SaveAttempt(ctx, chapter); err != nil {
+		t.Fatalf("failed to save attempt: %v", err)
+	}
+	sub := saveTestAttempt(t, repo, userID, "course-1", "lesson-00-sub-00", 4, 4, now)
+	saveTestAttempt(t, repo, userID, "course-2", "lesson-00-sub-00", 0, 4, now)
+
+	for i, correct := range []bool{true, true, false, false} {
+		concept := "maps"
+		if i >= 2 {
+			concept = "channels"
+		}
+		_, err := repo.SaveResponse(ctx, &entities.QuizResponse{
+			AttemptID:  sub.ID,
+			QuestionID: fmt.Sprintf("q%d", i+1),
+			UserAnswer: json.RawMessage(`0`),
+			IsCorrect:  correct,
+			Confidence: entities.ConfidenceHigh,
+			Concept:    concept,
+		})
+		if err != nil {
+			t.Fatalf("failed to save response: %v", err)
+		}
+	}
+
+	err := repo.AddToReviewQueue(ctx, &entities.ReviewQueueItem{
+		UserID: userID, CourseID: "course-1", QuizID: "lesson-00-sub-00", QuestionID: "q3",
+		LastAttempt: now, NextReview: now.Add(-time.Minute), Stability: 1,
+	})
+	if err != nil {
+		t.Fatalf("failed to queue review: %v", err)
+	}
+
+	summary, err := repo.GetCourseQuizSummary(ctx, userID, "course-1")
+	if err != nil {
+		t.Fatalf("failed to get course summary: %v", err)
+	}
+
+	if summary.TotalQuizzes != 2 || summary.CompletedQuizzes != 2 {
+		t.Errorf("expected 2 quizzes, got %d total and %d completed", summary.TotalQuizzes, summary.CompletedQuizzes)
+	}
+	if len(summary.ChapterStats) != 1 || len(summary.SubchapterStats) != 1 {
+		t.Errorf("expected 1 chapter and 1 subchapter quiz, got %d and %d", len(summary.ChapterStats), len(summary.SubchapterStats))
+	}
+	if summary.AverageScore != 75 {
+		t.Errorf("expected average score 75, got %v", summary.AverageScore)
+	}
+	if summary.ReviewQueueSize != 1 {
+		t.Errorf("expected 1 due review item, got %d", summary.ReviewQueueSize)
+	}
+	if len(summary.WeakConcepts) != 1 || summary.WeakConcepts[0] != "channels" {
+		t.Errorf("expected weak concepts [channels], got %v", summary.WeakConcepts)
+	}
+	if len(summary.StrongConcepts) != 1 || summary.StrongConcepts[0] != "maps" {
+		t.Errorf("expected strong concepts [maps], got %v", summary.StrongConcepts)
+	}
+	if len(summary.ConfidentlyWrongConcepts) != 1 || summary.ConfidentlyWrongConcepts[0] != "channels" {
+		t.Errorf("expected confidently wrong concepts [channels], got %v", summary.ConfidentlyWrongConcepts)
+	}
+}
+
+func TestQuizRepository_GetCalibration(t *testing.T) {
+	db, cleanup := setupTestCourseDB(t)
+	defer cleanup()
+
+	repo := NewQuizRepository(db)
+	ctx := context.Background()
+	userID := createTestQuizUser(t, db)
+	now := time.Now()
+
+	responses := map[string][]entities.QuizResponse{
+		"course-1": {
+			{QuestionID: "q1", IsCorrect: true, Confidence: entities.ConfidenceHigh, Concept: "maps"},
+			{QuestionID: "q2", IsCorrect: false, Confidence: entities.ConfidenceHigh, Concept: "maps"},
+			{QuestionID: "q3", IsCorrect: false, Confidence: entities.ConfidenceLow, Concept: "loops"},
+		},
+		"course-2": {
+			{QuestionID: "q1", IsCorrect: true, Confidence: entities.ConfidenceLow, Concept: "structs"},
+			{QuestionID: "q2", IsCorrect: true},
+		},
+	}
+	for courseID, courseResponses := range responses {
+		attempt := saveTestAttempt(t, repo, userID, courseID, "lesson-00", 1, 2, now)
+		for _, response := range courseResponses {
+			response.AttemptID = attempt.ID
+			response.UserAnswer = json.RawMessage(`0`)
+			if _, err := repo.SaveResponse(ctx, &response); err != nil {
+				t.Fatalf("failed to save response: %v", err)
+			}
+		}
+	}
+
+	course, err := repo.GetCalibration(ctx, userID, "course-1")
+	if err != nil {
+		t.Fatalf("failed to get calibration: %v", err)
+	}
+	if course.Responses != 3 || course.ConfidentlyWrong != 1 {
+		t.Errorf("expected 3 responses with 1 confidently wrong, got %d and %d", course.Responses, course.ConfidentlyWrong)
+	}
+	if len(course.ConfidentlyWrongConcepts) != 1 || course.ConfidentlyWrongConcepts[0] != "maps" {
+		t.Errorf("expected confidently wrong concepts [maps], got %v", course.ConfidentlyWrongConcepts)
+	}
+
+	all, err := repo.GetCalibration(ctx, userID, "")
+	if err != nil {
+		t.Fatalf("failed to get calibration: %v", err)
+	}
+	if all.Responses != 5 {
+		t.Errorf("expected 5 responses across courses, got %d", all.Responses)
+	}
+	if len(all.Levels) != 2 || all.Levels[0].Confidence != entities.ConfidenceLow || all.Levels[0].Accuracy != 50 {
+		t.Errorf("expected low confidence at 50%% accuracy first, got %+v", all.Levels)
+	}
+}
+
+func TestQuizRepository_GetDashboardQuizStats_DateFilter(t *testing.T) {
+	db, cleanup := setupTestCourseDB(t)
+	defer cleanup()
+
+	repo := NewQuizRepository(db)
+	ctx := context.Background()
+	userID := createTestQuizUser(t, db)
+
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 15, 0, 0, 0, time.UTC) }
+	saveTestAttempt(t, repo, userID, "course-1", "lesson-00", 1, 4, day(1))
+	saveTestAttempt(t, repo, userID, "course-1", "lesson-01", 2, 4, day(10))
+	saveTestAttempt(t, repo, userID, "course-2", "lesson-00", 4, 4, day(20))
+
+	tests := []struct {
+		name        string
+		from, to    *time.Time
+		wantRecent  int
+		wantCourses int
+		wantHistory int
+	}{
+		{"no filter", nil, nil, 3, 2, 3},
+		{"from only", timePtr(day(5)), nil, 2, 2, 2},
+		{"to only", nil, timePtr(day(15)), 2, 1, 2},
+		{"range", timePtr(day(5)), timePtr(day(15)), 1, 1, 1},
+		{"bounds are inclusive", timePtr(day(10)), timePtr(day(10)), 1, 1, 1},
+		{"empty range", timePtr(day(25)), nil, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats, err := repo.GetDashboardQuizStats(ctx, userID, tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("failed to get dashboard stats: %v", err)
+			}
+
+			if len(stats.RecentAttempts) != tt.wantRecent {
+				t.Errorf("expected %d recent attempts, got %d", tt.wantRecent, len(stats.RecentAttempts))
+			}
+			if len(stats.CourseSummaries) != tt.wantCourses {
+				t.Errorf("expected %d course summaries, got %d", tt.wantCourses, len(stats.CourseSummaries))
+			}
+			if len(stats.ScoreHistory) != tt.wantHistory {
+				t.Errorf("expected %d score history points, got %d", tt.wantHistory, len(stats.ScoreHistory))
+			}
+		})
+	}
+
+	// Filter times in another zone select the same instants
+	tokyo := time.FixedZone("JST", 9*60*60)
+	from := day(10).In(tokyo)
+	stats, err := repo.GetDashboardQuizStats(ctx, userID, &from, nil)
+	if err != nil {
+		t.Fatalf("failed to get dashboard stats: %v", err)
+	}
+	if len(stats.RecentAttempts) != 2 {
+		t.Errorf("expected 2 attempts from a zoned filter, got %d", len(stats.RecentAttempts))
+	}
+}
+
+func TestQuizRepository_ReviewQueue(t *testing.T) {
+	db, cleanup := setupTestCourseDB(t)
+	defer cleanup()
+
+	repo := NewQuizRepository(db)
+	ctx := context.Background()
+	userID := createTestQuizUser(t, db)
+	now := time.Now()
+
+	item := &entities.ReviewQueueItem{
+		UserID: userID, CourseID: "course-1", QuizID: "lesson-00-sub-00", QuestionID: "q1", Concept: "loops",
+		WrongCount: 1, LastAttempt: now, NextReview: now.Add(-time.Minute), Stability: 1,
+	}
+	if err := repo.AddToReviewQueue(ctx, item); err != nil {
+		t.Fatalf("failed to queue review: %v", err)
+	}
+	err := repo.AddToReviewQueue(ctx, &entities.ReviewQueueItem{
+		UserID: userID, CourseID: "course-2", QuizID: "lesson-00", QuestionID: "q2",
+		WrongCount: 1, LastAttempt: now, NextReview: now.Add(time.Hour), Stability: 1,
+	})
+	if err != nil {
+		t.Fatalf("failed to queue review: %v", err)
+	}
+
+	due, err := repo.GetReviewQueue(ctx, userID, "course-1", 10)
+	if err != nil {
+		t.Fatalf("failed to get review queue: %v", err)
+	}
+	if len(due) != 1 || due[0].QuestionID != "q1" {
+		t.Errorf("expected q1 to be due, got %+v", due)
+	}
+
+	// Re-adding replaces the schedule
+	item.Stability = 4
+	item.WrongCount = 2
+	reviewed := now
+	item.LastReviewed = &reviewed
+	item.NextReview = now.Add(-time.Second)
+	if err := repo.AddToReviewQueue(ctx, item); err != nil {
+		t.Fatalf("failed to reschedule review: %v", err)
+	}
+	stored, err := repo.GetReviewItem(ctx, userID, "course-1", "q1")
+	if err != nil {
+		t.Fatalf("failed to get review item: %v", err)
+	}
+	if stored.Stability != 4 || stored.WrongCount != 2 || stored.LastReviewed == nil {
+		t.Errorf("expected the schedule to be replaced, got %+v", stored)
+	}
+
+	allDue, err := repo.GetDueReviewItems(ctx, userID, []string{"course-1", "course-2"})
+	if err != nil {
+		t.Fatalf("failed to get due items: %v", err)
+	}
+	if len(allDue) != 1 {
+		t.Errorf("expected 1 due item across courses, got %d", len(allDue))
+	}
+
+	count, err := repo.CountReviewedSince(ctx, userID, now.Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("failed to count reviews: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 review today, got %d", count)
+	}
+
+	if err := repo.RemoveFromReviewQueue(ctx, userID, "course-1", "q1"); err != nil {
+		t.Fatalf("failed to remove review item: %v", err)
+	}
+	if _, err := repo.GetReviewItem(ctx, userID, "course-1", "q1"); err != entities.ErrReviewItemNotFound {
+		t.Errorf("expected ErrReviewItemNotFound after removal, got %v", err)
+	}
+}
+
+func TestQuizRepository_ReviewQueuePriority(t *testing.T) {
+	db, cleanup := setupTestCourseDB(t)
+	defer cleanup()
+
+	repo := NewQuizRepository(db)
+	ctx := context.Background()
+	userID := createTestQuizUser(t, db)
+	now := time.Now()
+
+	for i, priority := range []int{entities.ReviewPriorityLow, entities.ReviewPriorityHigh, entities.ReviewPriorityNormal} {
+		err := repo.AddToReviewQueue(ctx, &entities.ReviewQueueItem{
+			UserID: userID, CourseID: "course-1", QuizID: "lesson-00", QuestionID: fmt.Sprintf("q%d", i),
+			WrongCount: 1, LastAttempt: now, NextReview: now.Add(-time.Duration(10-i) * time.Minute), Stability: 1,
+			Priority: priority,
+		})
+		if err != nil {
+			t.Fatalf("failed to queue review: %v", err)
+		}
+	}
+
+	due, err := repo.GetReviewQueue(ctx, userID, "course-1", 10)
+	if err != nil {
+		t.Fatalf("failed to get review queue: %v", err)
+	}
+	if len(due) != 3 || due[0].QuestionID != "q1" || due[1].QuestionID != "q2" || due[2].QuestionID != "q0" {
+		t.Errorf("expected due items ordered by priority, got %+v", due)
+	}
+	if due[0].Priority != entities.ReviewPriorityHigh {
+		t.Errorf("expected the stored priority to be read back, got %d", due[0].Priority)
+	}
+}
+
+func TestQuizRepository_QuizInstance(t *testing.T) {
+	db, cleanup := setupTestCourseDB(t)
+	defer cleanup()
+
+	repo := NewQuizRepository(db)
+	ctx := context.Background()
+	userID := createTestQuizUser(t, db)
+
+	instance, err := entities.NewQuizInstance(userID, "course-1", []int{0}, true, -8123456789, []string{"q3", "q1", "q2"})
+	if err != nil {
+		t.Fatalf("failed to create instance: %v", err)
+	}
+	saved, err := repo.SaveQuizInstance(ctx, instance)
+	if err != nil {
+		t.Fatalf("failed to save instance: %v", err)
+	}
+
+	found, err := repo.GetQuizInstance(ctx, saved.ID)
+	if err != nil {
+		t.Fatalf("failed to get instance: %v", err)
+	}
+	if found.Seed != -8123456789 || !found.IncludeSublessons || found.QuizID != "lesson-00" {
+		t.Errorf("expected seed, pool and quiz ID to round-trip, got %+v", found)
+	}
+	if len(found.QuestionIDs) != 3 || found.QuestionIDs[0] != "q3" {
+		t.Errorf("expected question order to round-trip, got %v", found.QuestionIDs)
+	}
+
+	if _, err := repo.GetQuizInstance(ctx, "missing"); err != entities.ErrQuizInstanceNotFound {
+		t.Errorf("expected ErrQuizInstanceNotFound, got %v", err)
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func TestQuizRepository_QuizSessions(t *testing.T) {
+	db, cleanup := setupTestCourseDB(t)
+	defer cleanup()
+
+	repo := NewQuizRepository(db)
+	ctx := context.Background()
+	userID := createTestQuizUser(t, db)
+
+	timed := entities.NewQuizSession(userID, "course-1", []int{1}, entities.ExamConfig{TimeLimitMinutes: 30})
+	timed.StartedAt = timed.StartedAt.Add(-time.Hour)
+	if _, err := repo.SaveQuizSession(ctx, timed); err != nil {
+		t.Fatalf("failed to save session: %v", err)
+	}
+	untimed := entities.NewQuizSession(userID, "course-1", []int{1}, entities.ExamConfig{})
+	if _, err := repo.SaveQuizSession(ctx, untimed); err != nil {
+		t.Fatalf("failed to save session: %v", err)
+	}
+
+	sessions, err := repo.GetQuizSessions(ctx, userID, "course-1", "lesson-01")
+	if err != nil {
+		t.Fatalf("failed to get sessions: %v", err)
+	}
+	if len(sessions) != 2 || sessions[0].ID != untimed.ID || sessions[1].ExpiresAt == nil || sessions[0].ExpiresAt != nil {
+		t.Errorf("expected both sessions newest first, got %+v", sessions)
+	}
+
+	if err := repo.CloseQuizSession(ctx, untimed.ID, time.Now()); err != nil {
+		t.Fatalf("failed to close session: %v", err)
+	}
+	if err := repo.CloseQuizSession(ctx, untimed.ID, time.Now()); err != entities.ErrQuizSessionClosed {
+		t.Errorf("expected ErrQuizSessionClosed when closing twice, got %v", err)
+	}
+	if err := repo.CloseQuizSession(ctx, "missing", time.Now()); err != entities.ErrQuizSessionNotFound {
+		t.Errorf("expected ErrQuizSessionNotFound, got %v", err)
+	}
+
+	stored, err := repo.GetQuizSession(ctx, untimed.ID)
+	if err != nil {
+		t.Fatalf("failed to get session: %v", err)
+	}
+	if stored.SubmittedAt == nil || stored.UserID != userID || stored.QuizID != "lesson-01" {
+		t.Errorf("expected the submitted session to round-trip, got %+v", stored)
+	}
+}
+
+func TestQuizRepository_SaveGradedAttempt(t *testing.T) {
+	db, cleanup := setupTestCourseDB(t)
+	defer cleanup()
+
+	repo := NewQuizRepository(db)
+	ctx := context.Background()
+	userID := createTestQuizUser(t, db)
+
+	session := entities.NewQuizSession(userID, "course-1", []int{1}, entities.ExamConfig{})
+	if _, err := repo.SaveQuizSession(ctx, session); err != nil {
+		t.Fatalf("failed to save session: %v", err)
+	}
+	newAttempt := func() *entities.QuizAttempt {
+		return entities.NewQuizAttempt(userID, "course-1", entities.QuizTypeForLessonPath([]int{1}), "lesson-01", 1, 2, 2, 1)
+	}
+
+	// A response that cannot be stored leaves the session open and stores nothing
+	duplicate := []entities.QuizResponse{
+		{ID: "response-1", QuestionID: "q1", UserAnswer: json.RawMessage(`0`)},
+		{ID: "response-1", QuestionID: "q2", UserAnswer: json.RawMessage(`1`)},
+	}
+	if _, err := repo.SaveGradedAttempt(ctx, session.ID, time.Now(), newAttempt(), duplicate); err == nil {
+		t.Fatal("expected saving a duplicate response to fail")
+	}
+	if attempts, _ := repo.GetAttemptsByQuiz(ctx, userID, "course-1", "lesson-01"); len(attempts) != 0 {
+		t.Errorf("expected the attempt to be rolled back, got %+v", attempts)
+	}
+	if stored, _ := repo.GetQuizSession(ctx, session.ID); stored.SubmittedAt != nil {
+		t.Error("expected the session to be left open")
+	}
+
+	responses := []entities.QuizResponse{
+		{QuestionID: "q1", UserAnswer: json.RawMessage(`0`), IsCorrect: true},
+		{QuestionID: "q2", UserAnswer: json.RawMessage(`1`)},
+	}
+	saved, err := repo.SaveGradedAttempt(ctx, session.ID, time.Now(), newAttempt(), responses)
+	if err != nil {
+		t.Fatalf("failed to save graded attempt: %v", err)
+	}
+	stored, err := repo.GetResponsesByAttempt(ctx, saved.ID)
+	if err != nil {
+		t.Fatalf("failed to get responses: %v", err)
+	}
+	if len(stored) != 2 || responses[0].AttemptID != saved.ID {
+		t.Errorf("expected both responses linked to the attempt, got %+v", stored)
+	}
+	if closed, _ := repo.GetQuizSession(ctx, session.ID); closed.SubmittedAt == nil {
+		t.Error("expected the session to be closed")
+	}
+
+	// Submitting the closed session again stores nothing
+	if _, err := repo.SaveGradedAttempt(ctx, session.ID, time.Now(), newAttempt(), responses); err != entities.ErrQuizSessionClosed {
+		t.Errorf("expected ErrQuizSessionClosed, got %v", err)
+	}
+	if _, err := repo.SaveGradedAttempt(ctx, "missing", time.Now(), newAttempt(), nil); err != entities.ErrQuizSessionNotFound {
+		t.Errorf("expected ErrQuizSessionNotFound, got %v", err)
+	}
+	if attempts, _ := repo.GetAttemptsByQuiz(ctx, userID, "course-1", "lesson-01"); len(attempts) != 1 {
+		t.Errorf("expected only the first submission to be stored, got %d attempts", len(attempts))
+	}
+
+	// Practice attempts have no session
+	if _, err := repo.SaveGradedAttempt(ctx, "", time.Now(), newAttempt(), nil); err != nil {
+		t.Errorf("failed to save an attempt without a session: %v", err)
+	}
+}
+
+func TestQuizRepository_QuizDrafts(t *testing.T) {
+	db, cleanup := setupTestCourseDB(t)
+	defer cleanup()
+
+	repo := NewQuizRepository(db)
+	ctx := context.Background()
+	userID := createTestQuizUser(t, db)
+
+	draft, err := entities.NewQuizDraft(userID, "course-1", []int{1, 0}, "", "", nil)
+	if err != nil {
+		t.Fatalf("failed to create draft: %v", err)
+	}
+	if _, err := repo.SaveQuizDraft(ctx, draft); err != nil {
+		t.Fatalf("failed to save draft: %v", err)
+	}
+
+	answers := []entities.QuizAnswer{
+		{QuestionID: "q2", Answer: json.RawMessage(`[0, 2]`), Confidence: entities.ConfidenceHigh, TimeTakenSec: 12},
+		{QuestionID: "q1", Answer: json.RawMessage(`"ports"`)},
+		{QuestionID: "q2", Answer: json.RawMessage(`[1]`), Confidence: entities.ConfidenceLow, TimeTakenSec: 20},
+	}
+	for _, answer := range answers {
+		draft.Touch(time.Now())
+		if err := repo.SaveQuizDraftAnswer(ctx, draft, answer); err != nil {
+			t.Fatalf("failed to save answer: %v", err)
+		}
+	}
+
+	stored, err := repo.GetQuizDraft(ctx, draft.ID)
+	if err != nil {
+		t.Fatalf("failed to get draft: %v", err)
+	}
+	if stored.UserID != userID || stored.QuizID != "lesson-01-sub-00" || stored.Deadline != nil {
+		t.Errorf("expected the draft to round-trip, got %+v", stored)
+	}
+	if len(stored.Answers) != 2 || stored.Answers[0].QuestionID != "q2" || string(stored.Answers[0].Answer) != "[1]" ||
+		stored.Answers[0].Confidence != entities.ConfidenceLow || stored.Answers[0].TimeTakenSec != 20 {
+		t.Errorf("expected q2 replaced in place before q1, got %+v", stored.Answers)
+	}
+	if !stored.ExpiresAt.Equal(draft.ExpiresAt.UTC()) {
+		t.Errorf("expected the expiry to move with the last answer, got %v", stored.ExpiresAt)
+	}
+
+	stale, _ := entities.NewQuizDraft(userID, "course-1", []int{2}, "", "", nil)
+	stale.Touch(time.Now().Add(-entities.QuizDraftTTL - time.Hour))
+	stale.Answers = []entities.QuizAnswer{{QuestionID: "q1", Answer: json.RawMessage(`1`)}}
+	if _, err := repo.SaveQuizDraft(ctx, stale); err != nil {
+		t.Fatalf("failed to save draft: %v", err)
+	}
+
+	open, err := repo.GetOpenQuizDrafts(ctx, userID, time.Now())
+	if err != nil {
+		t.Fatalf("failed to get open drafts: %v", err)
+	}
+	if len(open) != 1 || open[0].ID != draft.ID || len(open[0].Answers) != 2 {
+		t.Errorf("expected only the unexpired draft, got %+v", open)
+	}
+
+	deleted, err := repo.DeleteExpiredQuizDrafts(ctx, time.Now())
+	if err != nil || deleted != 1 {
+		t.Errorf("expected 1 expired draft deleted, got %d, %v", deleted, err)
+	}
+	if _, err := repo.GetQuizDraft(ctx, stale.ID); err != entities.ErrQuizDraftNotFound {
+		t.Errorf("expected ErrQuizDraftNotFound for the purged draft, got %v", err)
+	}
+
+	if err := repo.DeleteQuizDraft(ctx, draft.ID); err != nil {
+		t.Fatalf("failed to delete draft: %v", err)
+	}
+	if _, err := repo.GetQuizDraft(ctx, draft.ID); err != entities.ErrQuizDraftNotFound {
+		t.Errorf("expected ErrQuizDraftNotFound after deleting, got %v", err)
+	}
+}
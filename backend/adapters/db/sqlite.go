@@ -179,6 +179,53 @@ func (s *SQLiteDB) Migrate() error {
 		)`,
 		`CREATE INDEX IF NOT EXISTS idx_review_queue_user_id ON review_queue(user_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_review_queue_next_review ON review_queue(next_review)`,
+		`CREATE TABLE IF NOT EXISTS quiz_instances (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			course_id TEXT NOT NULL,
+			quiz_id TEXT NOT NULL,
+			include_sublessons BOOLEAN NOT NULL DEFAULT 0,
+			seed INTEGER NOT NULL,
+			question_ids TEXT NOT NULL,
+			created_at DATETIME NOT NULL,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_quiz_instances_user_id ON quiz_instances(user_id)`,
+		`CREATE TABLE IF NOT EXISTS quiz_sessions (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			course_id TEXT NOT NULL,
+			quiz_id TEXT NOT NULL,
+			started_at DATETIME NOT NULL,
+			expires_at DATETIME,
+			submitted_at DATETIME,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_quiz_sessions_user_quiz ON quiz_sessions(user_id, course_id, quiz_id)`,
+		`CREATE TABLE IF NOT EXISTS quiz_drafts (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			course_id TEXT NOT NULL,
+			quiz_id TEXT NOT NULL,
+			instance_id TEXT NOT NULL DEFAULT '',
+			session_id TEXT NOT NULL DEFAULT '',
+			started_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL,
+			expires_at DATETIME NOT NULL,
+			deadline DATETIME,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_quiz_drafts_user_id ON quiz_drafts(user_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_quiz_drafts_expires_at ON quiz_drafts(expires_at)`,
+		`CREATE TABLE IF NOT EXISTS quiz_draft_answers (
+			draft_id TEXT NOT NULL,
+			question_id TEXT NOT NULL,
+			answer TEXT NOT NULL,
+			confidence TEXT,
+			time_taken_seconds INTEGER,
+			PRIMARY KEY (draft_id, question_id),
+			FOREIGN KEY (draft_id) REFERENCES quiz_drafts(id) ON DELETE CASCADE
+		)`,
 	}
 
 	for _, migration := range migrations {
@@ -196,6 +243,11 @@ func (s *SQLiteDB) Migrate() error {
 		{"library_courses", "author_id", "TEXT NOT NULL DEFAULT ''"},
 		{"library_courses", "tags", "TEXT NOT NULL DEFAULT '[]'"},
 		{"user_courses", "completed_lessons", "TEXT NOT NULL DEFAULT '[]'"},
+		{"user_courses", "completion_reasons", "TEXT NOT NULL DEFAULT '{}'"},
+		{"review_queue", "last_reviewed", "DATETIME"},
+		{"quiz_responses", "concept", "TEXT NOT NULL DEFAULT ''"},
+		{"review_queue", "priority", "INTEGER NOT NULL DEFAULT 1"},
+		{"quiz_responses", "test_results", "TEXT NOT NULL DEFAULT '[]'"},
 	}
 
 	for _, cm := range columnMigrations {
@@ -3,7 +3,9 @@ package folder
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"sort"
@@ -19,9 +21,19 @@ import (
 type FolderCourseRepository struct {
 	coursesPath string
 	cache       map[string]*entities.LibraryCourse
+	banks       map[string]*entities.QuestionBank
+	folders     map[string]string // Course folder paths, by course ID
+	duplicates  []error           // Folders skipped because their course ID was taken
 	cacheMu     sync.RWMutex
+	loadMu      sync.Mutex // Serializes reloads, which read from disk without holding cacheMu
 	lastLoad    time.Time
 	cacheTTL    time.Duration
+	watching    bool // Set while Watch reloads changed courses, which replaces the TTL
+
+	subscribersMu sync.Mutex
+	subscribers   map[chan entities.CourseChange]struct{}
+
+	historyMu sync.Mutex // Serializes lesson content saves, which number their revisions
 }
 
 // NewFolderCourseRepository creates a new folder-based course repository
@@ -29,7 +41,10 @@ func NewFolderCourseRepository(coursesPath string) *FolderCourseRepository {
 	return &FolderCourseRepository{
 		coursesPath: coursesPath,
 		cache:       make(map[string]*entities.LibraryCourse),
+		banks:       make(map[string]*entities.QuestionBank),
+		folders:     make(map[string]string),
 		cacheTTL:    10 * time.Second, // Reload courses every 10 seconds (dev mode)
+		subscribers: make(map[chan entities.CourseChange]struct{}),
 	}
 }
 
@@ -59,6 +74,9 @@ type courseJSON struct {
 		Primary   string   `json:"primary"`
 		Secondary []string `json:"secondary"`
 	} `json:"categories"`
+
+	// Optional overrides of entities.DefaultQuizConfig, e.g. {"testOutThreshold": 90}
+	QuizConfig json.RawMessage `json:"quiz_config"`
 }
 
 // lessonJSON represents the lesson.json file structure
@@ -81,66 +99,114 @@ type quizQuestionJSON struct {
 	Question       string   `json:"Question"`
 	Options        []string `json:"Options"`
 	CorrectIndex   int      `json:"CorrectIndex"`
-	CorrectAnswer  bool     `json:"CorrectAnswer"`   // For true-false
-	CorrectIndices []int    `json:"CorrectIndices"`  // For multiple-select
-	CorrectAnswers []string `json:"CorrectAnswers"`  // For fill-blank
+	CorrectAnswer  *bool    `json:"CorrectAnswer"`  // For true-false
+	CorrectIndices []int    `json:"CorrectIndices"` // For multiple-select
+	CorrectAnswers []string `json:"CorrectAnswers"` // For fill-blank
+	CaseSensitive  bool     `json:"CaseSensitive"`  // For fill-blank
 	Explanation    string   `json:"Explanation"`
 }
 
+// legacyQuestionTypes maps legacy quiz.json question types to extended question types
+var legacyQuestionTypes = map[string]entities.QuestionType{
+	"multiple-choice": entities.QuestionTypeMultipleChoice,
+	"true-false":      entities.QuestionTypeTrueFalse,
+	"multiple-select": entities.QuestionTypeMultipleSelect,
+	"fill-blank":      entities.QuestionTypeFillBlank,
+}
+
 // extendedQuizJSON represents the new quiz.json file structure (lowercase keys)
 type extendedQuizJSON struct {
 	Version      string                     `json:"version"`
 	SubchapterID string                     `json:"subchapterId"`
 	LessonID     string                     `json:"lessonId"`
 	Questions    []extendedQuizQuestionJSON `json:"questions"`
+	Exam         *entities.ExamConfig       `json:"exam,omitempty"`
+	BankRefs     []entities.QuestionBankRef `json:"bankRefs,omitempty"`
 }
 
 type extendedQuizQuestionJSON struct {
-	ID             string   `json:"id"`
-	Type           string   `json:"type"`
-	Difficulty     int      `json:"difficulty"`
-	Concept        string   `json:"concept"`
-	Question       string   `json:"question"`
-	Explanation    string   `json:"explanation"`
-	Options        []string `json:"options,omitempty"`
-	CorrectIndex   int      `json:"correctIndex,omitempty"`
-	CorrectAnswer  *bool    `json:"correctAnswer,omitempty"`
-	CorrectIndices []int    `json:"correctIndices,omitempty"`
-	MinSelections  int      `json:"minSelections,omitempty"`
-	MaxSelections  int      `json:"maxSelections,omitempty"`
-	CodeSnippet    string   `json:"codeSnippet,omitempty"`
-	Language       string   `json:"language,omitempty"`
-	LeftColumn     []string `json:"leftColumn,omitempty"`
-	RightColumn    []string `json:"rightColumn,omitempty"`
-	CorrectPairs   [][]int  `json:"correctPairs,omitempty"`
-	Items          []string `json:"items,omitempty"`
-	CorrectOrder   []int    `json:"correctOrder,omitempty"`
-}
-
-// loadCourses loads all courses from the folder structure
+	ID                string     `json:"id"`
+	Type              string     `json:"type"`
+	Difficulty        int        `json:"difficulty"`
+	Concept           string     `json:"concept"`
+	Question          string     `json:"question"`
+	Explanation       string     `json:"explanation"`
+	Options           []string   `json:"options,omitempty"`
+	CorrectIndex      int        `json:"correctIndex,omitempty"`
+	CorrectAnswer     *bool      `json:"correctAnswer,omitempty"`
+	CorrectIndices    []int      `json:"correctIndices,omitempty"`
+	MinSelections     int        `json:"minSelections,omitempty"`
+	MaxSelections     int        `json:"maxSelections,omitempty"`
+	CodeSnippet       string     `json:"codeSnippet,omitempty"`
+	Language          string     `json:"language,omitempty"`
+	LeftColumn        []string   `json:"leftColumn,omitempty"`
+	RightColumn       []string   `json:"rightColumn,omitempty"`
+	CorrectPairs      [][]int    `json:"correctPairs,omitempty"`
+	Items             []string   `json:"items,omitempty"`
+	CorrectOrder      []int      `json:"correctOrder,omitempty"`
+	AcceptedAnswers   []string   `json:"acceptedAnswers,omitempty"`
+	CaseSensitive     bool       `json:"caseSensitive,omitempty"`
+	AnswerPattern     string     `json:"answerPattern,omitempty"`
+	Synonyms          [][]string `json:"synonyms,omitempty"`
+	CorrectValue      *float64   `json:"correctValue,omitempty"`
+	AbsoluteTolerance float64    `json:"absoluteTolerance,omitempty"`
+	RelativeTolerance float64    `json:"relativeTolerance,omitempty"`
+	Unit              string     `json:"unit,omitempty"`
+	StarterFile       string     `json:"starterFile,omitempty"` // code_exercise files, relative to the quiz
+	TestFile          string     `json:"testFile,omitempty"`
+	Tags              []string   `json:"tags,omitempty"`
+}
+
+// loadCourses loads all courses from the folder structure unless the cache is still valid
 func (r *FolderCourseRepository) loadCourses(ctx context.Context) error {
-	r.cacheMu.Lock()
-	defer r.cacheMu.Unlock()
+	if r.cacheValid() {
+		return nil
+	}
 
-	// Check if cache is still valid
-	if time.Since(r.lastLoad) < r.cacheTTL && len(r.cache) > 0 {
+	r.loadMu.Lock()
+	defer r.loadMu.Unlock()
+
+	// Another request may have reloaded the courses while this one waited
+	if r.cacheValid() {
 		return nil
 	}
+	return r.reloadAll(ctx)
+}
+
+// cacheValid reports whether the cached courses can be used without reloading them
+// While watching, courses are reloaded as they change, so the cache does not expire
+func (r *FolderCourseRepository) cacheValid() bool {
+	r.cacheMu.RLock()
+	defer r.cacheMu.RUnlock()
+	return !r.lastLoad.IsZero() && (r.watching || time.Since(r.lastLoad) < r.cacheTTL)
+}
 
+// reloadAll loads every course and question bank from disk and swaps them into the cache
+// Callers must hold loadMu; requests keep reading the previous courses until the swap
+func (r *FolderCourseRepository) reloadAll(ctx context.Context) error {
 	entries, err := os.ReadDir(r.coursesPath)
 	if err != nil {
 		return fmt.Errorf("failed to read courses directory: %w", err)
 	}
 
+	// Banks are loaded first, so the courses' quizzes can pull questions from them
+	banks, err := loadQuestionBanks(filepath.Join(r.coursesPath, questionBanksDir))
+	if err != nil {
+		return err
+	}
+
 	newCache := make(map[string]*entities.LibraryCourse)
+	newFolders := make(map[string]string)
+	var duplicates []error
 
 	for _, entry := range entries {
 		if !entry.IsDir() {
 			continue
 		}
 
-		// Skip template folder
-		if entry.Name() == "COURSE-TEMPLATE" {
+		// Skip template and question bank folders, and hidden folders such as courses
+		// still being written
+		if entry.Name() == "COURSE-TEMPLATE" || entry.Name() == questionBanksDir || strings.HasPrefix(entry.Name(), ".") {
 			continue
 		}
 
@@ -148,15 +214,28 @@ func (r *FolderCourseRepository) loadCourses(ctx context.Context) error {
 		course, err := r.loadCourse(ctx, coursePath)
 		if err != nil {
 			// Log error but continue loading other courses
-			fmt.Printf("Warning: failed to load course %s: %v\n", entry.Name(), err)
+			slog.Warn("Failed to load course", "folder", entry.Name(), "error", err)
+			continue
+		}
+		addBankQuestions(course, banks, entry.Name())
+
+		// Folders are read in name order, so the first folder to claim an ID keeps it
+		if existing, ok := newFolders[course.ID]; ok {
+			duplicates = append(duplicates, fmt.Errorf("course ID %s of %s is already used by %s", course.ID, entry.Name(), filepath.Base(existing)))
 			continue
 		}
 
 		newCache[course.ID] = course
+		newFolders[course.ID] = coursePath
 	}
 
+	r.cacheMu.Lock()
 	r.cache = newCache
+	r.folders = newFolders
+	r.duplicates = duplicates
+	r.banks = banks
 	r.lastLoad = time.Now()
+	r.cacheMu.Unlock()
 	return nil
 }
 
@@ -174,10 +253,10 @@ func (r *FolderCourseRepository) loadCourse(ctx context.Context, coursePath stri
 		return nil, fmt.Errorf("failed to parse course.json: %w", err)
 	}
 
-	// Generate ID if not present
+	// Courses without a fixed ID get one derived from their folder name
 	courseID := cj.ID
 	if courseID == "" || courseID == "GENERATE-UUID" {
-		courseID = uuid.New().String()
+		courseID = courseIDForFolder(filepath.Base(coursePath))
 	}
 
 	// Load lessons
@@ -235,16 +314,28 @@ func (r *FolderCourseRepository) loadCourse(ctx context.Context, coursePath stri
 		updatedAt = time.Now()
 	}
 
+	// Parse quiz config overrides on top of the defaults
+	var quizConfig *entities.QuizConfig
+	if len(cj.QuizConfig) > 0 {
+		config, err := parseQuizConfig(cj.QuizConfig)
+		if err != nil {
+			slog.Warn("Ignoring invalid quiz_config", "folder", filepath.Base(coursePath), "error", err)
+		} else {
+			quizConfig = config
+		}
+	}
+
 	course := &entities.LibraryCourse{
 		ID:             courseID,
 		Title:          cj.Title,
 		Description:    cj.Description,
 		Lessons:        lessons,
 		Author:         authorName,
-		AuthorID:       "folder-author", // Folder-based courses don't have a real author ID
+		AuthorID:       entities.FolderAuthorID, // Folder-based courses don't have a real author ID
 		Tags:           tags,
 		Difficulty:     difficulty,
 		EstimatedHours: estimatedHours,
+		QuizConfig:     quizConfig,
 		CreatedAt:      updatedAt,
 		UpdatedAt:      updatedAt,
 	}
@@ -252,6 +343,41 @@ func (r *FolderCourseRepository) loadCourse(ctx context.Context, coursePath stri
 	return course, nil
 }
 
+// folderCourseNamespace is the UUID namespace of course IDs derived from folder names
+// Changing it would change those IDs and orphan everything recorded against them
+var folderCourseNamespace = uuid.MustParse("6f1c7a52-3d0e-4b8a-9c25-8e4f0d2b7a91")
+
+// courseIDForFolder returns the ID of a course whose course.json has none: a name-based
+// UUID of its folder name, so the course keeps its ID across reloads and restarts
+func courseIDForFolder(folderName string) string {
+	return uuid.NewSHA1(folderCourseNamespace, []byte(folderName)).String()
+}
+
+// CheckCourseIDs reports course folders that were skipped because another folder already
+// uses their course ID
+func (r *FolderCourseRepository) CheckCourseIDs(ctx context.Context) error {
+	if err := r.loadCourses(ctx); err != nil {
+		return err
+	}
+
+	r.cacheMu.RLock()
+	defer r.cacheMu.RUnlock()
+	return errors.Join(r.duplicates...)
+}
+
+// parseQuizConfig applies the course's quiz_config overrides to the default quiz config
+// Fields missing from course.json keep their default values
+func parseQuizConfig(data json.RawMessage) (*entities.QuizConfig, error) {
+	config := entities.DefaultQuizConfig()
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
 // loadLessons loads all lessons (chapters) from the lessons folder
 func (r *FolderCourseRepository) loadLessons(ctx context.Context, lessonsPath string) ([]entities.Lesson, error) {
 	entries, err := os.ReadDir(lessonsPath)
@@ -278,13 +404,14 @@ func (r *FolderCourseRepository) loadLessons(ctx context.Context, lessonsPath st
 		lessonPath := filepath.Join(lessonsPath, entry.Name())
 		lesson, err := r.loadLesson(ctx, lessonPath)
 		if err != nil {
-			fmt.Printf("Warning: failed to load lesson %s: %v\n", entry.Name(), err)
+			r.lessonLogger(lessonPath).Warn("Failed to load lesson", "error", err)
 			folderIndex++
 			continue
 		}
 
 		// Set the folder index for this lesson (position in alphabetically sorted list)
 		lesson.FolderIndex = folderIndex
+		lesson.Folder = entry.Name()
 		lessons = append(lessons, *lesson)
 		folderIndex++
 	}
@@ -313,17 +440,8 @@ func (r *FolderCourseRepository) loadLesson(ctx context.Context, lessonPath stri
 		content = []byte("")
 	}
 
-	// Load quiz if present - try extended format first, then legacy
-	var quiz *entities.Quiz
-	var extendedQuiz *entities.ExtendedQuiz
-	quizPath := filepath.Join(lessonPath, "quiz.json")
-
-	// Try loading as extended quiz first
-	extendedQuiz, err = r.loadExtendedQuiz(quizPath)
-	if err != nil {
-		// Fall back to legacy quiz format
-		quiz, _ = r.loadQuiz(quizPath)
-	}
+	// Load quiz if present, in either the extended or the legacy format
+	extendedQuiz := r.loadLessonQuiz(filepath.Join(lessonPath, "quiz.json"))
 
 	// Load sublessons
 	sublessonsPath := filepath.Join(lessonPath, "sublessons")
@@ -351,7 +469,6 @@ func (r *FolderCourseRepository) loadLesson(ctx context.Context, lessonPath stri
 		Content:      string(content),
 		Order:        order,
 		Sublessons:   sublessons,
-		Quiz:         quiz,
 		ExtendedQuiz: extendedQuiz,
 	}
 
@@ -395,25 +512,25 @@ func (r *FolderCourseRepository) loadSublessons(ctx context.Context, sublessonsP
 			content = []byte("")
 		}
 
-		// Load quiz if present - try extended format first, then legacy
-		var quiz *entities.Quiz
-		var extendedQuiz *entities.ExtendedQuiz
-		quizPath := filepath.Join(sublessonPath, "quiz.json")
+		// Load quiz if present, in either the extended or the legacy format
+		extendedQuiz := r.loadLessonQuiz(filepath.Join(sublessonPath, "quiz.json"))
 
-		// Try loading as extended quiz first
-		extendedQuiz, err = r.loadExtendedQuiz(quizPath)
-		if err != nil {
-			// Fall back to legacy quiz format
-			quiz, _ = r.loadQuiz(quizPath)
+		// Sublessons are titled after their folder unless a lesson.json gives a title
+		title := entry.Name()
+		if data, err := os.ReadFile(filepath.Join(sublessonPath, "lesson.json")); err == nil {
+			var lj lessonJSON
+			if json.Unmarshal(data, &lj) == nil && lj.Title != "" {
+				title = lj.Title
+			}
 		}
 
 		sublesson := entities.Lesson{
-			Title:        entry.Name(),
+			Title:        title,
 			Content:      string(content),
 			Order:        folderIndex,
 			FolderIndex:  folderIndex,
+			Folder:       entry.Name(),
 			Sublessons:   nil, // Sublessons don't have nested sublessons
-			Quiz:         quiz,
 			ExtendedQuiz: extendedQuiz,
 		}
 
@@ -424,74 +541,188 @@ func (r *FolderCourseRepository) loadSublessons(ctx context.Context, sublessonsP
 	return sublessons, nil
 }
 
-// loadQuiz loads a quiz from a quiz.json file
-func (r *FolderCourseRepository) loadQuiz(quizPath string) (*entities.Quiz, error) {
+// loadLessonQuiz loads a lesson's quiz.json, if it has one
+// A quiz that cannot be parsed is left out and parts of it that cannot be used are
+// skipped, with a warning; courses lint reports the same problems with their positions
+func (r *FolderCourseRepository) loadLessonQuiz(quizPath string) *entities.ExtendedQuiz {
+	quiz, err := r.loadQuiz(quizPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		r.lessonLogger(filepath.Dir(quizPath)).Warn("Ignoring quiz that cannot be loaded", "error", err)
+	}
+	return quiz
+}
+
+// loadQuiz loads a quiz.json file in either format
+func (r *FolderCourseRepository) loadQuiz(quizPath string) (*entities.ExtendedQuiz, error) {
 	data, err := os.ReadFile(quizPath)
 	if err != nil {
 		return nil, err
 	}
 
+	quiz, problems, err := parseQuiz(filepath.Dir(quizPath), data)
+	if err != nil {
+		return nil, err
+	}
+	warnProblems(r.lessonLogger(filepath.Dir(quizPath)), problems)
+	return quiz, nil
+}
+
+// quizProblem is a part of a quiz.json or bank.json that cannot be used: the loader
+// skips it with a warning and the linter reports it
+type quizProblem struct {
+	key   string // Top-level key of the value at fault, such as "questions" or "exam"
+	index int    // Element of that value at fault, or -1 for the whole value
+	err   error
+}
+
+func warnProblems(logger *slog.Logger, problems []quizProblem) {
+	for _, problem := range problems {
+		logger.Warn("Skipping part of a quiz", "error", problem.err)
+	}
+}
+
+// lessonLogger returns a logger for warnings about a lesson, naming its course folder and
+// its path within that folder
+func (r *FolderCourseRepository) lessonLogger(lessonFolder string) *slog.Logger {
+	rel, err := filepath.Rel(r.coursesPath, lessonFolder)
+	if err != nil {
+		return slog.With("lesson", lessonFolder)
+	}
+	courseFolder, lesson, _ := strings.Cut(filepath.ToSlash(rel), "/")
+	return slog.With("folder", courseFolder, "lesson", lesson)
+}
+
+// parseQuiz parses quiz.json data in either format, returning the quiz without the parts
+// that cannot be used and the problems with those parts; dir is the quiz's folder
+// Legacy files are recognised by their capitalized "Questions" key
+func parseQuiz(dir string, data []byte) (*entities.ExtendedQuiz, []quizProblem, error) {
+	legacy, err := isLegacyQuiz(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if legacy {
+		return parseLegacyQuiz(data)
+	}
+	return parseExtendedQuiz(dir, data)
+}
+
+// isLegacyQuiz reports whether quiz.json data is in the legacy format
+func isLegacyQuiz(data []byte) (bool, error) {
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return false, fmt.Errorf("failed to parse quiz.json: %w", err)
+	}
+	_, ok := keys["Questions"]
+	return ok, nil
+}
+
+// parseLegacyQuiz converts a legacy quiz.json (capitalized keys) to an extended quiz,
+// keeping each question's type and answer key
+// Questions of unknown types are skipped rather than guessed at
+func parseLegacyQuiz(data []byte) (*entities.ExtendedQuiz, []quizProblem, error) {
 	var qj quizJSON
 	if err := json.Unmarshal(data, &qj); err != nil {
-		return nil, fmt.Errorf("failed to parse quiz.json: %w", err)
+		return nil, nil, fmt.Errorf("failed to parse quiz.json: %w", err)
 	}
 
-	var questions []entities.QuizQuestion
-	for _, q := range qj.Questions {
-		question := entities.QuizQuestion{
-			ID:           q.ID,
-			Question:     q.Question,
-			Options:      q.Options,
-			CorrectIndex: q.CorrectIndex,
-			Explanation:  q.Explanation,
+	var questions []entities.ExtendedQuizQuestion
+	var problems []quizProblem
+	seen := make(map[string]bool)
+	for i, q := range qj.Questions {
+		question, err := convertLegacyQuestion(q)
+		if err == nil {
+			err = checkQuestion(&question, seen)
+		} else {
+			err = fmt.Errorf("question %s: %w", q.ID, err)
+		}
+		if err != nil {
+			problems = append(problems, quizProblem{key: "Questions", index: i, err: err})
+			continue
 		}
 		questions = append(questions, question)
 	}
 
-	return &entities.Quiz{Questions: questions}, nil
+	return &entities.ExtendedQuiz{Questions: questions}, problems, nil
 }
 
-// loadExtendedQuiz loads an extended quiz from a quiz.json file (new format with lowercase keys)
-func (r *FolderCourseRepository) loadExtendedQuiz(quizPath string) (*entities.ExtendedQuiz, error) {
-	data, err := os.ReadFile(quizPath)
+// convertLegacyQuestion converts a legacy question, keeping its ID, type and answer key
+// Legacy questions carry no difficulty, so they are given their type's default
+func convertLegacyQuestion(q quizQuestionJSON) (entities.ExtendedQuizQuestion, error) {
+	questionType, err := legacyQuestionType(q)
 	if err != nil {
-		return nil, err
+		return entities.ExtendedQuizQuestion{}, err
+	}
+
+	question := entities.ExtendedQuizQuestion{
+		ID:          q.ID,
+		Type:        questionType,
+		Difficulty:  entities.DefaultDifficulty(questionType),
+		Question:    q.Question,
+		Explanation: q.Explanation,
+	}
+	switch questionType {
+	case entities.QuestionTypeMultipleChoice:
+		question.Options = q.Options
+		question.CorrectIndex = q.CorrectIndex
+	case entities.QuestionTypeTrueFalse:
+		question.CorrectAnswer = q.CorrectAnswer
+	case entities.QuestionTypeMultipleSelect:
+		question.Options = q.Options
+		question.CorrectIndices = q.CorrectIndices
+	case entities.QuestionTypeFillBlank:
+		question.AcceptedAnswers = q.CorrectAnswers
+		question.CaseSensitive = q.CaseSensitive
+	}
+	return question, nil
+}
+
+// legacyQuestionType returns the extended type of a legacy question
+// Questions without a type predate the field; their type is inferred from the answer
+// key they carry, and those with only options are multiple choice
+func legacyQuestionType(q quizQuestionJSON) (entities.QuestionType, error) {
+	if q.Type != "" {
+		questionType, ok := legacyQuestionTypes[q.Type]
+		if !ok {
+			return "", fmt.Errorf("unknown type %q", q.Type)
+		}
+		return questionType, nil
 	}
 
+	switch {
+	case len(q.CorrectAnswers) > 0:
+		return entities.QuestionTypeFillBlank, nil
+	case len(q.CorrectIndices) > 0:
+		return entities.QuestionTypeMultipleSelect, nil
+	case q.CorrectAnswer != nil:
+		return entities.QuestionTypeTrueFalse, nil
+	case len(q.Options) > 0:
+		return entities.QuestionTypeMultipleChoice, nil
+	default:
+		return "", fmt.Errorf("type cannot be inferred: no options or answers")
+	}
+}
+
+// parseExtendedQuiz parses an extended quiz.json (new format with lowercase keys)
+// Invalid exam settings are ignored, leaving the quiz untimed
+func parseExtendedQuiz(dir string, data []byte) (*entities.ExtendedQuiz, []quizProblem, error) {
 	var eqj extendedQuizJSON
 	if err := json.Unmarshal(data, &eqj); err != nil {
-		return nil, fmt.Errorf("failed to parse quiz.json: %w", err)
+		return nil, nil, fmt.Errorf("failed to parse quiz.json: %w", err)
 	}
 
 	// Check if this is the new format (has version or lowercase questions)
-	if eqj.Version == "" && len(eqj.Questions) == 0 {
-		return nil, fmt.Errorf("not an extended quiz format")
+	if eqj.Version == "" && len(eqj.Questions) == 0 && len(eqj.BankRefs) == 0 {
+		return nil, nil, fmt.Errorf("quiz has no questions")
 	}
 
-	var questions []entities.ExtendedQuizQuestion
-	for _, q := range eqj.Questions {
-		question := entities.ExtendedQuizQuestion{
-			ID:             q.ID,
-			Type:           entities.QuestionType(q.Type),
-			Difficulty:     q.Difficulty,
-			Concept:        q.Concept,
-			Question:       q.Question,
-			Explanation:    q.Explanation,
-			Options:        q.Options,
-			CorrectIndex:   q.CorrectIndex,
-			CorrectAnswer:  q.CorrectAnswer,
-			CorrectIndices: q.CorrectIndices,
-			MinSelections:  q.MinSelections,
-			MaxSelections:  q.MaxSelections,
-			CodeSnippet:    q.CodeSnippet,
-			Language:       q.Language,
-			LeftColumn:     q.LeftColumn,
-			RightColumn:    q.RightColumn,
-			CorrectPairs:   q.CorrectPairs,
-			Items:          q.Items,
-			CorrectOrder:   q.CorrectOrder,
+	questions, problems := parseQuestions(dir, eqj.Questions)
+
+	exam := eqj.Exam
+	if exam != nil {
+		if err := exam.Validate(); err != nil {
+			problems = append(problems, quizProblem{key: "exam", index: -1, err: fmt.Errorf("invalid exam settings: %w", err)})
+			exam = nil
 		}
-		questions = append(questions, question)
 	}
 
 	return &entities.ExtendedQuiz{
@@ -499,16 +730,158 @@ func (r *FolderCourseRepository) loadExtendedQuiz(quizPath string) (*entities.Ex
 		SubchapterID: eqj.SubchapterID,
 		LessonID:     eqj.LessonID,
 		Questions:    questions,
-	}, nil
+		Exam:         exam,
+		BankRefs:     eqj.BankRefs,
+	}, problems, nil
 }
 
-// ---- LibraryCourseRepository Interface Implementation ----
+// parseQuestions converts the questions of a quiz.json or bank.json, reading code
+// exercise files from dir, and returns those that can be used
+func parseQuestions(dir string, qs []extendedQuizQuestionJSON) ([]entities.ExtendedQuizQuestion, []quizProblem) {
+	var questions []entities.ExtendedQuizQuestion
+	var problems []quizProblem
+	seen := make(map[string]bool)
+	for i, q := range qs {
+		question := fromQuestionJSON(q)
+		var err error
+		if question.Type == entities.QuestionTypeCodeExercise {
+			if err = loadCodeExerciseFiles(dir, q, &question); err != nil {
+				err = fmt.Errorf("question %s: %w", q.ID, err)
+			}
+		}
+		if err == nil {
+			err = checkQuestion(&question, seen)
+		}
+		if err != nil {
+			problems = append(problems, quizProblem{key: "questions", index: i, err: err})
+			continue
+		}
+		questions = append(questions, question)
+	}
+	return questions, problems
+}
+
+// checkQuestion checks a question as the authoring mutations do, and that no earlier
+// question in the same file has its ID
+func checkQuestion(question *entities.ExtendedQuizQuestion, seen map[string]bool) error {
+	if err := question.Validate(); err != nil {
+		return err
+	}
+	if seen[question.ID] {
+		return fmt.Errorf("%w %s: id is used by another question", entities.ErrInvalidQuestion, question.ID)
+	}
+	seen[question.ID] = true
+	return nil
+}
+
+// loadCodeExerciseFiles reads a code exercise's starter and hidden test files, which must
+// sit inside the quiz's folder; the test file is required, the starter file optional
+func loadCodeExerciseFiles(dir string, q extendedQuizQuestionJSON, question *entities.ExtendedQuizQuestion) error {
+	if q.TestFile == "" {
+		return fmt.Errorf("code exercise has no testFile")
+	}
 
-// Create is not supported for folder-based repository (read-only)
-func (r *FolderCourseRepository) Create(ctx context.Context, course *entities.LibraryCourse) (*entities.LibraryCourse, error) {
-	return nil, fmt.Errorf("folder-based repository is read-only")
+	read := func(name string) (string, error) {
+		if !filepath.IsLocal(name) {
+			return "", fmt.Errorf("%s is outside the lesson folder", name)
+		}
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return "", err
+		}
+		return string(content), nil
+	}
+
+	testCode, err := read(q.TestFile)
+	if err != nil {
+		return err
+	}
+	question.TestCode = testCode
+
+	if q.StarterFile != "" {
+		starterCode, err := read(q.StarterFile)
+		if err != nil {
+			return err
+		}
+		question.StarterCode = starterCode
+	}
+	return nil
 }
 
+// fromQuestionJSON converts a quiz.json question; code exercise files are read by the caller
+func fromQuestionJSON(q extendedQuizQuestionJSON) entities.ExtendedQuizQuestion {
+	return entities.ExtendedQuizQuestion{
+		ID:             q.ID,
+		Type:           entities.QuestionType(q.Type),
+		Difficulty:     q.Difficulty,
+		Concept:        q.Concept,
+		Question:       q.Question,
+		Explanation:    q.Explanation,
+		Options:        q.Options,
+		CorrectIndex:   q.CorrectIndex,
+		CorrectAnswer:  q.CorrectAnswer,
+		CorrectIndices: q.CorrectIndices,
+		MinSelections:  q.MinSelections,
+		MaxSelections:  q.MaxSelections,
+		CodeSnippet:    q.CodeSnippet,
+		Language:       q.Language,
+		LeftColumn:     q.LeftColumn,
+		RightColumn:    q.RightColumn,
+		CorrectPairs:   q.CorrectPairs,
+		Items:          q.Items,
+		CorrectOrder:   q.CorrectOrder,
+
+		AcceptedAnswers: q.AcceptedAnswers,
+		CaseSensitive:   q.CaseSensitive,
+		AnswerPattern:   q.AnswerPattern,
+		Synonyms:        q.Synonyms,
+
+		CorrectValue:      q.CorrectValue,
+		AbsoluteTolerance: q.AbsoluteTolerance,
+		RelativeTolerance: q.RelativeTolerance,
+		Unit:              q.Unit,
+
+		Tags: q.Tags,
+	}
+}
+
+// toQuestionJSON converts a question to its quiz.json form; code exercise files are
+// filled in by the caller
+func toQuestionJSON(q entities.ExtendedQuizQuestion) extendedQuizQuestionJSON {
+	return extendedQuizQuestionJSON{
+		ID:                q.ID,
+		Type:              string(q.Type),
+		Difficulty:        q.Difficulty,
+		Concept:           q.Concept,
+		Question:          q.Question,
+		Explanation:       q.Explanation,
+		Options:           q.Options,
+		CorrectIndex:      q.CorrectIndex,
+		CorrectAnswer:     q.CorrectAnswer,
+		CorrectIndices:    q.CorrectIndices,
+		MinSelections:     q.MinSelections,
+		MaxSelections:     q.MaxSelections,
+		CodeSnippet:       q.CodeSnippet,
+		Language:          q.Language,
+		LeftColumn:        q.LeftColumn,
+		RightColumn:       q.RightColumn,
+		CorrectPairs:      q.CorrectPairs,
+		Items:             q.Items,
+		CorrectOrder:      q.CorrectOrder,
+		AcceptedAnswers:   q.AcceptedAnswers,
+		CaseSensitive:     q.CaseSensitive,
+		AnswerPattern:     q.AnswerPattern,
+		Synonyms:          q.Synonyms,
+		CorrectValue:      q.CorrectValue,
+		AbsoluteTolerance: q.AbsoluteTolerance,
+		RelativeTolerance: q.RelativeTolerance,
+		Unit:              q.Unit,
+		Tags:              q.Tags,
+	}
+}
+
+// ---- LibraryCourseRepository Interface Implementation ----
+
 // GetByID retrieves a library course by ID
 func (r *FolderCourseRepository) GetByID(ctx context.Context, id string) (*entities.LibraryCourse, error) {
 	if err := r.loadCourses(ctx); err != nil {
@@ -520,22 +893,12 @@ func (r *FolderCourseRepository) GetByID(ctx context.Context, id string) (*entit
 
 	course, ok := r.cache[id]
 	if !ok {
-		return nil, fmt.Errorf("course not found: %s", id)
+		return nil, fmt.Errorf("%w: %s", entities.ErrCourseNotFound, id)
 	}
 
 	return course, nil
 }
 
-// Update is not supported for folder-based repository (read-only)
-func (r *FolderCourseRepository) Update(ctx context.Context, course *entities.LibraryCourse) (*entities.LibraryCourse, error) {
-	return nil, fmt.Errorf("folder-based repository is read-only")
-}
-
-// Delete is not supported for folder-based repository (read-only)
-func (r *FolderCourseRepository) Delete(ctx context.Context, id string) error {
-	return fmt.Errorf("folder-based repository is read-only")
-}
-
 // List retrieves all library courses with pagination
 func (r *FolderCourseRepository) List(ctx context.Context, limit, offset int) ([]*entities.LibraryCourse, int, error) {
 	if err := r.loadCourses(ctx); err != nil {
@@ -750,191 +1113,150 @@ func (r *FolderCourseRepository) GetAllTags(ctx context.Context) ([]string, erro
 
 // RefreshCache forces a reload of courses from disk
 func (r *FolderCourseRepository) RefreshCache(ctx context.Context) error {
-	r.cacheMu.Lock()
-	r.lastLoad = time.Time{} // Reset last load to force reload
-	r.cacheMu.Unlock()
+	r.loadMu.Lock()
+	defer r.loadMu.Unlock()
 
-	return r.loadCourses(ctx)
+	return r.reloadAll(ctx)
 }
 
-// UpdateLessonContent updates the content.md file for a specific lesson
-// It creates a .bak backup before saving
-func (r *FolderCourseRepository) UpdateLessonContent(ctx context.Context, courseID string, lessonPath []int, newContent string) error {
-	// Find the course folder by ID
+// SaveLessonQuiz writes a lesson's extended quiz to its quiz.json
+func (r *FolderCourseRepository) SaveLessonQuiz(ctx context.Context, courseID string, lessonPath []int, quiz *entities.ExtendedQuiz) error {
 	course, err := r.GetByID(ctx, courseID)
 	if err != nil {
-		return fmt.Errorf("course not found: %w", err)
+		return err
 	}
 
-	// Find the course folder path by scanning for matching course.json
-	entries, err := os.ReadDir(r.coursesPath)
-	if err != nil {
-		return fmt.Errorf("failed to read courses directory: %w", err)
-	}
-
-	var courseFolderPath string
-	for _, entry := range entries {
-		if !entry.IsDir() || entry.Name() == "COURSE-TEMPLATE" {
-			continue
-		}
-
-		testPath := filepath.Join(r.coursesPath, entry.Name())
-		courseJSONPath := filepath.Join(testPath, "course.json")
-		data, err := os.ReadFile(courseJSONPath)
-		if err != nil {
-			continue
-		}
-
-		var cj courseJSON
-		if err := json.Unmarshal(data, &cj); err != nil {
-			continue
-		}
-
-		// Check if this course matches
-		testID := cj.ID
-		if testID == "" || testID == "GENERATE-UUID" {
-			// For generated UUIDs, match by title
-			if cj.Title == course.Title {
-				courseFolderPath = testPath
-				break
-			}
-		} else if testID == courseID {
-			courseFolderPath = testPath
-			break
+	// Lesson paths count loaded lessons; the folders are found by their folder index
+	folderPath := make([]int, len(lessonPath))
+	lessons := course.Lessons
+	for i, index := range lessonPath {
+		if index < 0 || index >= len(lessons) {
+			return entities.ErrInvalidLessonIndex
 		}
+		folderPath[i] = lessons[index].FolderIndex
+		lessons = lessons[index].Sublessons
 	}
 
-	if courseFolderPath == "" {
-		return fmt.Errorf("course folder not found for ID: %s", courseID)
-	}
-
-	// Build the path to the content.md file based on lessonPath
-	// lessonPath is like [0] for first chapter, [0, 2] for first chapter's third sublesson
-	contentPath := filepath.Join(courseFolderPath, "lessons")
-
-	// Get sorted lesson folders
-	lessonEntries, err := os.ReadDir(contentPath)
+	lessonFolder, err := r.lessonFolderPath(ctx, courseID, folderPath)
 	if err != nil {
-		return fmt.Errorf("failed to read lessons directory: %w", err)
+		return err
 	}
+	quizPath := filepath.Join(lessonFolder, "quiz.json")
+	existing, _ := os.ReadFile(quizPath)
 
-	var lessonFolders []string
-	for _, e := range lessonEntries {
-		if e.IsDir() {
-			lessonFolders = append(lessonFolders, e.Name())
-		}
+	data, err := encodeQuiz(lessonFolder, existing, quiz)
+	if err != nil {
+		return err
 	}
-	sort.Strings(lessonFolders)
 
-	if len(lessonPath) == 0 {
-		return fmt.Errorf("empty lesson path")
+	if err := writeFileAtomic(quizPath, data); err != nil {
+		return fmt.Errorf("failed to write quiz: %w", err)
 	}
 
-	// Navigate to the lesson folder
-	if lessonPath[0] >= len(lessonFolders) {
-		return fmt.Errorf("lesson index %d out of range", lessonPath[0])
-	}
-	contentPath = filepath.Join(contentPath, lessonFolders[lessonPath[0]])
+	// Reload the course so the next request gets the new quiz
+	r.reloadCourseByID(ctx, courseID)
 
-	// If there are more path segments, navigate to sublessons
-	for i := 1; i < len(lessonPath); i++ {
-		sublessonsPath := filepath.Join(contentPath, "sublessons")
-		sublessonEntries, err := os.ReadDir(sublessonsPath)
-		if err != nil {
-			return fmt.Errorf("failed to read sublessons directory: %w", err)
-		}
+	return nil
+}
 
-		var sublessonFolders []string
-		for _, e := range sublessonEntries {
-			if e.IsDir() {
-				sublessonFolders = append(sublessonFolders, e.Name())
+// encodeQuiz returns the quiz.json for a lesson's quiz, given the lesson's current quiz.json
+// if any. Code exercise sources are written next to it, to the files the existing quiz.json
+// names or else under testdata/, which the Go tooling ignores
+func encodeQuiz(lessonFolder string, existing []byte, quiz *entities.ExtendedQuiz) ([]byte, error) {
+	// Keep the file names of existing code exercises
+	existingFiles := make(map[string]extendedQuizQuestionJSON)
+	if existing != nil {
+		var eqj extendedQuizJSON
+		if json.Unmarshal(existing, &eqj) == nil {
+			for _, q := range eqj.Questions {
+				existingFiles[q.ID] = q
 			}
 		}
-		sort.Strings(sublessonFolders)
-
-		if lessonPath[i] >= len(sublessonFolders) {
-			return fmt.Errorf("sublesson index %d out of range", lessonPath[i])
-		}
-		contentPath = filepath.Join(sublessonsPath, sublessonFolders[lessonPath[i]])
 	}
 
-	contentFilePath := filepath.Join(contentPath, "content.md")
-
-	// Create backup
-	existingContent, err := os.ReadFile(contentFilePath)
-	if err == nil {
-		backupPath := contentFilePath + ".bak"
-		if err := os.WriteFile(backupPath, existingContent, 0644); err != nil {
-			return fmt.Errorf("failed to create backup: %w", err)
+	eqj := extendedQuizJSON{
+		Version:      quiz.Version,
+		SubchapterID: quiz.SubchapterID,
+		LessonID:     quiz.LessonID,
+		Questions:    make([]extendedQuizQuestionJSON, 0, len(quiz.Questions)),
+		Exam:         quiz.Exam,
+		BankRefs:     quiz.BankRefs,
+	}
+	// Bank questions stay in their bank; the quiz keeps only its references to them
+	for _, q := range quiz.OwnQuestions() {
+		qj := toQuestionJSON(q)
+		if q.Type == entities.QuestionTypeCodeExercise {
+			if err := saveCodeExerciseFiles(lessonFolder, existingFiles[q.ID], q, &qj); err != nil {
+				return nil, err
+			}
 		}
+		eqj.Questions = append(eqj.Questions, qj)
 	}
 
-	// Write new content
-	if err := os.WriteFile(contentFilePath, []byte(newContent), 0644); err != nil {
-		return fmt.Errorf("failed to write content: %w", err)
+	data, err := json.MarshalIndent(eqj, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode quiz: %w", err)
 	}
-
-	// Invalidate cache so next request gets fresh content
-	r.cacheMu.Lock()
-	r.lastLoad = time.Time{}
-	r.cache = nil // Clear cache to force full reload
-	r.cacheMu.Unlock()
-
-	return nil
+	return append(data, '\n'), nil
 }
 
-// GetLessonContentPath returns the file path for a lesson's content.md
-func (r *FolderCourseRepository) GetLessonContentPath(ctx context.Context, courseID string, lessonPath []int) (string, error) {
-	// This is a helper to get the path without modifying anything
-	course, err := r.GetByID(ctx, courseID)
-	if err != nil {
-		return "", fmt.Errorf("course not found: %w", err)
+// saveCodeExerciseFiles writes a code exercise's starter and test code and points the
+// quiz.json entry at them
+func saveCodeExerciseFiles(lessonFolder string, existing extendedQuizQuestionJSON, q entities.ExtendedQuizQuestion, qj *extendedQuizQuestionJSON) error {
+	qj.StarterFile = existing.StarterFile
+	if qj.StarterFile == "" {
+		qj.StarterFile = filepath.ToSlash(filepath.Join("testdata", q.ID, "starter.go"))
+	}
+	qj.TestFile = existing.TestFile
+	if qj.TestFile == "" {
+		qj.TestFile = filepath.ToSlash(filepath.Join("testdata", q.ID, "exercise_test.go"))
 	}
 
-	entries, err := os.ReadDir(r.coursesPath)
-	if err != nil {
-		return "", fmt.Errorf("failed to read courses directory: %w", err)
+	files := map[string]string{qj.TestFile: q.TestCode}
+	if q.StarterCode != "" {
+		files[qj.StarterFile] = q.StarterCode
+	} else {
+		qj.StarterFile = ""
 	}
 
-	var courseFolderPath string
-	for _, entry := range entries {
-		if !entry.IsDir() || entry.Name() == "COURSE-TEMPLATE" {
-			continue
+	for name, content := range files {
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("%s is outside the lesson folder", name)
 		}
-
-		testPath := filepath.Join(r.coursesPath, entry.Name())
-		courseJSONPath := filepath.Join(testPath, "course.json")
-		data, err := os.ReadFile(courseJSONPath)
-		if err != nil {
-			continue
+		path := filepath.Join(lessonFolder, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(name), err)
 		}
-
-		var cj courseJSON
-		if err := json.Unmarshal(data, &cj); err != nil {
-			continue
+		if err := writeIfChanged(path, []byte(content)); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		testID := cj.ID
-		if testID == "" || testID == "GENERATE-UUID" {
-			if cj.Title == course.Title {
-				courseFolderPath = testPath
-				break
-			}
-		} else if testID == courseID {
-			courseFolderPath = testPath
-			break
-		}
+// lessonFolderPath returns the folder holding the lesson at lessonPath
+func (r *FolderCourseRepository) lessonFolderPath(ctx context.Context, courseID string, lessonPath []int) (string, error) {
+	// Find the course folder by ID
+	course, err := r.GetByID(ctx, courseID)
+	if err != nil {
+		return "", fmt.Errorf("course not found: %w", err)
 	}
 
-	if courseFolderPath == "" {
-		return "", fmt.Errorf("course folder not found")
+	r.cacheMu.RLock()
+	courseFolderPath, ok := r.folders[course.ID]
+	r.cacheMu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("course folder not found for ID: %s", courseID)
 	}
 
+	// Build the path to the content.md file based on lessonPath
+	// lessonPath is like [0] for first chapter, [0, 2] for first chapter's third sublesson
 	contentPath := filepath.Join(courseFolderPath, "lessons")
 
+	// Get sorted lesson folders
 	lessonEntries, err := os.ReadDir(contentPath)
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("failed to read lessons directory: %w", err)
 	}
 
 	var lessonFolders []string
@@ -945,17 +1267,22 @@ func (r *FolderCourseRepository) GetLessonContentPath(ctx context.Context, cours
 	}
 	sort.Strings(lessonFolders)
 
-	if len(lessonPath) == 0 || lessonPath[0] >= len(lessonFolders) {
-		return "", fmt.Errorf("invalid lesson path")
+	if len(lessonPath) == 0 {
+		return "", fmt.Errorf("empty lesson path")
 	}
 
+	// Navigate to the lesson folder
+	if lessonPath[0] >= len(lessonFolders) {
+		return "", fmt.Errorf("lesson index %d out of range", lessonPath[0])
+	}
 	contentPath = filepath.Join(contentPath, lessonFolders[lessonPath[0]])
 
+	// If there are more path segments, navigate to sublessons
 	for i := 1; i < len(lessonPath); i++ {
 		sublessonsPath := filepath.Join(contentPath, "sublessons")
 		sublessonEntries, err := os.ReadDir(sublessonsPath)
 		if err != nil {
-			return "", err
+			return "", fmt.Errorf("failed to read sublessons directory: %w", err)
 		}
 
 		var sublessonFolders []string
@@ -967,10 +1294,19 @@ func (r *FolderCourseRepository) GetLessonContentPath(ctx context.Context, cours
 		sort.Strings(sublessonFolders)
 
 		if lessonPath[i] >= len(sublessonFolders) {
-			return "", fmt.Errorf("invalid sublesson path")
+			return "", fmt.Errorf("sublesson index %d out of range", lessonPath[i])
 		}
 		contentPath = filepath.Join(sublessonsPath, sublessonFolders[lessonPath[i]])
 	}
 
+	return contentPath, nil
+}
+
+// GetLessonContentPath returns the file path for a lesson's content.md
+func (r *FolderCourseRepository) GetLessonContentPath(ctx context.Context, courseID string, lessonPath []int) (string, error) {
+	contentPath, err := r.lessonFolderPath(ctx, courseID, lessonPath)
+	if err != nil {
+		return "", err
+	}
 	return filepath.Join(contentPath, "content.md"), nil
 }
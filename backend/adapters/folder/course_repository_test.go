@@ -0,0 +1,327 @@
+package folder
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/project/backend/domain/entities"
+)
+
+// writeCourseFiles creates files under root, by slash-separated path relative to it
+func writeCourseFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create folder for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+}
+
+// loadTestCourse loads the only course in root
+func loadTestCourse(t *testing.T, root string) *entities.LibraryCourse {
+	t.Helper()
+
+	courses, total, err := NewFolderCourseRepository(root).List(context.Background(), 10, 0)
+	if err != nil {
+		t.Fatalf("failed to list courses: %v", err)
+	}
+	if total != 1 {
+		t.Fatalf("expected 1 course, got %d", total)
+	}
+	return courses[0]
+}
+
+func TestFolderCourseRepository_LoadCourse(t *testing.T) {
+	root := t.TempDir()
+	writeCourseFiles(t, root, map[string]string{
+		"go-basics/course.json": `{
+			"id": "go-basics",
+			"title": "Go Basics",
+			"author": {"name": "Jane Doe"},
+			"metadata": {"difficulty": "beginner", "estimated_hours": 4},
+			"tags": ["go"],
+			"categories": {"primary": "programming"},
+			"quiz_config": {"testOutThreshold": 90}
+		}`,
+		"go-basics/lessons/00-intro/lesson.json":                     `{"title": "Introduction", "order": 1}`,
+		"go-basics/lessons/00-intro/content.md":                      "# Welcome",
+		"go-basics/lessons/00-intro/sublessons/00-setup/content.md":  "Install Go",
+		"go-basics/lessons/00-intro/sublessons/01-hello/lesson.json": `{"title": "Hello, World"}`,
+		"go-basics/lessons/01-types/content.md":                      "Types",
+		"COURSE-TEMPLATE/course.json":                                `{"title": "Template"}`,
+	})
+
+	course := loadTestCourse(t, root)
+	if course.ID != "go-basics" || course.Title != "Go Basics" || course.Author != "Jane Doe" {
+		t.Errorf("unexpected course fields: %+v", course)
+	}
+	if course.Difficulty != entities.DifficultyBeginner || course.EstimatedHours != 4 {
+		t.Errorf("expected beginner, 4 hours, got %s, %d", course.Difficulty, course.EstimatedHours)
+	}
+	if len(course.Tags) != 2 || course.Tags[1] != "programming" {
+		t.Errorf("expected the primary category among the tags, got %v", course.Tags)
+	}
+	if course.QuizConfig == nil || course.QuizConfig.TestOutThreshold != 90 {
+		t.Errorf("expected the quiz config override, got %+v", course.QuizConfig)
+	}
+
+	if len(course.Lessons) != 2 {
+		t.Fatalf("expected 2 lessons, got %d", len(course.Lessons))
+	}
+	intro := course.Lessons[0]
+	if intro.Title != "Introduction" || intro.Content != "# Welcome" || intro.Order != 1 {
+		t.Errorf("unexpected first lesson: %+v", intro)
+	}
+	if len(intro.Sublessons) != 2 || intro.Sublessons[0].Title != "00-setup" || intro.Sublessons[1].Title != "Hello, World" {
+		t.Errorf("expected sublessons titled by folder and by lesson.json, got %+v", intro.Sublessons)
+	}
+	if types := course.Lessons[1]; types.Title != "01-types" || types.Order != 1 || types.FolderIndex != 1 {
+		t.Errorf("expected the order taken from the folder name, got %+v", types)
+	}
+}
+
+func TestFolderCourseRepository_LoadLegacyQuiz(t *testing.T) {
+	root := t.TempDir()
+	writeCourseFiles(t, root, map[string]string{
+		"go-basics/course.json":                 `{"id": "go-basics", "title": "Go Basics"}`,
+		"go-basics/lessons/00-intro/content.md": "Intro",
+		"go-basics/lessons/00-intro/quiz.json": `{"Questions": [
+			{"ID": "mc", "Type": "multiple-choice", "Question": "Pick", "Options": ["a", "b"], "CorrectIndex": 1},
+			{"ID": "tf", "Question": "True?", "CorrectAnswer": false},
+			{"ID": "ms", "Question": "Pick two", "Options": ["a", "b", "c"], "CorrectIndices": [0, 2]},
+			{"ID": "fb", "Type": "fill-blank", "Question": "Go is ___", "CorrectAnswers": ["fun"], "CaseSensitive": true},
+			{"ID": "bad", "Type": "essay", "Question": "Discuss"}
+		]}`,
+	})
+
+	quiz := loadTestCourse(t, root).Lessons[0].ExtendedQuiz
+	if quiz == nil {
+		t.Fatal("expected the legacy quiz to be loaded")
+	}
+
+	want := []struct {
+		id           string
+		questionType entities.QuestionType
+	}{
+		{"mc", entities.QuestionTypeMultipleChoice},
+		{"tf", entities.QuestionTypeTrueFalse},
+		{"ms", entities.QuestionTypeMultipleSelect},
+		{"fb", entities.QuestionTypeFillBlank},
+	}
+	if len(quiz.Questions) != len(want) {
+		t.Fatalf("expected the question of unknown type to be skipped, got %d questions", len(quiz.Questions))
+	}
+	for i, w := range want {
+		q := quiz.Questions[i]
+		if q.ID != w.id || q.Type != w.questionType {
+			t.Errorf("expected %s (%s) at position %d, got %s (%s)", w.id, w.questionType, i, q.ID, q.Type)
+		}
+		if q.Difficulty != entities.DefaultDifficulty(q.Type) {
+			t.Errorf("expected %s to have its type's default difficulty, got %d", q.ID, q.Difficulty)
+		}
+		if err := q.Validate(); err != nil {
+			t.Errorf("expected %s to be valid, got %v", q.ID, err)
+		}
+	}
+
+	if mc := quiz.Questions[0]; mc.CorrectIndex != 1 {
+		t.Errorf("expected correct index 1, got %d", mc.CorrectIndex)
+	}
+	if tf := quiz.Questions[1]; tf.CorrectAnswer == nil || *tf.CorrectAnswer {
+		t.Errorf("expected correct answer false, got %v", tf.CorrectAnswer)
+	}
+	if ms := quiz.Questions[2]; len(ms.CorrectIndices) != 2 || ms.CorrectIndices[1] != 2 {
+		t.Errorf("expected correct indices [0 2], got %v", ms.CorrectIndices)
+	}
+	if fb := quiz.Questions[3]; len(fb.AcceptedAnswers) != 1 || !fb.CaseSensitive {
+		t.Errorf("expected case-sensitive accepted answer fun, got %+v", fb)
+	}
+}
+
+func TestFolderCourseRepository_LoadExtendedQuiz(t *testing.T) {
+	root := t.TempDir()
+	writeCourseFiles(t, root, map[string]string{
+		"go-basics/course.json":                 `{"id": "go-basics", "title": "Go Basics"}`,
+		"go-basics/lessons/00-intro/content.md": "Intro",
+		"go-basics/lessons/00-intro/quiz.json": `{"version": "1.0", "questions": [
+			{"id": "q1", "type": "multiple_choice", "difficulty": 3, "question": "Pick", "options": ["a", "b"], "correctIndex": 1},
+			{"id": "code", "type": "code_exercise", "difficulty": 4, "question": "Write Add", "starterFile": "starter.go", "testFile": "add_test.go"},
+			{"id": "missing", "type": "code_exercise", "difficulty": 4, "question": "Write Sub", "testFile": "sub_test.go"}
+		]}`,
+		"go-basics/lessons/00-intro/starter.go":  "package main\n",
+		"go-basics/lessons/00-intro/add_test.go": "package main\n",
+	})
+
+	quiz := loadTestCourse(t, root).Lessons[0].ExtendedQuiz
+	if quiz == nil || quiz.Version != "1.0" {
+		t.Fatalf("expected the extended quiz to be loaded, got %+v", quiz)
+	}
+	if len(quiz.Questions) != 2 {
+		t.Fatalf("expected the exercise without its test file to be skipped, got %d questions", len(quiz.Questions))
+	}
+	if q := quiz.Questions[0]; q.Difficulty != 3 || q.CorrectIndex != 1 {
+		t.Errorf("expected difficulty and answer key from quiz.json, got %+v", q)
+	}
+	if code := quiz.Questions[1]; code.StarterCode != "package main\n" || code.TestCode != "package main\n" {
+		t.Errorf("expected the exercise files to be read, got %+v", code)
+	}
+}
+
+func TestFolderCourseRepository_CourseIDs(t *testing.T) {
+	tests := []struct {
+		name       string
+		files      map[string]string
+		wantIDs    map[string]string // Course IDs by title
+		duplicates []string          // Folders reported as duplicates
+	}{
+		{
+			name: "fixed IDs are kept",
+			files: map[string]string{
+				"go-basics/course.json": `{"id": "go-basics", "title": "Go Basics"}`,
+				"rust/course.json":      `{"id": "rust-intro", "title": "Rust"}`,
+			},
+			wantIDs: map[string]string{"Go Basics": "go-basics", "Rust": "rust-intro"},
+		},
+		{
+			name: "missing IDs come from the folder name",
+			files: map[string]string{
+				"go-basics/course.json": `{"id": "GENERATE-UUID", "title": "Go Basics"}`,
+				"rust/course.json":      `{"title": "Rust"}`,
+			},
+			wantIDs: map[string]string{"Go Basics": courseIDForFolder("go-basics"), "Rust": courseIDForFolder("rust")},
+		},
+		{
+			name: "the first folder keeps a duplicate ID",
+			files: map[string]string{
+				"a-go/course.json": `{"id": "go", "title": "Go"}`,
+				"b-go/course.json": `{"id": "go", "title": "Go Again"}`,
+				"c-go/course.json": `{"id": "go", "title": "Go Once More"}`,
+			},
+			wantIDs:    map[string]string{"Go": "go"},
+			duplicates: []string{"b-go", "c-go"},
+		},
+		{
+			name: "a fixed ID may clash with a derived one",
+			files: map[string]string{
+				"a-go/course.json": `{"id": "` + courseIDForFolder("b-go") + `", "title": "Go"}`,
+				"b-go/course.json": `{"id": "GENERATE-UUID", "title": "Go Again"}`,
+			},
+			wantIDs:    map[string]string{"Go": courseIDForFolder("b-go")},
+			duplicates: []string{"b-go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeCourseFiles(t, root, tt.files)
+			ctx := context.Background()
+
+			// IDs must not change between loads, or whatever is stored against them is orphaned
+			for load := 0; load < 2; load++ {
+				repo := NewFolderCourseRepository(root)
+				courses, _, err := repo.List(ctx, 10, 0)
+				if err != nil {
+					t.Fatalf("failed to list courses: %v", err)
+				}
+				got := make(map[string]string, len(courses))
+				for _, course := range courses {
+					got[course.Title] = course.ID
+				}
+				if len(got) != len(tt.wantIDs) {
+					t.Fatalf("expected courses %v, got %v", tt.wantIDs, got)
+				}
+				for title, id := range tt.wantIDs {
+					if got[title] != id {
+						t.Errorf("load %d: expected %s to have ID %s, got %s", load, title, id, got[title])
+					}
+				}
+
+				err = repo.CheckCourseIDs(ctx)
+				if len(tt.duplicates) == 0 && err != nil {
+					t.Errorf("expected no duplicate IDs, got %v", err)
+				}
+				for _, folder := range tt.duplicates {
+					if err == nil || !strings.Contains(err.Error(), " of "+folder+" ") {
+						t.Errorf("expected %s to be reported as a duplicate, got %v", folder, err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestFolderCourseRepository_LessonFolderByCourseID(t *testing.T) {
+	// Courses with derived IDs and the same title used to be told apart by title alone,
+	// which sent edits of the second course to the first
+	root := t.TempDir()
+	writeCourseFiles(t, root, map[string]string{
+		"go-2023/course.json":                 `{"title": "Go"}`,
+		"go-2023/lessons/00-intro/content.md": "Old intro",
+		"go-2024/course.json":                 `{"title": "Go"}`,
+		"go-2024/lessons/00-intro/content.md": "New intro",
+	})
+
+	repo := NewFolderCourseRepository(root)
+	ctx := context.Background()
+	if _, err := repo.UpdateLessonContent(ctx, courseIDForFolder("go-2024"), []int{0}, "Edited intro", "author-1", "Edit"); err != nil {
+		t.Fatalf("failed to update lesson: %v", err)
+	}
+
+	for folder, want := range map[string]string{"go-2023": "Old intro", "go-2024": "Edited intro"} {
+		content, err := os.ReadFile(filepath.Join(root, folder, "lessons", "00-intro", "content.md"))
+		if err != nil {
+			t.Fatalf("failed to read %s content: %v", folder, err)
+		}
+		if string(content) != want {
+			t.Errorf("expected %s content %q, got %q", folder, want, content)
+		}
+	}
+
+	if _, err := repo.UpdateLessonContent(ctx, "missing", []int{0}, "Edited", "author-1", "Edit"); err == nil {
+		t.Error("expected an error for an unknown course ID")
+	}
+}
+
+func TestFolderCourseRepository_LoadWarnings(t *testing.T) {
+	var logs bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	root := t.TempDir()
+	writeCourseFiles(t, root, map[string]string{
+		"go-basics/course.json":                                     `{"id": "go-basics", "title": "Go Basics"}`,
+		"go-basics/lessons/00-intro/content.md":                     "Intro",
+		"go-basics/lessons/00-intro/quiz.json":                      `{"questions": [}`,
+		"go-basics/lessons/00-intro/sublessons/00-setup/content.md": "Setup",
+		"go-basics/lessons/00-intro/sublessons/00-setup/quiz.json": `{"version": "1.0", "questions": [
+  {"id": "q1", "type": "true_false", "difficulty": 9, "question": "True?", "correctAnswer": true}
+]}`,
+	})
+	loadTestCourse(t, root)
+
+	// Warnings go to the structured log, naming the course folder and the lesson
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 warnings, got:\n%s", logs.String())
+	}
+	for i, want := range []string{
+		`level=WARN msg="Ignoring quiz that cannot be loaded" folder=go-basics lesson=lessons/00-intro error=`,
+		`level=WARN msg="Skipping part of a quiz" folder=go-basics lesson=lessons/00-intro/sublessons/00-setup error=`,
+	} {
+		if !strings.Contains(lines[i], want) {
+			t.Errorf("expected a warning containing %q, got %q", want, lines[i])
+		}
+	}
+}
@@ -0,0 +1,508 @@
+package folder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/project/backend/domain/entities"
+)
+
+// Create writes a new course folder, named after the course title, and loads it
+// The folder is written under a hidden name and renamed into place once complete, so a
+// half-written course is never loaded
+func (r *FolderCourseRepository) Create(ctx context.Context, course *entities.LibraryCourse) (*entities.LibraryCourse, error) {
+	if err := r.loadCourses(ctx); err != nil {
+		return nil, err
+	}
+
+	r.loadMu.Lock()
+	defer r.loadMu.Unlock()
+
+	folderName, err := uniqueFolderName(r.coursesPath, slugify(course.Title, "course"))
+	if err != nil {
+		return nil, err
+	}
+	if course.ID == "" {
+		course.ID = courseIDForFolder(folderName)
+	}
+	r.cacheMu.RLock()
+	_, taken := r.cache[course.ID]
+	r.cacheMu.RUnlock()
+	if taken {
+		return nil, fmt.Errorf("course ID %s is already used", course.ID)
+	}
+
+	tmpDir, err := os.MkdirTemp(r.coursesPath, "."+folderName+"-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create course folder: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	if err := os.Chmod(tmpDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create course folder: %w", err)
+	}
+
+	data, err := encodeCourseJSON(nil, course)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeFileAtomic(filepath.Join(tmpDir, "course.json"), data); err != nil {
+		return nil, err
+	}
+	if err := writeLessons(filepath.Join(tmpDir, "lessons"), course.Lessons, nil, false); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmpDir, filepath.Join(r.coursesPath, folderName)); err != nil {
+		return nil, fmt.Errorf("failed to create course folder: %w", err)
+	}
+
+	r.publish(r.reloadCourse(ctx, folderName))
+	return r.cachedCourse(course.ID)
+}
+
+// Update writes the changes to a course into its folder and reloads it
+// Files are compared with the course as it is on disk, not as cached, since callers may
+// have changed the cached course; unchanged files and folders are left untouched
+func (r *FolderCourseRepository) Update(ctx context.Context, course *entities.LibraryCourse) (*entities.LibraryCourse, error) {
+	if err := r.loadCourses(ctx); err != nil {
+		return nil, err
+	}
+
+	r.loadMu.Lock()
+	defer r.loadMu.Unlock()
+
+	coursePath, err := r.courseFolder(course.ID)
+	if err != nil {
+		return nil, err
+	}
+	folderName := filepath.Base(coursePath)
+	old, err := r.loadCourse(ctx, coursePath)
+	if err != nil {
+		return nil, err
+	}
+	addBankQuestions(old, r.banks, folderName)
+
+	if courseDetailsChanged(old, course) {
+		courseJSONPath := filepath.Join(coursePath, "course.json")
+		existing, err := os.ReadFile(courseJSONPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read course.json: %w", err)
+		}
+		data, err := encodeCourseJSON(existing, course)
+		if err != nil {
+			return nil, err
+		}
+		if err := writeIfChanged(courseJSONPath, data); err != nil {
+			return nil, err
+		}
+	}
+	if err := writeLessons(filepath.Join(coursePath, "lessons"), course.Lessons, old.Lessons, false); err != nil {
+		return nil, err
+	}
+
+	r.publish(r.reloadCourse(ctx, folderName))
+	return r.cachedCourse(course.ID)
+}
+
+// Delete removes a course folder, moving it out of the way before deleting its files so
+// the course disappears at once
+func (r *FolderCourseRepository) Delete(ctx context.Context, id string) error {
+	if err := r.loadCourses(ctx); err != nil {
+		return err
+	}
+
+	r.loadMu.Lock()
+	defer r.loadMu.Unlock()
+
+	coursePath, err := r.courseFolder(id)
+	if err != nil {
+		return err
+	}
+	folderName := filepath.Base(coursePath)
+
+	trash, err := os.MkdirTemp(r.coursesPath, "."+folderName+"-deleted-*")
+	if err != nil {
+		return fmt.Errorf("failed to delete course: %w", err)
+	}
+	defer os.RemoveAll(trash)
+	if err := os.Rename(coursePath, filepath.Join(trash, folderName)); err != nil {
+		return fmt.Errorf("failed to delete course: %w", err)
+	}
+
+	r.publish(r.reloadCourse(ctx, folderName))
+	return nil
+}
+
+// courseFolder returns the folder of a loaded course
+func (r *FolderCourseRepository) courseFolder(id string) (string, error) {
+	r.cacheMu.RLock()
+	defer r.cacheMu.RUnlock()
+
+	coursePath, ok := r.folders[id]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", entities.ErrCourseNotFound, id)
+	}
+	return coursePath, nil
+}
+
+// cachedCourse returns a course from the cache, without reloading it
+func (r *FolderCourseRepository) cachedCourse(id string) (*entities.LibraryCourse, error) {
+	r.cacheMu.RLock()
+	defer r.cacheMu.RUnlock()
+
+	course, ok := r.cache[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", entities.ErrCourseNotFound, id)
+	}
+	return course, nil
+}
+
+// courseDetailsChanged reports whether any field course.json holds differs
+func courseDetailsChanged(old, course *entities.LibraryCourse) bool {
+	return old.Title != course.Title ||
+		old.Description != course.Description ||
+		old.Author != course.Author ||
+		old.Difficulty != course.Difficulty ||
+		old.EstimatedHours != course.EstimatedHours ||
+		!slices.Equal(old.Tags, course.Tags)
+}
+
+// encodeCourseJSON returns the course.json for a course
+// An existing file is patched, so fields the course does not hold, such as the subtitle or
+// categories, are kept, and the fields keep the format the file uses
+func encodeCourseJSON(existing []byte, course *entities.LibraryCourse) ([]byte, error) {
+	obj := newJSONObject()
+	if existing != nil {
+		parsed, err := parseJSONObject(existing)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse course.json: %w", err)
+		}
+		obj = parsed
+	} else {
+		obj.set("id", course.ID)
+	}
+
+	obj.set("title", course.Title)
+	obj.set("description", course.Description)
+
+	// The author may be a name or an object with one
+	if author, ok := obj.object("author"); ok {
+		author.set("name", course.Author)
+		obj.set("author", author)
+	} else {
+		obj.set("author", course.Author)
+	}
+
+	// Difficulty and hours live under metadata in the newer format
+	if metadata, ok := obj.object("metadata"); ok && metadata.has("difficulty") {
+		metadata.set("difficulty", string(course.Difficulty))
+		metadata.set("estimated_hours", course.EstimatedHours)
+		obj.set("metadata", metadata)
+	} else {
+		obj.set("difficulty", string(course.Difficulty))
+		obj.set("estimated_hours", course.EstimatedHours)
+	}
+
+	// Categories are loaded as tags, so they are left out of the tags written back
+	var categories []string
+	if raw, ok := obj.raw["categories"]; ok {
+		var cj courseJSON
+		if json.Unmarshal(raw, &cj.Categories) == nil {
+			categories = append([]string{cj.Categories.Primary}, cj.Categories.Secondary...)
+		}
+	}
+	tags := []string{}
+	for _, tag := range course.Tags {
+		if !slices.Contains(categories, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	obj.set("tags", tags)
+
+	return obj.encodeFile(), nil
+}
+
+// writeLessons writes lessons into a lessons or sublessons folder
+// Lessons are matched to the old lessons by the folder they were loaded from, so a lesson
+// keeps its folder, and with it its history, when others are added, removed or moved.
+// Lessons without a folder get a new numbered one, the folders of old lessons that are
+// gone are deleted, and folders are renamed where needed so they sort in lesson order
+func writeLessons(dir string, lessons, old []entities.Lesson, sublessons bool) error {
+	if len(lessons) == 0 && len(old) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Base(dir), err)
+	}
+
+	oldByFolder := make(map[string]*entities.Lesson, len(old))
+	for i := range old {
+		oldByFolder[old[i].Folder] = &old[i]
+	}
+	// A folder belongs to the first lesson naming it; copies of a lesson are new lessons
+	matched := make([]*entities.Lesson, len(lessons))
+	for i, lesson := range lessons {
+		if previous, ok := oldByFolder[lesson.Folder]; ok && lesson.Folder != "" {
+			matched[i] = previous
+			delete(oldByFolder, lesson.Folder)
+		}
+	}
+
+	for _, removed := range slices.Sorted(maps.Keys(oldByFolder)) {
+		if err := os.RemoveAll(filepath.Join(dir, removed)); err != nil {
+			return fmt.Errorf("failed to remove lesson folder: %w", err)
+		}
+	}
+
+	folders, err := lessonFolders(dir)
+	if err != nil {
+		return err
+	}
+	taken := make(map[string]bool, len(folders))
+	for _, folder := range folders {
+		taken[folder] = true
+	}
+
+	previous := ""
+	for i, lesson := range lessons {
+		oldLesson := matched[i]
+		if oldLesson != nil && oldLesson.Folder > previous {
+			if err := writeLesson(filepath.Join(dir, oldLesson.Folder), lesson, oldLesson, sublessons); err != nil {
+				return err
+			}
+			previous = oldLesson.Folder
+			continue
+		}
+
+		slug := slugify(lesson.Title, "lesson")
+		if oldLesson != nil {
+			slug = folderSlug(oldLesson.Folder)
+		}
+		name := newLessonFolderName(taken, previous, i, slug)
+		taken[name] = true
+		previous = name
+
+		if oldLesson != nil {
+			// A moved lesson's folder is renamed rather than rewritten, keeping its history
+			if err := os.Rename(filepath.Join(dir, oldLesson.Folder), filepath.Join(dir, name)); err != nil {
+				return fmt.Errorf("failed to move lesson folder: %w", err)
+			}
+			// A lesson titled after its folder would take the new folder's name, so its
+			// title is written out
+			if oldLesson.Title == oldLesson.Folder {
+				renamed := *oldLesson
+				renamed.Title = name
+				oldLesson = &renamed
+			}
+			if err := writeLesson(filepath.Join(dir, name), lesson, oldLesson, sublessons); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := os.Mkdir(filepath.Join(dir, name), 0755); err != nil {
+			return fmt.Errorf("failed to create lesson folder: %w", err)
+		}
+		// New lessons without an order are ordered by position, like their folder
+		if lesson.Order == 0 {
+			lesson.Order = i
+		}
+		if err := writeLesson(filepath.Join(dir, name), lesson, nil, sublessons); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeLesson writes one lesson's files into its folder; old is the lesson as loaded from
+// the folder, or nil for a new folder, and parts equal to it are not written
+func writeLesson(folder string, lesson entities.Lesson, old *entities.Lesson, sublesson bool) error {
+	isNew := old == nil
+	if isNew {
+		old = &entities.Lesson{}
+	}
+
+	quiz := lesson.EffectiveQuiz()
+	metadataChanged := lesson.Title != old.Title || (!sublesson && (lesson.Order != old.Order || (quiz != nil) != (old.ExtendedQuiz != nil)))
+	if metadataChanged {
+		if err := writeLessonJSON(folder, lesson, quiz != nil, sublesson); err != nil {
+			return err
+		}
+	}
+
+	// content.md is optional, so a lesson without content keeps going without one
+	if isNew || lesson.Content != old.Content {
+		if err := writeIfChanged(filepath.Join(folder, "content.md"), []byte(lesson.Content)); err != nil {
+			return err
+		}
+	}
+
+	if !reflect.DeepEqual(quiz, old.ExtendedQuiz) {
+		quizPath := filepath.Join(folder, "quiz.json")
+		if quiz == nil {
+			if err := os.Remove(quizPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("failed to remove quiz: %w", err)
+			}
+		} else {
+			existing, _ := os.ReadFile(quizPath)
+			data, err := encodeQuiz(folder, existing, quiz)
+			if err != nil {
+				return err
+			}
+			if err := writeIfChanged(quizPath, data); err != nil {
+				return err
+			}
+		}
+	}
+
+	if sublesson {
+		return nil
+	}
+	return writeLessons(filepath.Join(folder, "sublessons"), lesson.Sublessons, old.Sublessons, true)
+}
+
+// writeLessonJSON patches a lesson's lesson.json
+// Sublessons are titled after their folder, so they only get a lesson.json for another title
+func writeLessonJSON(folder string, lesson entities.Lesson, hasQuiz, sublesson bool) error {
+	path := filepath.Join(folder, "lesson.json")
+	obj := newJSONObject()
+	existing, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if obj, err = parseJSONObject(existing); err != nil {
+			return fmt.Errorf("failed to parse lesson.json: %w", err)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return fmt.Errorf("failed to read lesson.json: %w", err)
+	case sublesson && lesson.Title == filepath.Base(folder):
+		return nil
+	}
+
+	obj.set("title", lesson.Title)
+	if !sublesson {
+		obj.set("order", lesson.Order)
+		obj.set("has_quiz", hasQuiz)
+	}
+
+	return writeIfChanged(path, obj.encodeFile())
+}
+
+// lessonFolders returns the names of the lesson folders in dir, in load order
+func lessonFolders(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(dir), err)
+	}
+
+	var folders []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			folders = append(folders, entry.Name())
+		}
+	}
+	sort.Strings(folders)
+	return folders, nil
+}
+
+// newLessonFolderName returns an "NN-slug" folder name that sorts after previous and is
+// not taken, numbered from the lesson's position
+func newLessonFolderName(taken map[string]bool, previous string, index int, slug string) string {
+	for n := index; ; n++ {
+		name := fmt.Sprintf("%02d-%s", n, slug)
+		if name > previous && !taken[name] {
+			return name
+		}
+	}
+}
+
+// folderSlug returns a lesson folder's name without its number prefix
+func folderSlug(folder string) string {
+	prefix, slug, ok := strings.Cut(folder, "-")
+	if !ok || prefix == "" || strings.Trim(prefix, "0123456789") != "" {
+		return folder
+	}
+	return slug
+}
+
+// uniqueFolderName returns name, or name with a numeric suffix if dir already has it
+func uniqueFolderName(dir, name string) (string, error) {
+	candidate := name
+	for n := 2; ; n++ {
+		_, err := os.Stat(filepath.Join(dir, candidate))
+		if errors.Is(err, os.ErrNotExist) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		candidate = fmt.Sprintf("%s-%d", name, n)
+	}
+}
+
+// slugify turns a title into a folder name of lower case letters, digits and dashes
+func slugify(title, fallback string) string {
+	var b strings.Builder
+	dash := false
+	for _, c := range strings.ToLower(title) {
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+			b.WriteRune(c)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+
+	slug := strings.TrimSuffix(b.String(), "-")
+	if len(slug) > 50 {
+		slug = strings.TrimSuffix(slug[:50], "-")
+	}
+	if slug == "" {
+		return fallback
+	}
+	return slug
+}
+
+// writeIfChanged writes data to path unless the file already holds exactly that
+func writeIfChanged(path string, data []byte) error {
+	existing, err := os.ReadFile(path)
+	if err == nil && bytes.Equal(existing, data) {
+		return nil
+	}
+	return writeFileAtomic(path, data)
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it into place,
+// so readers never see a partly written file
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
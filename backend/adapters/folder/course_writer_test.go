@@ -0,0 +1,334 @@
+package folder
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/project/backend/domain/entities"
+)
+
+// readCourseFile returns the content of a file under root, by slash-separated path
+func readCourseFile(t *testing.T, root, name string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(name)))
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", name, err)
+	}
+	return string(data)
+}
+
+// expectNoHiddenEntries fails if dir holds hidden files or folders, such as those left by
+// an interrupted write
+func expectNoHiddenEntries(t *testing.T, dir string) {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", dir, err)
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			t.Errorf("expected no temporary entries in %s, found %s", dir, entry.Name())
+		}
+	}
+}
+
+func lessonTitles(lessons []entities.Lesson) []string {
+	titles := make([]string, len(lessons))
+	for i, lesson := range lessons {
+		titles[i] = lesson.Title
+	}
+	return titles
+}
+
+func TestFolderCourseRepository_Create(t *testing.T) {
+	root := t.TempDir()
+	writeCourseFiles(t, root, map[string]string{"go-basics/course.json": `{"id": "go-basics", "title": "Go Basics"}`})
+	repo := NewFolderCourseRepository(root)
+	ctx := context.Background()
+
+	truth := true
+	course := &entities.LibraryCourse{
+		Title:          "Go Basics",
+		Description:    "Learn Go",
+		Author:         "Jane Doe",
+		Difficulty:     entities.DifficultyBeginner,
+		EstimatedHours: 4,
+		Tags:           []string{"go"},
+		Lessons: []entities.Lesson{
+			{Title: "Introduction", Content: "# Welcome", Sublessons: []entities.Lesson{
+				{Title: "Setup", Content: "Install Go"},
+			}},
+			{Title: "Types & Values", Content: "Types", ExtendedQuiz: &entities.ExtendedQuiz{
+				Version: entities.ExtendedQuizVersion,
+				Questions: []entities.ExtendedQuizQuestion{
+					{ID: "q1", Type: entities.QuestionTypeTrueFalse, Difficulty: 1, Question: "Go is typed", CorrectAnswer: &truth},
+				},
+			}},
+		},
+	}
+	created, err := repo.Create(ctx, course)
+	if err != nil {
+		t.Fatalf("failed to create course: %v", err)
+	}
+
+	// The title's folder is taken, so the course gets the next free name
+	if created.ID != courseIDForFolder("go-basics-2") {
+		t.Errorf("expected the ID derived from go-basics-2, got %s", created.ID)
+	}
+	if got := readCourseFile(t, root, "go-basics-2/lessons/01-types-values/content.md"); got != "Types" {
+		t.Errorf("expected the lesson content, got %q", got)
+	}
+	if got := readCourseFile(t, root, "go-basics-2/lessons/00-introduction/sublessons/00-setup/content.md"); got != "Install Go" {
+		t.Errorf("expected the sublesson content, got %q", got)
+	}
+	expectNoHiddenEntries(t, root)
+
+	if created.Author != "Jane Doe" || created.Difficulty != entities.DifficultyBeginner || created.EstimatedHours != 4 {
+		t.Errorf("expected the course details to round-trip, got %+v", created)
+	}
+	if titles := lessonTitles(created.Lessons); strings.Join(titles, ",") != "Introduction,Types & Values" {
+		t.Errorf("expected the lessons in order, got %v", titles)
+	}
+	if subs := created.Lessons[0].Sublessons; len(subs) != 1 || subs[0].Title != "Setup" {
+		t.Errorf("expected the sublesson to round-trip, got %+v", subs)
+	}
+	if quiz := created.Lessons[1].ExtendedQuiz; quiz == nil || len(quiz.Questions) != 1 || !*quiz.Questions[0].CorrectAnswer {
+		t.Errorf("expected the quiz to round-trip, got %+v", quiz)
+	}
+	if cached, err := repo.GetByID(ctx, created.ID); err != nil || cached != created {
+		t.Errorf("expected the new course in the cache, got %v", err)
+	}
+
+	if _, err := repo.Create(ctx, &entities.LibraryCourse{ID: "go-basics", Title: "Again"}); err == nil {
+		t.Error("expected an error for a course ID that is taken")
+	}
+}
+
+func TestFolderCourseRepository_Create_FailureLeavesNoFolder(t *testing.T) {
+	root := t.TempDir()
+	repo := NewFolderCourseRepository(root)
+
+	// The exercise's files would be written outside the lesson folder, failing the write
+	// after the course.json and the first lesson are written
+	course := &entities.LibraryCourse{
+		Title: "Go Basics",
+		Lessons: []entities.Lesson{
+			{Title: "Introduction", Content: "Intro"},
+			{Title: "Exercises", Content: "Exercises", ExtendedQuiz: &entities.ExtendedQuiz{
+				Questions: []entities.ExtendedQuizQuestion{
+					{ID: "../../../escape", Type: entities.QuestionTypeCodeExercise, Difficulty: 4, Question: "Write Add", TestCode: "package main\n"},
+				},
+			}},
+		},
+	}
+	if _, err := repo.Create(context.Background(), course); err == nil {
+		t.Fatal("expected the create to fail")
+	}
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		t.Fatalf("failed to read courses: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no course folder to be left behind, found %s", entries[0].Name())
+	}
+}
+
+func TestFolderCourseRepository_Update(t *testing.T) {
+	root := t.TempDir()
+	writeCourseFiles(t, root, map[string]string{
+		"go-basics/course.json":                                     `{"id": "go-basics", "title": "Go Basics", "subtitle": "Kept"}`,
+		"go-basics/lessons/00-intro/lesson.json":                    `{"title": "Introduction", "order": 0}`,
+		"go-basics/lessons/00-intro/content.md":                     "Intro",
+		"go-basics/lessons/00-intro/.history/000001.json":           `{"id": 1, "content": "Intro"}`,
+		"go-basics/lessons/00-intro/sublessons/00-setup/content.md": "Setup",
+		"go-basics/lessons/01-types/content.md":                     "Types",
+		"go-basics/lessons/01-types/.history/000001.json":           `{"id": 1, "content": "Types"}`,
+		"go-basics/lessons/02-funcs/lesson.json":                    `{"title": "Functions", "order": 2}`,
+		"go-basics/lessons/02-funcs/content.md":                     "Funcs",
+	})
+	repo := NewFolderCourseRepository(root)
+	ctx := context.Background()
+
+	// Unchanged files are not rewritten
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for _, name := range []string{"00-intro/content.md", "01-types/content.md", "02-funcs/content.md"} {
+		if err := os.Chtimes(filepath.Join(root, "go-basics", "lessons", filepath.FromSlash(name)), past, past); err != nil {
+			t.Fatalf("failed to set file times: %v", err)
+		}
+	}
+	unchanged := func(name string) bool {
+		info, err := os.Stat(filepath.Join(root, "go-basics", "lessons", filepath.FromSlash(name)))
+		return err == nil && info.ModTime().Equal(past)
+	}
+
+	course, err := repo.GetByID(ctx, "go-basics")
+	if err != nil {
+		t.Fatalf("failed to get course: %v", err)
+	}
+	update := *course
+	update.Title = "Go Fundamentals"
+	update.Lessons = []entities.Lesson{course.Lessons[0], course.Lessons[1], course.Lessons[2]}
+	update.Lessons[2].Content = "Functions"
+	updated, err := repo.Update(ctx, &update)
+	if err != nil {
+		t.Fatalf("failed to update course: %v", err)
+	}
+	if updated.Title != "Go Fundamentals" || !strings.Contains(readCourseFile(t, root, "go-basics/course.json"), `"subtitle": "Kept"`) {
+		t.Errorf("expected course.json to be patched, got %s", readCourseFile(t, root, "go-basics/course.json"))
+	}
+	if !unchanged("00-intro/content.md") || !unchanged("01-types/content.md") || unchanged("02-funcs/content.md") {
+		t.Error("expected only the edited lesson's content to be written")
+	}
+
+	// Removing a lesson leaves the folders of the lessons after it alone
+	update.Lessons = []entities.Lesson{updated.Lessons[1], updated.Lessons[2]}
+	updated, err = repo.Update(ctx, &update)
+	if err != nil {
+		t.Fatalf("failed to remove lesson: %v", err)
+	}
+	if titles := lessonTitles(updated.Lessons); strings.Join(titles, ",") != "01-types,Functions" {
+		t.Errorf("expected the remaining lessons, got %v", titles)
+	}
+	if _, err := os.Stat(filepath.Join(root, "go-basics", "lessons", "00-intro")); !errors.Is(err, os.ErrNotExist) {
+		t.Error("expected the removed lesson's folder to be deleted")
+	}
+	if !unchanged("01-types/content.md") || readCourseFile(t, root, "go-basics/lessons/01-types/.history/000001.json") == "" {
+		t.Error("expected the next lesson's folder and history to be left alone")
+	}
+
+	// Moving a lesson renames its folder, keeping its history and its title
+	update.Lessons = []entities.Lesson{updated.Lessons[1], updated.Lessons[0]}
+	updated, err = repo.Update(ctx, &update)
+	if err != nil {
+		t.Fatalf("failed to move lesson: %v", err)
+	}
+	if titles := lessonTitles(updated.Lessons); strings.Join(titles, ",") != "Functions,01-types" {
+		t.Errorf("expected the lessons in their new order, got %v", titles)
+	}
+	moved := updated.Lessons[1]
+	if moved.Folder == "01-types" || moved.Content != "Types" {
+		t.Errorf("expected the moved lesson in a renamed folder, got %+v", moved)
+	}
+	if !strings.Contains(readCourseFile(t, root, "go-basics/lessons/"+moved.Folder+"/.history/000001.json"), "Types") {
+		t.Error("expected the moved lesson's history to move with it")
+	}
+	if updated.Lessons[0].Folder != "02-funcs" {
+		t.Error("expected the lesson that kept its place to keep its folder")
+	}
+
+	// New lessons get new folders, placed by position, and copies of a lesson are new lessons
+	update.Lessons = []entities.Lesson{{Title: "Welcome", Content: "Hello"}, updated.Lessons[0], updated.Lessons[1], updated.Lessons[1]}
+	updated, err = repo.Update(ctx, &update)
+	if err != nil {
+		t.Fatalf("failed to add lessons: %v", err)
+	}
+	if titles := lessonTitles(updated.Lessons); strings.Join(titles, ",") != "Welcome,Functions,01-types,01-types" {
+		t.Errorf("expected the new lessons in place, got %v", titles)
+	}
+	if updated.Lessons[0].Content != "Hello" || updated.Lessons[2].Folder != moved.Folder || updated.Lessons[3].Folder == moved.Folder {
+		t.Errorf("expected the new lessons in new folders, got %+v", updated.Lessons)
+	}
+
+	if _, err := repo.Update(ctx, &entities.LibraryCourse{ID: "missing"}); !errors.Is(err, entities.ErrCourseNotFound) {
+		t.Errorf("expected ErrCourseNotFound, got %v", err)
+	}
+}
+
+func TestFolderCourseRepository_Update_Sublessons(t *testing.T) {
+	root := t.TempDir()
+	writeCourseFiles(t, root, map[string]string{
+		"go-basics/course.json":                                     `{"id": "go-basics", "title": "Go Basics"}`,
+		"go-basics/lessons/00-intro/content.md":                     "Intro",
+		"go-basics/lessons/00-intro/sublessons/00-setup/content.md": "Setup",
+		"go-basics/lessons/00-intro/sublessons/01-hello/content.md": "Hello",
+	})
+	repo := NewFolderCourseRepository(root)
+	ctx := context.Background()
+
+	course, err := repo.GetByID(ctx, "go-basics")
+	if err != nil {
+		t.Fatalf("failed to get course: %v", err)
+	}
+	update := *course
+	update.Lessons = []entities.Lesson{course.Lessons[0]}
+	subs := course.Lessons[0].Sublessons
+	update.Lessons[0].Sublessons = []entities.Lesson{subs[1], subs[0]}
+
+	updated, err := repo.Update(ctx, &update)
+	if err != nil {
+		t.Fatalf("failed to move sublesson: %v", err)
+	}
+	if titles := lessonTitles(updated.Lessons[0].Sublessons); strings.Join(titles, ",") != "01-hello,00-setup" {
+		t.Errorf("expected the sublessons to keep their titles in their new order, got %v", titles)
+	}
+}
+
+func TestFolderCourseRepository_Delete(t *testing.T) {
+	root := t.TempDir()
+	writeCourseFiles(t, root, map[string]string{
+		"go-basics/course.json":                 `{"id": "go-basics", "title": "Go Basics"}`,
+		"go-basics/lessons/00-intro/content.md": "Intro",
+		"rust/course.json":                      `{"id": "rust", "title": "Rust"}`,
+	})
+	repo := NewFolderCourseRepository(root)
+	ctx := context.Background()
+
+	if err := repo.Delete(ctx, "go-basics"); err != nil {
+		t.Fatalf("failed to delete course: %v", err)
+	}
+	if _, err := repo.GetByID(ctx, "go-basics"); err == nil {
+		t.Error("expected the deleted course to be gone")
+	}
+	if _, err := repo.GetByID(ctx, "rust"); err != nil {
+		t.Errorf("expected the other course to remain, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "go-basics")); !errors.Is(err, os.ErrNotExist) {
+		t.Error("expected the course folder to be deleted")
+	}
+	expectNoHiddenEntries(t, root)
+
+	if err := repo.Delete(ctx, "go-basics"); !errors.Is(err, entities.ErrCourseNotFound) {
+		t.Errorf("expected ErrCourseNotFound, got %v", err)
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "content.md")
+
+	for _, content := range []string{"first", "second"} {
+		if err := writeFileAtomic(path, []byte(content)); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+		if got := readCourseFile(t, dir, "content.md"); got != content {
+			t.Errorf("expected %q, got %q", content, got)
+		}
+	}
+	expectNoHiddenEntries(t, dir)
+
+	if err := writeFileAtomic(filepath.Join(dir, "missing", "content.md"), []byte("x")); err == nil {
+		t.Error("expected an error for a missing folder")
+	}
+}
+
+func TestFolderSlug(t *testing.T) {
+	tests := map[string]string{
+		"00-intro":        "intro",
+		"12-types-values": "types-values",
+		"intro":           "intro",
+		"go-basics":       "go-basics",
+	}
+	for folder, want := range tests {
+		if got := folderSlug(folder); got != want {
+			t.Errorf("folderSlug(%s) = %q, want %q", folder, got, want)
+		}
+	}
+}
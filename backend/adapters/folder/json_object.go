@@ -0,0 +1,201 @@
+package folder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// jsonObject is a JSON object read from a course file, patched by setting values
+// Encoding it rewrites only the values that were set, so the rest of the file keeps its
+// key order, formatting and blank lines, and diffs stay small
+type jsonObject struct {
+	src     []byte // The object as read; nil for new objects
+	keys    []string
+	raw     map[string]json.RawMessage // Values as read
+	spans   map[string][2]int          // Where each value sits in src
+	changes map[string]any             // Values set since
+}
+
+func newJSONObject() *jsonObject {
+	return &jsonObject{
+		raw:     make(map[string]json.RawMessage),
+		spans:   make(map[string][2]int),
+		changes: make(map[string]any),
+	}
+}
+
+// parseJSONObject reads an object, recording where each of its values is
+func parseJSONObject(data []byte) (*jsonObject, error) {
+	src := bytes.TrimSpace(data)
+	dec := json.NewDecoder(bytes.NewReader(src))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, fmt.Errorf("expected a JSON object")
+	}
+
+	obj := newJSONObject()
+	obj.src = src
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := tok.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		if _, ok := obj.raw[key]; !ok {
+			obj.keys = append(obj.keys, key)
+		}
+		end := int(dec.InputOffset())
+		obj.raw[key] = value
+		obj.spans[key] = [2]int{end - len(value), end}
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (o *jsonObject) has(key string) bool {
+	_, read := o.raw[key]
+	_, set := o.changes[key]
+	return read || set
+}
+
+// object returns the value of key if it is itself an object; setting it back after
+// changing it patches the nested object in place
+func (o *jsonObject) object(key string) (*jsonObject, bool) {
+	if changed, ok := o.changes[key].(*jsonObject); ok {
+		return changed, true
+	}
+	raw, ok := o.raw[key]
+	if !ok {
+		return nil, false
+	}
+	obj, err := parseJSONObject(raw)
+	return obj, err == nil
+}
+
+// set replaces the value of key, or adds key after the existing keys
+// Values are strings, numbers, string slices and objects; setting the value a key already
+// holds keeps its original text
+func (o *jsonObject) set(key string, value any) {
+	if !o.has(key) {
+		o.keys = append(o.keys, key)
+	}
+	if raw, ok := o.raw[key]; ok {
+		if _, isObject := value.(*jsonObject); !isObject && sameJSON(raw, encodeJSONValue(value, "")) {
+			delete(o.changes, key)
+			return
+		}
+	}
+	o.changes[key] = value
+}
+
+// encodeFile returns the object as the contents of a file
+func (o *jsonObject) encodeFile() []byte {
+	return append(o.encode(""), '\n')
+}
+
+// encode returns the object's JSON, with indent being the indentation of its first line
+func (o *jsonObject) encode(indent string) []byte {
+	if o.src == nil {
+		if len(o.keys) == 0 {
+			return []byte("{}")
+		}
+		var buf bytes.Buffer
+		buf.WriteString("{")
+		for i, key := range o.keys {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n" + indent + "  ")
+			buf.Write(encodeJSONValue(key, indent+"  "))
+			buf.WriteString(": ")
+			buf.Write(encodeJSONValue(o.changes[key], indent+"  "))
+		}
+		buf.WriteString("\n" + indent + "}")
+		return buf.Bytes()
+	}
+
+	// Replace changed values from the end, so earlier spans stay valid
+	var replaced []string
+	for key := range o.changes {
+		if _, ok := o.spans[key]; ok {
+			replaced = append(replaced, key)
+		}
+	}
+	sort.Slice(replaced, func(i, j int) bool { return o.spans[replaced[i]][0] > o.spans[replaced[j]][0] })
+
+	out := bytes.Clone(o.src)
+	for _, key := range replaced {
+		span := o.spans[key]
+		value := encodeJSONValue(o.changes[key], lineIndent(o.src, span[0]))
+		out = append(out[:span[0]], append(value, out[span[1]:]...)...)
+	}
+
+	// Add new keys after the last value, indented like the existing keys
+	childIndent := indent + "  "
+	if len(o.spans) > 0 {
+		childIndent = lineIndent(o.src, o.spans[o.keys[0]][0])
+	}
+	var added bytes.Buffer
+	for _, key := range o.keys {
+		if _, ok := o.spans[key]; ok {
+			continue
+		}
+		if len(o.spans) > 0 || added.Len() > 0 {
+			added.WriteString(",")
+		}
+		added.WriteString("\n" + childIndent)
+		added.Write(encodeJSONValue(key, childIndent))
+		added.WriteString(": ")
+		added.Write(encodeJSONValue(o.changes[key], childIndent))
+	}
+	if added.Len() > 0 {
+		end := bytes.LastIndexByte(out, '}')
+		last := len(bytes.TrimRight(out[:end], " \t\r\n"))
+		closing := out[last:]
+		if len(o.spans) == 0 {
+			closing = []byte("\n" + indent + "}")
+		}
+		out = append(append(out[:last:last], added.Bytes()...), closing...)
+	}
+	return out
+}
+
+// encodeJSONValue encodes a value placed on a line indented by indent
+func encodeJSONValue(value any, indent string) []byte {
+	if obj, ok := value.(*jsonObject); ok {
+		return obj.encode(indent)
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent(indent, "  ")
+	enc.Encode(value)
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+}
+
+// sameJSON reports whether two encoded values are equal
+func sameJSON(a, b []byte) bool {
+	var va, vb any
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// lineIndent returns the leading whitespace of the line holding src[pos]
+func lineIndent(src []byte, pos int) string {
+	start := bytes.LastIndexByte(src[:pos], '\n') + 1
+	end := start
+	for end < pos && (src[end] == ' ' || src[end] == '\t') {
+		end++
+	}
+	return string(src[start:end])
+}
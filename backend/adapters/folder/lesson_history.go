@@ -0,0 +1,238 @@
+package folder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/project/backend/domain/entities"
+)
+
+// lessonHistoryDir is the folder, inside a lesson's folder, holding one file per revision
+// of its content; being hidden, it is skipped by the loader and the watcher
+const lessonHistoryDir = ".history"
+
+// revisionJSON represents a revision file in a lesson's history
+type revisionJSON struct {
+	ID        int       `json:"id"`
+	AuthorID  string    `json:"author_id,omitempty"`
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"created_at"`
+	Content   string    `json:"content"`
+}
+
+// UpdateLessonContent saves a lesson's content.md and records it as a new revision
+// The first save also records the content the lesson had before, so it can be restored
+func (r *FolderCourseRepository) UpdateLessonContent(ctx context.Context, courseID string, lessonPath []int, newContent, authorID, message string) (*entities.LessonRevision, error) {
+	lessonFolder, err := r.lessonFolderPath(ctx, courseID, lessonPath)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := r.saveLessonRevision(lessonFolder, newContent, authorID, message)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reload the course so the next request gets fresh content
+	r.reloadCourseByID(ctx, courseID)
+
+	return revision, nil
+}
+
+// ListLessonRevisions returns a lesson's revisions, newest first
+func (r *FolderCourseRepository) ListLessonRevisions(ctx context.Context, courseID string, lessonPath []int) ([]entities.LessonRevision, error) {
+	lessonFolder, err := r.lessonFolderPath(ctx, courseID, lessonPath)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := readLessonRevisions(lessonFolder)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i].ID > revisions[j].ID })
+	return revisions, nil
+}
+
+// GetLessonRevision returns one revision of a lesson
+func (r *FolderCourseRepository) GetLessonRevision(ctx context.Context, courseID string, lessonPath []int, revisionID int) (*entities.LessonRevision, error) {
+	lessonFolder, err := r.lessonFolderPath(ctx, courseID, lessonPath)
+	if err != nil {
+		return nil, err
+	}
+	return readLessonRevision(lessonFolder, revisionID)
+}
+
+// RestoreLessonRevision makes an old revision's content the lesson's content again,
+// recording it as a new revision so the history is never rewritten
+func (r *FolderCourseRepository) RestoreLessonRevision(ctx context.Context, courseID string, lessonPath []int, revisionID int, authorID, message string) (*entities.LessonRevision, error) {
+	lessonFolder, err := r.lessonFolderPath(ctx, courseID, lessonPath)
+	if err != nil {
+		return nil, err
+	}
+
+	old, err := readLessonRevision(lessonFolder, revisionID)
+	if err != nil {
+		return nil, err
+	}
+	if message == "" {
+		message = fmt.Sprintf("Restore revision %d", revisionID)
+	}
+
+	revision, err := r.saveLessonRevision(lessonFolder, old.Content, authorID, message)
+	if err != nil {
+		return nil, err
+	}
+
+	r.reloadCourseByID(ctx, courseID)
+
+	return revision, nil
+}
+
+// saveLessonRevision records content as the next revision of a lesson and writes it to
+// content.md; the revision is removed again if the content cannot be written
+func (r *FolderCourseRepository) saveLessonRevision(lessonFolder, content, authorID, message string) (*entities.LessonRevision, error) {
+	r.historyMu.Lock()
+	defer r.historyMu.Unlock()
+
+	historyPath := filepath.Join(lessonFolder, lessonHistoryDir)
+	if err := os.MkdirAll(historyPath, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create lesson history: %w", err)
+	}
+	ids, err := lessonRevisionIDs(historyPath)
+	if err != nil {
+		return nil, err
+	}
+	next := 1
+	if len(ids) > 0 {
+		next = ids[len(ids)-1] + 1
+	}
+
+	// Lessons edited before their history was kept start it with the content they had
+	contentPath := filepath.Join(lessonFolder, "content.md")
+	if len(ids) == 0 {
+		if info, err := os.Stat(contentPath); err == nil {
+			existing, err := os.ReadFile(contentPath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read content: %w", err)
+			}
+			initial := revisionJSON{ID: next, Message: "Content before revision history", CreatedAt: info.ModTime().UTC(), Content: string(existing)}
+			if err := writeRevision(historyPath, initial); err != nil {
+				return nil, err
+			}
+			next++
+		}
+	}
+
+	rj := revisionJSON{ID: next, AuthorID: authorID, Message: message, CreatedAt: time.Now().UTC(), Content: content}
+	if err := writeRevision(historyPath, rj); err != nil {
+		return nil, err
+	}
+	if err := writeFileAtomic(contentPath, []byte(content)); err != nil {
+		os.Remove(filepath.Join(historyPath, revisionFileName(rj.ID)))
+		return nil, fmt.Errorf("failed to write content: %w", err)
+	}
+
+	revision := rj.toEntity()
+	return &revision, nil
+}
+
+// readLessonRevisions reads every revision in a lesson's history, oldest first
+func readLessonRevisions(lessonFolder string) ([]entities.LessonRevision, error) {
+	historyPath := filepath.Join(lessonFolder, lessonHistoryDir)
+	ids, err := lessonRevisionIDs(historyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := make([]entities.LessonRevision, 0, len(ids))
+	for _, id := range ids {
+		revision, err := readLessonRevision(lessonFolder, id)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, *revision)
+	}
+	return revisions, nil
+}
+
+func readLessonRevision(lessonFolder string, revisionID int) (*entities.LessonRevision, error) {
+	path := filepath.Join(lessonFolder, lessonHistoryDir, revisionFileName(revisionID))
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %d", entities.ErrLessonRevisionNotFound, revisionID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read revision %d: %w", revisionID, err)
+	}
+
+	var rj revisionJSON
+	if err := json.Unmarshal(data, &rj); err != nil {
+		return nil, fmt.Errorf("failed to parse revision %d: %w", revisionID, err)
+	}
+	rj.ID = revisionID
+	revision := rj.toEntity()
+	return &revision, nil
+}
+
+// lessonRevisionIDs returns the IDs of the revisions in a history folder, in order
+// A missing folder is an empty history
+func lessonRevisionIDs(historyPath string) ([]int, error) {
+	entries, err := os.ReadDir(historyPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lesson history: %w", err)
+	}
+
+	var ids []int
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if entry.IsDir() || !ok {
+			continue
+		}
+		if id, err := strconv.Atoi(name); err == nil && id > 0 {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
+
+// writeRevision writes a revision file; revisions are never overwritten
+func writeRevision(historyPath string, rj revisionJSON) error {
+	path := filepath.Join(historyPath, revisionFileName(rj.ID))
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("revision %d already exists", rj.ID)
+	}
+
+	data, err := json.MarshalIndent(rj, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode revision: %w", err)
+	}
+	return writeFileAtomic(path, append(data, '\n'))
+}
+
+// revisionFileName names revision files so they list in order
+func revisionFileName(id int) string {
+	return fmt.Sprintf("%06d.json", id)
+}
+
+func (rj revisionJSON) toEntity() entities.LessonRevision {
+	return entities.LessonRevision{
+		ID:        rj.ID,
+		AuthorID:  rj.AuthorID,
+		Message:   rj.Message,
+		Content:   rj.Content,
+		CreatedAt: rj.CreatedAt,
+	}
+}
@@ -0,0 +1,277 @@
+package folder
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/project/backend/adapters/textdiff"
+	"github.com/project/backend/domain/entities"
+)
+
+func revisionIDs(revisions []entities.LessonRevision) []int {
+	ids := make([]int, len(revisions))
+	for i, revision := range revisions {
+		ids[i] = revision.ID
+	}
+	return ids
+}
+
+func TestFolderCourseRepository_LessonHistory(t *testing.T) {
+	root := t.TempDir()
+	writeCourseFiles(t, root, map[string]string{
+		"go-basics/course.json":                                     `{"id": "go-basics", "title": "Go Basics"}`,
+		"go-basics/lessons/00-intro/content.md":                     "Intro\nGo is fun\n",
+		"go-basics/lessons/00-intro/sublessons/00-setup/content.md": "Setup",
+	})
+	repo := NewFolderCourseRepository(root)
+	ctx := context.Background()
+
+	// The first save records the content the lesson had before as revision 1
+	revision, err := repo.UpdateLessonContent(ctx, "go-basics", []int{0}, "Intro\nGo is simple\n", "author-1", "Reword")
+	if err != nil {
+		t.Fatalf("failed to update lesson: %v", err)
+	}
+	if revision.ID != 2 || revision.AuthorID != "author-1" || revision.Message != "Reword" || revision.CreatedAt.IsZero() {
+		t.Errorf("expected revision 2 by author-1, got %+v", revision)
+	}
+	initial, err := repo.GetLessonRevision(ctx, "go-basics", []int{0}, 1)
+	if err != nil {
+		t.Fatalf("failed to get revision: %v", err)
+	}
+	if initial.Content != "Intro\nGo is fun\n" || initial.AuthorID != "" {
+		t.Errorf("expected the original content as revision 1, got %+v", initial)
+	}
+
+	if revision, err = repo.UpdateLessonContent(ctx, "go-basics", []int{0}, "Intro\nGo is simple\nand fast\n", "author-2", "Expand"); err != nil {
+		t.Fatalf("failed to update lesson: %v", err)
+	}
+	if revision.ID != 3 {
+		t.Errorf("expected revision 3, got %d", revision.ID)
+	}
+
+	revisions, err := repo.ListLessonRevisions(ctx, "go-basics", []int{0})
+	if err != nil {
+		t.Fatalf("failed to list revisions: %v", err)
+	}
+	if ids := revisionIDs(revisions); len(ids) != 3 || ids[0] != 3 || ids[1] != 2 || ids[2] != 1 {
+		t.Errorf("expected revisions 3, 2, 1, got %v", ids)
+	}
+	if revisions[0].Content != "Intro\nGo is simple\nand fast\n" || revisions[1].Message != "Reword" {
+		t.Errorf("expected each revision's content and message, got %+v", revisions)
+	}
+
+	expected := "--- revision 1\n+++ revision 3\n@@ -1,2 +1,3 @@\n Intro\n-Go is fun\n+Go is simple\n+and fast\n"
+	if diff := textdiff.Unified("revision 1", "revision 3", []byte(revisions[2].Content), []byte(revisions[0].Content)); diff != expected {
+		t.Errorf("unexpected diff between revisions:\n%s", diff)
+	}
+
+	// Restoring records the old content as a new revision, leaving the history as it was
+	restored, err := repo.RestoreLessonRevision(ctx, "go-basics", []int{0}, 1, "author-1", "")
+	if err != nil {
+		t.Fatalf("failed to restore revision: %v", err)
+	}
+	if restored.ID != 4 || restored.Content != initial.Content || restored.Message != "Restore revision 1" {
+		t.Errorf("expected revision 4 restoring revision 1, got %+v", restored)
+	}
+	if content := readCourseFile(t, root, "go-basics/lessons/00-intro/content.md"); content != initial.Content {
+		t.Errorf("expected the restored content in content.md, got %q", content)
+	}
+	course, err := repo.GetByID(ctx, "go-basics")
+	if err != nil {
+		t.Fatalf("failed to get course: %v", err)
+	}
+	if course.Lessons[0].Content != initial.Content {
+		t.Errorf("expected the course to be reloaded, got %q", course.Lessons[0].Content)
+	}
+	if unchanged, _ := repo.GetLessonRevision(ctx, "go-basics", []int{0}, 3); unchanged.Content != revisions[0].Content {
+		t.Errorf("expected revision 3 to be left alone, got %+v", unchanged)
+	}
+
+	// Sublessons keep their own history
+	if revision, err = repo.UpdateLessonContent(ctx, "go-basics", []int{0, 0}, "Install Go", "author-1", "Setup"); err != nil {
+		t.Fatalf("failed to update sublesson: %v", err)
+	}
+	if revision.ID != 2 {
+		t.Errorf("expected the sublesson's own numbering, got revision %d", revision.ID)
+	}
+
+	if _, err := repo.GetLessonRevision(ctx, "go-basics", []int{0}, 99); !errors.Is(err, entities.ErrLessonRevisionNotFound) {
+		t.Errorf("expected ErrLessonRevisionNotFound, got %v", err)
+	}
+	if _, err := repo.RestoreLessonRevision(ctx, "go-basics", []int{0}, 99, "author-1", ""); !errors.Is(err, entities.ErrLessonRevisionNotFound) {
+		t.Errorf("expected ErrLessonRevisionNotFound, got %v", err)
+	}
+	if _, err := repo.ListLessonRevisions(ctx, "go-basics", []int{5}); err == nil {
+		t.Error("expected an error for a missing lesson")
+	}
+}
+
+func TestFolderCourseRepository_LessonHistory_NewLesson(t *testing.T) {
+	root := t.TempDir()
+	writeCourseFiles(t, root, map[string]string{
+		"go-basics/course.json":                  `{"id": "go-basics", "title": "Go Basics"}`,
+		"go-basics/lessons/00-intro/lesson.json": `{"title": "Introduction"}`,
+	})
+	repo := NewFolderCourseRepository(root)
+	ctx := context.Background()
+
+	revisions, err := repo.ListLessonRevisions(ctx, "go-basics", []int{0})
+	if err != nil || len(revisions) != 0 {
+		t.Errorf("expected an empty history, got %v, %v", revisions, err)
+	}
+
+	// Without earlier content, the first save is revision 1
+	revision, err := repo.UpdateLessonContent(ctx, "go-basics", []int{0}, "Intro", "author-1", "Write")
+	if err != nil {
+		t.Fatalf("failed to update lesson: %v", err)
+	}
+	if revision.ID != 1 {
+		t.Errorf("expected revision 1, got %d", revision.ID)
+	}
+
+	// A save whose content cannot be written leaves no revision behind
+	contentPath := filepath.Join(root, "go-basics", "lessons", "00-intro", "content.md")
+	if err := os.Remove(contentPath); err != nil {
+		t.Fatalf("failed to remove content: %v", err)
+	}
+	writeCourseFiles(t, root, map[string]string{"go-basics/lessons/00-intro/content.md/blocked": ""})
+	if _, err := repo.UpdateLessonContent(ctx, "go-basics", []int{0}, "Lost", "author-1", "Fail"); err == nil {
+		t.Fatal("expected the save to fail")
+	}
+	revisions, err = repo.ListLessonRevisions(ctx, "go-basics", []int{0})
+	if err != nil {
+		t.Fatalf("failed to list revisions: %v", err)
+	}
+	if ids := revisionIDs(revisions); len(ids) != 1 || ids[0] != 1 {
+		t.Errorf("expected only revision 1, got %v", ids)
+	}
+}
+
+func TestFolderCourseRepository_LessonHistory_MovedLesson(t *testing.T) {
+	root := t.TempDir()
+	writeCourseFiles(t, root, map[string]string{
+		"go-basics/course.json":                 `{"id": "go-basics", "title": "Go Basics"}`,
+		"go-basics/lessons/00-intro/content.md": "Intro",
+		"go-basics/lessons/01-types/content.md": "Types",
+		"go-basics/lessons/02-funcs/content.md": "Funcs",
+	})
+	repo := NewFolderCourseRepository(root)
+	ctx := context.Background()
+
+	if _, err := repo.UpdateLessonContent(ctx, "go-basics", []int{1}, "Types and values", "author-1", "Expand"); err != nil {
+		t.Fatalf("failed to update lesson: %v", err)
+	}
+	history := func(index int) []entities.LessonRevision {
+		t.Helper()
+		revisions, err := repo.ListLessonRevisions(ctx, "go-basics", []int{index})
+		if err != nil {
+			t.Fatalf("failed to list revisions: %v", err)
+		}
+		return revisions
+	}
+	update := func(order ...int) []entities.Lesson {
+		t.Helper()
+		course, err := repo.GetByID(ctx, "go-basics")
+		if err != nil {
+			t.Fatalf("failed to get course: %v", err)
+		}
+		changed := *course
+		changed.Lessons = nil
+		for _, index := range order {
+			changed.Lessons = append(changed.Lessons, course.Lessons[index])
+		}
+		updated, err := repo.Update(ctx, &changed)
+		if err != nil {
+			t.Fatalf("failed to update course: %v", err)
+		}
+		return updated.Lessons
+	}
+
+	// Deleting the lesson before it leaves the history with its lesson, now the first
+	lessons := update(1, 2)
+	if lessons[0].Content != "Types and values" {
+		t.Fatalf("expected the edited lesson first, got %+v", lessons)
+	}
+	if revisions := history(0); len(revisions) != 2 || revisions[0].Content != "Types and values" || revisions[1].Content != "Types" {
+		t.Errorf("expected the lesson's history after the delete, got %+v", revisions)
+	}
+	if revisions := history(1); len(revisions) != 0 {
+		t.Errorf("expected the other lesson to have no history, got %+v", revisions)
+	}
+
+	// Moving the lesson renames its folder, and the history moves with it
+	lessons = update(1, 0)
+	if lessons[1].Content != "Types and values" {
+		t.Fatalf("expected the edited lesson second, got %+v", lessons)
+	}
+	if revisions := history(1); len(revisions) != 2 {
+		t.Errorf("expected the lesson's history after the move, got %+v", revisions)
+	}
+	if revisions := history(0); len(revisions) != 0 {
+		t.Errorf("expected the other lesson to have no history, got %+v", revisions)
+	}
+
+	// Numbering carries on in the moved folder
+	revision, err := repo.UpdateLessonContent(ctx, "go-basics", []int{1}, "Types, values and zero values", "author-1", "Zero values")
+	if err != nil {
+		t.Fatalf("failed to update lesson: %v", err)
+	}
+	if revision.ID != 3 {
+		t.Errorf("expected revision 3, got %d", revision.ID)
+	}
+}
+
+func TestFolderCourseRepository_SaveLessonQuiz(t *testing.T) {
+	root := t.TempDir()
+	writeCourseFiles(t, root, map[string]string{
+		"go-basics/course.json":                 `{"id": "go-basics", "title": "Go Basics"}`,
+		"go-basics/lessons/00-intro/content.md": "Intro",
+		"go-basics/lessons/00-intro/quiz.json": `{"version": "1.0", "questions": [
+  {"id": "q1", "type": "true_false", "difficulty": 1, "question": "Old?", "correctAnswer": true}
+]}`,
+	})
+	repo := NewFolderCourseRepository(root)
+	ctx := context.Background()
+
+	truth := false
+	quiz := &entities.ExtendedQuiz{
+		Version: "1.0",
+		Questions: []entities.ExtendedQuizQuestion{
+			{ID: "q1", Type: entities.QuestionTypeTrueFalse, Difficulty: 2, Question: "New?", CorrectAnswer: &truth},
+		},
+	}
+	if err := repo.SaveLessonQuiz(ctx, "go-basics", []int{0}, quiz); err != nil {
+		t.Fatalf("failed to save quiz: %v", err)
+	}
+
+	course, err := repo.GetByID(ctx, "go-basics")
+	if err != nil {
+		t.Fatalf("failed to get course: %v", err)
+	}
+	saved := course.Lessons[0].ExtendedQuiz
+	if saved == nil || len(saved.Questions) != 1 || saved.Questions[0].Question != "New?" {
+		t.Errorf("expected the saved quiz to be loaded, got %+v", saved)
+	}
+
+	lessonFolder := filepath.Join(root, "go-basics", "lessons", "00-intro")
+	entries, err := os.ReadDir(lessonFolder)
+	if err != nil {
+		t.Fatalf("failed to read lesson folder: %v", err)
+	}
+	for _, entry := range entries {
+		if name := entry.Name(); name != "content.md" && name != "quiz.json" {
+			t.Errorf("expected no backup or temporary files, found %s", name)
+		}
+	}
+	if strings.Contains(readCourseFile(t, root, "go-basics/lessons/00-intro/quiz.json"), "Old?") {
+		t.Error("expected the old quiz to be replaced")
+	}
+
+	if err := repo.SaveLessonQuiz(ctx, "go-basics", []int{1}, quiz); !errors.Is(err, entities.ErrInvalidLessonIndex) {
+		t.Errorf("expected ErrInvalidLessonIndex, got %v", err)
+	}
+}
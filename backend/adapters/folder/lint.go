@@ -0,0 +1,343 @@
+package folder
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/project/backend/domain/entities"
+)
+
+// LintProblem is one problem found in the course folders
+type LintProblem struct {
+	Path    string
+	Line    int // Position in the file; 0 when the problem is with a whole file or folder
+	Column  int
+	Message string
+}
+
+func (p LintProblem) String() string {
+	if p.Line == 0 {
+		return fmt.Sprintf("%s: %s", p.Path, p.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", p.Path, p.Line, p.Column, p.Message)
+}
+
+// LintCourses checks every course folder and question bank under coursesPath, reporting
+// what the loader would skip with a warning or load without complaint, such as lessons
+// without content or quiz questions whose answer key points past their options
+func LintCourses(coursesPath string) ([]LintProblem, error) {
+	entries, err := os.ReadDir(coursesPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read courses directory: %w", err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	l := &linter{banks: make(map[string]*entities.QuestionBank)}
+	l.lintQuestionBanks(filepath.Join(coursesPath, questionBanksDir))
+
+	courseIDs := make(map[string]string)
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == "COURSE-TEMPLATE" || entry.Name() == questionBanksDir || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		coursePath := filepath.Join(coursesPath, entry.Name())
+		id, data := l.lintCourse(coursePath)
+		if id == "" {
+			continue
+		}
+		if other, ok := courseIDs[id]; ok {
+			l.add(filepath.Join(coursePath, "course.json"), data, valueOffset(data, "id"), "course ID %s is already used by %s", id, other)
+			continue
+		}
+		courseIDs[id] = entry.Name()
+	}
+
+	return l.problems, nil
+}
+
+// linter collects the problems found while checking the course folders
+type linter struct {
+	problems []LintProblem
+	banks    map[string]*entities.QuestionBank // Banks that loaded, for checking bank references
+}
+
+// add records a problem at a byte offset in a file's data; a negative offset points at
+// no particular place
+func (l *linter) add(path string, data []byte, offset int, format string, args ...any) {
+	problem := LintProblem{Path: path, Message: fmt.Sprintf(format, args...)}
+	if offset >= 0 && offset <= len(data) {
+		problem.Line, problem.Column = lineColumn(data, offset)
+	}
+	l.problems = append(l.problems, problem)
+}
+
+// readJSON reads and parses a JSON file, reporting where it is malformed
+// A missing file is reported only if it is required; ok is false if v was not filled
+func (l *linter) readJSON(path string, v any, required bool) (data []byte, ok bool) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !required {
+		return nil, false
+	}
+	if err != nil {
+		l.add(path, nil, -1, "%v", err)
+		return nil, false
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &syntaxErr):
+			l.add(path, data, max(int(syntaxErr.Offset)-1, 0), "malformed JSON: %v", err)
+		case errors.As(err, &typeErr):
+			l.add(path, data, max(int(typeErr.Offset)-1, 0), "%s must be %s, not %s", typeErr.Field, typeErr.Type, typeErr.Value)
+		default:
+			l.add(path, data, -1, "%v", err)
+		}
+		return data, false
+	}
+	return data, true
+}
+
+// lintCourse checks a course folder and returns its course ID and course.json, or "" if
+// the course.json cannot be read
+func (l *linter) lintCourse(coursePath string) (string, []byte) {
+	courseJSONPath := filepath.Join(coursePath, "course.json")
+	var cj courseJSON
+	data, ok := l.readJSON(courseJSONPath, &cj, true)
+	if !ok {
+		return "", nil
+	}
+
+	// Difficulty lives under metadata in the newer format
+	difficulty, difficultyOffset := cj.Metadata.Difficulty, valueOffset(data, "metadata", "difficulty")
+	if difficulty == "" {
+		difficulty, difficultyOffset = cj.Difficulty, valueOffset(data, "difficulty")
+	}
+	switch strings.ToLower(difficulty) {
+	case "", "beginner", "intermediate", "advanced":
+	default:
+		l.add(courseJSONPath, data, difficultyOffset, "unknown difficulty %q, expected beginner, intermediate or advanced", difficulty)
+	}
+
+	if len(cj.QuizConfig) > 0 {
+		if _, err := parseQuizConfig(cj.QuizConfig); err != nil {
+			l.add(courseJSONPath, data, valueOffset(data, "quiz_config"), "invalid quiz_config: %v", err)
+		}
+	}
+
+	l.lintLessons(filepath.Join(coursePath, "lessons"), false)
+
+	courseID := cj.ID
+	if courseID == "" || courseID == "GENERATE-UUID" {
+		courseID = courseIDForFolder(filepath.Base(coursePath))
+	}
+	return courseID, data
+}
+
+// lintLessons checks the lesson folders in a lessons or sublessons folder
+func (l *linter) lintLessons(dir string, sublessons bool) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	if err != nil {
+		l.add(dir, nil, -1, "%v", err)
+		return
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	// Folders are ordered by name, so two with the same number leave their order to the rest
+	// of the name
+	prefixes := make(map[int]string)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		lessonPath := filepath.Join(dir, entry.Name())
+
+		var order int
+		if _, err := fmt.Sscanf(entry.Name(), "%d-", &order); err == nil {
+			if other, ok := prefixes[order]; ok {
+				l.add(lessonPath, nil, -1, "order prefix %d is also used by %s", order, other)
+			} else {
+				prefixes[order] = entry.Name()
+			}
+		}
+
+		l.lintLesson(lessonPath, sublessons)
+	}
+}
+
+// lintLesson checks a lesson folder's lesson.json, content and quiz, and its sublessons
+func (l *linter) lintLesson(lessonPath string, sublesson bool) {
+	var lj lessonJSON
+	l.readJSON(filepath.Join(lessonPath, "lesson.json"), &lj, false)
+
+	// Chapters may leave their content to their sublessons
+	hasSublessons := false
+	if !sublesson {
+		entries, _ := os.ReadDir(filepath.Join(lessonPath, "sublessons"))
+		for _, entry := range entries {
+			hasSublessons = hasSublessons || entry.IsDir()
+		}
+	}
+	contentPath := filepath.Join(lessonPath, "content.md")
+	content, err := os.ReadFile(contentPath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		if !hasSublessons {
+			l.add(lessonPath, nil, -1, "lesson has no content.md")
+		}
+	case err != nil:
+		l.add(contentPath, nil, -1, "%v", err)
+	case len(bytes.TrimSpace(content)) == 0 && !hasSublessons:
+		l.add(contentPath, nil, -1, "content is empty")
+	}
+
+	quizPath := filepath.Join(lessonPath, "quiz.json")
+	var keys map[string]json.RawMessage
+	if data, ok := l.readJSON(quizPath, &keys, false); ok {
+		l.lintQuiz(quizPath, data)
+	}
+
+	if !sublesson {
+		l.lintLessons(filepath.Join(lessonPath, "sublessons"), true)
+	}
+}
+
+// lintQuiz checks a quiz.json with the loader's parser, reporting the parts it would skip,
+// and checks its bank references
+func (l *linter) lintQuiz(quizPath string, data []byte) {
+	// Decoding into the format's own structure first reports wrongly typed values with
+	// their position
+	var target any = &extendedQuizJSON{}
+	if legacy, _ := isLegacyQuiz(data); legacy {
+		target = &quizJSON{}
+	}
+	if _, ok := l.readJSON(quizPath, target, true); !ok {
+		return
+	}
+
+	quiz, problems, err := parseQuiz(filepath.Dir(quizPath), data)
+	if err != nil {
+		l.add(quizPath, data, -1, "%v", err)
+		return
+	}
+	l.addProblems(quizPath, data, problems)
+
+	refOffsets := elementOffsets(data, "bankRefs")
+	for i, ref := range quiz.BankRefs {
+		refQuiz := &entities.ExtendedQuiz{BankRefs: []entities.QuestionBankRef{ref}}
+		if err := refQuiz.AddBankQuestions(l.banks, ""); err != nil {
+			l.add(quizPath, data, offsetAt(refOffsets, i), "%v", err)
+		}
+	}
+}
+
+// addProblems records the problems the parser found in a file, at the value at fault
+func (l *linter) addProblems(path string, data []byte, problems []quizProblem) {
+	for _, problem := range problems {
+		offset := valueOffset(data, problem.key)
+		if problem.index >= 0 {
+			offset = offsetAt(elementOffsets(data, problem.key), problem.index)
+		}
+		l.add(path, data, offset, "%v", problem.err)
+	}
+}
+
+// lintQuestionBanks checks every bank under banksPath and keeps those that load, so quizzes
+// can be checked against them
+func (l *linter) lintQuestionBanks(banksPath string) {
+	entries, err := os.ReadDir(banksPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	if err != nil {
+		l.add(banksPath, nil, -1, "%v", err)
+		return
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		bankPath := filepath.Join(banksPath, entry.Name())
+		if !entities.IsValidBankID(entry.Name()) {
+			l.add(bankPath, nil, -1, "folder name is not a valid bank ID")
+			continue
+		}
+
+		bankFile := filepath.Join(bankPath, "bank.json")
+		var bj bankJSON
+		data, ok := l.readJSON(bankFile, &bj, true)
+		if !ok {
+			continue
+		}
+
+		questions, problems := parseQuestions(bankPath, bj.Questions)
+		l.addProblems(bankFile, data, problems)
+		bank := &entities.QuestionBank{ID: entry.Name(), Title: bj.Title, Questions: questions}
+		l.banks[bank.ID] = bank
+	}
+}
+
+// valueOffset returns where the value at a path of object keys starts in data, or -1 if
+// there is no such value
+func valueOffset(data []byte, keys ...string) int {
+	offset := len(data) - len(bytes.TrimLeft(data, " \t\r\n"))
+	for _, key := range keys {
+		obj, err := parseJSONObject(data[offset:])
+		if err != nil {
+			return -1
+		}
+		span, ok := obj.spans[key]
+		if !ok {
+			return -1
+		}
+		offset += span[0]
+	}
+	return offset
+}
+
+// elementOffsets returns where each element of the array under a top-level key starts
+func elementOffsets(data []byte, key string) []int {
+	start := valueOffset(data, key)
+	if start < 0 {
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data[start:]))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		return nil
+	}
+	var offsets []int
+	for dec.More() {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			break
+		}
+		offsets = append(offsets, start+int(dec.InputOffset())-len(raw))
+	}
+	return offsets
+}
+
+func offsetAt(offsets []int, i int) int {
+	if i < len(offsets) {
+		return offsets[i]
+	}
+	return -1
+}
+
+// lineColumn returns the 1-based line and column of a byte offset
+func lineColumn(data []byte, offset int) (int, int) {
+	before := data[:offset]
+	return bytes.Count(before, []byte("\n")) + 1, offset - bytes.LastIndexByte(before, '\n')
+}
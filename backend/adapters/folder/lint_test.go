@@ -0,0 +1,207 @@
+package folder
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const lintCourseJSON = `{"id": "go-basics", "title": "Go Basics"}`
+
+func TestLintCourses(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []string // Problems as "path:line:column: message" prefixes, in order
+	}{
+		{
+			name: "valid course",
+			files: map[string]string{
+				"go-basics/course.json":                                   `{"id": "go-basics", "title": "Go Basics", "metadata": {"difficulty": "Beginner"}}`,
+				"go-basics/lessons/00-intro/lesson.json":                  `{"title": "Introduction", "order": 0}`,
+				"go-basics/lessons/00-intro/content.md":                   "Intro",
+				"go-basics/lessons/01-chapter/sublessons/00-a/content.md": "A",
+				"go-basics/lessons/00-intro/quiz.json": `{"version": "1.0", "questions": [
+  {"id": "q1", "type": "true_false", "difficulty": 1, "question": "True?", "correctAnswer": true}
+]}`,
+			},
+		},
+		{
+			name: "malformed course.json",
+			files: map[string]string{
+				"go-basics/course.json": "{\n  \"id\": \"go-basics\",\n  \"title\": \"Go Basics\"\n  \"tags\": []\n}",
+			},
+			want: []string{"go-basics/course.json:4:3: malformed JSON"},
+		},
+		{
+			name: "unknown difficulty",
+			files: map[string]string{
+				"go-basics/course.json":                 "{\n  \"id\": \"go-basics\",\n  \"difficulty\": \"expert\"\n}",
+				"go-basics/lessons/00-intro/content.md": "Intro",
+			},
+			want: []string{`go-basics/course.json:3:17: unknown difficulty "expert"`},
+		},
+		{
+			name: "lesson folders",
+			files: map[string]string{
+				"go-basics/course.json":                   lintCourseJSON,
+				"go-basics/lessons/00-intro/content.md":   "Intro",
+				"go-basics/lessons/00-welcome/content.md": " \n",
+				"go-basics/lessons/01-types/lesson.json":  `{"title": "Types", "order": "one"}`,
+			},
+			want: []string{
+				"go-basics/lessons/00-welcome: order prefix 0 is also used by 00-intro",
+				"go-basics/lessons/00-welcome/content.md: content is empty",
+				"go-basics/lessons/01-types/lesson.json:1:33: order must be int, not string",
+				"go-basics/lessons/01-types: lesson has no content.md",
+			},
+		},
+		{
+			name: "invalid questions",
+			files: map[string]string{
+				"go-basics/course.json":                 lintCourseJSON,
+				"go-basics/lessons/00-intro/content.md": "Intro",
+				"go-basics/lessons/00-intro/quiz.json": `{"version": "1.0", "questions": [
+  {"id": "q1", "type": "multiple_choice", "difficulty": 2, "question": "Pick", "options": ["a", "b"], "correctIndex": 2},
+  {"id": "q2", "type": "matching", "difficulty": 3, "question": "Match", "leftColumn": ["a", "b"], "rightColumn": ["x", "y"], "correctPairs": [[0, 1], [1, 1]]},
+  {"id": "q3", "type": "ordering", "difficulty": 4, "question": "Order", "items": ["a", "b"], "correctOrder": [1, 1]},
+  {"id": "q4", "type": "true_false", "difficulty": 1, "question": "True?", "correctAnswer": true},
+  {"id": "q4", "type": "true_false", "difficulty": 1, "question": "Again?", "correctAnswer": false},
+  {"id": "q5", "type": "code_exercise", "difficulty": 4, "question": "Write Add", "testFile": "add_test.go"}
+], "exam": {"scoringPolicy": "worst"}}`,
+			},
+			want: []string{
+				"go-basics/lessons/00-intro/quiz.json:2:3: invalid quiz question q1: correctIndex 2 is not an option",
+				"go-basics/lessons/00-intro/quiz.json:3:3: invalid quiz question q2: right entry 1 is paired twice",
+				"go-basics/lessons/00-intro/quiz.json:4:3: invalid quiz question q3",
+				"go-basics/lessons/00-intro/quiz.json:6:3: invalid quiz question q4: id is used by another question",
+				"go-basics/lessons/00-intro/quiz.json:7:3: question q5",
+				"go-basics/lessons/00-intro/quiz.json:8:12: invalid exam settings",
+			},
+		},
+		{
+			name: "malformed quiz.json",
+			files: map[string]string{
+				"go-basics/course.json":                                 lintCourseJSON,
+				"go-basics/lessons/00-intro/content.md":                 "Intro",
+				"go-basics/lessons/00-intro/quiz.json":                  "{\"questions\": [\n  {\"id\": \"q1\", \"difficulty\": \"easy\"}\n]}",
+				"go-basics/lessons/00-intro/sublessons/00-a/content.md": "A",
+				"go-basics/lessons/00-intro/sublessons/00-a/quiz.json":  `{}`,
+			},
+			want: []string{
+				"go-basics/lessons/00-intro/quiz.json:2:35: questions.0.difficulty must be int, not string",
+				"go-basics/lessons/00-intro/sublessons/00-a/quiz.json: quiz has no questions",
+			},
+		},
+		{
+			name: "legacy quiz",
+			files: map[string]string{
+				"go-basics/course.json":                 lintCourseJSON,
+				"go-basics/lessons/00-intro/content.md": "Intro",
+				"go-basics/lessons/00-intro/quiz.json": `{"Questions": [
+  {"ID": "q1", "Question": "Pick", "Options": ["a", "b"], "CorrectIndex": 1},
+  {"ID": "q2", "Type": "essay", "Question": "Discuss"},
+  {"ID": "q3", "Question": "Pick", "Options": ["a", "b"], "CorrectIndex": 5}
+]}`,
+			},
+			want: []string{
+				`go-basics/lessons/00-intro/quiz.json:3:3: question q2: unknown type "essay"`,
+				"go-basics/lessons/00-intro/quiz.json:4:3: invalid quiz question q3: correctIndex 5 is not an option",
+			},
+		},
+		{
+			name: "question banks",
+			files: map[string]string{
+				"question-banks/Bad Bank/bank.json": `{"questions": []}`,
+				"question-banks/go/bank.json": `{"title": "Go", "questions": [
+  {"id": "b1", "type": "true_false", "difficulty": 1, "question": "True?", "correctAnswer": true},
+  {"id": "b2", "type": "true_false", "difficulty": 9, "question": "Hard?", "correctAnswer": true}
+]}`,
+				"go-basics/course.json":                 lintCourseJSON,
+				"go-basics/lessons/00-intro/content.md": "Intro",
+				"go-basics/lessons/00-intro/quiz.json": `{"version": "1.0", "bankRefs": [
+  {"bank": "go", "questions": ["b1"]},
+  {"bank": "go", "questions": ["b2"]},
+  {"bank": "rust"}
+]}`,
+			},
+			want: []string{
+				"question-banks/Bad Bank: folder name is not a valid bank ID",
+				"question-banks/go/bank.json:3:3: invalid quiz question b2: difficulty must be between 1 and 5",
+				"go-basics/lessons/00-intro/quiz.json:3:3: ",
+				"go-basics/lessons/00-intro/quiz.json:4:3: ",
+			},
+		},
+		{
+			name: "duplicate course IDs",
+			files: map[string]string{
+				"a-go/course.json":                 lintCourseJSON,
+				"a-go/lessons/00-intro/content.md": "Intro",
+				"b-go/course.json":                 "{\n  \"id\": \"go-basics\"\n}",
+				"b-go/lessons/00-intro/content.md": "Intro",
+			},
+			want: []string{"b-go/course.json:2:9: course ID go-basics is already used by a-go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeCourseFiles(t, root, tt.files)
+
+			problems, err := LintCourses(root)
+			if err != nil {
+				t.Fatalf("LintCourses failed: %v", err)
+			}
+
+			var got []string
+			for _, problem := range problems {
+				rel, _ := filepath.Rel(root, problem.Path)
+				problem.Path = filepath.ToSlash(rel)
+				got = append(got, problem.String())
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %d problems, got %d:\n%s", len(tt.want), len(got), strings.Join(got, "\n"))
+			}
+			for i, want := range tt.want {
+				if !strings.HasPrefix(got[i], want) {
+					t.Errorf("expected problem %q, got %q", want, got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLintCourses_MatchesLoader(t *testing.T) {
+	// The linter reports exactly the questions the loader leaves out
+	root := t.TempDir()
+	writeCourseFiles(t, root, map[string]string{
+		"go-basics/course.json":                 lintCourseJSON,
+		"go-basics/lessons/00-intro/content.md": "Intro",
+		"go-basics/lessons/00-intro/quiz.json": `{"version": "1.0", "questions": [
+  {"id": "q1", "type": "true_false", "difficulty": 1, "question": "True?", "correctAnswer": true},
+  {"id": "q2", "type": "multiple_choice", "difficulty": 2, "question": "Pick", "options": ["a", "b"], "correctIndex": 2},
+  {"id": "q1", "type": "true_false", "difficulty": 1, "question": "Again?", "correctAnswer": false},
+  {"id": "q3", "type": "numeric", "difficulty": 2, "question": "How many?", "correctValue": 3}
+]}`,
+		"go-basics/lessons/01-types/content.md": "Types",
+		"go-basics/lessons/01-types/quiz.json":  `{"questions": [}`,
+	})
+
+	problems, err := LintCourses(root)
+	if err != nil {
+		t.Fatalf("LintCourses failed: %v", err)
+	}
+	if len(problems) != 3 {
+		t.Fatalf("expected 3 problems, got %v", problems)
+	}
+
+	course := loadTestCourse(t, root)
+	quiz := course.Lessons[0].ExtendedQuiz
+	if quiz == nil || len(quiz.Questions) != 2 || quiz.Questions[0].ID != "q1" || quiz.Questions[1].ID != "q3" {
+		t.Errorf("expected the loader to keep q1 and q3, got %+v", quiz)
+	}
+	if course.Lessons[1].ExtendedQuiz != nil {
+		t.Error("expected the malformed quiz to be left out")
+	}
+}
@@ -0,0 +1,138 @@
+package folder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/project/backend/domain/entities"
+)
+
+// questionBanksDir is the folder, next to the courses, holding one folder per question bank
+const questionBanksDir = "question-banks"
+
+// bankJSON represents the structure of a bank.json file
+type bankJSON struct {
+	Title       string                     `json:"title"`
+	Description string                     `json:"description"`
+	Questions   []extendedQuizQuestionJSON `json:"questions"`
+}
+
+// loadQuestionBanks loads every bank under banksPath, keyed by ID
+// A missing folder means there are no banks; banks that cannot be read are skipped
+func loadQuestionBanks(banksPath string) (map[string]*entities.QuestionBank, error) {
+	banks := make(map[string]*entities.QuestionBank)
+
+	entries, err := os.ReadDir(banksPath)
+	if os.IsNotExist(err) {
+		return banks, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read question banks directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if !entities.IsValidBankID(entry.Name()) {
+			slog.Warn("Skipping question bank folder without a valid bank ID", "bank", entry.Name())
+			continue
+		}
+
+		bank, err := loadQuestionBank(filepath.Join(banksPath, entry.Name()))
+		if err != nil {
+			slog.Warn("Failed to load question bank", "bank", entry.Name(), "error", err)
+			continue
+		}
+		banks[bank.ID] = bank
+	}
+
+	return banks, nil
+}
+
+// loadQuestionBank loads a bank from its folder, whose name is the bank's ID
+// Invalid or duplicate questions are skipped with a warning
+func loadQuestionBank(bankPath string) (*entities.QuestionBank, error) {
+	bankFile := filepath.Join(bankPath, "bank.json")
+	data, err := os.ReadFile(bankFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bank.json: %w", err)
+	}
+
+	var bj bankJSON
+	if err := json.Unmarshal(data, &bj); err != nil {
+		return nil, fmt.Errorf("failed to parse bank.json: %w", err)
+	}
+
+	questions, problems := parseQuestions(bankPath, bj.Questions)
+	warnProblems(slog.With("bank", filepath.Base(bankPath)), problems)
+
+	return &entities.QuestionBank{
+		ID:          filepath.Base(bankPath),
+		Title:       bj.Title,
+		Description: bj.Description,
+		AuthorID:    entities.FolderAuthorID,
+		Questions:   questions,
+	}, nil
+}
+
+// addBankQuestions resolves the bank references of every quiz in a course
+// Draws are salted with the course's folder name and quiz ID, which stay the same across
+// reloads even for courses without a fixed ID
+func addBankQuestions(course *entities.LibraryCourse, banks map[string]*entities.QuestionBank, folderName string) {
+	resolve := func(quiz *entities.ExtendedQuiz, lessonPath []int) {
+		if quiz == nil || len(quiz.BankRefs) == 0 {
+			return
+		}
+		quizID := entities.QuizIDForLessonPath(lessonPath)
+		if err := quiz.AddBankQuestions(banks, folderName+"/"+quizID); err != nil {
+			slog.Warn("Unresolved bank questions", "folder", folderName, "quiz", quizID, "error", err)
+		}
+	}
+
+	for i := range course.Lessons {
+		lesson := &course.Lessons[i]
+		resolve(lesson.ExtendedQuiz, []int{i})
+		for j := range lesson.Sublessons {
+			resolve(lesson.Sublessons[j].ExtendedQuiz, []int{i, j})
+		}
+	}
+}
+
+// ListQuestionBanks returns every question bank, ordered by ID
+func (r *FolderCourseRepository) ListQuestionBanks(ctx context.Context) ([]*entities.QuestionBank, error) {
+	if err := r.loadCourses(ctx); err != nil {
+		return nil, err
+	}
+
+	r.cacheMu.RLock()
+	defer r.cacheMu.RUnlock()
+
+	banks := make([]*entities.QuestionBank, 0, len(r.banks))
+	for _, bank := range r.banks {
+		banks = append(banks, bank)
+	}
+	sort.Slice(banks, func(i, j int) bool { return banks[i].ID < banks[j].ID })
+	return banks, nil
+}
+
+// GetQuestionBank returns a question bank by ID
+func (r *FolderCourseRepository) GetQuestionBank(ctx context.Context, id string) (*entities.QuestionBank, error) {
+	if err := r.loadCourses(ctx); err != nil {
+		return nil, err
+	}
+
+	r.cacheMu.RLock()
+	defer r.cacheMu.RUnlock()
+
+	bank, ok := r.banks[id]
+	if !ok {
+		return nil, entities.ErrQuestionBankNotFound
+	}
+	return bank, nil
+}
@@ -0,0 +1,144 @@
+package folder
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/project/backend/domain/entities"
+)
+
+// QuizMigration is the result of converting one legacy quiz.json to the extended format
+type QuizMigration struct {
+	Path     string
+	Original []byte
+	Migrated []byte   // Nil when the quiz could not be converted
+	Problems []string // Why questions could not be converted; the file is then left as it is
+}
+
+// MigrateLegacyQuizzes converts every legacy quiz.json under coursesPath to the extended
+// format, keeping the original as quiz.json.bak; with dryRun nothing is written
+// A quiz with any question that cannot be converted is left untouched, so no question is lost
+func MigrateLegacyQuizzes(coursesPath string, dryRun bool) ([]QuizMigration, error) {
+	var migrations []QuizMigration
+	err := filepath.WalkDir(coursesPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || d.Name() != "quiz.json" {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		legacy, err := isLegacyQuiz(data)
+		if err != nil {
+			migrations = append(migrations, QuizMigration{Path: path, Original: data, Problems: []string{err.Error()}})
+			return nil
+		}
+		if !legacy {
+			return nil
+		}
+
+		migration := migrateLegacyQuiz(path, data)
+		if !dryRun && migration.Migrated != nil {
+			if err := os.WriteFile(path+".bak", data, 0644); err != nil {
+				return fmt.Errorf("failed to create backup: %w", err)
+			}
+			if err := os.WriteFile(path, migration.Migrated, 0644); err != nil {
+				return fmt.Errorf("failed to write quiz: %w", err)
+			}
+		}
+		migrations = append(migrations, migration)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate quizzes: %w", err)
+	}
+
+	return migrations, nil
+}
+
+// migrateLegacyQuiz converts a legacy quiz.json, inferring question types, difficulties and
+// the lesson it belongs to, and checking every question as the authoring mutations do
+func migrateLegacyQuiz(path string, data []byte) QuizMigration {
+	migration := QuizMigration{Path: path, Original: data}
+
+	var qj quizJSON
+	if err := json.Unmarshal(data, &qj); err != nil {
+		migration.Problems = append(migration.Problems, fmt.Sprintf("failed to parse quiz.json: %v", err))
+		return migration
+	}
+
+	lessonID, subchapterID := quizLocation(path)
+	eqj := extendedQuizJSON{
+		Version:      entities.ExtendedQuizVersion,
+		SubchapterID: subchapterID,
+		LessonID:     lessonID,
+		Questions:    make([]extendedQuizQuestionJSON, 0, len(qj.Questions)),
+	}
+
+	used := make(map[string]bool, len(qj.Questions))
+	for i, q := range qj.Questions {
+		label := q.ID
+		if label == "" {
+			label = fmt.Sprintf("#%d", i+1)
+		}
+
+		question, err := convertLegacyQuestion(q)
+		if err != nil {
+			migration.Problems = append(migration.Problems, fmt.Sprintf("question %s: %v", label, err))
+			continue
+		}
+		question.ID = stableQuestionID(q, used)
+		if err := question.Validate(); err != nil {
+			migration.Problems = append(migration.Problems, fmt.Sprintf("question %s: %v", label, err))
+			continue
+		}
+		eqj.Questions = append(eqj.Questions, toQuestionJSON(question))
+	}
+	if len(migration.Problems) > 0 {
+		return migration
+	}
+
+	migrated, err := json.MarshalIndent(eqj, "", "  ")
+	if err != nil {
+		migration.Problems = append(migration.Problems, fmt.Sprintf("failed to encode quiz: %v", err))
+		return migration
+	}
+	migration.Migrated = append(migrated, '\n')
+	return migration
+}
+
+// quizLocation returns the lessonId and subchapterId of a quiz from its folder, either
+// lessons/<lesson>/quiz.json or lessons/<lesson>/sublessons/<subchapter>/quiz.json
+func quizLocation(path string) (lessonID, subchapterID string) {
+	dir := filepath.Dir(path)
+	parent := filepath.Dir(dir)
+	if filepath.Base(parent) == "sublessons" {
+		return filepath.Base(filepath.Dir(parent)), filepath.Base(dir)
+	}
+	return filepath.Base(dir), ""
+}
+
+// stableQuestionID keeps a question's legacy ID, which recorded attempts refer to, when it
+// is usable; otherwise the ID is derived from the question text so reruns produce the same one
+func stableQuestionID(q quizQuestionJSON, used map[string]bool) string {
+	id := q.ID
+	if !entities.IsValidQuestionID(id) || used[id] {
+		sum := sha256.Sum256([]byte(q.Question))
+		base := "q-" + hex.EncodeToString(sum[:4])
+		id = base
+		for n := 2; used[id]; n++ {
+			id = fmt.Sprintf("%s-%d", base, n)
+		}
+	}
+	used[id] = true
+	return id
+}
@@ -0,0 +1,274 @@
+package folder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/project/backend/domain/entities"
+	"github.com/project/backend/domain/repositories"
+)
+
+// Ensure FolderCourseRepository publishes course changes
+var _ repositories.CourseChangeSource = (*FolderCourseRepository)(nil)
+
+// Watch reloads courses as their folders change until ctx is done
+// Bursts of changes are collected until debounce passes without another, then each changed
+// course is reloaded on its own and swapped into the cache; a change to the question banks
+// reloads every course, since bank questions are copied into their quizzes
+// While watching, the cache no longer expires after the TTL
+func (r *FolderCourseRepository) Watch(ctx context.Context, debounce time.Duration) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start course watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	// Folders are watched before the courses are loaded, so no change falls between the two
+	if err := watchTree(watcher, r.coursesPath); err != nil {
+		return err
+	}
+
+	r.loadMu.Lock()
+	err = r.reloadAll(ctx)
+	if err == nil {
+		r.setWatching(true)
+	}
+	r.loadMu.Unlock()
+	if err != nil {
+		return err
+	}
+	defer r.setWatching(false)
+
+	pending := make(map[string]bool)
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			// Folders created later, such as a new lesson, are watched too
+			if event.Has(fsnotify.Create) && !strings.HasPrefix(filepath.Base(event.Name), ".") {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := watchTree(watcher, event.Name); err != nil {
+						slog.Warn("Course watcher failed to watch a new folder", "error", err)
+					}
+				}
+			}
+			if folder := r.courseFolderOf(event.Name); folder != "" {
+				pending[folder] = true
+				timer.Reset(debounce)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			slog.Warn("Course watcher error", "error", err)
+		case <-timer.C:
+			r.applyChanges(ctx, pending)
+			pending = make(map[string]bool)
+		}
+	}
+}
+
+// SubscribeCourseChanges returns a channel receiving the course changes Watch finds and
+// the edits saved through the repository, and a function that ends the subscription
+// Changes are dropped for subscribers that fall behind, rather than holding up reloads
+func (r *FolderCourseRepository) SubscribeCourseChanges() (<-chan entities.CourseChange, func()) {
+	ch := make(chan entities.CourseChange, 16)
+
+	r.subscribersMu.Lock()
+	r.subscribers[ch] = struct{}{}
+	r.subscribersMu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			r.subscribersMu.Lock()
+			delete(r.subscribers, ch)
+			close(ch)
+			r.subscribersMu.Unlock()
+		})
+	}
+}
+
+func (r *FolderCourseRepository) publish(changes []entities.CourseChange) {
+	r.subscribersMu.Lock()
+	defer r.subscribersMu.Unlock()
+
+	for _, change := range changes {
+		for ch := range r.subscribers {
+			select {
+			case ch <- change:
+			default:
+			}
+		}
+	}
+}
+
+func (r *FolderCourseRepository) setWatching(watching bool) {
+	r.cacheMu.Lock()
+	r.watching = watching
+	r.cacheMu.Unlock()
+}
+
+// courseFolderOf returns the name of the top-level folder a changed path is in, or "" for
+// paths outside any course and hidden paths, such as temporary files and lesson histories
+func (r *FolderCourseRepository) courseFolderOf(path string) string {
+	rel, err := filepath.Rel(r.coursesPath, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return ""
+	}
+
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	for _, part := range parts {
+		if strings.HasPrefix(part, ".") {
+			return ""
+		}
+	}
+	folder := parts[0]
+	if folder == "COURSE-TEMPLATE" {
+		return ""
+	}
+	// Files next to the course folders belong to no course
+	if info, err := os.Stat(filepath.Join(r.coursesPath, folder)); err == nil && !info.IsDir() {
+		return ""
+	}
+	return folder
+}
+
+// applyChanges reloads the changed course folders and publishes what changed
+func (r *FolderCourseRepository) applyChanges(ctx context.Context, folders map[string]bool) {
+	var changes []entities.CourseChange
+
+	r.loadMu.Lock()
+	if folders[questionBanksDir] {
+		if err := r.reloadAll(ctx); err != nil {
+			slog.Warn("Failed to reload courses", "error", err)
+		} else {
+			r.cacheMu.RLock()
+			for _, id := range slices.Sorted(maps.Keys(r.cache)) {
+				changes = append(changes, entities.CourseChange{CourseID: id, Kind: entities.CourseUpdated, ChangedAt: time.Now()})
+			}
+			r.cacheMu.RUnlock()
+		}
+	} else {
+		for _, folder := range slices.Sorted(maps.Keys(folders)) {
+			changes = append(changes, r.reloadCourse(ctx, folder)...)
+		}
+	}
+	r.loadMu.Unlock()
+
+	r.publish(changes)
+}
+
+// reloadCourseByID reloads a course after it was edited through the repository
+func (r *FolderCourseRepository) reloadCourseByID(ctx context.Context, courseID string) {
+	r.loadMu.Lock()
+	r.cacheMu.RLock()
+	coursePath, ok := r.folders[courseID]
+	r.cacheMu.RUnlock()
+
+	var changes []entities.CourseChange
+	if ok {
+		changes = r.reloadCourse(ctx, filepath.Base(coursePath))
+	}
+	r.loadMu.Unlock()
+
+	r.publish(changes)
+}
+
+// reloadCourse loads one course folder and swaps it into the cache, returning the changes
+// A course whose folder or course.json is gone is removed; one that fails to load for any
+// other reason, such as a half-saved file, keeps its previous version
+// Callers must hold loadMu
+func (r *FolderCourseRepository) reloadCourse(ctx context.Context, folderName string) []entities.CourseChange {
+	coursePath := filepath.Join(r.coursesPath, folderName)
+	course, err := r.loadCourse(ctx, coursePath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		slog.Warn("Failed to reload course", "folder", folderName, "error", err)
+		return nil
+	}
+	if course != nil {
+		addBankQuestions(course, r.banks, folderName)
+	}
+
+	r.cacheMu.Lock()
+	defer r.cacheMu.Unlock()
+
+	// Courses not loaded yet are picked up by the next full load
+	if r.lastLoad.IsZero() {
+		return nil
+	}
+
+	oldID := ""
+	for id, path := range r.folders {
+		if path == coursePath {
+			oldID = id
+			break
+		}
+	}
+
+	now := time.Now()
+	var changes []entities.CourseChange
+	if course == nil {
+		if oldID != "" {
+			delete(r.cache, oldID)
+			delete(r.folders, oldID)
+			changes = append(changes, entities.CourseChange{CourseID: oldID, Kind: entities.CourseRemoved, ChangedAt: now})
+		}
+		return changes
+	}
+
+	if other, ok := r.folders[course.ID]; ok && other != coursePath {
+		slog.Warn("Skipped course with a duplicate course ID", "folder", folderName, "course", course.ID, "used_by", filepath.Base(other))
+		return nil
+	}
+
+	if oldID != "" && oldID != course.ID {
+		delete(r.cache, oldID)
+		delete(r.folders, oldID)
+		changes = append(changes, entities.CourseChange{CourseID: oldID, Kind: entities.CourseRemoved, ChangedAt: now})
+	}
+
+	kind := entities.CourseUpdated
+	if _, ok := r.cache[course.ID]; !ok {
+		kind = entities.CourseAdded
+	}
+	r.cache[course.ID] = course
+	r.folders[course.ID] = coursePath
+	return append(changes, entities.CourseChange{CourseID: course.ID, Kind: kind, ChangedAt: now})
+}
+
+// watchTree adds root and every folder below it to the watcher; hidden folders are skipped
+func watchTree(watcher *fsnotify.Watcher, root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != root && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if err := watcher.Add(path); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+		return nil
+	})
+}
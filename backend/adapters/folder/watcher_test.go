@@ -0,0 +1,221 @@
+package folder
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/project/backend/domain/entities"
+)
+
+const testDebounce = 100 * time.Millisecond
+
+// startWatch watches root until the test ends and returns the repository once its courses
+// are loaded, with a subscription to its changes
+func startWatch(t *testing.T, root string) (*FolderCourseRepository, <-chan entities.CourseChange) {
+	t.Helper()
+
+	repo := NewFolderCourseRepository(root)
+	changes, unsubscribe := repo.SubscribeCourseChanges()
+	t.Cleanup(unsubscribe)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- repo.Watch(ctx, testDebounce) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Watch failed: %v", err)
+		}
+	})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !repo.isWatching() {
+		if time.Now().After(deadline) {
+			t.Fatal("watcher did not start")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return repo, changes
+}
+
+func (r *FolderCourseRepository) isWatching() bool {
+	r.cacheMu.RLock()
+	defer r.cacheMu.RUnlock()
+	return r.watching
+}
+
+// nextChange waits for the next published change
+func nextChange(t *testing.T, changes <-chan entities.CourseChange) entities.CourseChange {
+	t.Helper()
+
+	select {
+	case change := <-changes:
+		return change
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a course change")
+		return entities.CourseChange{}
+	}
+}
+
+// expectNoChange fails if a change is published within a few debounce periods
+func expectNoChange(t *testing.T, changes <-chan entities.CourseChange) {
+	t.Helper()
+
+	select {
+	case change := <-changes:
+		t.Errorf("expected no further change, got %+v", change)
+	case <-time.After(3 * testDebounce):
+	}
+}
+
+func TestFolderCourseRepository_Watch(t *testing.T) {
+	root := t.TempDir()
+	writeCourseFiles(t, root, map[string]string{
+		"go-basics/course.json":                 `{"id": "go-basics", "title": "Go Basics"}`,
+		"go-basics/lessons/00-intro/content.md": "Intro",
+		"rust/course.json":                      `{"id": "rust", "title": "Rust"}`,
+		"rust/lessons/00-intro/content.md":      "Rust intro",
+	})
+	repo, changes := startWatch(t, root)
+	ctx := context.Background()
+
+	rust, err := repo.GetByID(ctx, "rust")
+	if err != nil {
+		t.Fatalf("failed to get course: %v", err)
+	}
+
+	// A burst of edits is reloaded once, after the debounce period
+	contentPath := filepath.Join(root, "go-basics", "lessons", "00-intro", "content.md")
+	for i := 0; i < 5; i++ {
+		if err := os.WriteFile(contentPath, []byte("Edit "+string(rune('1'+i))), 0644); err != nil {
+			t.Fatalf("failed to edit content: %v", err)
+		}
+		time.Sleep(testDebounce / 4)
+	}
+	if change := nextChange(t, changes); change.CourseID != "go-basics" || change.Kind != entities.CourseUpdated {
+		t.Errorf("expected go-basics to be updated, got %+v", change)
+	}
+	expectNoChange(t, changes)
+
+	course, err := repo.GetByID(ctx, "go-basics")
+	if err != nil {
+		t.Fatalf("failed to get course: %v", err)
+	}
+	if course.Lessons[0].Content != "Edit 5" {
+		t.Errorf("expected the last edit to be loaded, got %q", course.Lessons[0].Content)
+	}
+	if unchanged, _ := repo.GetByID(ctx, "rust"); unchanged != rust {
+		t.Error("expected the unchanged course not to be reloaded")
+	}
+
+	// Changes to hidden files, such as lesson histories, are ignored
+	writeCourseFiles(t, root, map[string]string{"go-basics/lessons/00-intro/.history/000001.json": "{}"})
+	expectNoChange(t, changes)
+
+	writeCourseFiles(t, root, map[string]string{
+		"python/course.json":                 `{"id": "python", "title": "Python"}`,
+		"python/lessons/00-intro/content.md": "Python intro",
+	})
+	if change := nextChange(t, changes); change.CourseID != "python" || change.Kind != entities.CourseAdded {
+		t.Errorf("expected python to be added, got %+v", change)
+	}
+	if _, err := repo.GetByID(ctx, "python"); err != nil {
+		t.Errorf("expected the new course to be loaded, got %v", err)
+	}
+
+	if err := os.RemoveAll(filepath.Join(root, "rust")); err != nil {
+		t.Fatalf("failed to remove course: %v", err)
+	}
+	if change := nextChange(t, changes); change.CourseID != "rust" || change.Kind != entities.CourseRemoved {
+		t.Errorf("expected rust to be removed, got %+v", change)
+	}
+	if _, err := repo.GetByID(ctx, "rust"); err == nil {
+		t.Error("expected the removed course to be gone")
+	}
+
+	// A course whose ID changes is removed under its old ID and added under the new one
+	writeCourseFiles(t, root, map[string]string{"python/course.json": `{"id": "python-3", "title": "Python"}`})
+	first, second := nextChange(t, changes), nextChange(t, changes)
+	if first.CourseID != "python" || first.Kind != entities.CourseRemoved || second.CourseID != "python-3" || second.Kind != entities.CourseAdded {
+		t.Errorf("expected python to be replaced by python-3, got %+v and %+v", first, second)
+	}
+}
+
+func TestFolderCourseRepository_Watch_QuestionBanks(t *testing.T) {
+	root := t.TempDir()
+	writeCourseFiles(t, root, map[string]string{
+		"go-basics/course.json":                 `{"id": "go-basics", "title": "Go Basics"}`,
+		"go-basics/lessons/00-intro/content.md": "Intro",
+		"rust/course.json":                      `{"id": "rust", "title": "Rust"}`,
+	})
+	_, changes := startWatch(t, root)
+
+	// Bank questions are copied into the courses' quizzes, so every course is reloaded
+	writeCourseFiles(t, root, map[string]string{questionBanksDir + "/go/bank.json": `{"title": "Go", "questions": []}`})
+	first, second := nextChange(t, changes), nextChange(t, changes)
+	if first.CourseID != "go-basics" || second.CourseID != "rust" || first.Kind != entities.CourseUpdated || second.Kind != entities.CourseUpdated {
+		t.Errorf("expected every course to be updated, got %+v and %+v", first, second)
+	}
+}
+
+func TestFolderCourseRepository_ReloadAfterEdit(t *testing.T) {
+	root := t.TempDir()
+	writeCourseFiles(t, root, map[string]string{
+		"go-basics/course.json":                 `{"id": "go-basics", "title": "Go Basics"}`,
+		"go-basics/lessons/00-intro/content.md": "Intro",
+	})
+
+	// Edits saved through the repository are published without a watcher
+	repo := NewFolderCourseRepository(root)
+	changes, unsubscribe := repo.SubscribeCourseChanges()
+	ctx := context.Background()
+	if _, err := repo.UpdateLessonContent(ctx, "go-basics", []int{0}, "Edited", "author-1", "Edit"); err != nil {
+		t.Fatalf("failed to update lesson: %v", err)
+	}
+	if change := nextChange(t, changes); change.CourseID != "go-basics" || change.Kind != entities.CourseUpdated {
+		t.Errorf("expected go-basics to be updated, got %+v", change)
+	}
+	if course, _ := repo.GetByID(ctx, "go-basics"); course.Lessons[0].Content != "Edited" {
+		t.Errorf("expected the edit to be loaded, got %q", course.Lessons[0].Content)
+	}
+
+	unsubscribe()
+	unsubscribe()
+	if _, ok := <-changes; ok {
+		t.Error("expected the channel to be closed once unsubscribed")
+	}
+}
+
+func TestFolderCourseRepository_CourseFolderOf(t *testing.T) {
+	root := t.TempDir()
+	writeCourseFiles(t, root, map[string]string{
+		"go-basics/course.json": `{"id": "go-basics"}`,
+		"README.md":             "Courses",
+	})
+	repo := NewFolderCourseRepository(root)
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{filepath.Join(root, "go-basics"), "go-basics"},
+		{filepath.Join(root, "go-basics", "lessons", "00-intro", "content.md"), "go-basics"},
+		{filepath.Join(root, "new-course"), "new-course"},
+		{filepath.Join(root, questionBanksDir, "go", "bank.json"), questionBanksDir},
+		{filepath.Join(root, "go-basics", "lessons", "00-intro", ".history", "000001.json"), ""},
+		{filepath.Join(root, "go-basics", ".course.json.tmp"), ""},
+		{filepath.Join(root, ".go-basics-new", "course.json"), ""},
+		{filepath.Join(root, "COURSE-TEMPLATE", "course.json"), ""},
+		{filepath.Join(root, "README.md"), ""},
+		{root, ""},
+		{filepath.Dir(root), ""},
+	}
+	for _, tt := range tests {
+		if got := repo.courseFolderOf(tt.path); got != tt.want {
+			t.Errorf("courseFolderOf(%s) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/project/backend/domain/entities"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// errorCodes are the domain errors clients need to tell apart, exposed as extensions.code
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{entities.ErrNotAnExam, "NOT_AN_EXAM"},
+	{entities.ErrQuizSessionNotFound, "QUIZ_SESSION_NOT_FOUND"},
+	{entities.ErrExamSessionRequired, "EXAM_SESSION_REQUIRED"},
+	{entities.ErrQuizSessionClosed, "QUIZ_SESSION_CLOSED"},
+	{entities.ErrExamTimeLimitExceeded, "EXAM_TIME_LIMIT_EXCEEDED"},
+	{entities.ErrExamAttemptsExhausted, "EXAM_ATTEMPTS_EXHAUSTED"},
+	{entities.ErrExamCooldown, "EXAM_COOLDOWN"},
+	{entities.ErrQuizDraftNotFound, "QUIZ_DRAFT_NOT_FOUND"},
+	{entities.ErrQuestionBankNotFound, "QUESTION_BANK_NOT_FOUND"},
+	{entities.ErrBankQuestionReadOnly, "BANK_QUESTION_READ_ONLY"},
+	{entities.ErrLessonRevisionNotFound, "LESSON_REVISION_NOT_FOUND"},
+}
+
+// ErrorPresenter adds an extensions.code to errors caused by the domain errors above
+func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	presented := graphql.DefaultErrorPresenter(ctx, err)
+	for _, c := range errorCodes {
+		if errors.Is(err, c.err) {
+			if presented.Extensions == nil {
+				presented.Extensions = map[string]interface{}{}
+			}
+			presented.Extensions["code"] = c.code
+			break
+		}
+	}
+	return presented
+}
@@ -1,10 +1,47 @@
 package graphql
 
 import (
+	"context"
+	"errors"
+	"strings"
+
 	"github.com/google/uuid"
+	httpAdapter "github.com/project/backend/adapters/http"
 	"github.com/project/backend/domain/entities"
 )
 
+// lessonEditor returns the signed-in user if they may edit the lessons of a folder course
+func (r *Resolver) lessonEditor(ctx context.Context, courseID string) (string, error) {
+	userID := httpAdapter.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return "", errors.New("authentication required")
+	}
+	if r.FolderCourseRepo == nil {
+		return "", errors.New("content editing only available for folder-based courses")
+	}
+
+	course, err := r.FolderCourseRepo.GetByID(ctx, courseID)
+	if err != nil {
+		return "", err
+	}
+	if !course.CanEditContent(userID, r.Editors) {
+		return "", errors.New("not authorized to edit this course")
+	}
+	return userID, nil
+}
+
+// admin returns the signed-in user if they are one of the configured administrators
+func (r *Resolver) admin(ctx context.Context) (string, error) {
+	userID := httpAdapter.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return "", errors.New("authentication required")
+	}
+	if !r.Admins.Contains(userID) {
+		return "", errors.New("administrator access required")
+	}
+	return userID, nil
+}
+
 // convertQuizInput converts QuizInput to entities.Quiz
 func convertQuizInput(input *QuizInput) *entities.Quiz {
 	if input == nil {
@@ -34,6 +71,9 @@ func convertLessonInput(input *LessonInput) entities.Lesson {
 		Content: input.Content,
 		Order:   input.Order,
 	}
+	if input.Folder != nil {
+		lesson.Folder = *input.Folder
+	}
 	if len(input.Sublessons) > 0 {
 		lesson.Sublessons = make([]entities.Lesson, len(input.Sublessons))
 		for i, sub := range input.Sublessons {
@@ -54,3 +94,86 @@ func convertLessonsInput(inputs []*LessonInput) []entities.Lesson {
 	}
 	return lessons
 }
+
+// convertQuizResponsesInput converts submitted quiz responses to answers for grading
+func convertQuizResponsesInput(inputs []*QuizResponseInput) []entities.QuizAnswer {
+	answers := make([]entities.QuizAnswer, len(inputs))
+	for i, resp := range inputs {
+		answers[i] = entities.QuizAnswer{
+			QuestionID: resp.QuestionID,
+			Answer:     []byte(resp.UserAnswer),
+		}
+		if resp.Confidence != nil {
+			answers[i].Confidence = *resp.Confidence
+		}
+		if resp.TimeTakenSeconds != nil {
+			answers[i].TimeTakenSec = *resp.TimeTakenSeconds
+		}
+	}
+	return answers
+}
+
+// convertExtendedQuizQuestionInput converts an authored question to an entity
+// Enum values arrive upper case, while question types are stored lower case
+func convertExtendedQuizQuestionInput(input *ExtendedQuizQuestionInput) entities.ExtendedQuizQuestion {
+	q := entities.ExtendedQuizQuestion{
+		ID:              input.ID,
+		Type:            entities.QuestionType(strings.ToLower(string(input.Type))),
+		Difficulty:      input.Difficulty,
+		Question:        input.Question,
+		Options:         input.Options,
+		CorrectAnswer:   input.CorrectAnswer,
+		CorrectIndices:  input.CorrectIndices,
+		LeftColumn:      input.LeftColumn,
+		RightColumn:     input.RightColumn,
+		CorrectPairs:    input.CorrectPairs,
+		Items:           input.Items,
+		CorrectOrder:    input.CorrectOrder,
+		AcceptedAnswers: input.AcceptedAnswers,
+		Synonyms:        input.Synonyms,
+		CorrectValue:    input.CorrectValue,
+	}
+	if input.Concept != nil {
+		q.Concept = *input.Concept
+	}
+	if input.Explanation != nil {
+		q.Explanation = *input.Explanation
+	}
+	if input.CorrectIndex != nil {
+		q.CorrectIndex = *input.CorrectIndex
+	}
+	if input.MinSelections != nil {
+		q.MinSelections = *input.MinSelections
+	}
+	if input.MaxSelections != nil {
+		q.MaxSelections = *input.MaxSelections
+	}
+	if input.CodeSnippet != nil {
+		q.CodeSnippet = *input.CodeSnippet
+	}
+	if input.Language != nil {
+		q.Language = *input.Language
+	}
+	if input.CaseSensitive != nil {
+		q.CaseSensitive = *input.CaseSensitive
+	}
+	if input.AnswerPattern != nil {
+		q.AnswerPattern = *input.AnswerPattern
+	}
+	if input.AbsoluteTolerance != nil {
+		q.AbsoluteTolerance = *input.AbsoluteTolerance
+	}
+	if input.RelativeTolerance != nil {
+		q.RelativeTolerance = *input.RelativeTolerance
+	}
+	if input.Unit != nil {
+		q.Unit = *input.Unit
+	}
+	if input.StarterCode != nil {
+		q.StarterCode = *input.StarterCode
+	}
+	if input.TestCode != nil {
+		q.TestCode = *input.TestCode
+	}
+	return q
+}
@@ -3,6 +3,7 @@
 package graphql
 
 import (
+	"github.com/project/backend/adapters/quizformat"
 	"github.com/project/backend/domain/entities"
 )
 
@@ -28,14 +29,57 @@ type CreateUserInput struct {
 	Password string `json:"password"`
 }
 
+type ExtendedQuizInput struct {
+	Version   *string                      `json:"version,omitempty"`
+	Questions []*ExtendedQuizQuestionInput `json:"questions"`
+}
+
+type ExtendedQuizQuestionInput struct {
+	ID                string                `json:"id"`
+	Type              entities.QuestionType `json:"type"`
+	Difficulty        int                   `json:"difficulty"`
+	Concept           *string               `json:"concept,omitempty"`
+	Question          string                `json:"question"`
+	Explanation       *string               `json:"explanation,omitempty"`
+	Options           []string              `json:"options,omitempty"`
+	CorrectIndex      *int                  `json:"correctIndex,omitempty"`
+	CorrectAnswer     *bool                 `json:"correctAnswer,omitempty"`
+	CorrectIndices    []int                 `json:"correctIndices,omitempty"`
+	MinSelections     *int                  `json:"minSelections,omitempty"`
+	MaxSelections     *int                  `json:"maxSelections,omitempty"`
+	CodeSnippet       *string               `json:"codeSnippet,omitempty"`
+	Language          *string               `json:"language,omitempty"`
+	LeftColumn        []string              `json:"leftColumn,omitempty"`
+	RightColumn       []string              `json:"rightColumn,omitempty"`
+	CorrectPairs      [][]int               `json:"correctPairs,omitempty"`
+	Items             []string              `json:"items,omitempty"`
+	CorrectOrder      []int                 `json:"correctOrder,omitempty"`
+	AcceptedAnswers   []string              `json:"acceptedAnswers,omitempty"`
+	CaseSensitive     *bool                 `json:"caseSensitive,omitempty"`
+	AnswerPattern     *string               `json:"answerPattern,omitempty"`
+	Synonyms          [][]string            `json:"synonyms,omitempty"`
+	CorrectValue      *float64              `json:"correctValue,omitempty"`
+	AbsoluteTolerance *float64              `json:"absoluteTolerance,omitempty"`
+	RelativeTolerance *float64              `json:"relativeTolerance,omitempty"`
+	Unit              *string               `json:"unit,omitempty"`
+	StarterCode       *string               `json:"starterCode,omitempty"`
+	TestCode          *string               `json:"testCode,omitempty"`
+}
+
 type ImportCoursesInput struct {
 	Courses []*CreateLibraryCourseInput `json:"courses"`
 }
 
+type LessonCompletionReason struct {
+	LessonIndex int    `json:"lessonIndex"`
+	Reason      string `json:"reason"`
+}
+
 type LessonInput struct {
 	Title      string         `json:"title"`
 	Content    string         `json:"content"`
 	Order      int            `json:"order"`
+	Folder     *string        `json:"folder,omitempty"`
 	Sublessons []*LessonInput `json:"sublessons,omitempty"`
 	Quiz       *QuizInput     `json:"quiz,omitempty"`
 }
@@ -64,6 +108,12 @@ type PaginationInput struct {
 type Query struct {
 }
 
+type QuizExport struct {
+	Format  quizformat.Format               `json:"format"`
+	Content string                          `json:"content"`
+	Skipped []*entities.QuizConversionIssue `json:"skipped"`
+}
+
 type QuizInput struct {
 	Questions []*QuizQuestionInput `json:"questions"`
 }
@@ -78,9 +128,6 @@ type QuizQuestionInput struct {
 type QuizResponseInput struct {
 	QuestionID       string                    `json:"questionId"`
 	UserAnswer       string                    `json:"userAnswer"`
-	IsCorrect        bool                      `json:"isCorrect"`
-	PointsEarned     int                       `json:"pointsEarned"`
-	PointsPossible   int                       `json:"pointsPossible"`
 	Confidence       *entities.ConfidenceLevel `json:"confidence,omitempty"`
 	TimeTakenSeconds *int                      `json:"timeTakenSeconds,omitempty"`
 }
@@ -91,19 +138,30 @@ type RegisterInput struct {
 	Password string `json:"password"`
 }
 
+type RestoreLessonRevisionInput struct {
+	LibraryCourseID string  `json:"libraryCourseId"`
+	LessonPath      []int   `json:"lessonPath"`
+	RevisionID      int     `json:"revisionId"`
+	Message         *string `json:"message,omitempty"`
+}
+
 type StartCourseInput struct {
 	LibraryCourseID string `json:"libraryCourseId"`
 }
 
 type SubmitQuizAttemptInput struct {
-	CourseID       string               `json:"courseId"`
-	QuizType       string               `json:"quizType"`
-	QuizID         string               `json:"quizId"`
-	Score          int                  `json:"score"`
-	MaxScore       int                  `json:"maxScore"`
-	TotalQuestions int                  `json:"totalQuestions"`
-	CorrectCount   int                  `json:"correctCount"`
-	Responses      []*QuizResponseInput `json:"responses"`
+	CourseID   string               `json:"courseId"`
+	LessonPath []int                `json:"lessonPath"`
+	InstanceID *string              `json:"instanceId,omitempty"`
+	SessionID  *string              `json:"sessionId,omitempty"`
+	Responses  []*QuizResponseInput `json:"responses"`
+}
+
+type TestOutInput struct {
+	CourseID  string               `json:"courseId"`
+	Chapter   int                  `json:"chapter"`
+	SessionID *string              `json:"sessionId,omitempty"`
+	Responses []*QuizResponseInput `json:"responses"`
 }
 
 type TokenPayload struct {
@@ -112,9 +170,10 @@ type TokenPayload struct {
 }
 
 type UpdateLessonContentInput struct {
-	LibraryCourseID string `json:"libraryCourseId"`
-	LessonPath      []int  `json:"lessonPath"`
-	Content         string `json:"content"`
+	LibraryCourseID string  `json:"libraryCourseId"`
+	LessonPath      []int   `json:"lessonPath"`
+	Content         string  `json:"content"`
+	Message         *string `json:"message,omitempty"`
 }
 
 type UpdateLibraryCourseInput struct {
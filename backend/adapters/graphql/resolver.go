@@ -5,19 +5,28 @@ package graphql
 import (
 	"github.com/project/backend/adapters/folder"
 	"github.com/project/backend/application/ports"
+	"github.com/project/backend/domain/entities"
 	"github.com/project/backend/domain/repositories"
 )
 
 // Resolver is the root resolver for GraphQL
 type Resolver struct {
-	UserUseCase       ports.UserPort
-	AuthUseCase       ports.AuthPort
-	LibraryCourseRepo repositories.LibraryCourseRepository
-	UserCourseRepo    repositories.UserCourseRepository
-	BookmarkRepo      repositories.BookmarkRepository
-	AnalyticsRepo     repositories.AnalyticsRepository
-	AttachmentRepo    repositories.AttachmentRepository
-	QuizRepo          repositories.QuizRepository
+	UserUseCase   ports.UserPort
+	AuthUseCase   ports.AuthPort
+	QuizUseCase   ports.QuizPort
+	ReviewUseCase ports.ReviewPort
+	// QuestionBankUseCase is set when using folder-based courses, which hold the banks
+	QuestionBankUseCase ports.QuestionBankPort
+	LibraryCourseRepo   repositories.LibraryCourseRepository
+	UserCourseRepo      repositories.UserCourseRepository
+	BookmarkRepo        repositories.BookmarkRepository
+	AnalyticsRepo       repositories.AnalyticsRepository
+	AttachmentRepo      repositories.AttachmentRepository
+	QuizRepo            repositories.QuizRepository
 	// FolderCourseRepo is set when using folder-based courses for content editing
 	FolderCourseRepo *folder.FolderCourseRepository
+	// Editors may edit folder courses, which have no author account
+	Editors entities.UserGroup
+	// Admins may run administrative mutations such as refreshCourses
+	Admins entities.UserGroup
 }
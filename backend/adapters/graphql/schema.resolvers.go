@@ -9,9 +9,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	httpAdapter "github.com/project/backend/adapters/http"
+	"github.com/project/backend/adapters/quizformat"
+	"github.com/project/backend/adapters/textdiff"
 	"github.com/project/backend/application/ports"
 	"github.com/project/backend/domain/entities"
 )
@@ -21,11 +24,50 @@ func (r *attachmentResolver) DownloadURL(ctx context.Context, obj *entities.Atta
 	return fmt.Sprintf("/api/attachments/%s", obj.ID), nil
 }
 
+// CourseID is the resolver for the courseId field.
+func (r *calibrationReportResolver) CourseID(ctx context.Context, obj *entities.CalibrationReport) (*string, error) {
+	if obj.CourseID == "" {
+		return nil, nil
+	}
+	return &obj.CourseID, nil
+}
+
+// ScoringPolicy is the resolver for the scoringPolicy field.
+func (r *examConfigResolver) ScoringPolicy(ctx context.Context, obj *entities.ExamConfig) (entities.ScoringPolicy, error) {
+	return obj.Policy(), nil
+}
+
+// CorrectIndex is the resolver for the correctIndex field.
+func (r *extendedQuizQuestionResolver) CorrectIndex(ctx context.Context, obj *entities.ExtendedQuizQuestion) (*int, error) {
+	if obj.AnswerKeyHidden {
+		return nil, nil
+	}
+	return &obj.CorrectIndex, nil
+}
+
 // HasSublessons is the resolver for the hasSublessons field.
 func (r *lessonResolver) HasSublessons(ctx context.Context, obj *entities.Lesson) (bool, error) {
 	return obj.HasSublessons(), nil
 }
 
+// Lessons is the resolver for the lessons field.
+func (r *libraryCourseResolver) Lessons(ctx context.Context, obj *entities.LibraryCourse) ([]*entities.Lesson, error) {
+	userID := httpAdapter.GetUserIDFromContext(ctx)
+
+	// Answer keys are redacted unless the viewer is the author or has answered the question
+	lessons, err := r.QuizUseCase.VisibleLessons(ctx, userID, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*entities.Lesson, len(lessons))
+	for i := range lessons {
+		result[i] = &lessons[i]
+	}
+
+	return result, nil
+}
+
 // TotalLessonCount is the resolver for the totalLessonCount field.
 func (r *libraryCourseResolver) TotalLessonCount(ctx context.Context, obj *entities.LibraryCourse) (int, error) {
 	return obj.TotalLessonCount(), nil
@@ -145,8 +187,8 @@ func (r *mutationResolver) UpdateLibraryCourse(ctx context.Context, id string, i
 		return nil, err
 	}
 
-	// Check if user is the author
-	if course.AuthorID != userID {
+	// Check if user may edit the course; folder courses only by the configured editors
+	if !course.CanEditContent(userID, r.Editors) {
 		return nil, errors.New("not authorized to update this course")
 	}
 
@@ -187,8 +229,8 @@ func (r *mutationResolver) DeleteLibraryCourse(ctx context.Context, id string) (
 		return false, err
 	}
 
-	// Check if user is the author
-	if course.AuthorID != userID {
+	// Check if user may edit the course; folder courses only by the configured editors
+	if !course.CanEditContent(userID, r.Editors) {
 		return false, errors.New("not authorized to delete this course")
 	}
 
@@ -503,72 +545,45 @@ func (r *mutationResolver) SubmitQuizAttempt(ctx context.Context, input SubmitQu
 		return nil, errors.New("authentication required")
 	}
 
-	attempt := entities.NewQuizAttempt(
-		userID,
-		input.CourseID,
-		input.QuizType,
-		input.QuizID,
-		input.Score,
-		input.MaxScore,
-		input.TotalQuestions,
-		input.CorrectCount,
-	)
-
-	savedAttempt, err := r.QuizRepo.SaveAttempt(ctx, attempt)
-	if err != nil {
-		return nil, err
+	instanceID := ""
+	if input.InstanceID != nil {
+		instanceID = *input.InstanceID
 	}
-
-	// Save individual responses
-	for _, resp := range input.Responses {
-		response := &entities.QuizResponse{
-			AttemptID:      savedAttempt.ID,
-			QuestionID:     resp.QuestionID,
-			UserAnswer:     []byte(resp.UserAnswer),
-			IsCorrect:      resp.IsCorrect,
-			PointsEarned:   resp.PointsEarned,
-			PointsPossible: resp.PointsPossible,
-		}
-		if resp.Confidence != nil {
-			response.Confidence = *resp.Confidence
-		}
-		if resp.TimeTakenSeconds != nil {
-			response.TimeTakenSec = *resp.TimeTakenSeconds
-		}
-
-		if _, err := r.QuizRepo.SaveResponse(ctx, response); err != nil {
-			return nil, err
-		}
+	sessionID := ""
+	if input.SessionID != nil {
+		sessionID = *input.SessionID
 	}
 
-	return savedAttempt, nil
+	// Score, correctness and points are computed server-side from the answer key
+	return r.QuizUseCase.SubmitAttempt(ctx, ports.SubmitQuizAttemptInput{
+		UserID:     userID,
+		CourseID:   input.CourseID,
+		LessonPath: input.LessonPath,
+		InstanceID: instanceID,
+		SessionID:  sessionID,
+		Answers:    convertQuizResponsesInput(input.Responses),
+	})
 }
 
 // AddToReviewQueue is the resolver for the addToReviewQueue field.
-func (r *mutationResolver) AddToReviewQueue(ctx context.Context, courseID string, quizID string, questionID string, concept string) (*entities.ReviewQueueItem, error) {
+func (r *mutationResolver) AddToReviewQueue(ctx context.Context, courseID string, quizID string, questionID string, concept string, confidence *entities.ConfidenceLevel) (*entities.ReviewQueueItem, error) {
 	userID := httpAdapter.GetUserIDFromContext(ctx)
 	if userID == "" {
 		return nil, errors.New("authentication required")
 	}
 
-	now := time.Now()
-	item := &entities.ReviewQueueItem{
-		UserID:      userID,
-		CourseID:    courseID,
-		QuizID:      quizID,
-		QuestionID:  questionID,
-		Concept:     concept,
-		WrongCount:  1,
-		LastAttempt: now,
-		NextReview:  now.Add(24 * time.Hour), // Review in 24 hours
-		Stability:   1.0,
+	input := ports.QueueForReviewInput{
+		UserID:     userID,
+		CourseID:   courseID,
+		QuizID:     quizID,
+		QuestionID: questionID,
+		Concept:    concept,
 	}
-
-	if err := r.QuizRepo.AddToReviewQueue(ctx, item); err != nil {
-		return nil, err
+	if confidence != nil {
+		input.Confidence = *confidence
 	}
 
-	return item, nil
+	return r.ReviewUseCase.QueueForReview(ctx, input)
 }
 
 // RemoveFromReviewQueue is the resolver for the removeFromReviewQueue field.
@@ -582,11 +597,104 @@ func (r *mutationResolver) RemoveFromReviewQueue(ctx context.Context, courseID s
 	return err == nil, err
 }
 
+// RecordReviewOutcome is the resolver for the recordReviewOutcome field.
+func (r *mutationResolver) RecordReviewOutcome(ctx context.Context, courseID string, questionID string, userAnswer string, confidence *entities.ConfidenceLevel) (*entities.ReviewQueueItem, error) {
+	userID := httpAdapter.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errors.New("authentication required")
+	}
+
+	input := ports.RecordReviewOutcomeInput{
+		UserID:     userID,
+		CourseID:   courseID,
+		QuestionID: questionID,
+		Answer:     []byte(userAnswer),
+	}
+	if confidence != nil {
+		input.Confidence = *confidence
+	}
+
+	return r.ReviewUseCase.RecordReviewOutcome(ctx, input)
+}
+
+// StartQuizSession is the resolver for the startQuizSession field.
+func (r *mutationResolver) StartQuizSession(ctx context.Context, courseID string, lessonPath []int) (*entities.QuizSession, error) {
+	userID := httpAdapter.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errors.New("authentication required")
+	}
+
+	return r.QuizUseCase.StartQuizSession(ctx, userID, courseID, lessonPath)
+}
+
+// StartQuizAttempt is the resolver for the startQuizAttempt field.
+func (r *mutationResolver) StartQuizAttempt(ctx context.Context, courseID string, lessonPath []int, instanceID *string, sessionID *string) (*entities.QuizDraft, error) {
+	userID := httpAdapter.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errors.New("authentication required")
+	}
+
+	input := ports.StartQuizAttemptInput{
+		UserID:     userID,
+		CourseID:   courseID,
+		LessonPath: lessonPath,
+	}
+	if instanceID != nil {
+		input.InstanceID = *instanceID
+	}
+	if sessionID != nil {
+		input.SessionID = *sessionID
+	}
+
+	return r.QuizUseCase.StartQuizAttempt(ctx, input)
+}
+
+// SaveQuizAnswer is the resolver for the saveQuizAnswer field.
+func (r *mutationResolver) SaveQuizAnswer(ctx context.Context, draftID string, response QuizResponseInput) (*entities.QuizDraft, error) {
+	userID := httpAdapter.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errors.New("authentication required")
+	}
+
+	answers := convertQuizResponsesInput([]*QuizResponseInput{&response})
+	return r.QuizUseCase.SaveQuizAnswer(ctx, userID, draftID, answers[0])
+}
+
+// FinishQuizAttempt is the resolver for the finishQuizAttempt field.
+func (r *mutationResolver) FinishQuizAttempt(ctx context.Context, draftID string) (*entities.QuizAttempt, error) {
+	userID := httpAdapter.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errors.New("authentication required")
+	}
+
+	return r.QuizUseCase.FinishQuizAttempt(ctx, userID, draftID)
+}
+
+// TestOutOfChapter is the resolver for the testOutOfChapter field.
+func (r *mutationResolver) TestOutOfChapter(ctx context.Context, input TestOutInput) (*entities.TestOutResult, error) {
+	userID := httpAdapter.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errors.New("authentication required")
+	}
+
+	testOut := ports.TestOutInput{
+		UserID:   userID,
+		CourseID: input.CourseID,
+		Chapter:  input.Chapter,
+		Answers:  convertQuizResponsesInput(input.Responses),
+	}
+	if input.SessionID != nil {
+		testOut.SessionID = *input.SessionID
+	}
+
+	return r.QuizUseCase.TestOut(ctx, testOut)
+}
+
 // UpdateLessonContent is the resolver for the updateLessonContent field.
 func (r *mutationResolver) UpdateLessonContent(ctx context.Context, input UpdateLessonContentInput) (bool, error) {
-	// Check if folder-based repository is available
-	if r.FolderCourseRepo == nil {
-		return false, errors.New("content editing only available for folder-based courses")
+	userID, err := r.lessonEditor(ctx, input.LibraryCourseID)
+	if err != nil {
+		return false, err
 	}
 
 	// Convert lesson path from []int to []int
@@ -595,7 +703,12 @@ func (r *mutationResolver) UpdateLessonContent(ctx context.Context, input Update
 		lessonPath[i] = v
 	}
 
-	err := r.FolderCourseRepo.UpdateLessonContent(ctx, input.LibraryCourseID, lessonPath, input.Content)
+	message := ""
+	if input.Message != nil {
+		message = *input.Message
+	}
+
+	_, err = r.FolderCourseRepo.UpdateLessonContent(ctx, input.LibraryCourseID, lessonPath, input.Content, userID, message)
 	if err != nil {
 		return false, fmt.Errorf("failed to update lesson content: %w", err)
 	}
@@ -603,6 +716,128 @@ func (r *mutationResolver) UpdateLessonContent(ctx context.Context, input Update
 	return true, nil
 }
 
+// RestoreLessonRevision is the resolver for the restoreLessonRevision field.
+func (r *mutationResolver) RestoreLessonRevision(ctx context.Context, input RestoreLessonRevisionInput) (*entities.LessonRevision, error) {
+	userID, err := r.lessonEditor(ctx, input.LibraryCourseID)
+	if err != nil {
+		return nil, err
+	}
+
+	message := ""
+	if input.Message != nil {
+		message = *input.Message
+	}
+
+	return r.FolderCourseRepo.RestoreLessonRevision(ctx, input.LibraryCourseID, input.LessonPath, input.RevisionID, userID, message)
+}
+
+// RefreshCourses is the resolver for the refreshCourses field.
+func (r *mutationResolver) RefreshCourses(ctx context.Context) (bool, error) {
+	if _, err := r.admin(ctx); err != nil {
+		return false, err
+	}
+	if r.FolderCourseRepo == nil {
+		return false, errors.New("course refresh only available for folder-based courses")
+	}
+
+	if err := r.FolderCourseRepo.RefreshCache(ctx); err != nil {
+		return false, fmt.Errorf("failed to refresh courses: %w", err)
+	}
+
+	return true, nil
+}
+
+// UpsertLessonQuiz is the resolver for the upsertLessonQuiz field.
+func (r *mutationResolver) UpsertLessonQuiz(ctx context.Context, courseID string, lessonPath []int, quiz ExtendedQuizInput) (*entities.ExtendedQuiz, error) {
+	userID := httpAdapter.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errors.New("authentication required")
+	}
+
+	target := ports.EditQuizInput{UserID: userID, CourseID: courseID, LessonPath: lessonPath}
+	edited := entities.ExtendedQuiz{Questions: make([]entities.ExtendedQuizQuestion, len(quiz.Questions))}
+	if quiz.Version != nil {
+		edited.Version = *quiz.Version
+	}
+	for i, q := range quiz.Questions {
+		edited.Questions[i] = convertExtendedQuizQuestionInput(q)
+	}
+
+	return r.QuizUseCase.UpsertLessonQuiz(ctx, target, edited)
+}
+
+// AddQuizQuestion is the resolver for the addQuizQuestion field.
+func (r *mutationResolver) AddQuizQuestion(ctx context.Context, courseID string, lessonPath []int, question ExtendedQuizQuestionInput, position *int) (*entities.ExtendedQuiz, error) {
+	userID := httpAdapter.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errors.New("authentication required")
+	}
+
+	target := ports.EditQuizInput{UserID: userID, CourseID: courseID, LessonPath: lessonPath}
+	index := -1
+	if position != nil {
+		index = *position
+	}
+
+	return r.QuizUseCase.AddQuizQuestion(ctx, target, convertExtendedQuizQuestionInput(&question), index)
+}
+
+// UpdateQuizQuestion is the resolver for the updateQuizQuestion field.
+func (r *mutationResolver) UpdateQuizQuestion(ctx context.Context, courseID string, lessonPath []int, question ExtendedQuizQuestionInput) (*entities.ExtendedQuiz, error) {
+	userID := httpAdapter.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errors.New("authentication required")
+	}
+
+	target := ports.EditQuizInput{UserID: userID, CourseID: courseID, LessonPath: lessonPath}
+	return r.QuizUseCase.UpdateQuizQuestion(ctx, target, convertExtendedQuizQuestionInput(&question))
+}
+
+// DeleteQuizQuestion is the resolver for the deleteQuizQuestion field.
+func (r *mutationResolver) DeleteQuizQuestion(ctx context.Context, courseID string, lessonPath []int, questionID string) (*entities.ExtendedQuiz, error) {
+	userID := httpAdapter.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errors.New("authentication required")
+	}
+
+	target := ports.EditQuizInput{UserID: userID, CourseID: courseID, LessonPath: lessonPath}
+	return r.QuizUseCase.DeleteQuizQuestion(ctx, target, questionID)
+}
+
+// ReorderQuizQuestions is the resolver for the reorderQuizQuestions field.
+func (r *mutationResolver) ReorderQuizQuestions(ctx context.Context, courseID string, lessonPath []int, questionIds []string) (*entities.ExtendedQuiz, error) {
+	userID := httpAdapter.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errors.New("authentication required")
+	}
+
+	target := ports.EditQuizInput{UserID: userID, CourseID: courseID, LessonPath: lessonPath}
+	return r.QuizUseCase.ReorderQuizQuestions(ctx, target, questionIds)
+}
+
+// ImportQuiz is the resolver for the importQuiz field.
+func (r *mutationResolver) ImportQuiz(ctx context.Context, courseID string, lessonPath []int, format quizformat.Format, payload string) (*entities.QuizImportResult, error) {
+	userID := httpAdapter.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errors.New("authentication required")
+	}
+
+	questions, issues, err := quizformat.Import(format, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	target := ports.EditQuizInput{UserID: userID, CourseID: courseID, LessonPath: lessonPath}
+	result, err := r.QuizUseCase.ImportQuizQuestions(ctx, target, questions)
+	if err != nil {
+		return nil, err
+	}
+
+	// Items the parser could not convert come first, in the order they appear in the payload
+	result.Skipped = append(issues, result.Skipped...)
+	return result, nil
+}
+
 // User returns a single user by ID
 func (r *queryResolver) User(ctx context.Context, id string) (*entities.User, error) {
 	return r.UserUseCase.GetUser(ctx, id)
@@ -1005,7 +1240,9 @@ func (r *queryResolver) DashboardQuizStats(ctx context.Context, fromDate *string
 	if toDate != nil {
 		parsed, err := time.Parse("2006-01-02", *toDate)
 		if err == nil {
-			to = &parsed
+			// Include the whole end day
+			endOfDay := parsed.AddDate(0, 0, 1).Add(-time.Nanosecond)
+			to = &endOfDay
 		}
 	}
 
@@ -1038,27 +1275,354 @@ func (r *queryResolver) ReviewQueue(ctx context.Context, courseID string, limit
 	return result, nil
 }
 
+// ConceptMastery is the resolver for the conceptMastery field.
+func (r *queryResolver) ConceptMastery(ctx context.Context, courseID string) ([]*entities.ConceptStrength, error) {
+	userID := httpAdapter.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errors.New("authentication required")
+	}
+
+	strengths, err := r.QuizRepo.GetConceptStrengths(ctx, userID, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to pointers
+	result := make([]*entities.ConceptStrength, len(strengths))
+	for i := range strengths {
+		result[i] = &strengths[i]
+	}
+
+	return result, nil
+}
+
+// ConfidenceCalibration is the resolver for the confidenceCalibration field.
+func (r *queryResolver) ConfidenceCalibration(ctx context.Context, courseID *string) (*entities.CalibrationReport, error) {
+	userID := httpAdapter.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errors.New("authentication required")
+	}
+
+	var course string
+	if courseID != nil {
+		course = *courseID
+	}
+
+	return r.QuizRepo.GetCalibration(ctx, userID, course)
+}
+
+// RevealQuizQuestion is the resolver for the revealQuizQuestion field.
+func (r *queryResolver) RevealQuizQuestion(ctx context.Context, courseID string, lessonPath []int, questionID string) (*entities.ExtendedQuizQuestion, error) {
+	userID := httpAdapter.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errors.New("authentication required")
+	}
+
+	return r.QuizUseCase.RevealQuestion(ctx, userID, courseID, lessonPath, questionID)
+}
+
+// GenerateQuiz is the resolver for the generateQuiz field.
+func (r *queryResolver) GenerateQuiz(ctx context.Context, courseID string, lessonPath []int, includeSublessons *bool) (*entities.QuizInstance, error) {
+	userID := httpAdapter.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errors.New("authentication required")
+	}
+
+	return r.QuizUseCase.GenerateQuiz(ctx, ports.GenerateQuizInput{
+		UserID:            userID,
+		CourseID:          courseID,
+		LessonPath:        lessonPath,
+		IncludeSublessons: includeSublessons != nil && *includeSublessons,
+	})
+}
+
+// ExamStatus is the resolver for the examStatus field.
+func (r *queryResolver) ExamStatus(ctx context.Context, courseID string, lessonPath []int) (*entities.ExamStatus, error) {
+	userID := httpAdapter.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errors.New("authentication required")
+	}
+
+	status, err := r.QuizUseCase.ExamStatus(ctx, userID, courseID, lessonPath)
+	if errors.Is(err, entities.ErrNotAnExam) {
+		return nil, nil
+	}
+	return status, err
+}
+
+// QuizAttempt is the resolver for the quizAttempt field.
+func (r *queryResolver) QuizAttempt(ctx context.Context, id string) (*entities.QuizAttempt, error) {
+	userID := httpAdapter.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errors.New("authentication required")
+	}
+
+	return r.QuizUseCase.GetAttempt(ctx, userID, id)
+}
+
+// MyOpenQuizAttempts is the resolver for the myOpenQuizAttempts field.
+func (r *queryResolver) MyOpenQuizAttempts(ctx context.Context) ([]*entities.QuizDraft, error) {
+	userID := httpAdapter.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errors.New("authentication required")
+	}
+
+	drafts, err := r.QuizUseCase.OpenQuizAttempts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*entities.QuizDraft, len(drafts))
+	for i := range drafts {
+		result[i] = &drafts[i]
+	}
+
+	return result, nil
+}
+
+// QuizItemAnalysis is the resolver for the quizItemAnalysis field.
+func (r *queryResolver) QuizItemAnalysis(ctx context.Context, courseID string, quizID string) (*entities.QuizItemAnalysis, error) {
+	userID := httpAdapter.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errors.New("authentication required")
+	}
+
+	return r.QuizUseCase.ItemAnalysis(ctx, userID, courseID, quizID)
+}
+
+// DailyReview is the resolver for the dailyReview field.
+func (r *queryResolver) DailyReview(ctx context.Context, limit *int) (*entities.DailyReview, error) {
+	userID := httpAdapter.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errors.New("authentication required")
+	}
+
+	dailyCap := 0
+	if limit != nil {
+		dailyCap = *limit
+	}
+
+	return r.ReviewUseCase.DailyReview(ctx, userID, dailyCap)
+}
+
+// ExportQuiz is the resolver for the exportQuiz field.
+func (r *queryResolver) ExportQuiz(ctx context.Context, courseID string, lessonPath []int, format quizformat.Format) (*QuizExport, error) {
+	userID := httpAdapter.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errors.New("authentication required")
+	}
+
+	target := ports.EditQuizInput{UserID: userID, CourseID: courseID, LessonPath: lessonPath}
+	quiz, err := r.QuizUseCase.GetLessonQuizForEditing(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+
+	content, issues, err := quizformat.Export(format, quiz.Questions)
+	if err != nil {
+		return nil, err
+	}
+
+	skipped := make([]*entities.QuizConversionIssue, len(issues))
+	for i := range issues {
+		skipped[i] = &issues[i]
+	}
+	return &QuizExport{Format: format, Content: content, Skipped: skipped}, nil
+}
+
+// QuestionBanks is the resolver for the questionBanks field.
+func (r *queryResolver) QuestionBanks(ctx context.Context) ([]*entities.QuestionBank, error) {
+	userID := httpAdapter.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errors.New("authentication required")
+	}
+	if r.QuestionBankUseCase == nil {
+		return nil, errors.New("question banks only available for folder-based courses")
+	}
+
+	return r.QuestionBankUseCase.ListQuestionBanks(ctx, userID)
+}
+
+// QuestionBank is the resolver for the questionBank field.
+func (r *queryResolver) QuestionBank(ctx context.Context, id string) (*entities.QuestionBank, error) {
+	userID := httpAdapter.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errors.New("authentication required")
+	}
+	if r.QuestionBankUseCase == nil {
+		return nil, errors.New("question banks only available for folder-based courses")
+	}
+
+	return r.QuestionBankUseCase.GetQuestionBank(ctx, userID, id)
+}
+
+// QuestionBankAnalysis is the resolver for the questionBankAnalysis field.
+func (r *queryResolver) QuestionBankAnalysis(ctx context.Context, bankID string) (*entities.QuestionBankAnalysis, error) {
+	userID := httpAdapter.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errors.New("authentication required")
+	}
+	if r.QuestionBankUseCase == nil {
+		return nil, errors.New("question banks only available for folder-based courses")
+	}
+
+	return r.QuestionBankUseCase.QuestionBankAnalysis(ctx, userID, bankID)
+}
+
+// LessonRevisions is the resolver for the lessonRevisions field.
+func (r *queryResolver) LessonRevisions(ctx context.Context, libraryCourseID string, lessonPath []int) ([]*entities.LessonRevision, error) {
+	if _, err := r.lessonEditor(ctx, libraryCourseID); err != nil {
+		return nil, err
+	}
+
+	revisions, err := r.FolderCourseRepo.ListLessonRevisions(ctx, libraryCourseID, lessonPath)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*entities.LessonRevision, len(revisions))
+	for i := range revisions {
+		result[i] = &revisions[i]
+	}
+	return result, nil
+}
+
+// LessonRevisionDiff is the resolver for the lessonRevisionDiff field.
+func (r *queryResolver) LessonRevisionDiff(ctx context.Context, libraryCourseID string, lessonPath []int, fromRevision int, toRevision int) (string, error) {
+	if _, err := r.lessonEditor(ctx, libraryCourseID); err != nil {
+		return "", err
+	}
+
+	from, err := r.FolderCourseRepo.GetLessonRevision(ctx, libraryCourseID, lessonPath, fromRevision)
+	if err != nil {
+		return "", err
+	}
+	to, err := r.FolderCourseRepo.GetLessonRevision(ctx, libraryCourseID, lessonPath, toRevision)
+	if err != nil {
+		return "", err
+	}
+
+	return textdiff.Unified(fmt.Sprintf("revision %d", from.ID), fmt.Sprintf("revision %d", to.ID), []byte(from.Content), []byte(to.Content)), nil
+}
+
+// Responses is the resolver for the responses field.
+func (r *quizAttemptResolver) Responses(ctx context.Context, obj *entities.QuizAttempt) ([]*entities.ResponseReview, error) {
+	userID := httpAdapter.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errors.New("authentication required")
+	}
+
+	reviews, err := r.QuizUseCase.ReviewAttempt(ctx, userID, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*entities.ResponseReview, len(reviews))
+	for i := range reviews {
+		result[i] = &reviews[i]
+	}
+	return result, nil
+}
+
+// LessonPath is the resolver for the lessonPath field.
+func (r *quizDraftResolver) LessonPath(ctx context.Context, obj *entities.QuizDraft) ([]int, error) {
+	return obj.LessonPath()
+}
+
+// InstanceID is the resolver for the instanceId field.
+func (r *quizDraftResolver) InstanceID(ctx context.Context, obj *entities.QuizDraft) (*string, error) {
+	if obj.InstanceID == "" {
+		return nil, nil
+	}
+	return &obj.InstanceID, nil
+}
+
+// SessionID is the resolver for the sessionId field.
+func (r *quizDraftResolver) SessionID(ctx context.Context, obj *entities.QuizDraft) (*string, error) {
+	if obj.SessionID == "" {
+		return nil, nil
+	}
+	return &obj.SessionID, nil
+}
+
+// UserAnswer is the resolver for the userAnswer field.
+func (r *quizDraftAnswerResolver) UserAnswer(ctx context.Context, obj *entities.QuizAnswer) (string, error) {
+	return string(obj.Answer), nil
+}
+
+// TimeTakenSeconds is the resolver for the timeTakenSeconds field.
+func (r *quizDraftAnswerResolver) TimeTakenSeconds(ctx context.Context, obj *entities.QuizAnswer) (*int, error) {
+	if obj.TimeTakenSec == 0 {
+		return nil, nil
+	}
+	return &obj.TimeTakenSec, nil
+}
+
+// CorrectIndex is the resolver for the correctIndex field.
+func (r *quizQuestionResolver) CorrectIndex(ctx context.Context, obj *entities.QuizQuestion) (*int, error) {
+	if obj.AnswerKeyHidden {
+		return nil, nil
+	}
+	return &obj.CorrectIndex, nil
+}
+
 // UserAnswer is the resolver for the userAnswer field.
-func (r *quizResponseResolver) UserAnswer(ctx context.Context, obj *entities.QuizResponse) (string, error) {
+func (r *quizResponseResolver) UserAnswer(ctx context.Context, obj *entities.ResponseReview) (string, error) {
 	return string(obj.UserAnswer), nil
 }
 
 // TimeTakenSeconds is the resolver for the timeTakenSeconds field.
-func (r *quizResponseResolver) TimeTakenSeconds(ctx context.Context, obj *entities.QuizResponse) (*int, error) {
+func (r *quizResponseResolver) TimeTakenSeconds(ctx context.Context, obj *entities.ResponseReview) (*int, error) {
 	if obj.TimeTakenSec == 0 {
 		return nil, nil
 	}
 	return &obj.TimeTakenSec, nil
 }
 
+// QuestionType is the resolver for the questionType field.
+func (r *quizResponseResolver) QuestionType(ctx context.Context, obj *entities.ResponseReview) (*entities.QuestionType, error) {
+	if obj.QuestionType == "" {
+		return nil, nil
+	}
+	return &obj.QuestionType, nil
+}
+
 // LibraryCourse is the resolver for the libraryCourse field on UserCourse.
 func (r *userCourseResolver) LibraryCourse(ctx context.Context, obj *entities.UserCourse) (*entities.LibraryCourse, error) {
 	return r.LibraryCourseRepo.GetByID(ctx, obj.LibraryCourseID)
 }
 
+// CompletionReasons is the resolver for the completionReasons field.
+func (r *userCourseResolver) CompletionReasons(ctx context.Context, obj *entities.UserCourse) ([]*LessonCompletionReason, error) {
+	reasons := make([]*LessonCompletionReason, 0, len(obj.CompletionReasons))
+	for lessonIndex, reason := range obj.CompletionReasons {
+		reasons = append(reasons, &LessonCompletionReason{
+			LessonIndex: lessonIndex,
+			Reason:      string(reason),
+		})
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		return reasons[i].LessonIndex < reasons[j].LessonIndex
+	})
+	return reasons, nil
+}
+
 // Attachment returns AttachmentResolver implementation.
 func (r *Resolver) Attachment() AttachmentResolver { return &attachmentResolver{r} }
 
+// CalibrationReport returns CalibrationReportResolver implementation.
+func (r *Resolver) CalibrationReport() CalibrationReportResolver {
+	return &calibrationReportResolver{r}
+}
+
+// ExamConfig returns ExamConfigResolver implementation.
+func (r *Resolver) ExamConfig() ExamConfigResolver { return &examConfigResolver{r} }
+
+// ExtendedQuizQuestion returns ExtendedQuizQuestionResolver implementation.
+func (r *Resolver) ExtendedQuizQuestion() ExtendedQuizQuestionResolver {
+	return &extendedQuizQuestionResolver{r}
+}
+
 // Lesson returns LessonResolver implementation.
 func (r *Resolver) Lesson() LessonResolver { return &lessonResolver{r} }
 
@@ -1071,6 +1635,18 @@ func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
+// QuizAttempt returns QuizAttemptResolver implementation.
+func (r *Resolver) QuizAttempt() QuizAttemptResolver { return &quizAttemptResolver{r} }
+
+// QuizDraft returns QuizDraftResolver implementation.
+func (r *Resolver) QuizDraft() QuizDraftResolver { return &quizDraftResolver{r} }
+
+// QuizDraftAnswer returns QuizDraftAnswerResolver implementation.
+func (r *Resolver) QuizDraftAnswer() QuizDraftAnswerResolver { return &quizDraftAnswerResolver{r} }
+
+// QuizQuestion returns QuizQuestionResolver implementation.
+func (r *Resolver) QuizQuestion() QuizQuestionResolver { return &quizQuestionResolver{r} }
+
 // QuizResponse returns QuizResponseResolver implementation.
 func (r *Resolver) QuizResponse() QuizResponseResolver { return &quizResponseResolver{r} }
 
@@ -1078,9 +1654,16 @@ func (r *Resolver) QuizResponse() QuizResponseResolver { return &quizResponseRes
 func (r *Resolver) UserCourse() UserCourseResolver { return &userCourseResolver{r} }
 
 type attachmentResolver struct{ *Resolver }
+type calibrationReportResolver struct{ *Resolver }
+type examConfigResolver struct{ *Resolver }
+type extendedQuizQuestionResolver struct{ *Resolver }
 type lessonResolver struct{ *Resolver }
 type libraryCourseResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type quizAttemptResolver struct{ *Resolver }
+type quizDraftResolver struct{ *Resolver }
+type quizDraftAnswerResolver struct{ *Resolver }
+type quizQuestionResolver struct{ *Resolver }
 type quizResponseResolver struct{ *Resolver }
 type userCourseResolver struct{ *Resolver }
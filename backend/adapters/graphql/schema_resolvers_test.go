@@ -0,0 +1,53 @@
+package graphql
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/project/backend/adapters/folder"
+	httpAdapter "github.com/project/backend/adapters/http"
+	"github.com/project/backend/domain/entities"
+)
+
+func TestMutationResolver_RefreshCourses(t *testing.T) {
+	root := t.TempDir()
+	lessonPath := filepath.Join(root, "go-basics", "lessons", "00-intro")
+	if err := os.MkdirAll(lessonPath, 0755); err != nil {
+		t.Fatalf("failed to create course: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "go-basics", "course.json"), []byte(`{"id": "go-basics", "title": "Go Basics"}`), 0644); err != nil {
+		t.Fatalf("failed to write course: %v", err)
+	}
+
+	resolver := &mutationResolver{&Resolver{
+		FolderCourseRepo: folder.NewFolderCourseRepository(root),
+		Editors:          entities.NewUserGroup([]string{"editor-1"}),
+		Admins:           entities.NewUserGroup([]string{"admin-1"}),
+	}}
+	signedIn := func(userID string) context.Context {
+		return context.WithValue(context.Background(), httpAdapter.UserIDKey, userID)
+	}
+
+	// Editing courses does not make a user an administrator
+	for _, userID := range []string{"user-1", "editor-1"} {
+		if ok, err := resolver.RefreshCourses(signedIn(userID)); err == nil || ok {
+			t.Errorf("expected %s to be denied, got %v, %v", userID, ok, err)
+		}
+	}
+	if _, err := resolver.RefreshCourses(context.Background()); err == nil {
+		t.Error("expected an anonymous caller to be denied")
+	}
+
+	ok, err := resolver.RefreshCourses(signedIn("admin-1"))
+	if err != nil || !ok {
+		t.Fatalf("expected an administrator to refresh the courses, got %v, %v", ok, err)
+	}
+
+	// Without folder courses there is nothing to refresh
+	resolver.FolderCourseRepo = nil
+	if _, err := resolver.RefreshCourses(signedIn("admin-1")); err == nil {
+		t.Error("expected an error without folder courses")
+	}
+}
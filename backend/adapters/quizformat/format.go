@@ -0,0 +1,156 @@
+// Package quizformat converts extended quizzes to and from the quiz formats used by other
+// learning platforms: GIFT, Moodle XML and IMS QTI 2.1
+package quizformat
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/project/backend/domain/entities"
+)
+
+// Format identifies a quiz interchange format
+type Format string
+
+const (
+	FormatGIFT      Format = "gift"
+	FormatMoodleXML Format = "moodle_xml"
+	FormatQTI       Format = "qti"
+)
+
+// ErrUnknownFormat is returned for formats other than those above
+var ErrUnknownFormat = errors.New("unknown quiz format")
+
+var (
+	unsafeIDChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+	htmlBreaks    = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|li|h[1-6]|pre)>`)
+	htmlTags      = regexp.MustCompile(`<[^>]*>`)
+	blankLines    = regexp.MustCompile(`\n{3,}`)
+	blank         = regexp.MustCompile(`_{3,}`)
+)
+
+// blankMarker stands for the missing word in fill_blank question text
+const blankMarker = "_______"
+
+// Import parses a quiz in the given format
+// Items that have no equivalent question type, or that are incomplete, are reported as
+// issues instead of being imported; an error means the payload itself could not be read
+func Import(format Format, payload string) ([]entities.ExtendedQuizQuestion, []entities.QuizConversionIssue, error) {
+	switch format {
+	case FormatGIFT:
+		questions, issues := importGIFT(payload)
+		return questions, issues, nil
+	case FormatMoodleXML:
+		return importMoodleXML(payload)
+	case FormatQTI:
+		return importQTI(payload)
+	default:
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+	}
+}
+
+// Export writes questions in the given format
+// Questions the format cannot represent are reported as issues and left out
+func Export(format Format, questions []entities.ExtendedQuizQuestion) (string, []entities.QuizConversionIssue, error) {
+	switch format {
+	case FormatGIFT:
+		content, issues := exportGIFT(questions)
+		return content, issues, nil
+	case FormatMoodleXML:
+		return exportMoodleXML(questions)
+	case FormatQTI:
+		content, issues := exportQTI(questions)
+		return content, issues, nil
+	default:
+		return "", nil, fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+	}
+}
+
+// issue reports a question that could not be converted
+func issue(item, format string, args ...any) entities.QuizConversionIssue {
+	return entities.QuizConversionIssue{Item: item, Reason: fmt.Sprintf(format, args...)}
+}
+
+// questionID turns an item's name into a question ID, falling back to prefix and the
+// item's position when the name has nothing usable
+func questionID(name, prefix string, n int) string {
+	id := strings.Trim(unsafeIDChars.ReplaceAllString(strings.TrimSpace(name), "-"), "-._")
+	if len(id) > 64 {
+		id = strings.Trim(id[:64], "-._")
+	}
+	if !entities.IsValidQuestionID(id) {
+		id = fmt.Sprintf("%s-%d", prefix, n)
+	}
+	return id
+}
+
+// newQuestion starts an imported question with the difficulty its type defaults to
+func newQuestion(id string, questionType entities.QuestionType, text, concept string) entities.ExtendedQuizQuestion {
+	return entities.ExtendedQuizQuestion{
+		ID:         id,
+		Type:       questionType,
+		Difficulty: entities.DefaultDifficulty(questionType),
+		Concept:    concept,
+		Question:   text,
+	}
+}
+
+// htmlToText reduces HTML question text to plain text, keeping paragraph breaks
+func htmlToText(s string) string {
+	s = htmlBreaks.ReplaceAllString(s, "\n")
+	s = htmlTags.ReplaceAllString(s, "")
+	return normalizeText(html.UnescapeString(s))
+}
+
+// normalizeText trims each line and collapses runs of blank lines
+func normalizeText(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+// lastSegment returns the last part of a category path such as "$course$/Go/Interfaces"
+func lastSegment(path string) string {
+	path = strings.TrimRight(strings.TrimSpace(path), "/")
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		path = path[i+1:]
+	}
+	if strings.HasPrefix(path, "$") && strings.HasSuffix(path, "$") {
+		return ""
+	}
+	return strings.TrimSpace(path)
+}
+
+// questionText returns the text shown for a question in formats without code blocks
+// Code analysis snippets are appended as a Markdown code block
+func questionText(q entities.ExtendedQuizQuestion) string {
+	if q.CodeSnippet == "" {
+		return q.Question
+	}
+	return q.Question + "\n\n```" + q.Language + "\n" + q.CodeSnippet + "\n```"
+}
+
+// tolerance returns the absolute tolerance a numeric question accepts
+func tolerance(q entities.ExtendedQuizQuestion) float64 {
+	if q.CorrectValue == nil {
+		return q.AbsoluteTolerance
+	}
+	relative := q.RelativeTolerance * *q.CorrectValue
+	if relative < 0 {
+		relative = -relative
+	}
+	return max(q.AbsoluteTolerance, relative)
+}
+
+// unsupportedExport reports question types that no export format can represent
+func unsupportedExport(q entities.ExtendedQuizQuestion) (entities.QuizConversionIssue, bool) {
+	if q.Type == entities.QuestionTypeCodeExercise {
+		return issue(q.ID, "code exercises are graded by running tests and cannot be exported"), true
+	}
+	return entities.QuizConversionIssue{}, false
+}
@@ -0,0 +1,69 @@
+package quizformat
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/project/backend/domain/entities"
+)
+
+func sampleQuestions() []entities.ExtendedQuizQuestion {
+	yes := true
+	value := 12.5
+	return []entities.ExtendedQuizQuestion{
+		{ID: "mc1", Type: entities.QuestionTypeMultipleChoice, Difficulty: 2, Question: "Which layer owns ports?", Options: []string{"Adapters", "Domain {core}", "UI"}, CorrectIndex: 1, Explanation: "Ports belong to the domain."},
+		{ID: "tf1", Type: entities.QuestionTypeTrueFalse, Difficulty: 1, Question: "Adapters depend on the domain.", CorrectAnswer: &yes},
+		{ID: "ms1", Type: entities.QuestionTypeMultipleSelect, Difficulty: 3, Question: "Select the driving adapters", Options: []string{"HTTP handler", "SQL repository", "CLI"}, CorrectIndices: []int{0, 2}},
+		{ID: "m1", Type: entities.QuestionTypeMatching, Difficulty: 3, Question: "Match each adapter to its port", LeftColumn: []string{"GraphQL resolver", "SQLite repository"}, RightColumn: []string{"QuizPort", "QuizRepository"}, CorrectPairs: [][]int{{0, 0}, {1, 1}}},
+		{ID: "fb1", Type: entities.QuestionTypeFillBlank, Difficulty: 2, Question: "The domain defines _______ for the outside world.", AcceptedAnswers: []string{"ports", "interfaces"}},
+		{ID: "sa1", Type: entities.QuestionTypeShortAnswer, Difficulty: 2, Question: "Name the pattern", AcceptedAnswers: []string{"hexagonal"}},
+		{ID: "n1", Type: entities.QuestionTypeNumeric, Difficulty: 2, Question: "Median latency?", CorrectValue: &value, AbsoluteTolerance: 0.5, Unit: "ms"},
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, format := range []Format{FormatGIFT, FormatMoodleXML, FormatQTI} {
+		exported, issues, err := Export(format, sampleQuestions())
+		if err != nil || len(issues) != 0 {
+			t.Fatalf("%s: Export failed: %v %v", format, err, issues)
+		}
+
+		imported, issues, err := Import(format, exported)
+		if err != nil || len(issues) != 0 {
+			t.Fatalf("%s: Import failed: %v %v\n%s", format, err, issues, exported)
+		}
+		expected := sampleQuestions()
+		if len(imported) != len(expected) {
+			t.Fatalf("%s: expected %d questions, got %d", format, len(expected), len(imported))
+		}
+		for i, got := range imported {
+			want := expected[i]
+			if format == FormatGIFT {
+				// GIFT numeric answers have no unit
+				want.Unit = ""
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s: question %d round tripped as\n%+v\nwant\n%+v", format, i, got, want)
+			}
+		}
+	}
+}
+
+func TestExport_ReportsUnsupportedQuestions(t *testing.T) {
+	questions := []entities.ExtendedQuizQuestion{
+		{ID: "ce1", Type: entities.QuestionTypeCodeExercise, Question: "Implement it", TestCode: "package exercise"},
+		{ID: "o1", Type: entities.QuestionTypeOrdering, Question: "Order", Items: []string{"a", "b"}, CorrectOrder: []int{1, 0}},
+	}
+
+	_, issues, err := Export(FormatGIFT, questions)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if len(issues) != 2 || issues[0].Item != "ce1" || issues[1].Item != "o1" {
+		t.Errorf("expected the code exercise and the ordering question to be reported, got %+v", issues)
+	}
+
+	if _, _, err := Export("csv", questions); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,509 @@
+package quizformat
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/project/backend/domain/entities"
+)
+
+// giftSpecial are the characters GIFT text escapes with a backslash
+const giftSpecial = "~=#{}:"
+
+var giftTextFormat = regexp.MustCompile(`^\[(html|moodle|plain|markdown)\]`)
+
+// giftAnswer is one answer in a GIFT answer block: "=right", "~wrong" or "~%50%partly right"
+// Per-answer feedback has no equivalent and is dropped
+type giftAnswer struct {
+	marker byte
+	weight *float64
+	text   string
+}
+
+// correct reports whether the answer earns credit
+func (a giftAnswer) correct() bool {
+	if a.weight != nil {
+		return *a.weight > 0
+	}
+	return a.marker == '='
+}
+
+// fullCredit reports whether the answer earns full credit
+func (a giftAnswer) fullCredit() bool {
+	if a.weight != nil {
+		return *a.weight >= 100
+	}
+	return a.marker == '='
+}
+
+// importGIFT parses questions in Moodle's GIFT text format
+// $CATEGORY lines set the concept of the questions that follow them
+func importGIFT(payload string) ([]entities.ExtendedQuizQuestion, []entities.QuizConversionIssue) {
+	var questions []entities.ExtendedQuizQuestion
+	var issues []entities.QuizConversionIssue
+
+	concept := ""
+	n := 0
+	for _, block := range giftBlocks(payload) {
+		if category, ok := strings.CutPrefix(block, "$CATEGORY:"); ok {
+			concept = lastSegment(category)
+			continue
+		}
+
+		n++
+		question, err := parseGIFTQuestion(block, n, concept)
+		if err != nil {
+			issues = append(issues, issue(question.ID, "%v", err))
+			continue
+		}
+		questions = append(questions, question)
+	}
+
+	return questions, issues
+}
+
+// giftBlocks splits GIFT text into questions, which are separated by blank lines,
+// dropping // comment lines
+func giftBlocks(payload string) []string {
+	var blocks []string
+	var current []string
+	flush := func() {
+		if len(current) > 0 {
+			blocks = append(blocks, strings.Join(current, "\n"))
+			current = nil
+		}
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(payload, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			flush()
+		case strings.HasPrefix(trimmed, "//"):
+			continue
+		default:
+			current = append(current, trimmed)
+		}
+	}
+	flush()
+
+	return blocks
+}
+
+// parseGIFTQuestion parses one question; the returned question carries its ID even when
+// it cannot be imported, so the issue can name it
+func parseGIFTQuestion(block string, n int, concept string) (entities.ExtendedQuizQuestion, error) {
+	text := block
+	title := ""
+	if rest, ok := strings.CutPrefix(text, "::"); ok {
+		end := indexUnescaped(rest, "::")
+		if end < 0 {
+			return entities.ExtendedQuizQuestion{ID: fmt.Sprintf("gift-%d", n)}, fmt.Errorf("question title is not closed")
+		}
+		title = unescapeGIFT(strings.TrimSpace(rest[:end]))
+		text = strings.TrimSpace(rest[end+2:])
+	}
+	id := questionID(title, "gift", n)
+
+	format := ""
+	if m := giftTextFormat.FindStringSubmatch(text); m != nil {
+		format = m[1]
+		text = text[len(m[0]):]
+	}
+	clean := func(s string) string {
+		s = unescapeGIFT(strings.TrimSpace(s))
+		if format == "html" {
+			s = htmlToText(s)
+		}
+		return s
+	}
+
+	open := indexUnescaped(text, "{")
+	if open < 0 {
+		return entities.ExtendedQuizQuestion{ID: id}, fmt.Errorf("description items have no answers")
+	}
+	end := indexUnescaped(text[open:], "}")
+	if end < 0 {
+		return entities.ExtendedQuizQuestion{ID: id}, fmt.Errorf("answer block is not closed")
+	}
+	stem, tail := clean(text[:open]), clean(text[open+end+1:])
+	body, explanation := cutUnescaped(text[open+1:open+end], "####")
+	body = strings.TrimSpace(body)
+
+	// Text after the answer block makes it a missing word question
+	withBlank := stem
+	if tail != "" {
+		withBlank = strings.TrimSpace(stem + " " + blankMarker + " " + tail)
+	}
+
+	var question entities.ExtendedQuizQuestion
+	var err error
+	switch {
+	case body == "":
+		return entities.ExtendedQuizQuestion{ID: id}, fmt.Errorf("essay questions cannot be graded automatically")
+	case body[0] == '#':
+		question, err = parseGIFTNumeric(id, stem, concept, body[1:])
+	case isGIFTBoolean(body):
+		value, _ := cutUnescaped(body, "#")
+		answer := strings.HasPrefix(strings.ToUpper(strings.TrimSpace(value)), "T")
+		question = newQuestion(id, entities.QuestionTypeTrueFalse, stem, concept)
+		question.CorrectAnswer = &answer
+	default:
+		question, err = parseGIFTAnswers(id, stem, withBlank, concept, body, tail != "", clean)
+	}
+	if err != nil {
+		return entities.ExtendedQuizQuestion{ID: id}, err
+	}
+
+	question.Explanation = clean(explanation)
+	return question, nil
+}
+
+// parseGIFTAnswers parses the answer list of choice, matching, short answer and missing word questions
+func parseGIFTAnswers(id, stem, withBlank, concept, body string, missingWord bool, clean func(string) string) (entities.ExtendedQuizQuestion, error) {
+	answers, err := splitGIFTAnswers(body)
+	if err != nil {
+		return entities.ExtendedQuizQuestion{}, err
+	}
+
+	hasChoices, weighted, matching := false, false, true
+	for _, a := range answers {
+		hasChoices = hasChoices || a.marker == '~'
+		weighted = weighted || a.weight != nil
+		matching = matching && a.marker == '=' && indexUnescaped(a.text, "->") >= 0
+	}
+
+	switch {
+	case matching:
+		q := newQuestion(id, entities.QuestionTypeMatching, stem, concept)
+		rights := make(map[string]int)
+		for _, a := range answers {
+			left, right := cutUnescaped(a.text, "->")
+			left, right = clean(left), clean(right)
+			index, ok := rights[right]
+			if !ok {
+				index = len(q.RightColumn)
+				rights[right] = index
+				q.RightColumn = append(q.RightColumn, right)
+			}
+			// An empty left side only adds a distractor to the right column
+			if left != "" {
+				q.CorrectPairs = append(q.CorrectPairs, []int{len(q.LeftColumn), index})
+				q.LeftColumn = append(q.LeftColumn, left)
+			}
+		}
+		return q, nil
+
+	case hasChoices:
+		var correct []int
+		options := make([]string, len(answers))
+		for i, a := range answers {
+			options[i] = clean(a.text)
+			if a.correct() {
+				correct = append(correct, i)
+			}
+		}
+		if len(correct) == 0 {
+			return entities.ExtendedQuizQuestion{}, fmt.Errorf("no answer is marked correct")
+		}
+		if len(correct) == 1 && answers[correct[0]].fullCredit() {
+			q := newQuestion(id, entities.QuestionTypeMultipleChoice, withBlank, concept)
+			q.Options = options
+			q.CorrectIndex = correct[0]
+			return q, nil
+		}
+		q := newQuestion(id, entities.QuestionTypeMultipleSelect, withBlank, concept)
+		q.Options = options
+		q.CorrectIndices = correct
+		return q, nil
+
+	default:
+		questionType := entities.QuestionTypeShortAnswer
+		text := stem
+		if missingWord {
+			questionType, text = entities.QuestionTypeFillBlank, withBlank
+		}
+		q := newQuestion(id, questionType, text, concept)
+		for _, a := range answers {
+			// Answers worth partial credit have no equivalent and are left out
+			if a.fullCredit() || !weighted {
+				q.AcceptedAnswers = append(q.AcceptedAnswers, clean(a.text))
+			}
+		}
+		if len(q.AcceptedAnswers) == 0 {
+			return entities.ExtendedQuizQuestion{}, fmt.Errorf("no answer earns full credit")
+		}
+		return q, nil
+	}
+}
+
+// parseGIFTNumeric parses a numeric answer block ("3.14:0.01", "1..5" or "=3:0 =%50%3:1")
+// The first answer worth full credit is the correct value
+func parseGIFTNumeric(id, stem, concept, body string) (entities.ExtendedQuizQuestion, error) {
+	candidates := []string{body}
+	if indexUnescaped(body, "=") >= 0 {
+		answers, err := splitGIFTAnswers(body)
+		if err != nil {
+			return entities.ExtendedQuizQuestion{}, err
+		}
+		candidates = nil
+		for _, a := range answers {
+			if a.fullCredit() {
+				candidates = append(candidates, a.text)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return entities.ExtendedQuizQuestion{}, fmt.Errorf("no answer earns full credit")
+	}
+
+	answer, _ := cutUnescaped(candidates[0], "#")
+	answer = strings.TrimSpace(unescapeGIFT(answer))
+
+	var value, tol float64
+	var err error
+	if low, high, ok := strings.Cut(answer, ".."); ok {
+		var lo, hi float64
+		if lo, err = strconv.ParseFloat(strings.TrimSpace(low), 64); err == nil {
+			hi, err = strconv.ParseFloat(strings.TrimSpace(high), 64)
+		}
+		value, tol = (lo+hi)/2, (hi-lo)/2
+	} else if number, margin, ok := strings.Cut(answer, ":"); ok {
+		if value, err = strconv.ParseFloat(strings.TrimSpace(number), 64); err == nil {
+			tol, err = strconv.ParseFloat(strings.TrimSpace(margin), 64)
+		}
+	} else {
+		value, err = strconv.ParseFloat(answer, 64)
+	}
+	if err != nil {
+		return entities.ExtendedQuizQuestion{}, fmt.Errorf("numeric answer %q is not a number", answer)
+	}
+
+	q := newQuestion(id, entities.QuestionTypeNumeric, stem, concept)
+	q.CorrectValue = &value
+	q.AbsoluteTolerance = max(tol, -tol)
+	return q, nil
+}
+
+// splitGIFTAnswers splits an answer block at each unescaped = or ~
+func splitGIFTAnswers(body string) ([]giftAnswer, error) {
+	var answers []giftAnswer
+	start := -1
+	add := func(end int) error {
+		if start < 0 {
+			return nil
+		}
+		a := giftAnswer{marker: body[start]}
+		text := strings.TrimSpace(body[start+1 : end])
+		if rest, ok := strings.CutPrefix(text, "%"); ok {
+			value, after, found := strings.Cut(rest, "%")
+			weight, err := strconv.ParseFloat(value, 64)
+			if !found || err != nil {
+				return fmt.Errorf("answer weight %q is not a percentage", value)
+			}
+			a.weight = &weight
+			text = after
+		}
+		text, _ = cutUnescaped(text, "#")
+		a.text = strings.TrimSpace(text)
+		answers = append(answers, a)
+		return nil
+	}
+
+	for i := 0; i < len(body); i++ {
+		switch body[i] {
+		case '\\':
+			i++
+		case '=', '~':
+			if err := add(i); err != nil {
+				return nil, err
+			}
+			start = i
+		}
+	}
+	if err := add(len(body)); err != nil {
+		return nil, err
+	}
+
+	if len(answers) == 0 {
+		return nil, fmt.Errorf("answer block has no answers")
+	}
+	return answers, nil
+}
+
+// isGIFTBoolean reports whether an answer block is a true/false answer
+func isGIFTBoolean(body string) bool {
+	value, _ := cutUnescaped(body, "#")
+	switch strings.ToUpper(strings.TrimSpace(value)) {
+	case "T", "TRUE", "F", "FALSE":
+		return true
+	}
+	return false
+}
+
+// indexUnescaped returns the index of the first sub in s that is not escaped, or -1
+func indexUnescaped(s, sub string) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.HasPrefix(s[i:], sub) {
+			return i
+		}
+	}
+	return -1
+}
+
+// cutUnescaped splits s around the first unescaped sep
+func cutUnescaped(s, sep string) (before, after string) {
+	if i := indexUnescaped(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):]
+	}
+	return s, ""
+}
+
+// unescapeGIFT removes GIFT escapes; \n stands for a line break
+func unescapeGIFT(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			switch next := s[i+1]; {
+			case next == 'n':
+				b.WriteByte('\n')
+				i++
+				continue
+			case next == '\\' || strings.IndexByte(giftSpecial, next) >= 0:
+				b.WriteByte(next)
+				i++
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// escapeGIFT escapes text for GIFT, writing line breaks as \n so that blank lines in
+// code do not end the question
+func escapeGIFT(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\n':
+			b.WriteString(`\n`)
+		case c == '\r':
+		case c == '\\' || strings.IndexByte(giftSpecial, c) >= 0:
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// exportGIFT writes questions in GIFT, with question text in Markdown
+func exportGIFT(questions []entities.ExtendedQuizQuestion) (string, []entities.QuizConversionIssue) {
+	var b strings.Builder
+	var issues []entities.QuizConversionIssue
+
+	for _, q := range questions {
+		if problem, ok := unsupportedExport(q); ok {
+			issues = append(issues, problem)
+			continue
+		}
+		answers, err := giftAnswerBlock(q)
+		if err != nil {
+			issues = append(issues, issue(q.ID, "%v", err))
+			continue
+		}
+		if q.Explanation != "" {
+			answers += "####" + escapeGIFT(q.Explanation) + "\n"
+		}
+
+		text := escapeGIFT(questionText(q))
+		block := "{" + answers + "}"
+		if q.Type == entities.QuestionTypeFillBlank && blank.MatchString(text) {
+			location := blank.FindStringIndex(text)
+			text = text[:location[0]] + block + text[location[1]:]
+		} else {
+			text += " " + block
+		}
+		fmt.Fprintf(&b, "::%s::[markdown]%s\n\n", escapeGIFT(q.ID), text)
+	}
+
+	return b.String(), issues
+}
+
+// giftAnswerBlock writes the contents of a question's answer block
+func giftAnswerBlock(q entities.ExtendedQuizQuestion) (string, error) {
+	var b strings.Builder
+	b.WriteString("\n")
+
+	switch q.Type {
+	case entities.QuestionTypeMultipleChoice, entities.QuestionTypeCodeAnalysis:
+		for i, option := range q.Options {
+			marker := "~"
+			if i == q.CorrectIndex {
+				marker = "="
+			}
+			fmt.Fprintf(&b, "%s%s\n", marker, escapeGIFT(option))
+		}
+	case entities.QuestionTypeTrueFalse:
+		if q.CorrectAnswer == nil {
+			return "", fmt.Errorf("true/false question has no answer")
+		}
+		return strings.ToUpper(strconv.FormatBool(*q.CorrectAnswer)) + "\n", nil
+	case entities.QuestionTypeMultipleSelect:
+		right, wrong := giftWeights(len(q.CorrectIndices), len(q.Options)-len(q.CorrectIndices))
+		correct := make(map[int]bool, len(q.CorrectIndices))
+		for _, index := range q.CorrectIndices {
+			correct[index] = true
+		}
+		for i, option := range q.Options {
+			weight := wrong
+			if correct[i] {
+				weight = right
+			}
+			fmt.Fprintf(&b, "~%%%s%%%s\n", weight, escapeGIFT(option))
+		}
+	case entities.QuestionTypeMatching:
+		for _, pair := range q.CorrectPairs {
+			fmt.Fprintf(&b, "=%s -> %s\n", escapeGIFT(q.LeftColumn[pair[0]]), escapeGIFT(q.RightColumn[pair[1]]))
+		}
+	case entities.QuestionTypeFillBlank, entities.QuestionTypeShortAnswer:
+		if len(q.AcceptedAnswers) == 0 {
+			return "", fmt.Errorf("answer patterns have no equivalent in GIFT")
+		}
+		for _, answer := range q.AcceptedAnswers {
+			fmt.Fprintf(&b, "=%s\n", escapeGIFT(answer))
+		}
+	case entities.QuestionTypeNumeric:
+		if q.CorrectValue == nil {
+			return "", fmt.Errorf("numeric question has no value")
+		}
+		return "#" + formatNumber(*q.CorrectValue) + ":" + formatNumber(tolerance(q)) + "\n", nil
+	default:
+		return "", fmt.Errorf("%s questions have no equivalent in GIFT", q.Type)
+	}
+
+	return b.String(), nil
+}
+
+// giftWeights returns the percentages for right and wrong answers of a multiple select
+// question: the right answers share full credit and choosing every wrong one cancels it
+func giftWeights(right, wrong int) (string, string) {
+	rightWeight := 100 / float64(max(right, 1))
+	wrongWeight := -100 / float64(max(wrong, 1))
+	// Moodle matches weights to its grade options, which have five decimals
+	return formatNumber(math.Round(rightWeight*1e5) / 1e5), formatNumber(math.Round(wrongWeight*1e5) / 1e5)
+}
+
+// formatNumber writes a number in its shortest decimal form
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,76 @@
+package quizformat
+
+import (
+	"testing"
+
+	"github.com/project/backend/domain/entities"
+)
+
+const giftSample = `// Go basics
+$CATEGORY: $course$/Go/Interfaces
+
+::iface-1:: Which keyword declares an interface? {
+	=interface
+	~struct
+	~type only
+	#### Interfaces are declared with type Name interface.
+}
+
+::iface-2:: Interfaces are satisfied implicitly. {T}
+
+::iface-3:: Select the methods of io.ReadWriter {~%50%Read ~%50%Write ~%-100%Close}
+
+The zero value of an interface is {=nil =NIL} in Go.
+
+::answer:: What is the answer? {#42:0.5}
+
+::essay:: Explain duck typing. {}
+
+Just some text without an answer block.
+`
+
+func TestImportGIFT(t *testing.T) {
+	questions, issues, err := Import(FormatGIFT, giftSample)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	expectedTypes := []entities.QuestionType{
+		entities.QuestionTypeMultipleChoice,
+		entities.QuestionTypeTrueFalse,
+		entities.QuestionTypeMultipleSelect,
+		entities.QuestionTypeFillBlank,
+		entities.QuestionTypeNumeric,
+	}
+	if len(questions) != len(expectedTypes) {
+		t.Fatalf("expected %d questions, got %d: %+v", len(expectedTypes), len(questions), questions)
+	}
+	for i, q := range questions {
+		if q.Type != expectedTypes[i] {
+			t.Errorf("question %d: expected type %s, got %s", i, expectedTypes[i], q.Type)
+		}
+		if q.Concept != "Interfaces" {
+			t.Errorf("question %d: expected concept Interfaces, got %q", i, q.Concept)
+		}
+		if err := q.Validate(); err != nil {
+			t.Errorf("question %d does not validate: %v", i, err)
+		}
+	}
+
+	if questions[0].ID != "iface-1" || questions[0].CorrectIndex != 0 || questions[0].Explanation == "" {
+		t.Errorf("unexpected multiple choice question: %+v", questions[0])
+	}
+	if questions[2].CorrectIndices == nil || len(questions[2].CorrectIndices) != 2 {
+		t.Errorf("expected two correct options, got %v", questions[2].CorrectIndices)
+	}
+	if questions[3].Question != "The zero value of an interface is _______ in Go." {
+		t.Errorf("unexpected fill blank text %q", questions[3].Question)
+	}
+	if *questions[4].CorrectValue != 42 || questions[4].AbsoluteTolerance != 0.5 {
+		t.Errorf("unexpected numeric question: %+v", questions[4])
+	}
+
+	if len(issues) != 2 || issues[0].Item != "essay" {
+		t.Errorf("expected the essay and the description to be reported, got %+v", issues)
+	}
+}
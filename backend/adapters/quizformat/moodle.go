@@ -0,0 +1,389 @@
+package quizformat
+
+import (
+	"cmp"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/project/backend/domain/entities"
+)
+
+// moodleQuiz is the root of a Moodle XML question export
+type moodleQuiz struct {
+	XMLName   xml.Name         `xml:"quiz"`
+	Questions []moodleQuestion `xml:"question"`
+}
+
+type moodleQuestion struct {
+	Type            string              `xml:"type,attr"`
+	Category        *moodleText         `xml:"category,omitempty"`
+	Name            *moodleText         `xml:"name,omitempty"`
+	QuestionText    *moodleText         `xml:"questiontext,omitempty"`
+	GeneralFeedback *moodleText         `xml:"generalfeedback,omitempty"`
+	Single          string              `xml:"single,omitempty"`
+	UseCase         string              `xml:"usecase,omitempty"`
+	Answers         []moodleAnswer      `xml:"answer"`
+	Subquestions    []moodleSubquestion `xml:"subquestion"`
+	Units           []moodleUnit        `xml:"units>unit"`
+}
+
+// moodleText is text with the format it is written in: html, markdown, plain_text or
+// moodle_auto_format
+type moodleText struct {
+	Format string `xml:"format,attr,omitempty"`
+	Text   string `xml:"text"`
+}
+
+type moodleAnswer struct {
+	Fraction  string `xml:"fraction,attr"`
+	Format    string `xml:"format,attr,omitempty"`
+	Text      string `xml:"text"`
+	Tolerance string `xml:"tolerance,omitempty"`
+}
+
+type moodleSubquestion struct {
+	Format string     `xml:"format,attr,omitempty"`
+	Text   string     `xml:"text"`
+	Answer moodleText `xml:"answer"`
+}
+
+type moodleUnit struct {
+	Multiplier string `xml:"multiplier"`
+	Name       string `xml:"unit_name"`
+}
+
+// moodlePlain returns Moodle text as plain text; HTML, Moodle's default, is reduced to text
+func moodlePlain(format, text string) string {
+	switch format {
+	case "markdown", "plain_text", "moodle_auto_format":
+		return strings.TrimSpace(text)
+	default:
+		return htmlToText(text)
+	}
+}
+
+// plain returns the text of an optional element
+func (t *moodleText) plain() string {
+	if t == nil {
+		return ""
+	}
+	return moodlePlain(t.Format, t.Text)
+}
+
+// fraction returns the share of the grade an answer is worth, in percent
+func (a moodleAnswer) fraction() float64 {
+	value, err := strconv.ParseFloat(strings.TrimSpace(a.Fraction), 64)
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+// importMoodleXML parses a Moodle XML question export
+// Category entries set the concept of the questions that follow them
+func importMoodleXML(payload string) ([]entities.ExtendedQuizQuestion, []entities.QuizConversionIssue, error) {
+	var quiz moodleQuiz
+	if err := xml.Unmarshal([]byte(payload), &quiz); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse Moodle XML: %w", err)
+	}
+
+	var questions []entities.ExtendedQuizQuestion
+	var issues []entities.QuizConversionIssue
+	concept := ""
+	n := 0
+	for _, mq := range quiz.Questions {
+		if mq.Type == "category" {
+			if mq.Category != nil {
+				concept = lastSegment(mq.Category.Text)
+			}
+			continue
+		}
+
+		n++
+		name := ""
+		if mq.Name != nil {
+			name = mq.Name.Text
+		}
+		id := questionID(name, "moodle", n)
+		question, err := convertMoodleQuestion(mq, id, concept)
+		if err != nil {
+			issues = append(issues, issue(id, "%v", err))
+			continue
+		}
+		questions = append(questions, question)
+	}
+
+	return questions, issues, nil
+}
+
+// convertMoodleQuestion converts one Moodle question to an extended question
+func convertMoodleQuestion(mq moodleQuestion, id, concept string) (entities.ExtendedQuizQuestion, error) {
+	text := mq.QuestionText.plain()
+	var q entities.ExtendedQuizQuestion
+
+	switch mq.Type {
+	case "multichoice":
+		options := make([]string, len(mq.Answers))
+		var correct []int
+		best := -1
+		for i, a := range mq.Answers {
+			options[i] = moodlePlain(a.Format, a.Text)
+			if a.fraction() > 0 {
+				correct = append(correct, i)
+				if best < 0 || a.fraction() > mq.Answers[best].fraction() {
+					best = i
+				}
+			}
+		}
+		if len(correct) == 0 {
+			return q, fmt.Errorf("no answer is marked correct")
+		}
+		if mq.Single == "false" || mq.Single == "0" {
+			q = newQuestion(id, entities.QuestionTypeMultipleSelect, text, concept)
+			q.CorrectIndices = correct
+		} else {
+			q = newQuestion(id, entities.QuestionTypeMultipleChoice, text, concept)
+			q.CorrectIndex = best
+		}
+		q.Options = options
+
+	case "truefalse":
+		q = newQuestion(id, entities.QuestionTypeTrueFalse, text, concept)
+		for _, a := range mq.Answers {
+			if a.fraction() >= 100 {
+				answer := strings.EqualFold(moodlePlain(a.Format, a.Text), "true")
+				q.CorrectAnswer = &answer
+			}
+		}
+		if q.CorrectAnswer == nil {
+			return q, fmt.Errorf("no answer is marked correct")
+		}
+
+	case "shortanswer":
+		// Short answers with a blank in the question read as fill in the blank
+		questionType := entities.QuestionTypeShortAnswer
+		if blank.MatchString(text) {
+			questionType = entities.QuestionTypeFillBlank
+		}
+		q = newQuestion(id, questionType, text, concept)
+		q.CaseSensitive = mq.UseCase == "1"
+		for _, a := range mq.Answers {
+			if a.fraction() >= 100 {
+				q.AcceptedAnswers = append(q.AcceptedAnswers, moodlePlain(a.Format, a.Text))
+			}
+		}
+		if len(q.AcceptedAnswers) == 0 {
+			return q, fmt.Errorf("no answer earns full credit")
+		}
+
+	case "numerical":
+		q = newQuestion(id, entities.QuestionTypeNumeric, text, concept)
+		for _, a := range mq.Answers {
+			if a.fraction() < 100 {
+				continue
+			}
+			answer := strings.TrimSpace(a.Text)
+			value, err := strconv.ParseFloat(answer, 64)
+			if err != nil {
+				return q, fmt.Errorf("numeric answer %q is not a number", answer)
+			}
+			q.CorrectValue = &value
+			if a.Tolerance != "" {
+				tol, err := strconv.ParseFloat(strings.TrimSpace(a.Tolerance), 64)
+				if err != nil {
+					return q, fmt.Errorf("tolerance %q is not a number", a.Tolerance)
+				}
+				q.AbsoluteTolerance = math.Abs(tol)
+			}
+			break
+		}
+		if q.CorrectValue == nil {
+			return q, fmt.Errorf("no answer earns full credit")
+		}
+		for _, unit := range mq.Units {
+			if multiplier, err := strconv.ParseFloat(unit.Multiplier, 64); err == nil && multiplier == 1 {
+				q.Unit = strings.TrimSpace(unit.Name)
+				break
+			}
+		}
+
+	case "matching":
+		q = newQuestion(id, entities.QuestionTypeMatching, text, concept)
+		rights := make(map[string]int)
+		for _, sub := range mq.Subquestions {
+			right := moodlePlain(sub.Answer.Format, sub.Answer.Text)
+			index, ok := rights[right]
+			if !ok {
+				index = len(q.RightColumn)
+				rights[right] = index
+				q.RightColumn = append(q.RightColumn, right)
+			}
+			// Subquestions without text only add a distractor to the right column
+			if left := moodlePlain(sub.Format, sub.Text); left != "" {
+				q.CorrectPairs = append(q.CorrectPairs, []int{len(q.LeftColumn), index})
+				q.LeftColumn = append(q.LeftColumn, left)
+			}
+		}
+
+	case "ordering":
+		// Items are listed in their correct order, numbered by their fraction when present
+		answers := slices.Clone(mq.Answers)
+		slices.SortStableFunc(answers, func(a, b moodleAnswer) int {
+			return cmp.Compare(a.fraction(), b.fraction())
+		})
+		q = newQuestion(id, entities.QuestionTypeOrdering, text, concept)
+		for i, a := range answers {
+			q.Items = append(q.Items, moodlePlain(a.Format, a.Text))
+			q.CorrectOrder = append(q.CorrectOrder, i)
+		}
+
+	case "essay":
+		return q, fmt.Errorf("essay questions cannot be graded automatically")
+	case "description":
+		return q, fmt.Errorf("description items have no answers")
+	case "multianswer":
+		return q, fmt.Errorf("embedded answer (cloze) questions have no equivalent question type")
+	default:
+		return q, fmt.Errorf("%s questions have no equivalent question type", mq.Type)
+	}
+
+	q.Explanation = mq.GeneralFeedback.plain()
+	return q, nil
+}
+
+// boolToInt returns 1 for true and 0 for false
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// exportMoodleXML writes questions as a Moodle XML question export, with text in Markdown
+// A category entry is written whenever the concept changes
+func exportMoodleXML(questions []entities.ExtendedQuizQuestion) (string, []entities.QuizConversionIssue, error) {
+	var quiz moodleQuiz
+	var issues []entities.QuizConversionIssue
+
+	concept := ""
+	for _, q := range questions {
+		if problem, ok := unsupportedExport(q); ok {
+			issues = append(issues, problem)
+			continue
+		}
+		mq, err := toMoodleQuestion(q)
+		if err != nil {
+			issues = append(issues, issue(q.ID, "%v", err))
+			continue
+		}
+
+		if q.Concept != "" && q.Concept != concept {
+			concept = q.Concept
+			quiz.Questions = append(quiz.Questions, moodleQuestion{
+				Type:     "category",
+				Category: &moodleText{Text: "$course$/top/" + strings.ReplaceAll(concept, "/", "-")},
+			})
+		}
+		quiz.Questions = append(quiz.Questions, mq)
+	}
+
+	data, err := xml.MarshalIndent(quiz, "", "  ")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to encode Moodle XML: %w", err)
+	}
+	return xml.Header + string(data) + "\n", issues, nil
+}
+
+// toMoodleQuestion converts an extended question to a Moodle question
+func toMoodleQuestion(q entities.ExtendedQuizQuestion) (moodleQuestion, error) {
+	mq := moodleQuestion{
+		Name:         &moodleText{Text: q.ID},
+		QuestionText: &moodleText{Format: "markdown", Text: questionText(q)},
+	}
+	if q.Explanation != "" {
+		mq.GeneralFeedback = &moodleText{Format: "markdown", Text: q.Explanation}
+	}
+	answer := func(fraction float64, text string) moodleAnswer {
+		return moodleAnswer{Fraction: formatNumber(math.Round(fraction*1e5) / 1e5), Format: "plain_text", Text: text}
+	}
+
+	switch q.Type {
+	case entities.QuestionTypeMultipleChoice, entities.QuestionTypeCodeAnalysis:
+		mq.Type, mq.Single = "multichoice", "true"
+		for i, option := range q.Options {
+			mq.Answers = append(mq.Answers, answer(float64(100*boolToInt(i == q.CorrectIndex)), option))
+		}
+	case entities.QuestionTypeMultipleSelect:
+		mq.Type, mq.Single = "multichoice", "false"
+		correct := make(map[int]bool, len(q.CorrectIndices))
+		for _, index := range q.CorrectIndices {
+			correct[index] = true
+		}
+		right := 100 / float64(max(len(q.CorrectIndices), 1))
+		wrong := -100 / float64(max(len(q.Options)-len(q.CorrectIndices), 1))
+		for i, option := range q.Options {
+			fraction := wrong
+			if correct[i] {
+				fraction = right
+			}
+			mq.Answers = append(mq.Answers, answer(fraction, option))
+		}
+	case entities.QuestionTypeTrueFalse:
+		if q.CorrectAnswer == nil {
+			return mq, fmt.Errorf("true/false question has no answer")
+		}
+		mq.Type = "truefalse"
+		mq.Answers = []moodleAnswer{
+			answer(float64(100*boolToInt(*q.CorrectAnswer)), "true"),
+			answer(float64(100*boolToInt(!*q.CorrectAnswer)), "false"),
+		}
+	case entities.QuestionTypeFillBlank, entities.QuestionTypeShortAnswer:
+		if len(q.AcceptedAnswers) == 0 {
+			return mq, fmt.Errorf("answer patterns have no equivalent in Moodle XML")
+		}
+		mq.Type, mq.UseCase = "shortanswer", strconv.Itoa(boolToInt(q.CaseSensitive))
+		for _, accepted := range q.AcceptedAnswers {
+			mq.Answers = append(mq.Answers, answer(100, accepted))
+		}
+	case entities.QuestionTypeNumeric:
+		if q.CorrectValue == nil {
+			return mq, fmt.Errorf("numeric question has no value")
+		}
+		mq.Type = "numerical"
+		numeric := answer(100, formatNumber(*q.CorrectValue))
+		numeric.Tolerance = formatNumber(tolerance(q))
+		mq.Answers = []moodleAnswer{numeric}
+		if q.Unit != "" {
+			mq.Units = []moodleUnit{{Multiplier: "1", Name: q.Unit}}
+		}
+	case entities.QuestionTypeMatching:
+		mq.Type = "matching"
+		used := make(map[int]bool, len(q.CorrectPairs))
+		for _, pair := range q.CorrectPairs {
+			used[pair[1]] = true
+			mq.Subquestions = append(mq.Subquestions, moodleSubquestion{
+				Format: "plain_text",
+				Text:   q.LeftColumn[pair[0]],
+				Answer: moodleText{Text: q.RightColumn[pair[1]]},
+			})
+		}
+		for i, right := range q.RightColumn {
+			if !used[i] {
+				mq.Subquestions = append(mq.Subquestions, moodleSubquestion{Format: "plain_text", Answer: moodleText{Text: right}})
+			}
+		}
+	case entities.QuestionTypeOrdering:
+		mq.Type = "ordering"
+		for position, index := range q.CorrectOrder {
+			mq.Answers = append(mq.Answers, answer(float64(position+1), q.Items[index]))
+		}
+	default:
+		return mq, fmt.Errorf("%s questions have no equivalent in Moodle XML", q.Type)
+	}
+
+	return mq, nil
+}
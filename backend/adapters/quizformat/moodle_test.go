@@ -0,0 +1,74 @@
+package quizformat
+
+import (
+	"testing"
+
+	"github.com/project/backend/domain/entities"
+)
+
+const moodleSample = `<?xml version="1.0" encoding="UTF-8"?>
+<quiz>
+  <question type="category">
+    <category><text>$course$/top/Concurrency</text></category>
+  </question>
+  <question type="multichoice">
+    <name><text>Channel close</text></name>
+    <questiontext format="html"><text><![CDATA[<p>Who should close a channel?</p>]]></text></questiontext>
+    <generalfeedback format="html"><text><![CDATA[<p>Only the sender knows when it is done.</p>]]></text></generalfeedback>
+    <single>true</single>
+    <answer fraction="0" format="html"><text>The receiver</text></answer>
+    <answer fraction="100" format="html"><text>The sender</text></answer>
+  </question>
+  <question type="truefalse">
+    <name><text>nil-channel</text></name>
+    <questiontext format="plain_text"><text>Sending on a nil channel blocks forever.</text></questiontext>
+    <answer fraction="100"><text>true</text></answer>
+    <answer fraction="0"><text>false</text></answer>
+  </question>
+  <question type="numerical">
+    <name><text>buffer</text></name>
+    <questiontext format="plain_text"><text>How many values fit in make(chan int, 3)?</text></questiontext>
+    <answer fraction="100"><text>3</text><tolerance>0</tolerance></answer>
+  </question>
+  <question type="essay">
+    <name><text>explain-select</text></name>
+    <questiontext format="plain_text"><text>Explain select.</text></questiontext>
+  </question>
+</quiz>`
+
+func TestImportMoodleXML(t *testing.T) {
+	questions, issues, err := Import(FormatMoodleXML, moodleSample)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	if len(questions) != 3 {
+		t.Fatalf("expected 3 questions, got %d: %+v", len(questions), questions)
+	}
+	mc := questions[0]
+	if mc.ID != "Channel-close" || mc.Type != entities.QuestionTypeMultipleChoice || mc.CorrectIndex != 1 {
+		t.Errorf("unexpected multiple choice question: %+v", mc)
+	}
+	if mc.Question != "Who should close a channel?" || mc.Explanation != "Only the sender knows when it is done." {
+		t.Errorf("expected HTML to be reduced to text, got %q and %q", mc.Question, mc.Explanation)
+	}
+	if mc.Concept != "Concurrency" {
+		t.Errorf("expected concept Concurrency, got %q", mc.Concept)
+	}
+	if questions[1].CorrectAnswer == nil || !*questions[1].CorrectAnswer {
+		t.Errorf("expected true, got %+v", questions[1].CorrectAnswer)
+	}
+	if questions[2].Type != entities.QuestionTypeNumeric || *questions[2].CorrectValue != 3 {
+		t.Errorf("unexpected numeric question: %+v", questions[2])
+	}
+
+	if len(issues) != 1 || issues[0].Item != "explain-select" {
+		t.Errorf("expected the essay to be reported, got %+v", issues)
+	}
+}
+
+func TestImportMoodleXML_InvalidPayload(t *testing.T) {
+	if _, _, err := Import(FormatMoodleXML, "<quiz><question>"); err == nil {
+		t.Error("expected an error for malformed XML")
+	}
+}
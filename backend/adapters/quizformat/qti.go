@@ -0,0 +1,553 @@
+package quizformat
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/project/backend/domain/entities"
+)
+
+const (
+	qtiNamespace      = "http://www.imsglobal.org/xsd/imsqti_v2p1"
+	qtiSchemaLocation = qtiNamespace + " http://www.imsglobal.org/xsd/qti/qtiv2p1/imsqti_v2p1.xsd"
+	qtiMatchCorrect   = "http://www.imsglobal.org/question/qti_v2p1/rptemplates/match_correct"
+	qtiMapResponse    = "http://www.imsglobal.org/question/qti_v2p1/rptemplates/map_response"
+	qtiResponse       = "RESPONSE"
+)
+
+// qtiItem is an IMS QTI 2.1 assessmentItem
+type qtiItem struct {
+	Identifier string                   `xml:"identifier,attr"`
+	Responses  []qtiResponseDeclaration `xml:"responseDeclaration"`
+	Body       qtiInner                 `xml:"itemBody"`
+	Processing qtiInner                 `xml:"responseProcessing"`
+	Feedback   []qtiInner               `xml:"modalFeedback"`
+}
+
+type qtiResponseDeclaration struct {
+	Identifier  string        `xml:"identifier,attr"`
+	Cardinality string        `xml:"cardinality,attr"`
+	BaseType    string        `xml:"baseType,attr"`
+	Correct     []string      `xml:"correctResponse>value"`
+	Mapping     []qtiMapEntry `xml:"mapping>mapEntry"`
+}
+
+type qtiMapEntry struct {
+	Key           string  `xml:"mapKey,attr"`
+	Value         float64 `xml:"mappedValue,attr"`
+	CaseSensitive bool    `xml:"caseSensitive,attr"`
+}
+
+// qtiInner keeps an element's content as written
+type qtiInner struct {
+	XML string `xml:",innerxml"`
+}
+
+// qtiInteraction holds the parts of the interactions that have an equivalent question type
+type qtiInteraction struct {
+	XMLName            xml.Name
+	ResponseIdentifier string        `xml:"responseIdentifier,attr"`
+	MaxChoices         int           `xml:"maxChoices,attr"`
+	Prompt             qtiInner      `xml:"prompt"`
+	Choices            []qtiChoice   `xml:"simpleChoice"`
+	MatchSets          []qtiMatchSet `xml:"simpleMatchSet"`
+}
+
+type qtiChoice struct {
+	Identifier string `xml:"identifier,attr"`
+	XML        string `xml:",innerxml"`
+}
+
+type qtiMatchSet struct {
+	Choices []qtiChoice `xml:"simpleAssociableChoice"`
+}
+
+// importQTI parses IMS QTI 2.1 items; the payload may hold one assessmentItem or
+// several, one after another or inside any wrapping element
+func importQTI(payload string) ([]entities.ExtendedQuizQuestion, []entities.QuizConversionIssue, error) {
+	var questions []entities.ExtendedQuizQuestion
+	var issues []entities.QuizConversionIssue
+
+	decoder := xml.NewDecoder(strings.NewReader(payload))
+	n := 0
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse QTI: %w", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "assessmentItem" {
+			continue
+		}
+
+		var item qtiItem
+		if err := decoder.DecodeElement(&item, &start); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse QTI: %w", err)
+		}
+		n++
+		id := questionID(item.Identifier, "qti", n)
+		question, err := convertQTIItem(item, id)
+		if err != nil {
+			issues = append(issues, issue(id, "%v", err))
+			continue
+		}
+		questions = append(questions, question)
+	}
+
+	if n == 0 {
+		return nil, nil, fmt.Errorf("failed to parse QTI: no assessmentItem found")
+	}
+	return questions, issues, nil
+}
+
+// convertQTIItem converts an item with a single interaction to an extended question
+func convertQTIItem(item qtiItem, id string) (entities.ExtendedQuizQuestion, error) {
+	var q entities.ExtendedQuizQuestion
+
+	text, interactions, err := parseQTIBody(item.Body.XML)
+	if err != nil {
+		return q, err
+	}
+	switch len(interactions) {
+	case 0:
+		return q, fmt.Errorf("items without an interaction have no answers")
+	case 1:
+	default:
+		return q, fmt.Errorf("items with several interactions have no equivalent question type")
+	}
+	in := interactions[0]
+	if prompt := htmlToText(in.Prompt.XML); prompt != "" {
+		text = strings.TrimSpace(text + "\n\n" + prompt)
+	}
+
+	var decl qtiResponseDeclaration
+	for _, d := range item.Responses {
+		if d.Identifier == in.ResponseIdentifier {
+			decl = d
+		}
+	}
+
+	switch in.XMLName.Local {
+	case "choiceInteraction":
+		options, indices := qtiChoices(in.Choices)
+		correct, err := qtiIndices(decl.Correct, indices)
+		if err != nil {
+			return q, err
+		}
+		if len(correct) == 0 {
+			return q, fmt.Errorf("no choice is marked correct")
+		}
+
+		switch {
+		case decl.Cardinality == "multiple":
+			q = newQuestion(id, entities.QuestionTypeMultipleSelect, text, "")
+			q.Options = options
+			q.CorrectIndices = correct
+			if in.MaxChoices > 0 && in.MaxChoices < len(options) {
+				q.MaxSelections = in.MaxChoices
+			}
+		case len(correct) > 1:
+			return q, fmt.Errorf("a single choice item has several correct choices")
+		case isTrueFalse(options):
+			answer := strings.EqualFold(options[correct[0]], "true")
+			q = newQuestion(id, entities.QuestionTypeTrueFalse, text, "")
+			q.CorrectAnswer = &answer
+		default:
+			q = newQuestion(id, entities.QuestionTypeMultipleChoice, text, "")
+			q.Options = options
+			q.CorrectIndex = correct[0]
+		}
+
+	case "orderInteraction":
+		items, indices := qtiChoices(in.Choices)
+		order, err := qtiIndices(decl.Correct, indices)
+		if err != nil {
+			return q, err
+		}
+		q = newQuestion(id, entities.QuestionTypeOrdering, text, "")
+		q.Items = items
+		q.CorrectOrder = order
+
+	case "matchInteraction":
+		if len(in.MatchSets) != 2 {
+			return q, fmt.Errorf("match items need exactly two sets of choices")
+		}
+		left, leftIndices := qtiChoices(in.MatchSets[0].Choices)
+		right, rightIndices := qtiChoices(in.MatchSets[1].Choices)
+		q = newQuestion(id, entities.QuestionTypeMatching, text, "")
+		q.LeftColumn, q.RightColumn = left, right
+		for _, value := range decl.Correct {
+			pair := strings.Fields(value)
+			if len(pair) != 2 {
+				return q, fmt.Errorf("correct response %q is not a pair of choices", value)
+			}
+			l, lok := leftIndices[pair[0]]
+			r, rok := rightIndices[pair[1]]
+			if !lok || !rok {
+				return q, fmt.Errorf("correct response %q is not a pair of choices", value)
+			}
+			q.CorrectPairs = append(q.CorrectPairs, []int{l, r})
+		}
+
+	case "textEntryInteraction":
+		if decl.BaseType == "float" || decl.BaseType == "integer" {
+			q, err = qtiNumeric(decl, item.Processing.XML, id, text)
+		} else {
+			q, err = qtiTextEntry(decl, id, text)
+		}
+		if err != nil {
+			return q, err
+		}
+
+	case "extendedTextInteraction":
+		return q, fmt.Errorf("extended text items cannot be graded automatically")
+	default:
+		return q, fmt.Errorf("%s items have no equivalent question type", in.XMLName.Local)
+	}
+
+	if len(item.Feedback) > 0 {
+		q.Explanation = htmlToText(item.Feedback[0].XML)
+	}
+	return q, nil
+}
+
+// qtiNumeric converts a text entry with a numeric response
+func qtiNumeric(decl qtiResponseDeclaration, processing, id, text string) (entities.ExtendedQuizQuestion, error) {
+	if len(decl.Correct) == 0 {
+		return entities.ExtendedQuizQuestion{}, fmt.Errorf("no correct value is given")
+	}
+	value, err := strconv.ParseFloat(strings.TrimSpace(decl.Correct[0]), 64)
+	if err != nil {
+		return entities.ExtendedQuizQuestion{}, fmt.Errorf("correct value %q is not a number", decl.Correct[0])
+	}
+
+	// A unit may follow the entry on the same line
+	unit := ""
+	if before, after, ok := strings.Cut(text, blankMarker); ok {
+		line, rest, _ := strings.Cut(after, "\n")
+		unit = strings.TrimSpace(line)
+		text = normalizeText(before + "\n" + rest)
+	}
+
+	q := newQuestion(id, entities.QuestionTypeNumeric, text, "")
+	q.CorrectValue = &value
+	q.Unit = unit
+	q.AbsoluteTolerance, q.RelativeTolerance = qtiTolerance(processing)
+	return q, nil
+}
+
+// qtiTextEntry converts a text entry with a string response; an entry at the end of the
+// text is a short answer, anywhere else a blank to fill in
+func qtiTextEntry(decl qtiResponseDeclaration, id, text string) (entities.ExtendedQuizQuestion, error) {
+	questionType := entities.QuestionTypeFillBlank
+	if trimmed, ok := strings.CutSuffix(text, blankMarker); ok {
+		questionType, text = entities.QuestionTypeShortAnswer, strings.TrimSpace(trimmed)
+	}
+	q := newQuestion(id, questionType, text, "")
+
+	// Matching the correct response exactly is case sensitive; a mapping may not be
+	q.CaseSensitive = true
+	seen := make(map[string]bool)
+	for _, answer := range decl.Correct {
+		if !seen[answer] {
+			seen[answer] = true
+			q.AcceptedAnswers = append(q.AcceptedAnswers, answer)
+		}
+	}
+	for _, entry := range decl.Mapping {
+		if entry.Value <= 0 {
+			continue
+		}
+		if !seen[entry.Key] {
+			seen[entry.Key] = true
+			q.AcceptedAnswers = append(q.AcceptedAnswers, entry.Key)
+		}
+		q.CaseSensitive = q.CaseSensitive && entry.CaseSensitive
+	}
+	if len(q.AcceptedAnswers) == 0 {
+		return q, fmt.Errorf("no correct response is given")
+	}
+	return q, nil
+}
+
+// parseQTIBody returns an item body's text and its interactions
+// A text entry interaction is replaced by a blank in the text
+func parseQTIBody(body string) (string, []qtiInteraction, error) {
+	var text strings.Builder
+	var interactions []qtiInteraction
+
+	decoder := xml.NewDecoder(strings.NewReader(body))
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to parse item body: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if strings.HasSuffix(t.Name.Local, "Interaction") {
+				var in qtiInteraction
+				if err := decoder.DecodeElement(&in, &t); err != nil {
+					return "", nil, fmt.Errorf("failed to parse %s: %w", t.Name.Local, err)
+				}
+				interactions = append(interactions, in)
+				if t.Name.Local == "textEntryInteraction" {
+					text.WriteString(blankMarker)
+				}
+			}
+			if t.Name.Local == "br" {
+				text.WriteString("\n")
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "p", "div", "pre", "li", "h1", "h2", "h3", "h4", "h5", "h6", "blockquote":
+				text.WriteString("\n\n")
+			}
+		case xml.CharData:
+			text.Write(t)
+		}
+	}
+
+	return normalizeText(text.String()), interactions, nil
+}
+
+// qtiChoices returns the text of each choice and the index of each identifier
+func qtiChoices(choices []qtiChoice) ([]string, map[string]int) {
+	texts := make([]string, len(choices))
+	indices := make(map[string]int, len(choices))
+	for i, c := range choices {
+		texts[i] = htmlToText(c.XML)
+		indices[c.Identifier] = i
+	}
+	return texts, indices
+}
+
+// qtiIndices maps the identifiers of a correct response to choice indices
+func qtiIndices(values []string, indices map[string]int) ([]int, error) {
+	result := make([]int, 0, len(values))
+	for _, value := range values {
+		index, ok := indices[strings.TrimSpace(value)]
+		if !ok {
+			return nil, fmt.Errorf("correct response %q is not a choice", value)
+		}
+		result = append(result, index)
+	}
+	return result, nil
+}
+
+// isTrueFalse reports whether the choices are just "True" and "False"
+func isTrueFalse(options []string) bool {
+	return len(options) == 2 &&
+		(strings.EqualFold(options[0], "true") && strings.EqualFold(options[1], "false") ||
+			strings.EqualFold(options[0], "false") && strings.EqualFold(options[1], "true"))
+}
+
+// qtiTolerance reads the tolerance of the first equal comparison in response processing
+// QTI gives relative tolerances in percent
+func qtiTolerance(processing string) (absolute, relative float64) {
+	decoder := xml.NewDecoder(strings.NewReader(processing))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return 0, 0
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "equal" {
+			continue
+		}
+
+		mode, value := "", ""
+		for _, attr := range start.Attr {
+			switch attr.Name.Local {
+			case "toleranceMode":
+				mode = attr.Value
+			case "tolerance":
+				value = attr.Value
+			}
+		}
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			return 0, 0
+		}
+		tol, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return 0, 0
+		}
+		switch mode {
+		case "absolute":
+			return max(tol, -tol), 0
+		case "relative":
+			return 0, max(tol, -tol) / 100
+		}
+		return 0, 0
+	}
+}
+
+// exportQTI writes each question as an IMS QTI 2.1 assessmentItem
+// The items follow one another under a single XML declaration; each can be saved as its
+// own item file for a content package
+func exportQTI(questions []entities.ExtendedQuizQuestion) (string, []entities.QuizConversionIssue) {
+	var b strings.Builder
+	var issues []entities.QuizConversionIssue
+
+	b.WriteString(xml.Header)
+	for _, q := range questions {
+		if problem, ok := unsupportedExport(q); ok {
+			issues = append(issues, problem)
+			continue
+		}
+		item, err := qtiItemXML(q)
+		if err != nil {
+			issues = append(issues, issue(q.ID, "%v", err))
+			continue
+		}
+		b.WriteString(item)
+	}
+
+	return b.String(), issues
+}
+
+// qtiItemXML writes one question as an assessmentItem
+func qtiItemXML(q entities.ExtendedQuizQuestion) (string, error) {
+	var declaration, interaction, processing string
+	body := qtiParagraphs(q.Question)
+	if q.CodeSnippet != "" {
+		body += "    <pre>" + escapeXML(q.CodeSnippet) + "</pre>\n"
+	}
+	choices := func(element, prefix string, texts []string) string {
+		var c strings.Builder
+		for i, text := range texts {
+			fmt.Fprintf(&c, "      <%s identifier=\"%s%d\">%s</%s>\n", element, prefix, i, escapeXML(text), element)
+		}
+		return c.String()
+	}
+	values := func(prefix string, indices []int) string {
+		var v strings.Builder
+		for _, index := range indices {
+			fmt.Fprintf(&v, "<value>%s%d</value>", prefix, index)
+		}
+		return v.String()
+	}
+	processing = `  <responseProcessing template="` + qtiMatchCorrect + `"/>` + "\n"
+
+	switch q.Type {
+	case entities.QuestionTypeMultipleChoice, entities.QuestionTypeCodeAnalysis:
+		declaration = qtiDeclaration("single", "identifier", values("C", []int{q.CorrectIndex}), "")
+		interaction = fmt.Sprintf("    <choiceInteraction responseIdentifier=\"%s\" shuffle=\"true\" maxChoices=\"1\">\n%s    </choiceInteraction>\n",
+			qtiResponse, choices("simpleChoice", "C", q.Options))
+	case entities.QuestionTypeTrueFalse:
+		if q.CorrectAnswer == nil {
+			return "", fmt.Errorf("true/false question has no answer")
+		}
+		correct := 1
+		if *q.CorrectAnswer {
+			correct = 0
+		}
+		declaration = qtiDeclaration("single", "identifier", values("C", []int{correct}), "")
+		interaction = fmt.Sprintf("    <choiceInteraction responseIdentifier=\"%s\" shuffle=\"false\" maxChoices=\"1\">\n%s    </choiceInteraction>\n",
+			qtiResponse, choices("simpleChoice", "C", []string{"True", "False"}))
+	case entities.QuestionTypeMultipleSelect:
+		declaration = qtiDeclaration("multiple", "identifier", values("C", q.CorrectIndices), "")
+		interaction = fmt.Sprintf("    <choiceInteraction responseIdentifier=\"%s\" shuffle=\"true\" maxChoices=\"%d\">\n%s    </choiceInteraction>\n",
+			qtiResponse, q.MaxSelections, choices("simpleChoice", "C", q.Options))
+	case entities.QuestionTypeOrdering:
+		declaration = qtiDeclaration("ordered", "identifier", values("I", q.CorrectOrder), "")
+		interaction = fmt.Sprintf("    <orderInteraction responseIdentifier=\"%s\" shuffle=\"true\">\n%s    </orderInteraction>\n",
+			qtiResponse, choices("simpleChoice", "I", q.Items))
+	case entities.QuestionTypeMatching:
+		var pairs strings.Builder
+		for _, pair := range q.CorrectPairs {
+			fmt.Fprintf(&pairs, "<value>L%d R%d</value>", pair[0], pair[1])
+		}
+		declaration = qtiDeclaration("multiple", "directedPair", pairs.String(), "")
+		left := strings.ReplaceAll(choices("simpleAssociableChoice", "L", q.LeftColumn), "\"><", "\" matchMax=\"1\"><")
+		left = strings.ReplaceAll(left, "<simpleAssociableChoice ", "  <simpleAssociableChoice ")
+		right := strings.ReplaceAll(choices("simpleAssociableChoice", "R", q.RightColumn), "<simpleAssociableChoice ", "  <simpleAssociableChoice ")
+		interaction = fmt.Sprintf("    <matchInteraction responseIdentifier=\"%s\" shuffle=\"true\" maxAssociations=\"%d\">\n"+
+			"      <simpleMatchSet>\n%s      </simpleMatchSet>\n      <simpleMatchSet>\n%s      </simpleMatchSet>\n    </matchInteraction>\n",
+			qtiResponse, len(q.CorrectPairs), left, right)
+	case entities.QuestionTypeFillBlank, entities.QuestionTypeShortAnswer:
+		if len(q.AcceptedAnswers) == 0 {
+			return "", fmt.Errorf("answer patterns have no equivalent in QTI")
+		}
+		var mapping strings.Builder
+		mapping.WriteString("    <mapping defaultValue=\"0\">\n")
+		for _, answer := range q.AcceptedAnswers {
+			fmt.Fprintf(&mapping, "      <mapEntry mapKey=\"%s\" mappedValue=\"1\" caseSensitive=\"%t\"/>\n", escapeXML(answer), q.CaseSensitive)
+		}
+		mapping.WriteString("    </mapping>\n")
+		declaration = qtiDeclaration("single", "string", "<value>"+escapeXML(q.AcceptedAnswers[0])+"</value>", mapping.String())
+		processing = `  <responseProcessing template="` + qtiMapResponse + `"/>` + "\n"
+
+		entry := fmt.Sprintf(`<textEntryInteraction responseIdentifier="%s" expectedLength="20"/>`, qtiResponse)
+		if location := blank.FindStringIndex(body); q.Type == entities.QuestionTypeFillBlank && location != nil {
+			body = body[:location[0]] + entry + body[location[1]:]
+		} else {
+			interaction = "    <p>" + entry + "</p>\n"
+		}
+	case entities.QuestionTypeNumeric:
+		if q.CorrectValue == nil {
+			return "", fmt.Errorf("numeric question has no value")
+		}
+		declaration = qtiDeclaration("single", "float", "<value>"+formatNumber(*q.CorrectValue)+"</value>", "")
+		tol := formatNumber(tolerance(q))
+		interaction = fmt.Sprintf("    <p><textEntryInteraction responseIdentifier=\"%s\" expectedLength=\"10\"/> %s</p>\n", qtiResponse, escapeXML(q.Unit))
+		processing = "  <responseProcessing>\n    <responseCondition>\n      <responseIf>\n" +
+			fmt.Sprintf("        <equal toleranceMode=\"absolute\" tolerance=\"%s %s\"><variable identifier=\"%s\"/><correct identifier=\"%s\"/></equal>\n", tol, tol, qtiResponse, qtiResponse) +
+			"        <setOutcomeValue identifier=\"SCORE\"><baseValue baseType=\"float\">1</baseValue></setOutcomeValue>\n" +
+			"      </responseIf>\n    </responseCondition>\n  </responseProcessing>\n"
+	default:
+		return "", fmt.Errorf("%s questions have no equivalent in QTI", q.Type)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "<assessmentItem xmlns=\"%s\" xmlns:xsi=\"http://www.w3.org/2001/XMLSchema-instance\" xsi:schemaLocation=\"%s\" identifier=\"%s\" title=\"%s\" adaptive=\"false\" timeDependent=\"false\">\n",
+		qtiNamespace, qtiSchemaLocation, escapeXML(q.ID), escapeXML(q.ID))
+	b.WriteString(declaration)
+	b.WriteString("  <outcomeDeclaration identifier=\"SCORE\" cardinality=\"single\" baseType=\"float\"/>\n")
+	if q.Explanation != "" {
+		b.WriteString("  <outcomeDeclaration identifier=\"FEEDBACK\" cardinality=\"single\" baseType=\"identifier\"/>\n")
+	}
+	b.WriteString("  <itemBody>\n" + body + interaction + "  </itemBody>\n")
+	b.WriteString(processing)
+	if q.Explanation != "" {
+		fmt.Fprintf(&b, "  <modalFeedback outcomeIdentifier=\"FEEDBACK\" identifier=\"EXPLANATION\" showHide=\"show\">%s</modalFeedback>\n", escapeXML(q.Explanation))
+	}
+	b.WriteString("</assessmentItem>\n")
+	return b.String(), nil
+}
+
+// qtiDeclaration writes the response declaration with the correct response values
+func qtiDeclaration(cardinality, baseType, values, mapping string) string {
+	return fmt.Sprintf("  <responseDeclaration identifier=\"%s\" cardinality=\"%s\" baseType=\"%s\">\n    <correctResponse>%s</correctResponse>\n%s  </responseDeclaration>\n",
+		qtiResponse, cardinality, baseType, values, mapping)
+}
+
+// qtiParagraphs writes text as XHTML paragraphs, keeping line breaks
+func qtiParagraphs(text string) string {
+	var b strings.Builder
+	for _, paragraph := range strings.Split(strings.TrimSpace(text), "\n\n") {
+		if paragraph = strings.TrimSpace(paragraph); paragraph != "" {
+			b.WriteString("    <p>" + strings.ReplaceAll(escapeXML(paragraph), "\n", "<br/>") + "</p>\n")
+		}
+	}
+	return b.String()
+}
+
+// xmlEscaper escapes text for XML content and attribute values, leaving line breaks as they are
+var xmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;", "'", "&apos;")
+
+// escapeXML escapes text for XML content and attribute values
+func escapeXML(s string) string {
+	return xmlEscaper.Replace(s)
+}
@@ -0,0 +1,78 @@
+package quizformat
+
+import (
+	"testing"
+
+	"github.com/project/backend/domain/entities"
+)
+
+const qtiSample = `<?xml version="1.0" encoding="UTF-8"?>
+<assessmentTest>
+<assessmentItem xmlns="http://www.imsglobal.org/xsd/imsqti_v2p1" identifier="slices-1" title="Slices" adaptive="false" timeDependent="false">
+  <responseDeclaration identifier="RESPONSE" cardinality="multiple" baseType="identifier">
+    <correctResponse><value>A</value><value>C</value></correctResponse>
+  </responseDeclaration>
+  <itemBody>
+    <choiceInteraction responseIdentifier="RESPONSE" shuffle="false" maxChoices="0">
+      <prompt>Which operations can grow a slice?</prompt>
+      <simpleChoice identifier="A">append</simpleChoice>
+      <simpleChoice identifier="B">len</simpleChoice>
+      <simpleChoice identifier="C">slices.Grow</simpleChoice>
+    </choiceInteraction>
+  </itemBody>
+  <responseProcessing template="http://www.imsglobal.org/question/qti_v2p1/rptemplates/match_correct"/>
+</assessmentItem>
+<assessmentItem xmlns="http://www.imsglobal.org/xsd/imsqti_v2p1" identifier="order-1" title="Order" adaptive="false" timeDependent="false">
+  <responseDeclaration identifier="RESPONSE" cardinality="ordered" baseType="identifier">
+    <correctResponse><value>B</value><value>A</value></correctResponse>
+  </responseDeclaration>
+  <itemBody>
+    <orderInteraction responseIdentifier="RESPONSE" shuffle="true">
+      <prompt>Order the steps of a build</prompt>
+      <simpleChoice identifier="A">Link</simpleChoice>
+      <simpleChoice identifier="B">Compile</simpleChoice>
+    </orderInteraction>
+  </itemBody>
+  <responseProcessing template="http://www.imsglobal.org/question/qti_v2p1/rptemplates/match_correct"/>
+</assessmentItem>
+<assessmentItem xmlns="http://www.imsglobal.org/xsd/imsqti_v2p1" identifier="essay-1" title="Essay" adaptive="false" timeDependent="false">
+  <responseDeclaration identifier="RESPONSE" cardinality="single" baseType="string"/>
+  <itemBody>
+    <extendedTextInteraction responseIdentifier="RESPONSE">
+      <prompt>Describe escape analysis.</prompt>
+    </extendedTextInteraction>
+  </itemBody>
+</assessmentItem>
+</assessmentTest>`
+
+func TestImportQTI(t *testing.T) {
+	questions, issues, err := Import(FormatQTI, qtiSample)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	if len(questions) != 2 {
+		t.Fatalf("expected 2 questions, got %d: %+v", len(questions), questions)
+	}
+	ms := questions[0]
+	if ms.ID != "slices-1" || ms.Type != entities.QuestionTypeMultipleSelect || len(ms.CorrectIndices) != 2 || ms.CorrectIndices[1] != 2 {
+		t.Errorf("unexpected multiple select question: %+v", ms)
+	}
+	if ms.Question != "Which operations can grow a slice?" {
+		t.Errorf("unexpected question text %q", ms.Question)
+	}
+	ordering := questions[1]
+	if ordering.Type != entities.QuestionTypeOrdering || len(ordering.CorrectOrder) != 2 || ordering.CorrectOrder[0] != 1 {
+		t.Errorf("unexpected ordering question: %+v", ordering)
+	}
+
+	if len(issues) != 1 || issues[0].Item != "essay-1" {
+		t.Errorf("expected the essay to be reported, got %+v", issues)
+	}
+}
+
+func TestImportQTI_NoItems(t *testing.T) {
+	if _, _, err := Import(FormatQTI, "<assessmentTest/>"); err == nil {
+		t.Error("expected an error when the payload has no items")
+	}
+}
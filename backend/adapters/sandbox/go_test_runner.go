@@ -0,0 +1,410 @@
+package sandbox
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/project/backend/domain/entities"
+	"github.com/project/backend/domain/services"
+)
+
+const (
+	goMod            = "module exercise\n\ngo 1.24\n"
+	testBinary       = "exercise.test"
+	rootDir          = "root" // Holds only the test binary, and becomes its root directory
+	buildResultName  = "build"
+	maxCapturedBytes = 1024 * 1024 // Output kept from one build or test run
+	maxResultOutput  = 4 * 1024    // Output kept per test result
+	maxFileBytes     = 16 << 20    // Largest file the test binary may write
+	nonceBytes       = 16
+)
+
+var (
+	testStartLine  = regexp.MustCompile(`^=== (?:RUN|CONT|NAME)\s+(\S+)`)
+	testResultLine = regexp.MustCompile(`^\s*--- (PASS|FAIL|SKIP): (\S+) \(`)
+
+	errTimeLimit = errors.New("time limit exceeded")
+)
+
+var _ services.CodeRunner = (*GoTestRunner)(nil)
+
+// Limits bounds the resources code exercises may use
+type Limits struct {
+	Timeout     time.Duration // Wall-clock limit for the build and, separately, the test run
+	CPUTime     time.Duration // CPU time limit for the test binary
+	MemoryMB    int           // Data segment (heap) limit for the test binary
+	Processes   int           // Processes and threads the test binary may have at once
+	Concurrency int           // Exercises run at once; further submissions wait their turn
+}
+
+// GoTestRunner grades Go code exercises by building the submission together with the
+// hidden tests in a throwaway module, then running the test binary confined to a folder
+// holding only itself, without network access and under CPU, memory and process limits
+//
+// Test outcomes are reported by a harness compiled into the binary, on a pipe the
+// submission cannot write valid results to, so output the submission prints cannot pass a test
+type GoTestRunner struct {
+	goBinary string
+	cacheDir string
+	limits   Limits
+	slots    chan struct{}
+}
+
+// NewGoTestRunner creates a runner that builds with goBinary, sharing cacheDir as the
+// build cache so the standard library is only compiled once
+func NewGoTestRunner(goBinary, cacheDir string, limits Limits) *GoTestRunner {
+	return &GoTestRunner{
+		goBinary: goBinary,
+		cacheDir: cacheDir,
+		limits:   limits,
+		slots:    make(chan struct{}, max(limits.Concurrency, 1)),
+	}
+}
+
+// RunTests builds the submission with the question's TestCode and runs every test
+// A build failure is reported as a single failed "build" result with the compiler output
+func (r *GoTestRunner) RunTests(ctx context.Context, q *entities.ExtendedQuizQuestion, code string) ([]entities.CodeTestResult, error) {
+	if strings.TrimSpace(q.TestCode) == "" {
+		return nil, fmt.Errorf("exercise %s has no tests", q.ID)
+	}
+	pkg, tests, err := hiddenTests(q.TestCode)
+	if err != nil {
+		return nil, fmt.Errorf("exercise %s: %w", q.ID, err)
+	}
+	if err := checkImports(code); err != nil {
+		return []entities.CodeTestResult{{Name: buildResultName, Output: err.Error()}}, nil
+	}
+
+	select {
+	case r.slots <- struct{}{}:
+		defer func() { <-r.slots }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	cacheDir, err := filepath.Abs(r.cacheDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve build cache: %w", err)
+	}
+	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create build cache: %w", err)
+	}
+
+	dir, err := os.MkdirTemp("", "code-exercise-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create exercise module: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	wrapper := wrapperName(tests)
+	files := map[string]string{
+		"go.mod":           goMod,
+		"exercise.go":      code,
+		"exercise_test.go": q.TestCode,
+		wrapperFile:        wrapperSource(pkg, wrapper, tests),
+		harnessFile:        harnessSource,
+	}
+	for _, folder := range []string{filepath.Dir(harnessFile), filepath.Join(rootDir, "tmp")} {
+		if err := os.MkdirAll(filepath.Join(dir, folder), 0o700); err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", folder, err)
+		}
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+
+	build := process{
+		dir: dir,
+		env: []string{
+			"PATH=" + os.Getenv("PATH"),
+			"HOME=" + dir,
+			"GOPATH=" + filepath.Join(dir, "gopath"),
+			"GOCACHE=" + cacheDir,
+			"GOENV=off",
+			"GOFLAGS=-mod=mod",
+			"GOPROXY=off",
+			"GOSUMDB=off",
+			"GOTOOLCHAIN=local",
+			"CGO_ENABLED=0",
+		},
+		args: []string{r.goBinary, "test", "-c", "-trimpath", "-ldflags=-s -w", "-o", filepath.Join(rootDir, testBinary), "."},
+	}
+	output, err := r.run(ctx, build)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) && !errors.Is(err, errTimeLimit) {
+			return nil, fmt.Errorf("failed to build exercise: %w", err)
+		}
+		if errors.Is(err, errTimeLimit) {
+			output = []byte(withReason(string(output), err))
+		}
+		return []entities.CodeTestResult{{Name: buildResultName, Output: truncate(string(output))}}, nil
+	}
+
+	output, reported, err := r.runTestBinary(ctx, filepath.Join(dir, rootDir), wrapper)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) && !errors.Is(err, errTimeLimit) {
+		return nil, fmt.Errorf("failed to run exercise tests: %w", err)
+	}
+	return parseTestOutput(tests, reported, output, err), nil
+}
+
+// runTestBinary runs the compiled tests confined to root, returning their output and the
+// status the harness reported for each test
+// The harness reads the run's nonce and limits from file descriptor 4 and writes results
+// to file descriptor 3; lines there without the nonce were not written by the harness
+func (r *GoTestRunner) runTestBinary(ctx context.Context, root, wrapper string) ([]byte, map[string]string, error) {
+	nonce, err := newNonce()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resultsRead, resultsWrite, err := os.Pipe()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create results pipe: %w", err)
+	}
+	defer resultsRead.Close()
+	setupRead, setupWrite, err := os.Pipe()
+	if err != nil {
+		resultsWrite.Close()
+		return nil, nil, fmt.Errorf("failed to create setup pipe: %w", err)
+	}
+
+	_, err = fmt.Fprintf(setupWrite, "%s %d %d %d %d\n", nonce,
+		max(int(r.limits.CPUTime.Seconds()), 1), r.limits.MemoryMB<<20, maxFileBytes, max(r.limits.Processes, 1))
+	setupWrite.Close()
+	if err != nil {
+		resultsWrite.Close()
+		setupRead.Close()
+		return nil, nil, fmt.Errorf("failed to write test setup: %w", err)
+	}
+
+	// Results are read as they arrive, so a harness reporting many tests never blocks
+	results := &cappedBuffer{limit: maxCapturedBytes}
+	copied := make(chan struct{})
+	go func() {
+		io.Copy(results, resultsRead)
+		close(copied)
+	}()
+
+	output, err := r.run(ctx, process{
+		dir:   "/",
+		root:  root,
+		env:   []string{"HOME=/", "TMPDIR=/tmp", "GOMAXPROCS=1"},
+		files: []*os.File{resultsWrite, setupRead},
+		args: []string{"/" + testBinary, "-test.v", "-test.count=1",
+			"-test.run=^" + wrapper + "$", "-test.timeout=" + r.limits.Timeout.String()},
+	})
+	<-copied
+
+	return output, reportedResults(results.Bytes(), nonce), err
+}
+
+// process is a command run by run
+type process struct {
+	dir   string     // Working directory, within root when root is set
+	root  string     // Folder the command is confined to; empty to leave the filesystem visible
+	env   []string   // The command's entire environment
+	files []*os.File // Passed on as file descriptors 3 onwards, and closed once the command starts
+	args  []string   // Command and its arguments
+}
+
+// run executes a command isolated from the network within the wall-clock limit
+// and returns its combined output
+func (r *GoTestRunner) run(ctx context.Context, p process) ([]byte, error) {
+	// The command gets its own copies of the files; closing these once it has started
+	// lets readers see it exit
+	closeFiles := func() {
+		for _, f := range p.files {
+			f.Close()
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.limits.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, p.args[0], p.args[1:]...)
+	cmd.Dir = p.dir
+	cmd.Env = p.env
+	cmd.ExtraFiles = p.files
+	output := &cappedBuffer{limit: maxCapturedBytes}
+	cmd.Stdout = output
+	cmd.Stderr = output
+	cmd.WaitDelay = time.Second
+	if err := isolate(cmd, p.root); err != nil {
+		closeFiles()
+		return nil, err
+	}
+
+	err := cmd.Start()
+	closeFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	err = cmd.Wait()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return output.Bytes(), errTimeLimit
+	}
+	return output.Bytes(), err
+}
+
+// newNonce returns a random value that identifies the harness's results for one run
+func newNonce() (string, error) {
+	b := make([]byte, nonceBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to create nonce: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// reportedResults reads the status of each test from the harness's result lines, which
+// are "<nonce> <status> <test>"; lines with another nonce are ignored
+func reportedResults(data []byte, nonce string) map[string]string {
+	status := make(map[string]string)
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 || fields[0] != nonce {
+			continue
+		}
+		if _, ok := status[fields[2]]; !ok {
+			status[fields[2]] = fields[1]
+		}
+	}
+	return status
+}
+
+// parseTestOutput turns the harness's reported statuses into one result per hidden test,
+// with the output `go test -v` printed while the test ran
+// Subtest and log lines are kept with their hidden test; tests the harness did not report,
+// because the binary crashed or was killed, are failed with the reason. The output is
+// written by the submission as much as by the tests, so it is never used to decide a status
+func parseTestOutput(tests []string, reported map[string]string, output []byte, runErr error) []entities.CodeTestResult {
+	logs := make(map[string]*strings.Builder, len(tests))
+	for _, name := range tests {
+		logs[name] = &strings.Builder{}
+	}
+	current := ""
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 64*1024), maxCapturedBytes)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if m := testStartLine.FindStringSubmatch(line); m != nil {
+			current = hiddenTestOf(m[1])
+			continue
+		}
+		if m := testResultLine.FindStringSubmatch(line); m != nil && hiddenSubtest(m[2]) == "" {
+			continue
+		}
+		if isSummaryLine(line) {
+			continue
+		}
+		if log, ok := logs[current]; ok {
+			log.WriteString(line + "\n")
+		}
+	}
+
+	results := make([]entities.CodeTestResult, 0, len(tests))
+	for _, name := range tests {
+		status := reported[name]
+		if status == "SKIP" {
+			continue
+		}
+		out := logs[name].String()
+		if status == "" {
+			out = withReason(out, runErr)
+		}
+		results = append(results, entities.CodeTestResult{
+			Name:   name,
+			Passed: status == "PASS",
+			Output: truncate(strings.TrimSpace(out)),
+		})
+	}
+	return results
+}
+
+// withReason puts the reason a run stopped ahead of its output, so truncation keeps it
+func withReason(output string, err error) string {
+	switch {
+	case errors.Is(err, errTimeLimit):
+		return "time limit exceeded\n" + output
+	case err != nil:
+		return "stopped: " + err.Error() + "\n" + output
+	default:
+		return "did not run\n" + output
+	}
+}
+
+// hiddenTestOf returns the hidden test a test belongs to; hidden tests run as subtests
+// of the harness's wrapper test
+func hiddenTestOf(name string) string {
+	parts := strings.SplitN(name, "/", 3)
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[1]
+}
+
+// hiddenSubtest returns the part of a test's name below its hidden test, if any
+func hiddenSubtest(name string) string {
+	parts := strings.SplitN(name, "/", 3)
+	if len(parts) < 3 {
+		return ""
+	}
+	return parts[2]
+}
+
+// isSummaryLine reports whether a line is part of the package summary go test prints last
+func isSummaryLine(line string) bool {
+	return line == "PASS" || line == "FAIL" ||
+		strings.HasPrefix(line, "ok ") || strings.HasPrefix(line, "FAIL\t") ||
+		strings.HasPrefix(line, "testing: warning: no tests to run")
+}
+
+// truncate keeps the start of long output, where the first failure is reported
+func truncate(output string) string {
+	if len(output) <= maxResultOutput {
+		return output
+	}
+	return output[:maxResultOutput] + "\n... output truncated"
+}
+
+// cappedBuffer collects output up to a limit and silently drops the rest, so a
+// submission printing in a loop cannot exhaust the server's memory
+type cappedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.Len(); room > 0 {
+		if len(p) > room {
+			b.Buffer.Write(p[:room])
+		} else {
+			b.Buffer.Write(p)
+		}
+	}
+	return len(p), nil
+}
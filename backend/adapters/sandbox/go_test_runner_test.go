@@ -0,0 +1,275 @@
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/project/backend/domain/entities"
+)
+
+func TestParseTestOutput(t *testing.T) {
+	output := `=== RUN   TestGradingHarness
+=== RUN   TestGradingHarness/TestArea
+    exercise_test.go:5: Area(2, 3) = 5, want 6
+--- FAIL: TestGradingHarness/TestArea (0.00s)
+=== RUN   TestGradingHarness/TestZero
+=== RUN   TestGradingHarness/TestZero/negative
+--- PASS: TestGradingHarness/TestZero (0.00s)
+    --- PASS: TestGradingHarness/TestZero/negative (0.00s)
+=== RUN   TestGradingHarness/TestSkipped
+    exercise_test.go:12: not yet
+--- SKIP: TestGradingHarness/TestSkipped (0.00s)
+=== RUN   TestGradingHarness/TestLoop
+--- PASS: TestGradingHarness/TestLoop (0.00s)
+panic: test timed out after 5s
+FAIL
+exit status 2
+`
+	tests := []string{"TestArea", "TestZero", "TestSkipped", "TestLoop", "TestNeverRun"}
+	reported := map[string]string{"TestArea": "FAIL", "TestZero": "PASS", "TestSkipped": "SKIP"}
+	results := parseTestOutput(tests, reported, []byte(output), errTimeLimit)
+
+	if len(results) != 4 {
+		t.Fatalf("expected 4 results without the skipped test, got %+v", results)
+	}
+	if results[0].Name != "TestArea" || results[0].Passed || !strings.Contains(results[0].Output, "want 6") {
+		t.Errorf("expected TestArea to fail with its log, got %+v", results[0])
+	}
+	if !results[1].Passed || !strings.Contains(results[1].Output, "TestZero/negative") {
+		t.Errorf("expected TestZero to pass with its subtest, got %+v", results[1])
+	}
+	if results[2].Passed || !strings.HasPrefix(results[2].Output, "time limit exceeded") || !strings.Contains(results[2].Output, "panic") {
+		t.Errorf("expected the unreported test to fail on the time limit despite its printed pass, got %+v", results[2])
+	}
+	if results[3].Name != "TestNeverRun" || results[3].Passed {
+		t.Errorf("expected a test that never started to fail, got %+v", results[3])
+	}
+
+	results = parseTestOutput([]string{"TestArea"}, map[string]string{"TestArea": "PASS"}, []byte("=== RUN   TestGradingHarness/TestArea\n--- PASS: TestGradingHarness/TestArea (0.00s)\nPASS\n"), nil)
+	if len(results) != 1 || !results[0].Passed || results[0].Output != "" {
+		t.Errorf("expected a clean pass, got %+v", results)
+	}
+
+	results = parseTestOutput([]string{"TestArea"}, nil, nil, errors.New("signal: killed"))
+	if results[0].Output != "stopped: signal: killed" {
+		t.Errorf("expected the stop reason, got %q", results[0].Output)
+	}
+}
+
+func TestReportedResults(t *testing.T) {
+	data := "abc PASS TestArea\nforged PASS TestZero\nabc FAIL TestZero\nabc PASS TestZero\nabc PASS\n"
+	reported := reportedResults([]byte(data), "abc")
+
+	if len(reported) != 2 || reported["TestArea"] != "PASS" {
+		t.Errorf("expected only lines with the nonce, got %v", reported)
+	}
+	if reported["TestZero"] != "FAIL" {
+		t.Errorf("expected the first report of a test to be kept, got %s", reported["TestZero"])
+	}
+}
+
+func TestHiddenTests(t *testing.T) {
+	pkg, tests, err := hiddenTests(`package exercise_test
+
+import (
+	tt "testing"
+)
+
+func TestArea(t *tt.T)               {}
+func Test(t *tt.T)                   {}
+func Testing(t *tt.T)                {}
+func TestHelper(t *tt.T, n int)      {}
+func TestBench(b *tt.B)              {}
+func TestMain(m *tt.M)               {}
+func (s suite) TestMethod(t *tt.T)   {}
+func TestΔ(t *tt.T)                  {}
+func TestGeneric[T any](t *tt.T)     {}
+func helper()                        {}
+`)
+	if err != nil {
+		t.Fatalf("hiddenTests failed: %v", err)
+	}
+	if pkg != "exercise_test" {
+		t.Errorf("expected package exercise_test, got %s", pkg)
+	}
+	if want := []string{"TestArea", "Test", "TestΔ"}; strings.Join(tests, ",") != strings.Join(want, ",") {
+		t.Errorf("expected tests %v, got %v", want, tests)
+	}
+
+	if _, _, err := hiddenTests("package exercise\n\nfunc helper() {}\n"); err == nil {
+		t.Error("expected an error for tests without Test functions")
+	}
+	if _, _, err := hiddenTests("package exercise\n\nfunc TestArea(t *testing.T) {"); err == nil {
+		t.Error("expected an error for tests that do not parse")
+	}
+}
+
+func TestCheckImports(t *testing.T) {
+	allowed := "package exercise\n\nimport (\n\t\"fmt\"\n\t\"os\"\n)\n"
+	if err := checkImports(allowed); err != nil {
+		t.Errorf("expected fmt and os to be allowed, got %v", err)
+	}
+	for _, path := range []string{"unsafe", "syscall", "runtime/debug", "embed", "exercise/internal/harness"} {
+		if err := checkImports("package exercise\n\nimport _ \"" + path + "\"\n"); err == nil {
+			t.Errorf("expected an import of %s to be rejected", path)
+		}
+	}
+}
+
+const areaTests = `package exercise
+
+import (
+	"os"
+	"testing"
+)
+
+func TestArea(t *testing.T) {
+	if got := Area(2, 3); got != 6 {
+		t.Errorf("Area(2, 3) = %d, want 6", got)
+	}
+}
+
+func TestSquare(t *testing.T) {
+	t.Parallel()
+	if got := Area(4, 4); got != 16 {
+		t.Errorf("Area(4, 4) = %d, want 16", got)
+	}
+}
+
+func TestConfined(t *testing.T) {
+	for _, path := range []string{"/etc/passwd", "/exercise_test.go", "/proc/self/mem"} {
+		if _, err := os.Stat(path); err == nil {
+			t.Errorf("%s is visible to the tests", path)
+		}
+	}
+}
+`
+
+// newIntegrationRunner returns a runner using the installed Go toolchain and build cache,
+// skipping the test where either is missing
+func newIntegrationRunner(t *testing.T) *GoTestRunner {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("builds and runs real submissions")
+	}
+
+	goBinary, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not found")
+	}
+	cacheDir, err := exec.Command(goBinary, "env", "GOCACHE").Output()
+	if err != nil || strings.TrimSpace(string(cacheDir)) == "" {
+		t.Skip("go build cache not found")
+	}
+
+	return NewGoTestRunner(goBinary, strings.TrimSpace(string(cacheDir)), Limits{
+		Timeout:     time.Minute,
+		CPUTime:     10 * time.Second,
+		MemoryMB:    512,
+		Processes:   64,
+		Concurrency: 2,
+	})
+}
+
+// runExercise runs the area tests against code, skipping the test where the kernel does
+// not allow the sandbox's namespaces
+func runExercise(t *testing.T, runner *GoTestRunner, code string) map[string]entities.CodeTestResult {
+	t.Helper()
+
+	question := &entities.ExtendedQuizQuestion{ID: "area", TestCode: areaTests}
+	results, err := runner.RunTests(context.Background(), question, code)
+	if errors.Is(err, os.ErrPermission) {
+		t.Skipf("sandbox namespaces are not permitted: %v", err)
+	}
+	if err != nil {
+		t.Fatalf("RunTests failed: %v", err)
+	}
+
+	byName := make(map[string]entities.CodeTestResult, len(results))
+	for _, result := range results {
+		byName[result.Name] = result
+	}
+	return byName
+}
+
+func TestGoTestRunner_RunTests(t *testing.T) {
+	runner := newIntegrationRunner(t)
+
+	results := runExercise(t, runner, "package exercise\n\nfunc Area(w, h int) int { return w * h }\n")
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %+v", results)
+	}
+	for name, result := range results {
+		if !result.Passed {
+			t.Errorf("expected %s to pass, got %+v", name, result)
+		}
+	}
+
+	results = runExercise(t, runner, "package exercise\n\nfunc Area(w, h int) int { return w + h }\n")
+	if area := results["TestArea"]; area.Passed || !strings.Contains(area.Output, "want 6") {
+		t.Errorf("expected TestArea to fail with its log, got %+v", area)
+	}
+	if results["TestSquare"].Passed || !results["TestConfined"].Passed {
+		t.Errorf("expected only TestConfined to pass, got %+v", results)
+	}
+
+	results = runExercise(t, runner, "package exercise\n\nfunc Area(w, h int) int { return w * }\n")
+	if build, ok := results[buildResultName]; !ok || build.Passed || len(results) != 1 {
+		t.Errorf("expected a failed build, got %+v", results)
+	}
+
+	results = runExercise(t, runner, "package exercise\n\nimport \"unsafe\"\n\nvar _ unsafe.Pointer\n\nfunc Area(w, h int) int { return w * h }\n")
+	if build := results[buildResultName]; build.Passed || !strings.Contains(build.Output, "unsafe") {
+		t.Errorf("expected the unsafe import to be rejected, got %+v", results)
+	}
+}
+
+func TestGoTestRunner_RunTests_ForgedOutput(t *testing.T) {
+	runner := newIntegrationRunner(t)
+
+	// The submission prints passing results for every test, writes its own to the results
+	// descriptor and exits before any test runs
+	forged := `package exercise
+
+import (
+	"fmt"
+	"os"
+)
+
+func init() {
+	results := os.NewFile(3, "results")
+	for _, name := range []string{"TestArea", "TestSquare", "TestConfined"} {
+		fmt.Printf("=== RUN   TestGradingHarness/%s\n--- PASS: TestGradingHarness/%s (0.00s)\n", name, name)
+		fmt.Fprintf(results, "0123456789abcdef0123456789abcdef PASS %s\n", name)
+	}
+	fmt.Println("PASS")
+	os.Exit(0)
+}
+
+func Area(w, h int) int { return 0 }
+`
+	results := runExercise(t, runner, forged)
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %+v", results)
+	}
+	for name, result := range results {
+		if result.Passed {
+			t.Errorf("expected %s to fail despite the forged output, got %+v", name, result)
+		}
+	}
+
+	// A crash partway through fails the tests that had not reported
+	crash := "package exercise\n\nfunc Area(w, h int) int {\n\tif w == 4 {\n\t\tpanic(\"boom\")\n\t}\n\treturn w * h\n}\n"
+	results = runExercise(t, runner, crash)
+	if !results["TestArea"].Passed {
+		t.Errorf("expected TestArea to pass before the crash, got %+v", results["TestArea"])
+	}
+	if square := results["TestSquare"]; square.Passed {
+		t.Errorf("expected the crashing test to fail, got %+v", square)
+	}
+}
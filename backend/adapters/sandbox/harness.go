@@ -0,0 +1,224 @@
+package sandbox
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	harnessImportPath = "exercise/internal/harness"
+	harnessFile       = "internal/harness/harness.go"
+	wrapperFile       = "harness_test.go"
+)
+
+// harnessSource is compiled into every test binary. Its package is initialised before the
+// submission's, so it takes the run's nonce and applies the resource limits before any
+// submitted code runs; it then reports each hidden test's outcome on a pipe only the
+// grader reads, tagged with the nonce so that lines written by the submission are ignored
+const harnessSource = `// Package harness reports the outcome of each hidden test to the grader
+package harness
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+)
+
+const (
+	resultsFD = 3
+	setupFD   = 4
+
+	rlimitNproc = 6 // RLIMIT_NPROC, which the syscall package does not define
+)
+
+var (
+	nonce     string
+	results   *os.File
+	resultsMu sync.Mutex
+)
+
+func init() {
+	setupFile := os.NewFile(setupFD, "setup")
+	setup, err := io.ReadAll(setupFile)
+	setupFile.Close()
+	if err != nil {
+		os.Exit(3)
+	}
+	syscall.CloseOnExec(resultsFD)
+	results = os.NewFile(resultsFD, "results")
+
+	var cpu, data, files, procs uint64
+	if _, err := fmt.Sscan(string(setup), &nonce, &cpu, &data, &files, &procs); err != nil {
+		os.Exit(3)
+	}
+	limits := map[int]uint64{
+		syscall.RLIMIT_CPU:   cpu,
+		syscall.RLIMIT_DATA:  data,
+		syscall.RLIMIT_FSIZE: files,
+		rlimitNproc:          procs,
+	}
+	for resource, limit := range limits {
+		if err := syscall.Setrlimit(resource, &syscall.Rlimit{Cur: limit, Max: limit}); err != nil {
+			os.Exit(3)
+		}
+	}
+}
+
+// Test is a hidden test run by Run
+type Test struct {
+	Name string
+	F    func(*testing.T)
+}
+
+// Run runs each test as a subtest and reports it once it and its own subtests are done
+// A test that panicked or exited without returning is reported as failed
+func Run(t *testing.T, tests []Test) {
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			returned := false
+			t.Cleanup(func() { report(test.Name, t, returned) })
+			test.F(t)
+			returned = true
+		})
+	}
+}
+
+func report(name string, t *testing.T, returned bool) {
+	status := "PASS"
+	switch {
+	case t.Skipped():
+		status = "SKIP"
+	case t.Failed() || !returned:
+		status = "FAIL"
+	}
+
+	resultsMu.Lock()
+	defer resultsMu.Unlock()
+	fmt.Fprintf(results, "%s %s %s\n", nonce, status, name)
+}
+`
+
+// deniedImports are packages a submission may not import: they could read the harness's
+// nonce out of memory or embed the hidden tests in the submission
+var deniedImports = map[string]bool{
+	"embed":         true,
+	"runtime/debug": true,
+	"syscall":       true,
+	"unsafe":        true,
+}
+
+// hiddenTests parses an exercise's test file and returns its package name and the names of
+// the top-level tests go test would run, in the order they are declared
+func hiddenTests(testCode string) (string, []string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "exercise_test.go", testCode, parser.SkipObjectResolution)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to parse tests: %w", err)
+	}
+
+	// The testing package may be imported under another name, or into the file's scope
+	testingName := ""
+	for _, spec := range file.Imports {
+		if path, _ := strconv.Unquote(spec.Path.Value); path == "testing" {
+			testingName = "testing"
+			if spec.Name != nil {
+				testingName = spec.Name.Name
+			}
+		}
+	}
+
+	var tests []string
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || fn.Type.TypeParams != nil || !isTestName(fn.Name.Name) {
+			continue
+		}
+		if params := fn.Type.Params.List; len(params) == 1 && len(params[0].Names) <= 1 && isTestingT(params[0].Type, testingName) {
+			tests = append(tests, fn.Name.Name)
+		}
+	}
+	if len(tests) == 0 {
+		return "", nil, fmt.Errorf("tests declare no Test functions")
+	}
+	return file.Name.Name, tests, nil
+}
+
+// isTestName reports whether name is a test function name: Test, optionally followed by
+// a name that does not start with a lower-case letter
+func isTestName(name string) bool {
+	rest, ok := strings.CutPrefix(name, "Test")
+	if !ok {
+		return false
+	}
+	if rest == "" {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(rest)
+	return !unicode.IsLower(r)
+}
+
+// isTestingT reports whether expr is the type *testing.T
+func isTestingT(expr ast.Expr, testingName string) bool {
+	star, ok := expr.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	switch t := star.X.(type) {
+	case *ast.SelectorExpr:
+		pkg, ok := t.X.(*ast.Ident)
+		return ok && pkg.Name == testingName && t.Sel.Name == "T"
+	case *ast.Ident:
+		return testingName == "." && t.Name == "T"
+	}
+	return false
+}
+
+// checkImports rejects a submission importing a denied package or the harness; a
+// submission that does not parse is left for the compiler to report
+func checkImports(code string) error {
+	file, err := parser.ParseFile(token.NewFileSet(), "exercise.go", code, parser.ImportsOnly)
+	if err != nil {
+		return nil
+	}
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		if deniedImports[path] || path == "exercise" || strings.HasPrefix(path, "exercise/") {
+			return fmt.Errorf("import of %s is not allowed", path)
+		}
+	}
+	return nil
+}
+
+// wrapperSource returns a test file that runs the hidden tests through the harness under a
+// single top-level test named wrapper
+func wrapperSource(pkg, wrapper string, tests []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "package %s\n\nimport (\n\t\"testing\"\n\n\tharness %q\n)\n\n", pkg, harnessImportPath)
+	fmt.Fprintf(&b, "func %s(t *testing.T) {\n\tharness.Run(t, []harness.Test{\n", wrapper)
+	for _, name := range tests {
+		fmt.Fprintf(&b, "\t\t{Name: %q, F: %s},\n", name, name)
+	}
+	b.WriteString("\t})\n}\n")
+	return b.String()
+}
+
+// wrapperName returns a name for the wrapper test that the hidden tests do not use
+func wrapperName(tests []string) string {
+	used := make(map[string]bool, len(tests))
+	for _, name := range tests {
+		used[name] = true
+	}
+	name := "TestGradingHarness"
+	for i := 2; used[name]; i++ {
+		name = fmt.Sprintf("TestGradingHarness%d", i)
+	}
+	return name
+}
@@ -0,0 +1,33 @@
+//go:build linux
+
+package sandbox
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// sandboxID is the user and group ID commands run as inside their user namespace; any ID
+// but 0 drops the namespace's capabilities when the command is executed, so a confined
+// command cannot leave its root directory
+const sandboxID = 1000
+
+// isolate starts the command in new user, PID and network namespaces, leaving it with
+// only a loopback interface that is down, and in its own process group so a timeout kills
+// everything it started; anything it leaves running is killed when it exits. A non-empty
+// root becomes the command's root directory. If the kernel does not allow the namespaces
+// the command fails to start
+func isolate(cmd *exec.Cmd, root string) error {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Cloneflags:  syscall.CLONE_NEWUSER | syscall.CLONE_NEWPID | syscall.CLONE_NEWNET,
+		UidMappings: []syscall.SysProcIDMap{{ContainerID: sandboxID, HostID: os.Getuid(), Size: 1}},
+		GidMappings: []syscall.SysProcIDMap{{ContainerID: sandboxID, HostID: os.Getgid(), Size: 1}},
+		Chroot:      root,
+		Setpgid:     true,
+	}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package textdiff
+
+import "testing"
+
+func TestUnified(t *testing.T) {
+	before := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n"
+	after := "one\n2\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\n"
+
+	expected := `--- before
++++ after
+@@ -1,5 +1,5 @@
+ one
+-two
++2
+ three
+ four
+ five
+@@ -8,3 +8,4 @@
+ eight
+ nine
+ ten
++eleven
+`
+	if got := Unified("before", "after", []byte(before), []byte(after)); got != expected {
+		t.Errorf("unexpected diff:\n%s", got)
+	}
+}
+
+func TestUnifiedEqual(t *testing.T) {
+	if got := Unified("a", "b", []byte("same\n"), []byte("same\n")); got != "" {
+		t.Errorf("expected no diff for equal texts, got:\n%s", got)
+	}
+}
+
+func TestUnifiedFromEmpty(t *testing.T) {
+	expected := "--- a\n+++ b\n@@ -0,0 +1,2 @@\n+new\n+text\n"
+	if got := Unified("a", "b", nil, []byte("new\ntext\n")); got != expected {
+		t.Errorf("unexpected diff:\n%s", got)
+	}
+}
@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/project/backend/domain/entities"
+)
+
+// QuestionBankPort defines the interface for shared question bank use cases
+type QuestionBankPort interface {
+	// ListQuestionBanks returns every bank; answer keys are left out unless the user may edit it
+	ListQuestionBanks(ctx context.Context, userID string) ([]*entities.QuestionBank, error)
+
+	// GetQuestionBank returns a bank; answer keys are left out unless the user may edit it
+	GetQuestionBank(ctx context.Context, userID, bankID string) (*entities.QuestionBank, error)
+
+	// QuestionBankAnalysis reports how each bank question performed across every course using it
+	QuestionBankAnalysis(ctx context.Context, userID, bankID string) (*entities.QuestionBankAnalysis, error)
+}
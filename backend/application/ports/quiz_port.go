@@ -0,0 +1,128 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/project/backend/domain/entities"
+)
+
+// SubmitQuizAttemptInput represents a learner's raw answers to a lesson quiz
+type SubmitQuizAttemptInput struct {
+	UserID     string
+	CourseID   string
+	LessonPath []int  // [chapter] or [chapter, sublesson] locating the quiz
+	InstanceID string // Optional generated quiz being answered, issued for the same lesson
+	SessionID  string // Session the answers were given in; required for exams
+	Answers    []entities.QuizAnswer
+}
+
+// StartQuizAttemptInput starts a draft attempt whose answers are saved one at a time
+type StartQuizAttemptInput struct {
+	UserID     string
+	CourseID   string
+	LessonPath []int
+	InstanceID string // Optional generated quiz to answer, issued for the same lesson
+	SessionID  string // Required when the quiz is an exam
+}
+
+// GenerateQuizInput requests a quiz drawn from a lesson's question pool
+type GenerateQuizInput struct {
+	UserID            string
+	CourseID          string
+	LessonPath        []int
+	IncludeSublessons bool // Also draw from the quizzes of the lesson's sublessons
+}
+
+// TestOutInput represents a learner's answers to a chapter quiz taken to skip the chapter
+type TestOutInput struct {
+	UserID    string
+	CourseID  string
+	Chapter   int
+	SessionID string // Required when the chapter quiz is an exam
+	Answers   []entities.QuizAnswer
+}
+
+// EditQuizInput locates the lesson quiz an author is editing
+type EditQuizInput struct {
+	UserID     string
+	CourseID   string
+	LessonPath []int
+}
+
+// QuizPort defines the interface for quiz use cases
+type QuizPort interface {
+	// SubmitAttempt grades the answers server-side and stores the attempt and its responses
+	SubmitAttempt(ctx context.Context, input SubmitQuizAttemptInput) (*entities.QuizAttempt, error)
+
+	// StartQuizAttempt starts a draft attempt, or returns the learner's open draft for the
+	// same quiz, generated quiz and session so it can be resumed
+	StartQuizAttempt(ctx context.Context, input StartQuizAttemptInput) (*entities.QuizDraft, error)
+
+	// SaveQuizAnswer saves the answer to one question of a draft, replacing any earlier one
+	SaveQuizAnswer(ctx context.Context, userID, draftID string, answer entities.QuizAnswer) (*entities.QuizDraft, error)
+
+	// FinishQuizAttempt grades a draft's saved answers as a submitted attempt and discards it
+	FinishQuizAttempt(ctx context.Context, userID, draftID string) (*entities.QuizAttempt, error)
+
+	// OpenQuizAttempts returns the learner's unexpired drafts, most recently saved first
+	OpenQuizAttempts(ctx context.Context, userID string) ([]entities.QuizDraft, error)
+
+	// PurgeExpiredDrafts deletes drafts that have expired and returns how many
+	PurgeExpiredDrafts(ctx context.Context) (int, error)
+
+	// StartQuizSession starts a timed attempt at an exam, or returns the session already in
+	// progress; it fails once the attempts are used up or while a cooldown is running
+	StartQuizSession(ctx context.Context, userID, courseID string, lessonPath []int) (*entities.QuizSession, error)
+
+	// ExamStatus reports the learner's attempts, cooldown, open session and score for an exam
+	ExamStatus(ctx context.Context, userID, courseID string, lessonPath []int) (*entities.ExamStatus, error)
+
+	// GetAttempt returns an attempt to the learner who made it or to the course author
+	GetAttempt(ctx context.Context, userID, attemptID string) (*entities.QuizAttempt, error)
+
+	// ReviewAttempt returns an attempt's responses joined to their questions, decoded answers,
+	// answer keys and explanations; only the learner and the course author may review it
+	ReviewAttempt(ctx context.Context, userID string, attempt *entities.QuizAttempt) ([]entities.ResponseReview, error)
+
+	// GenerateQuiz samples questions from a lesson's pool following the course's difficulty
+	// distribution, shuffles their options and stores the instance for grading
+	GenerateQuiz(ctx context.Context, input GenerateQuizInput) (*entities.QuizInstance, error)
+
+	// VisibleLessons returns the course lessons as the user may see them: authors get the
+	// full answer keys, learners only for questions they have already answered
+	VisibleLessons(ctx context.Context, userID string, course *entities.LibraryCourse) ([]entities.Lesson, error)
+
+	// RevealQuestion returns a question with its answer key once the user has answered it
+	RevealQuestion(ctx context.Context, userID, courseID string, lessonPath []int, questionID string) (*entities.ExtendedQuizQuestion, error)
+
+	// ItemAnalysis reports how each question of a quiz performed across all learners
+	// Only the course author may see it
+	ItemAnalysis(ctx context.Context, userID, courseID, quizID string) (*entities.QuizItemAnalysis, error)
+
+	// TestOut grades a chapter quiz and, when the score meets the course's test-out threshold,
+	// marks the chapter and all of its sublessons as completed
+	TestOut(ctx context.Context, input TestOutInput) (*entities.TestOutResult, error)
+
+	// UpsertLessonQuiz creates or replaces a lesson's quiz after validating every question
+	UpsertLessonQuiz(ctx context.Context, input EditQuizInput, quiz entities.ExtendedQuiz) (*entities.ExtendedQuiz, error)
+
+	// AddQuizQuestion inserts a question at position, or appends it when position is
+	// negative or past the end
+	AddQuizQuestion(ctx context.Context, input EditQuizInput, question entities.ExtendedQuizQuestion, position int) (*entities.ExtendedQuiz, error)
+
+	// UpdateQuizQuestion replaces the question with the same ID
+	UpdateQuizQuestion(ctx context.Context, input EditQuizInput, question entities.ExtendedQuizQuestion) (*entities.ExtendedQuiz, error)
+
+	// DeleteQuizQuestion removes a question from the quiz
+	DeleteQuizQuestion(ctx context.Context, input EditQuizInput, questionID string) (*entities.ExtendedQuiz, error)
+
+	// ReorderQuizQuestions puts the questions in the given order, which must list each once
+	ReorderQuizQuestions(ctx context.Context, input EditQuizInput, questionIDs []string) (*entities.ExtendedQuiz, error)
+
+	// ImportQuizQuestions appends converted questions to a lesson's quiz, reporting those
+	// that fail validation instead of importing them
+	ImportQuizQuestions(ctx context.Context, input EditQuizInput, questions []entities.ExtendedQuizQuestion) (*entities.QuizImportResult, error)
+
+	// GetLessonQuizForEditing returns a lesson's quiz with all answer keys, for its authors
+	GetLessonQuizForEditing(ctx context.Context, input EditQuizInput) (*entities.ExtendedQuiz, error)
+}
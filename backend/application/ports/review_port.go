@@ -0,0 +1,39 @@
+package ports
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/project/backend/domain/entities"
+)
+
+// QueueForReviewInput represents a question the learner got wrong and should revisit
+type QueueForReviewInput struct {
+	UserID     string
+	CourseID   string
+	QuizID     string
+	QuestionID string
+	Concept    string
+	Confidence entities.ConfidenceLevel // Confidence of the wrong answer; high confidence is reviewed first
+}
+
+// RecordReviewOutcomeInput represents the learner's answer to a question from the review queue
+type RecordReviewOutcomeInput struct {
+	UserID     string
+	CourseID   string
+	QuestionID string
+	Answer     json.RawMessage
+	Confidence entities.ConfidenceLevel
+}
+
+// ReviewPort defines the interface for spaced repetition use cases
+type ReviewPort interface {
+	// QueueForReview adds a question to the review queue, or treats it as a lapse if already queued
+	QueueForReview(ctx context.Context, input QueueForReviewInput) (*entities.ReviewQueueItem, error)
+
+	// RecordReviewOutcome grades a review answer and schedules the question's next review
+	RecordReviewOutcome(ctx context.Context, input RecordReviewOutcomeInput) (*entities.ReviewQueueItem, error)
+
+	// DailyReview builds today's review session across every course the user is enrolled in
+	DailyReview(ctx context.Context, userID string, dailyCap int) (*entities.DailyReview, error)
+}
@@ -0,0 +1,88 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/project/backend/application/ports"
+	"github.com/project/backend/domain/entities"
+	"github.com/project/backend/domain/repositories"
+)
+
+// QuestionBankUseCase handles browsing and analysing shared question banks
+type QuestionBankUseCase struct {
+	bankRepo repositories.QuestionBankRepository
+	quizRepo repositories.QuizRepository
+	editors  entities.UserGroup // May see the answer keys of banks loaded from folders
+}
+
+// Ensure QuestionBankUseCase implements QuestionBankPort
+var _ ports.QuestionBankPort = (*QuestionBankUseCase)(nil)
+
+// NewQuestionBankUseCase creates a new question bank use case
+func NewQuestionBankUseCase(bankRepo repositories.QuestionBankRepository, quizRepo repositories.QuizRepository, editors entities.UserGroup) *QuestionBankUseCase {
+	return &QuestionBankUseCase{
+		bankRepo: bankRepo,
+		quizRepo: quizRepo,
+		editors:  editors,
+	}
+}
+
+// ListQuestionBanks returns every bank, redacted for users who may not edit it
+func (uc *QuestionBankUseCase) ListQuestionBanks(ctx context.Context, userID string) ([]*entities.QuestionBank, error) {
+	banks, err := uc.bankRepo.ListQuestionBanks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*entities.QuestionBank, len(banks))
+	for i, bank := range banks {
+		result[i] = uc.bankForUser(bank, userID)
+	}
+	return result, nil
+}
+
+// GetQuestionBank returns a bank, redacted for users who may not edit it
+func (uc *QuestionBankUseCase) GetQuestionBank(ctx context.Context, userID, bankID string) (*entities.QuestionBank, error) {
+	bank, err := uc.bankRepo.GetQuestionBank(ctx, bankID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.bankForUser(bank, userID), nil
+}
+
+// QuestionBankAnalysis computes per-question statistics for a bank from the responses
+// recorded in every course whose quizzes use its questions
+func (uc *QuestionBankUseCase) QuestionBankAnalysis(ctx context.Context, userID, bankID string) (*entities.QuestionBankAnalysis, error) {
+	bank, err := uc.bankRepo.GetQuestionBank(ctx, bankID)
+	if err != nil {
+		return nil, err
+	}
+	if !bank.CanEditContent(userID, uc.editors) {
+		return nil, entities.ErrUnauthorized
+	}
+
+	questionIDs := make([]string, len(bank.Questions))
+	for i, q := range bank.Questions {
+		questionIDs[i] = entities.BankQuestionID(bank.ID, q.ID)
+	}
+	responses, err := uc.quizRepo.GetQuestionResponses(ctx, questionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return entities.ComputeQuestionBankAnalysis(bank, responses), nil
+}
+
+// bankForUser returns the bank as is for users who may edit it, or a copy without answer keys
+func (uc *QuestionBankUseCase) bankForUser(bank *entities.QuestionBank, userID string) *entities.QuestionBank {
+	if bank.CanEditContent(userID, uc.editors) {
+		return bank
+	}
+
+	redacted := *bank
+	redacted.Questions = make([]entities.ExtendedQuizQuestion, len(bank.Questions))
+	for i, q := range bank.Questions {
+		redacted.Questions[i] = q.Redacted()
+	}
+	return &redacted
+}
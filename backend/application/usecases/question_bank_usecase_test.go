@@ -0,0 +1,150 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/project/backend/application/ports"
+	"github.com/project/backend/domain/entities"
+	"github.com/project/backend/domain/services"
+)
+
+// MockQuestionBankRepository for testing
+type MockQuestionBankRepository struct {
+	banks []*entities.QuestionBank
+}
+
+func (m *MockQuestionBankRepository) ListQuestionBanks(ctx context.Context) ([]*entities.QuestionBank, error) {
+	return m.banks, nil
+}
+
+func (m *MockQuestionBankRepository) GetQuestionBank(ctx context.Context, id string) (*entities.QuestionBank, error) {
+	for _, bank := range m.banks {
+		if bank.ID == id {
+			return bank, nil
+		}
+	}
+	return nil, entities.ErrQuestionBankNotFound
+}
+
+func newTestQuestionBank() *entities.QuestionBank {
+	return &entities.QuestionBank{
+		ID:       "go-basics",
+		Title:    "Go Basics",
+		AuthorID: "author-1",
+		Questions: []entities.ExtendedQuizQuestion{
+			{ID: "b1", Type: entities.QuestionTypeMultipleChoice, Difficulty: 2, Options: []string{"a", "b"}, CorrectIndex: 1, Explanation: "Because"},
+			{ID: "b2", Type: entities.QuestionTypeMultipleChoice, Difficulty: 2, Options: []string{"a", "b"}, CorrectIndex: 0},
+		},
+	}
+}
+
+func TestQuestionBankUseCase_GetQuestionBank(t *testing.T) {
+	bankRepo := &MockQuestionBankRepository{banks: []*entities.QuestionBank{newTestQuestionBank()}}
+	useCase := NewQuestionBankUseCase(bankRepo, NewMockQuizRepository(), nil)
+	ctx := context.Background()
+
+	bank, err := useCase.GetQuestionBank(ctx, "learner-1", "go-basics")
+	if err != nil {
+		t.Fatalf("GetQuestionBank failed: %v", err)
+	}
+	if bank.Questions[0].CorrectIndex != 0 || bank.Questions[0].Explanation != "" {
+		t.Errorf("expected answer keys left out for a learner, got %+v", bank.Questions[0])
+	}
+	if bankRepo.banks[0].Questions[0].CorrectIndex != 1 {
+		t.Error("expected the stored bank to be left unchanged")
+	}
+
+	bank, _ = useCase.GetQuestionBank(ctx, "author-1", "go-basics")
+	if bank.Questions[0].CorrectIndex != 1 {
+		t.Errorf("expected answer keys for the author, got %+v", bank.Questions[0])
+	}
+
+	if _, err := useCase.GetQuestionBank(ctx, "author-1", "missing"); !errors.Is(err, entities.ErrQuestionBankNotFound) {
+		t.Errorf("expected ErrQuestionBankNotFound, got %v", err)
+	}
+}
+
+func TestQuestionBankUseCase_QuestionBankAnalysis(t *testing.T) {
+	bank := newTestQuestionBank()
+	quizRepo := NewMockQuizRepository()
+	useCase := NewQuestionBankUseCase(&MockQuestionBankRepository{banks: []*entities.QuestionBank{bank}}, quizRepo, nil)
+	ctx := context.Background()
+
+	// Two courses pull the same bank question into their quizzes
+	for _, courseID := range []string{"course-1", "course-2"} {
+		course := newQuizTestCourse()
+		course.ID = courseID
+		quiz := course.Lessons[0].Sublessons[0].ExtendedQuiz
+		quiz.BankRefs = []entities.QuestionBankRef{{Bank: bank.ID, Questions: []string{"b1"}}}
+		if err := quiz.AddBankQuestions(map[string]*entities.QuestionBank{bank.ID: bank}, courseID); err != nil {
+			t.Fatalf("AddBankQuestions failed: %v", err)
+		}
+
+		quizUseCase := NewQuizUseCase(NewMockLibraryCourseRepository(course), &MockUserCourseRepository{}, quizRepo, services.NewQuizGrader(nil), services.NewQuizAssembler(), services.NewReviewScheduler(), nil)
+		_, err := quizUseCase.SubmitAttempt(ctx, ports.SubmitQuizAttemptInput{
+			UserID:     "user-1",
+			CourseID:   courseID,
+			LessonPath: []int{0, 0},
+			Answers:    []entities.QuizAnswer{{QuestionID: "go-basics.b1", Answer: json.RawMessage(`1`)}},
+		})
+		if err != nil {
+			t.Fatalf("SubmitAttempt failed: %v", err)
+		}
+	}
+
+	if _, err := useCase.QuestionBankAnalysis(ctx, "learner-1", bank.ID); !errors.Is(err, entities.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized for a learner, got %v", err)
+	}
+
+	analysis, err := useCase.QuestionBankAnalysis(ctx, "author-1", bank.ID)
+	if err != nil {
+		t.Fatalf("QuestionBankAnalysis failed: %v", err)
+	}
+	if len(analysis.Courses) != 2 || analysis.Attempts != 2 {
+		t.Errorf("expected 2 attempts from 2 courses, got %+v", analysis)
+	}
+	if len(analysis.Items) != 2 || analysis.Items[0].Responses != 2 || analysis.Items[1].Responses != 0 {
+		t.Errorf("expected b1 answered in both courses and b2 unanswered, got %+v", analysis.Items)
+	}
+}
+
+func TestQuestionBankUseCase_FolderBank(t *testing.T) {
+	bank := newTestQuestionBank()
+	bank.AuthorID = entities.FolderAuthorID
+	editors := entities.NewUserGroup([]string{"editor-1"})
+	useCase := NewQuestionBankUseCase(&MockQuestionBankRepository{banks: []*entities.QuestionBank{bank}}, NewMockQuizRepository(), editors)
+	ctx := context.Background()
+
+	// Banks loaded from folders have no author account, so signed-in learners are not editors
+	banks, err := useCase.ListQuestionBanks(ctx, "learner-1")
+	if err != nil {
+		t.Fatalf("ListQuestionBanks failed: %v", err)
+	}
+	if q := banks[0].Questions[0]; q.CorrectIndex != 0 || q.Explanation != "" {
+		t.Errorf("expected answer keys left out for a signed-in learner, got %+v", q)
+	}
+	learnerBank, err := useCase.GetQuestionBank(ctx, "learner-1", bank.ID)
+	if err != nil {
+		t.Fatalf("GetQuestionBank failed: %v", err)
+	}
+	if q := learnerBank.Questions[0]; q.CorrectIndex != 0 || q.Explanation != "" {
+		t.Errorf("expected answer keys left out for a signed-in learner, got %+v", q)
+	}
+	if _, err := useCase.QuestionBankAnalysis(ctx, "learner-1", bank.ID); !errors.Is(err, entities.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized for a learner, got %v", err)
+	}
+
+	editorBank, err := useCase.GetQuestionBank(ctx, "editor-1", bank.ID)
+	if err != nil {
+		t.Fatalf("GetQuestionBank failed: %v", err)
+	}
+	if q := editorBank.Questions[0]; q.CorrectIndex != 1 || q.Explanation != "Because" {
+		t.Errorf("expected answer keys for a configured editor, got %+v", q)
+	}
+	if _, err := useCase.QuestionBankAnalysis(ctx, "editor-1", bank.ID); err != nil {
+		t.Errorf("expected a configured editor to see the analysis, got %v", err)
+	}
+}
@@ -0,0 +1,970 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/project/backend/application/ports"
+	"github.com/project/backend/domain/entities"
+	"github.com/project/backend/domain/repositories"
+	"github.com/project/backend/domain/services"
+)
+
+// QuizUseCase handles quiz submission and grading
+type QuizUseCase struct {
+	courseRepo     repositories.LibraryCourseRepository
+	userCourseRepo repositories.UserCourseRepository
+	quizRepo       repositories.QuizRepository
+	grader         *services.QuizGrader
+	assembler      *services.QuizAssembler
+	scheduler      *services.ReviewScheduler
+	editors        entities.UserGroup // May edit the quizzes of folder courses
+}
+
+// Ensure QuizUseCase implements QuizPort
+var _ ports.QuizPort = (*QuizUseCase)(nil)
+
+// NewQuizUseCase creates a new quiz use case
+func NewQuizUseCase(courseRepo repositories.LibraryCourseRepository, userCourseRepo repositories.UserCourseRepository, quizRepo repositories.QuizRepository, grader *services.QuizGrader, assembler *services.QuizAssembler, scheduler *services.ReviewScheduler, editors entities.UserGroup) *QuizUseCase {
+	return &QuizUseCase{
+		courseRepo:     courseRepo,
+		userCourseRepo: userCourseRepo,
+		quizRepo:       quizRepo,
+		grader:         grader,
+		assembler:      assembler,
+		scheduler:      scheduler,
+		editors:        editors,
+	}
+}
+
+// SubmitAttempt grades the answers against the lesson's quiz and stores the result
+// Scores sent by the client are never trusted; everything is derived from the answer key
+func (uc *QuizUseCase) SubmitAttempt(ctx context.Context, input ports.SubmitQuizAttemptInput) (*entities.QuizAttempt, error) {
+	if input.UserID == "" {
+		return nil, entities.ErrInvalidUserID
+	}
+
+	// Exam answers count as given when they arrive, however long grading takes
+	submittedAt := time.Now()
+	session, err := uc.examSession(ctx, input, submittedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	quiz, instance, err := uc.submissionQuiz(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	grade, err := uc.grader.GradeQuiz(ctx, quiz, input.Answers)
+	if err != nil {
+		return nil, err
+	}
+
+	// Store answers to a generated quiz in the original option order, so they can be
+	// compared with every other attempt at the same questions
+	if instance != nil {
+		for i := range grade.Responses {
+			grade.Responses[i].UserAnswer = uc.assembler.UnshuffleAnswer(&quiz.Questions[i], instance.Seed, grade.Responses[i].UserAnswer)
+		}
+	}
+
+	// Quiz IDs are derived from the lesson path so answers can be matched back to questions
+	attempt := entities.NewQuizAttempt(
+		input.UserID,
+		input.CourseID,
+		entities.QuizTypeForLessonPath(input.LessonPath),
+		entities.QuizIDForLessonPath(input.LessonPath),
+		grade.Score,
+		grade.MaxScore,
+		grade.TotalQuestions,
+		grade.CorrectCount,
+	)
+
+	// The session is closed with the attempt and its responses stored, all or none of them,
+	// so a session submitted twice at once is only graded once
+	sessionID := ""
+	if session != nil {
+		sessionID = session.ID
+	}
+	savedAttempt, err := uc.quizRepo.SaveGradedAttempt(ctx, sessionID, submittedAt, attempt, grade.Responses)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, response := range grade.Responses {
+		// A confidently wrong answer points at a misconception, so it is queued for review
+		// straight away; other misses are left for the learner to queue
+		if !response.IsCorrect && response.Confidence == entities.ConfidenceHigh {
+			_, err := queueForReview(ctx, uc.quizRepo, uc.scheduler, ports.QueueForReviewInput{
+				UserID:     input.UserID,
+				CourseID:   input.CourseID,
+				QuizID:     attempt.QuizID,
+				QuestionID: response.QuestionID,
+				Concept:    response.Concept,
+				Confidence: response.Confidence,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return savedAttempt, nil
+}
+
+// submissionQuiz loads the quiz the answers are graded against: the generated quiz when an
+// instance is given, otherwise the lesson's own quiz
+func (uc *QuizUseCase) submissionQuiz(ctx context.Context, input ports.SubmitQuizAttemptInput) (*entities.ExtendedQuiz, *entities.QuizInstance, error) {
+	if input.InstanceID != "" {
+		return uc.loadInstanceQuiz(ctx, input)
+	}
+	quiz, err := loadLessonQuiz(ctx, uc.courseRepo, input.CourseID, input.LessonPath)
+	return quiz, nil, err
+}
+
+// StartQuizAttempt starts a draft attempt, checking up front that it could be submitted:
+// a generated quiz must have been issued to the learner, and exams need an open session
+// The learner's open draft for the same quiz, generated quiz and session is resumed instead
+func (uc *QuizUseCase) StartQuizAttempt(ctx context.Context, input ports.StartQuizAttemptInput) (*entities.QuizDraft, error) {
+	if input.UserID == "" {
+		return nil, entities.ErrInvalidUserID
+	}
+
+	submission := ports.SubmitQuizAttemptInput{
+		UserID:     input.UserID,
+		CourseID:   input.CourseID,
+		LessonPath: input.LessonPath,
+		InstanceID: input.InstanceID,
+		SessionID:  input.SessionID,
+	}
+	if _, _, err := uc.submissionQuiz(ctx, submission); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session, err := uc.examSession(ctx, submission, now)
+	if err != nil {
+		return nil, err
+	}
+
+	drafts, err := uc.quizRepo.GetOpenQuizDrafts(ctx, input.UserID, now)
+	if err != nil {
+		return nil, err
+	}
+	quizID := entities.QuizIDForLessonPath(input.LessonPath)
+	for i := range drafts {
+		d := &drafts[i]
+		if d.CourseID == input.CourseID && d.QuizID == quizID && d.InstanceID == input.InstanceID && d.SessionID == input.SessionID {
+			return d, nil
+		}
+	}
+
+	// A draft of a timed exam is of no use once its session stops accepting submissions
+	var deadline *time.Time
+	if session != nil && session.ExpiresAt != nil {
+		end := session.ExpiresAt.Add(entities.ExamSubmissionGrace)
+		deadline = &end
+	}
+
+	draft, err := entities.NewQuizDraft(input.UserID, input.CourseID, input.LessonPath, input.InstanceID, input.SessionID, deadline)
+	if err != nil {
+		return nil, err
+	}
+	return uc.quizRepo.SaveQuizDraft(ctx, draft)
+}
+
+// SaveQuizAnswer stores the answer to one question of a draft and extends the draft's expiry
+// Answers are only graded when the attempt is finished, so any answer to a question of the
+// quiz is accepted as given
+func (uc *QuizUseCase) SaveQuizAnswer(ctx context.Context, userID, draftID string, answer entities.QuizAnswer) (*entities.QuizDraft, error) {
+	now := time.Now()
+	draft, err := uc.openDraft(ctx, userID, draftID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	submission, err := draftSubmission(draft)
+	if err != nil {
+		return nil, err
+	}
+	quiz, _, err := uc.submissionQuiz(ctx, submission)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := quiz.FindQuestion(answer.QuestionID); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, answer.QuestionID)
+	}
+
+	draft.SetAnswer(answer)
+	draft.Touch(now)
+	if err := uc.quizRepo.SaveQuizDraftAnswer(ctx, draft, answer); err != nil {
+		return nil, err
+	}
+
+	return draft, nil
+}
+
+// FinishQuizAttempt submits a draft's saved answers through SubmitAttempt, so exams and
+// generated quizzes are checked exactly as for a direct submission, then discards the draft
+// A draft whose submission fails is kept, so grading can be retried
+func (uc *QuizUseCase) FinishQuizAttempt(ctx context.Context, userID, draftID string) (*entities.QuizAttempt, error) {
+	draft, err := uc.openDraft(ctx, userID, draftID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	submission, err := draftSubmission(draft)
+	if err != nil {
+		return nil, err
+	}
+	attempt, err := uc.SubmitAttempt(ctx, submission)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.quizRepo.DeleteQuizDraft(ctx, draft.ID); err != nil {
+		return nil, err
+	}
+
+	return attempt, nil
+}
+
+// OpenQuizAttempts lists the learner's drafts that can still be resumed
+func (uc *QuizUseCase) OpenQuizAttempts(ctx context.Context, userID string) ([]entities.QuizDraft, error) {
+	if userID == "" {
+		return nil, entities.ErrInvalidUserID
+	}
+
+	return uc.quizRepo.GetOpenQuizDrafts(ctx, userID, time.Now())
+}
+
+// PurgeExpiredDrafts deletes stale drafts; they are hidden as soon as they expire, so this
+// only reclaims their storage
+func (uc *QuizUseCase) PurgeExpiredDrafts(ctx context.Context) (int, error) {
+	return uc.quizRepo.DeleteExpiredQuizDrafts(ctx, time.Now())
+}
+
+// openDraft returns a draft of the user's that has not expired
+// Other learners' drafts are reported as missing rather than forbidden
+func (uc *QuizUseCase) openDraft(ctx context.Context, userID, draftID string, now time.Time) (*entities.QuizDraft, error) {
+	if userID == "" {
+		return nil, entities.ErrInvalidUserID
+	}
+
+	draft, err := uc.quizRepo.GetQuizDraft(ctx, draftID)
+	if err != nil {
+		return nil, err
+	}
+	if draft.UserID != userID || draft.IsExpired(now) {
+		return nil, entities.ErrQuizDraftNotFound
+	}
+
+	return draft, nil
+}
+
+// draftSubmission turns a draft into the submission of its saved answers
+func draftSubmission(draft *entities.QuizDraft) (ports.SubmitQuizAttemptInput, error) {
+	lessonPath, err := draft.LessonPath()
+	if err != nil {
+		return ports.SubmitQuizAttemptInput{}, err
+	}
+
+	return ports.SubmitQuizAttemptInput{
+		UserID:     draft.UserID,
+		CourseID:   draft.CourseID,
+		LessonPath: lessonPath,
+		InstanceID: draft.InstanceID,
+		SessionID:  draft.SessionID,
+		Answers:    draft.Answers,
+	}, nil
+}
+
+// StartQuizSession starts an attempt at an exam, which may then be submitted with the
+// session's ID until its time limit runs out
+// A session already in progress is returned instead, so restarting does not reset the clock
+func (uc *QuizUseCase) StartQuizSession(ctx context.Context, userID, courseID string, lessonPath []int) (*entities.QuizSession, error) {
+	if userID == "" {
+		return nil, entities.ErrInvalidUserID
+	}
+
+	status, err := uc.ExamStatus(ctx, userID, courseID, lessonPath)
+	if err != nil {
+		return nil, err
+	}
+	if status.OpenSession != nil {
+		return status.OpenSession, nil
+	}
+	if status.AttemptsRemaining != nil && *status.AttemptsRemaining == 0 {
+		return nil, fmt.Errorf("%w: all %d attempts have been used", entities.ErrExamAttemptsExhausted, status.Config.MaxAttempts)
+	}
+	if status.NextAttemptAt != nil {
+		return nil, fmt.Errorf("%w: try again after %s", entities.ErrExamCooldown, status.NextAttemptAt.UTC().Format(time.RFC3339))
+	}
+
+	session := entities.NewQuizSession(userID, courseID, lessonPath, status.Config)
+	return uc.quizRepo.SaveQuizSession(ctx, session)
+}
+
+// ExamStatus works out where a learner stands on an exam from their sessions and attempts
+func (uc *QuizUseCase) ExamStatus(ctx context.Context, userID, courseID string, lessonPath []int) (*entities.ExamStatus, error) {
+	if userID == "" {
+		return nil, entities.ErrInvalidUserID
+	}
+
+	course, err := uc.courseRepo.GetByID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	exam, err := course.ExamConfigFor(lessonPath)
+	if err != nil {
+		return nil, err
+	}
+	if exam == nil {
+		return nil, entities.ErrNotAnExam
+	}
+
+	quizID := entities.QuizIDForLessonPath(lessonPath)
+	sessions, err := uc.quizRepo.GetQuizSessions(ctx, userID, courseID, quizID)
+	if err != nil {
+		return nil, err
+	}
+	attempts, err := uc.quizRepo.GetAttemptsByQuiz(ctx, userID, courseID, quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &entities.ExamStatus{
+		Config:       *exam,
+		AttemptsUsed: len(sessions),
+		Score:        exam.Score(attempts),
+	}
+	if exam.MaxAttempts > 0 {
+		remaining := max(exam.MaxAttempts-len(sessions), 0)
+		status.AttemptsRemaining = &remaining
+	}
+
+	now := time.Now()
+	if len(sessions) > 0 {
+		latest := &sessions[0]
+		if latest.IsOpen(now) {
+			status.OpenSession = latest
+		} else if endedAt := latest.EndedAt(now); endedAt != nil && exam.Cooldown() > 0 {
+			if next := endedAt.Add(exam.Cooldown()); now.Before(next) {
+				status.NextAttemptAt = &next
+			}
+		}
+	}
+
+	return status, nil
+}
+
+// examSession checks that answers to an exam were given in an open session of the learner's
+// Answers to open practice quizzes need no session, and nil is returned for them
+func (uc *QuizUseCase) examSession(ctx context.Context, input ports.SubmitQuizAttemptInput, submittedAt time.Time) (*entities.QuizSession, error) {
+	course, err := uc.courseRepo.GetByID(ctx, input.CourseID)
+	if err != nil {
+		return nil, err
+	}
+	exam, err := course.ExamConfigFor(input.LessonPath)
+	if err != nil || exam == nil {
+		return nil, err
+	}
+
+	if input.SessionID == "" {
+		return nil, entities.ErrExamSessionRequired
+	}
+	session, err := uc.quizRepo.GetQuizSession(ctx, input.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.UserID != input.UserID || session.CourseID != input.CourseID || session.QuizID != entities.QuizIDForLessonPath(input.LessonPath) {
+		return nil, entities.ErrQuizSessionNotFound
+	}
+	if session.SubmittedAt != nil {
+		return nil, entities.ErrQuizSessionClosed
+	}
+	if !session.IsOpen(submittedAt) {
+		return nil, fmt.Errorf("%w: the session ended at %s", entities.ErrExamTimeLimitExceeded, session.ExpiresAt.UTC().Format(time.RFC3339))
+	}
+
+	return session, nil
+}
+
+// GenerateQuiz assembles a fresh quiz for the learner from the lesson's question pool
+// The returned questions have their answer keys hidden
+func (uc *QuizUseCase) GenerateQuiz(ctx context.Context, input ports.GenerateQuizInput) (*entities.QuizInstance, error) {
+	if input.UserID == "" {
+		return nil, entities.ErrInvalidUserID
+	}
+
+	course, err := uc.courseRepo.GetByID(ctx, input.CourseID)
+	if err != nil {
+		return nil, err
+	}
+
+	lesson, err := course.LessonAt(input.LessonPath)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := lesson.QuestionPool(input.IncludeSublessons)
+	if len(pool) == 0 {
+		return nil, entities.ErrQuizNotFound
+	}
+
+	config := course.EffectiveQuizConfig()
+	count := config.QuestionsPerSubchapter
+	if len(input.LessonPath) == 1 {
+		count = config.QuestionsPerChapter
+	}
+
+	seed := rand.Int64()
+	questionIDs := uc.assembler.SelectQuestions(pool, count, config.DifficultyDistribution, seed)
+
+	instance, err := entities.NewQuizInstance(input.UserID, input.CourseID, input.LessonPath, input.IncludeSublessons, seed, questionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	questions, err := uc.assembler.BuildQuestions(pool, questionIDs, seed)
+	if err != nil {
+		return nil, err
+	}
+
+	instance, err = uc.quizRepo.SaveQuizInstance(ctx, instance)
+	if err != nil {
+		return nil, err
+	}
+
+	instance.Questions = make([]entities.ExtendedQuizQuestion, len(questions))
+	for i, q := range questions {
+		instance.Questions[i] = q.Redacted()
+	}
+
+	return instance, nil
+}
+
+// loadInstanceQuiz rebuilds a generated quiz, with its shuffled answer key, for grading
+func (uc *QuizUseCase) loadInstanceQuiz(ctx context.Context, input ports.SubmitQuizAttemptInput) (*entities.ExtendedQuiz, *entities.QuizInstance, error) {
+	instance, err := uc.quizRepo.GetQuizInstance(ctx, input.InstanceID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// An instance can only be answered by the learner it was issued to, for its own lesson
+	if instance.UserID != input.UserID || instance.CourseID != input.CourseID ||
+		instance.QuizID != entities.QuizIDForLessonPath(input.LessonPath) {
+		return nil, nil, entities.ErrQuizInstanceNotFound
+	}
+
+	course, err := uc.courseRepo.GetByID(ctx, input.CourseID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lesson, err := course.LessonAt(input.LessonPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	questions, err := uc.assembler.BuildQuestions(lesson.QuestionPool(instance.IncludeSublessons), instance.QuestionIDs, instance.Seed)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &entities.ExtendedQuiz{Questions: questions}, instance, nil
+}
+
+// GetAttempt returns an attempt if the user may review it
+func (uc *QuizUseCase) GetAttempt(ctx context.Context, userID, attemptID string) (*entities.QuizAttempt, error) {
+	attempt, err := uc.quizRepo.GetAttempt(ctx, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.checkAttemptAccess(ctx, userID, attempt); err != nil {
+		return nil, err
+	}
+
+	return attempt, nil
+}
+
+// ReviewAttempt joins each response of an attempt to the question it answered
+// Questions drawn from sublessons by generated quizzes are found through the lesson's pool;
+// questions removed from the course since the attempt are returned without question details
+func (uc *QuizUseCase) ReviewAttempt(ctx context.Context, userID string, attempt *entities.QuizAttempt) ([]entities.ResponseReview, error) {
+	if err := uc.checkAttemptAccess(ctx, userID, attempt); err != nil {
+		return nil, err
+	}
+
+	responses, err := uc.quizRepo.GetResponsesByAttempt(ctx, attempt.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	questions := make(map[string]*entities.ExtendedQuizQuestion)
+	course, err := uc.courseRepo.GetByID(ctx, attempt.CourseID)
+	if err != nil {
+		return nil, err
+	}
+	if lessonPath, err := entities.LessonPathForQuizID(attempt.QuizID); err == nil {
+		if lesson, err := course.LessonAt(lessonPath); err == nil {
+			pool := lesson.QuestionPool(true)
+			for i := range pool {
+				questions[pool[i].ID] = &pool[i]
+			}
+		}
+	}
+
+	reviews := make([]entities.ResponseReview, len(responses))
+	for i, response := range responses {
+		reviews[i] = entities.NewResponseReview(response, questions[response.QuestionID])
+	}
+
+	return reviews, nil
+}
+
+// checkAttemptAccess allows the learner who made an attempt and the course author
+func (uc *QuizUseCase) checkAttemptAccess(ctx context.Context, userID string, attempt *entities.QuizAttempt) error {
+	if userID == "" {
+		return entities.ErrInvalidUserID
+	}
+	if attempt.UserID == userID {
+		return nil
+	}
+
+	course, err := uc.courseRepo.GetByID(ctx, attempt.CourseID)
+	if err != nil {
+		return err
+	}
+	if course.AuthorID != userID {
+		return entities.ErrUnauthorized
+	}
+
+	return nil
+}
+
+// VisibleLessons returns the course lessons with answer keys redacted for learners
+func (uc *QuizUseCase) VisibleLessons(ctx context.Context, userID string, course *entities.LibraryCourse) ([]entities.Lesson, error) {
+	if userID != "" && userID == course.AuthorID {
+		return course.Lessons, nil
+	}
+
+	answered := map[string][]string{}
+	if userID != "" {
+		var err error
+		answered, err = uc.quizRepo.GetAnsweredQuestions(ctx, userID, course.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return entities.RedactLessons(course.Lessons, answered), nil
+}
+
+// RevealQuestion returns the full question, including its answer key and explanation,
+// to the course author or to a learner who has already answered it
+func (uc *QuizUseCase) RevealQuestion(ctx context.Context, userID, courseID string, lessonPath []int, questionID string) (*entities.ExtendedQuizQuestion, error) {
+	if userID == "" {
+		return nil, entities.ErrInvalidUserID
+	}
+
+	course, err := uc.courseRepo.GetByID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	quiz, err := loadLessonQuiz(ctx, uc.courseRepo, courseID, lessonPath)
+	if err != nil {
+		return nil, err
+	}
+
+	question, err := quiz.FindQuestion(questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if course.AuthorID == userID {
+		return question, nil
+	}
+
+	answered, err := uc.quizRepo.GetAnsweredQuestions(ctx, userID, courseID)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range answered[entities.QuizIDForLessonPath(lessonPath)] {
+		if id == questionID {
+			return question, nil
+		}
+	}
+
+	return nil, entities.ErrAnswerKeyHidden
+}
+
+// ItemAnalysis computes per-question statistics for a quiz from every learner's responses
+// Questions drawn from sublessons into generated quizzes are included once answered
+func (uc *QuizUseCase) ItemAnalysis(ctx context.Context, userID, courseID, quizID string) (*entities.QuizItemAnalysis, error) {
+	course, err := uc.courseRepo.GetByID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if userID == "" || course.AuthorID != userID {
+		return nil, entities.ErrUnauthorized
+	}
+
+	lessonPath, err := entities.LessonPathForQuizID(quizID)
+	if err != nil {
+		return nil, err
+	}
+	lesson, err := course.LessonAt(lessonPath)
+	if err != nil {
+		return nil, err
+	}
+
+	responses, err := uc.quizRepo.GetItemResponses(ctx, courseID, quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	include := make(map[string]bool)
+	if quiz := lesson.EffectiveQuiz(); quiz != nil {
+		for _, q := range quiz.Questions {
+			include[q.ID] = true
+		}
+	}
+	for _, r := range responses {
+		include[r.QuestionID] = true
+	}
+
+	var questions []entities.ExtendedQuizQuestion
+	for _, q := range lesson.QuestionPool(true) {
+		if include[q.ID] {
+			questions = append(questions, q)
+		}
+	}
+	if len(questions) == 0 {
+		return nil, entities.ErrQuizNotFound
+	}
+
+	return entities.ComputeItemAnalysis(courseID, quizID, questions, responses), nil
+}
+
+// TestOut grades a chapter quiz against the course's test-out threshold
+// A passing attempt completes every lesson in the chapter, enrolling the user if needed
+func (uc *QuizUseCase) TestOut(ctx context.Context, input ports.TestOutInput) (*entities.TestOutResult, error) {
+	course, err := uc.courseRepo.GetByID(ctx, input.CourseID)
+	if err != nil {
+		return nil, err
+	}
+
+	indices, err := course.ChapterLessonIndices(input.Chapter)
+	if err != nil {
+		return nil, err
+	}
+
+	attempt, err := uc.SubmitAttempt(ctx, ports.SubmitQuizAttemptInput{
+		UserID:     input.UserID,
+		CourseID:   input.CourseID,
+		LessonPath: []int{input.Chapter},
+		SessionID:  input.SessionID,
+		Answers:    input.Answers,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	threshold := course.EffectiveQuizConfig().TestOutThreshold
+	result := &entities.TestOutResult{
+		Attempt:          attempt,
+		Passed:           attempt.Percentage >= float64(threshold),
+		Threshold:        threshold,
+		CompletedLessons: []int{},
+	}
+	if !result.Passed {
+		return result, nil
+	}
+
+	userCourse, err := uc.userCourseRepo.GetByUserAndCourse(ctx, input.UserID, input.CourseID)
+	if errors.Is(err, entities.ErrCourseNotFound) {
+		userCourse, err = entities.NewUserCourse(input.UserID, input.CourseID)
+		if err != nil {
+			return nil, err
+		}
+		userCourse, err = uc.userCourseRepo.Create(ctx, userCourse)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	totalLessons := course.TotalLessonCount()
+	for _, index := range indices {
+		if userCourse.IsLessonCompleted(index) {
+			continue
+		}
+		if err := userCourse.MarkLessonTestedOut(index, totalLessons); err != nil {
+			return nil, err
+		}
+		result.CompletedLessons = append(result.CompletedLessons, index)
+	}
+
+	result.UserCourse, err = uc.userCourseRepo.Update(ctx, userCourse)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpsertLessonQuiz replaces the lesson's quiz; the submitted questions are validated as a whole
+// Questions the quiz pulls from banks are kept after the submitted ones, which need not repeat them
+func (uc *QuizUseCase) UpsertLessonQuiz(ctx context.Context, input ports.EditQuizInput, quiz entities.ExtendedQuiz) (*entities.ExtendedQuiz, error) {
+	return uc.editLessonQuiz(ctx, input, func(current *entities.ExtendedQuiz) error {
+		if quiz.Version != "" {
+			current.Version = quiz.Version
+		}
+		bankQuestions := bankQuestionsOf(current)
+		questions := make([]entities.ExtendedQuizQuestion, 0, len(quiz.Questions)+len(bankQuestions))
+		for _, q := range quiz.Questions {
+			if !slices.ContainsFunc(bankQuestions, func(b entities.ExtendedQuizQuestion) bool { return b.ID == q.ID }) {
+				questions = append(questions, q)
+			}
+		}
+		current.Questions = append(questions, bankQuestions...)
+		return current.Validate()
+	})
+}
+
+// AddQuizQuestion validates a new question and inserts it into the lesson's quiz
+func (uc *QuizUseCase) AddQuizQuestion(ctx context.Context, input ports.EditQuizInput, question entities.ExtendedQuizQuestion, position int) (*entities.ExtendedQuiz, error) {
+	return uc.editLessonQuiz(ctx, input, func(current *entities.ExtendedQuiz) error {
+		if err := question.Validate(); err != nil {
+			return err
+		}
+		if _, err := current.FindQuestion(question.ID); err == nil {
+			return fmt.Errorf("%w %s: id is used by another question", entities.ErrInvalidQuestion, question.ID)
+		}
+		if position < 0 || position > len(current.Questions) {
+			position = len(current.Questions)
+		}
+		current.Questions = slices.Insert(current.Questions, position, question)
+		return nil
+	})
+}
+
+// UpdateQuizQuestion validates a question and replaces the one with the same ID
+func (uc *QuizUseCase) UpdateQuizQuestion(ctx context.Context, input ports.EditQuizInput, question entities.ExtendedQuizQuestion) (*entities.ExtendedQuiz, error) {
+	return uc.editLessonQuiz(ctx, input, func(current *entities.ExtendedQuiz) error {
+		if err := question.Validate(); err != nil {
+			return err
+		}
+		existing, err := current.FindQuestion(question.ID)
+		if err != nil {
+			return err
+		}
+		if existing.BankID != "" {
+			return entities.ErrBankQuestionReadOnly
+		}
+		*existing = question
+		return nil
+	})
+}
+
+// DeleteQuizQuestion removes a question from the lesson's quiz
+func (uc *QuizUseCase) DeleteQuizQuestion(ctx context.Context, input ports.EditQuizInput, questionID string) (*entities.ExtendedQuiz, error) {
+	return uc.editLessonQuiz(ctx, input, func(current *entities.ExtendedQuiz) error {
+		index := slices.IndexFunc(current.Questions, func(q entities.ExtendedQuizQuestion) bool { return q.ID == questionID })
+		if index < 0 {
+			return entities.ErrQuestionNotFound
+		}
+		if current.Questions[index].BankID != "" {
+			return entities.ErrBankQuestionReadOnly
+		}
+		current.Questions = slices.Delete(current.Questions, index, index+1)
+		return nil
+	})
+}
+
+// ReorderQuizQuestions rearranges the lesson's quiz into the given order
+// Bank questions are listed too, but always follow the quiz's own questions, in the order
+// their bank references give
+func (uc *QuizUseCase) ReorderQuizQuestions(ctx context.Context, input ports.EditQuizInput, questionIDs []string) (*entities.ExtendedQuiz, error) {
+	return uc.editLessonQuiz(ctx, input, func(current *entities.ExtendedQuiz) error {
+		if len(questionIDs) != len(current.Questions) {
+			return fmt.Errorf("%w: expected %d question IDs, got %d", entities.ErrInvalidQuestion, len(current.Questions), len(questionIDs))
+		}
+
+		reordered := make([]entities.ExtendedQuizQuestion, 0, len(questionIDs))
+		seen := make(map[string]bool, len(questionIDs))
+		for _, id := range questionIDs {
+			question, err := current.FindQuestion(id)
+			if err != nil {
+				return fmt.Errorf("%w: %s", err, id)
+			}
+			if seen[id] {
+				return fmt.Errorf("%w %s: listed twice", entities.ErrInvalidQuestion, id)
+			}
+			seen[id] = true
+			if question.BankID == "" {
+				reordered = append(reordered, *question)
+			}
+		}
+		current.Questions = append(reordered, bankQuestionsOf(current)...)
+		return nil
+	})
+}
+
+// ImportQuizQuestions appends imported questions to the lesson's quiz
+// IDs that are empty or already taken get a numeric suffix; questions that fail validation
+// are reported in the result instead of failing the whole import
+func (uc *QuizUseCase) ImportQuizQuestions(ctx context.Context, input ports.EditQuizInput, questions []entities.ExtendedQuizQuestion) (*entities.QuizImportResult, error) {
+	lesson, err := uc.lessonForEditing(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	quiz := copyLessonQuiz(lesson)
+
+	taken := make(map[string]bool, len(quiz.Questions)+len(questions))
+	for _, q := range quiz.Questions {
+		taken[q.ID] = true
+	}
+
+	result := &entities.QuizImportResult{Quiz: quiz}
+	imported := 0
+	for _, question := range questions {
+		question.ID = uniqueQuestionID(question.ID, taken)
+		if err := question.Validate(); err != nil {
+			result.Skipped = append(result.Skipped, entities.QuizConversionIssue{Item: question.ID, Reason: err.Error()})
+			continue
+		}
+		taken[question.ID] = true
+		quiz.Questions = append(quiz.Questions, question)
+		imported++
+	}
+
+	if imported == 0 {
+		return result, nil
+	}
+	if err := uc.courseRepo.SaveLessonQuiz(ctx, input.CourseID, input.LessonPath, quiz); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetLessonQuizForEditing returns a lesson's quiz with every answer key, for export
+// Only users who may edit the course's content can read it this way
+func (uc *QuizUseCase) GetLessonQuizForEditing(ctx context.Context, input ports.EditQuizInput) (*entities.ExtendedQuiz, error) {
+	lesson, err := uc.lessonForEditing(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if lesson.EffectiveQuiz() == nil {
+		return nil, entities.ErrQuizNotFound
+	}
+
+	return copyLessonQuiz(lesson), nil
+}
+
+// editLessonQuiz applies an edit to a copy of the lesson's quiz and saves the result
+// Only users who may edit the course's content can change its quizzes
+func (uc *QuizUseCase) editLessonQuiz(ctx context.Context, input ports.EditQuizInput, edit func(quiz *entities.ExtendedQuiz) error) (*entities.ExtendedQuiz, error) {
+	lesson, err := uc.lessonForEditing(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	quiz := copyLessonQuiz(lesson)
+	if err := edit(quiz); err != nil {
+		return nil, err
+	}
+
+	if err := uc.courseRepo.SaveLessonQuiz(ctx, input.CourseID, input.LessonPath, quiz); err != nil {
+		return nil, err
+	}
+
+	return quiz, nil
+}
+
+// lessonForEditing finds the lesson an author is editing, checking they may edit the course
+func (uc *QuizUseCase) lessonForEditing(ctx context.Context, input ports.EditQuizInput) (*entities.Lesson, error) {
+	if input.UserID == "" {
+		return nil, entities.ErrInvalidUserID
+	}
+
+	course, err := uc.courseRepo.GetByID(ctx, input.CourseID)
+	if err != nil {
+		return nil, err
+	}
+	if !course.CanEditContent(input.UserID, uc.editors) {
+		return nil, entities.ErrUnauthorized
+	}
+
+	return course.LessonAt(input.LessonPath)
+}
+
+// bankQuestionsOf returns the questions a quiz pulls from banks
+func bankQuestionsOf(quiz *entities.ExtendedQuiz) []entities.ExtendedQuizQuestion {
+	var questions []entities.ExtendedQuizQuestion
+	for _, q := range quiz.Questions {
+		if q.BankID != "" {
+			questions = append(questions, q)
+		}
+	}
+	return questions
+}
+
+// copyLessonQuiz returns a copy of the lesson's quiz, or an empty quiz if it has none
+// The course may be shared with other requests, so edits are made to a copy
+func copyLessonQuiz(lesson *entities.Lesson) *entities.ExtendedQuiz {
+	effective := lesson.EffectiveQuiz()
+	if effective == nil {
+		return &entities.ExtendedQuiz{Version: entities.ExtendedQuizVersion}
+	}
+
+	quiz := *effective
+	quiz.Questions = slices.Clone(quiz.Questions)
+	if quiz.Version == "" {
+		quiz.Version = entities.ExtendedQuizVersion
+	}
+	return &quiz
+}
+
+// uniqueQuestionID returns id, or id with the first free numeric suffix when it is taken
+func uniqueQuestionID(id string, taken map[string]bool) string {
+	if id == "" {
+		id = "q"
+	}
+	if !taken[id] {
+		return id
+	}
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s-%d", id, n)
+		if !taken[candidate] {
+			return candidate
+		}
+	}
+}
+
+// loadLessonQuiz finds the extended quiz attached to a lesson of a course
+func loadLessonQuiz(ctx context.Context, courseRepo repositories.LibraryCourseRepository, courseID string, lessonPath []int) (*entities.ExtendedQuiz, error) {
+	course, err := courseRepo.GetByID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	lesson, err := course.LessonAt(lessonPath)
+	if err != nil {
+		return nil, err
+	}
+
+	quiz := lesson.EffectiveQuiz()
+	if quiz == nil || len(quiz.Questions) == 0 {
+		return nil, entities.ErrQuizNotFound
+	}
+
+	return quiz, nil
+}
@@ -0,0 +1,1419 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/project/backend/application/ports"
+	"github.com/project/backend/domain/entities"
+	"github.com/project/backend/domain/services"
+)
+
+// MockLibraryCourseRepository for testing
+type MockLibraryCourseRepository struct {
+	courses map[string]*entities.LibraryCourse
+}
+
+func NewMockLibraryCourseRepository(courses ...*entities.LibraryCourse) *MockLibraryCourseRepository {
+	m := &MockLibraryCourseRepository{courses: make(map[string]*entities.LibraryCourse)}
+	for _, c := range courses {
+		m.courses[c.ID] = c
+	}
+	return m
+}
+
+func (m *MockLibraryCourseRepository) Create(ctx context.Context, course *entities.LibraryCourse) (*entities.LibraryCourse, error) {
+	m.courses[course.ID] = course
+	return course, nil
+}
+
+func (m *MockLibraryCourseRepository) GetByID(ctx context.Context, id string) (*entities.LibraryCourse, error) {
+	if course, ok := m.courses[id]; ok {
+		return course, nil
+	}
+	return nil, entities.ErrCourseNotFound
+}
+
+func (m *MockLibraryCourseRepository) Update(ctx context.Context, course *entities.LibraryCourse) (*entities.LibraryCourse, error) {
+	m.courses[course.ID] = course
+	return course, nil
+}
+
+func (m *MockLibraryCourseRepository) Delete(ctx context.Context, id string) error {
+	delete(m.courses, id)
+	return nil
+}
+
+func (m *MockLibraryCourseRepository) List(ctx context.Context, limit, offset int) ([]*entities.LibraryCourse, int, error) {
+	var courses []*entities.LibraryCourse
+	for _, c := range m.courses {
+		courses = append(courses, c)
+	}
+	return courses, len(courses), nil
+}
+
+func (m *MockLibraryCourseRepository) ListByDifficulty(ctx context.Context, difficulty entities.Difficulty, limit, offset int) ([]*entities.LibraryCourse, int, error) {
+	return m.List(ctx, limit, offset)
+}
+
+func (m *MockLibraryCourseRepository) Search(ctx context.Context, query string, limit, offset int) ([]*entities.LibraryCourse, int, error) {
+	return m.List(ctx, limit, offset)
+}
+
+func (m *MockLibraryCourseRepository) GetByAuthorID(ctx context.Context, authorID string, limit, offset int) ([]*entities.LibraryCourse, int, error) {
+	return m.List(ctx, limit, offset)
+}
+
+func (m *MockLibraryCourseRepository) GetByTag(ctx context.Context, tag string, limit, offset int) ([]*entities.LibraryCourse, int, error) {
+	return m.List(ctx, limit, offset)
+}
+
+func (m *MockLibraryCourseRepository) GetAllTags(ctx context.Context) ([]string, error) {
+	return []string{}, nil
+}
+
+func (m *MockLibraryCourseRepository) SaveLessonQuiz(ctx context.Context, courseID string, lessonPath []int, quiz *entities.ExtendedQuiz) error {
+	course, err := m.GetByID(ctx, courseID)
+	if err != nil {
+		return err
+	}
+	lesson, err := course.LessonAt(lessonPath)
+	if err != nil {
+		return err
+	}
+	lesson.ExtendedQuiz = quiz
+	return nil
+}
+
+// MockQuizRepository for testing
+type MockQuizRepository struct {
+	attempts    []*entities.QuizAttempt
+	responses   []*entities.QuizResponse
+	reviewItems map[string]entities.ReviewQueueItem
+	instances   map[string]*entities.QuizInstance
+	sessions    []*entities.QuizSession
+	drafts      []*entities.QuizDraft
+}
+
+func NewMockQuizRepository() *MockQuizRepository {
+	return &MockQuizRepository{
+		reviewItems: make(map[string]entities.ReviewQueueItem),
+		instances:   make(map[string]*entities.QuizInstance),
+	}
+}
+
+func reviewItemKey(userID, courseID, questionID string) string {
+	return userID + "/" + courseID + "/" + questionID
+}
+
+func (m *MockQuizRepository) SaveAttempt(ctx context.Context, attempt *entities.QuizAttempt) (*entities.QuizAttempt, error) {
+	attempt.ID = fmt.Sprintf("test-attempt-%d", len(m.attempts)+1)
+	m.attempts = append(m.attempts, attempt)
+	return attempt, nil
+}
+
+func (m *MockQuizRepository) SaveResponse(ctx context.Context, response *entities.QuizResponse) (*entities.QuizResponse, error) {
+	m.responses = append(m.responses, response)
+	return response, nil
+}
+
+func (m *MockQuizRepository) SaveGradedAttempt(ctx context.Context, sessionID string, submittedAt time.Time, attempt *entities.QuizAttempt, responses []entities.QuizResponse) (*entities.QuizAttempt, error) {
+	if sessionID != "" {
+		if err := m.CloseQuizSession(ctx, sessionID, submittedAt); err != nil {
+			return nil, err
+		}
+	}
+	saved, _ := m.SaveAttempt(ctx, attempt)
+	for i := range responses {
+		responses[i].AttemptID = saved.ID
+		response := responses[i]
+		m.responses = append(m.responses, &response)
+	}
+	return saved, nil
+}
+
+func (m *MockQuizRepository) GetAttempt(ctx context.Context, id string) (*entities.QuizAttempt, error) {
+	for _, a := range m.attempts {
+		if a.ID == id {
+			return a, nil
+		}
+	}
+	return nil, entities.ErrQuizAttemptNotFound
+}
+
+func (m *MockQuizRepository) GetAttemptsByQuiz(ctx context.Context, userID, courseID, quizID string) ([]entities.QuizAttempt, error) {
+	var result []entities.QuizAttempt
+	for i := len(m.attempts) - 1; i >= 0; i-- {
+		a := m.attempts[i]
+		if a.UserID == userID && a.CourseID == courseID && a.QuizID == quizID {
+			result = append(result, *a)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockQuizRepository) GetQuizStats(ctx context.Context, userID, courseID, quizID string) (*entities.QuizStats, error) {
+	return &entities.QuizStats{QuizID: quizID}, nil
+}
+
+func (m *MockQuizRepository) GetCourseQuizSummary(ctx context.Context, userID, courseID string) (*entities.CourseQuizSummary, error) {
+	return &entities.CourseQuizSummary{CourseID: courseID}, nil
+}
+
+func (m *MockQuizRepository) GetConceptStrengths(ctx context.Context, userID, courseID string) ([]entities.ConceptStrength, error) {
+	return []entities.ConceptStrength{}, nil
+}
+
+func (m *MockQuizRepository) GetCalibration(ctx context.Context, userID, courseID string) (*entities.CalibrationReport, error) {
+	return entities.ComputeCalibration(courseID, nil), nil
+}
+
+func (m *MockQuizRepository) GetDashboardQuizStats(ctx context.Context, userID string, fromDate, toDate *time.Time) (*entities.DashboardQuizStats, error) {
+	return &entities.DashboardQuizStats{}, nil
+}
+
+func (m *MockQuizRepository) AddToReviewQueue(ctx context.Context, item *entities.ReviewQueueItem) error {
+	if item.ID == "" {
+		item.ID = "review-" + item.QuestionID
+	}
+	m.reviewItems[reviewItemKey(item.UserID, item.CourseID, item.QuestionID)] = *item
+	return nil
+}
+
+func (m *MockQuizRepository) GetDueReviewItems(ctx context.Context, userID string, courseIDs []string) ([]entities.ReviewQueueItem, error) {
+	now := time.Now()
+	items := []entities.ReviewQueueItem{}
+	for _, courseID := range courseIDs {
+		for _, item := range m.reviewItems {
+			if item.UserID == userID && item.CourseID == courseID && !item.NextReview.After(now) {
+				items = append(items, item)
+			}
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Priority != items[j].Priority {
+			return items[i].Priority > items[j].Priority
+		}
+		return items[i].NextReview.Before(items[j].NextReview)
+	})
+	return items, nil
+}
+
+func (m *MockQuizRepository) CountReviewedSince(ctx context.Context, userID string, since time.Time) (int, error) {
+	count := 0
+	for _, item := range m.reviewItems {
+		if item.UserID == userID && item.LastReviewed != nil && !item.LastReviewed.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MockQuizRepository) GetReviewItem(ctx context.Context, userID, courseID, questionID string) (*entities.ReviewQueueItem, error) {
+	item, ok := m.reviewItems[reviewItemKey(userID, courseID, questionID)]
+	if !ok {
+		// Wrapped, as repositories may wrap the errors they return
+		return nil, fmt.Errorf("%w: %s", entities.ErrReviewItemNotFound, questionID)
+	}
+	return &item, nil
+}
+
+func (m *MockQuizRepository) GetReviewQueue(ctx context.Context, userID, courseID string, limit int) ([]entities.ReviewQueueItem, error) {
+	return nil, nil
+}
+
+func (m *MockQuizRepository) RemoveFromReviewQueue(ctx context.Context, userID, courseID, questionID string) error {
+	delete(m.reviewItems, reviewItemKey(userID, courseID, questionID))
+	return nil
+}
+
+func (m *MockQuizRepository) GetResponsesByAttempt(ctx context.Context, attemptID string) ([]entities.QuizResponse, error) {
+	var result []entities.QuizResponse
+	for _, r := range m.responses {
+		if r.AttemptID == attemptID {
+			result = append(result, *r)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockQuizRepository) GetAnsweredQuestions(ctx context.Context, userID, courseID string) (map[string][]string, error) {
+	answered := make(map[string][]string)
+	for _, a := range m.attempts {
+		if a.UserID != userID || a.CourseID != courseID {
+			continue
+		}
+		for _, r := range m.responses {
+			if r.AttemptID == a.ID && string(r.UserAnswer) != "null" {
+				answered[a.QuizID] = append(answered[a.QuizID], r.QuestionID)
+			}
+		}
+	}
+	return answered, nil
+}
+
+func (m *MockQuizRepository) GetItemResponses(ctx context.Context, courseID, quizID string) ([]entities.ItemResponse, error) {
+	var result []entities.ItemResponse
+	for _, a := range m.attempts {
+		if a.CourseID != courseID || a.QuizID != quizID {
+			continue
+		}
+		for _, r := range m.responses {
+			if r.AttemptID == a.ID {
+				result = append(result, entities.ItemResponse{QuizResponse: *r, CourseID: a.CourseID, AttemptPercentage: a.Percentage})
+			}
+		}
+	}
+	return result, nil
+}
+
+func (m *MockQuizRepository) GetQuestionResponses(ctx context.Context, questionIDs []string) ([]entities.ItemResponse, error) {
+	var result []entities.ItemResponse
+	for _, a := range m.attempts {
+		for _, r := range m.responses {
+			if r.AttemptID == a.ID && slices.Contains(questionIDs, r.QuestionID) {
+				result = append(result, entities.ItemResponse{QuizResponse: *r, CourseID: a.CourseID, AttemptPercentage: a.Percentage})
+			}
+		}
+	}
+	return result, nil
+}
+
+func (m *MockQuizRepository) SaveQuizInstance(ctx context.Context, instance *entities.QuizInstance) (*entities.QuizInstance, error) {
+	instance.ID = fmt.Sprintf("instance-%d", len(m.instances)+1)
+	stored := *instance
+	m.instances[instance.ID] = &stored
+	return instance, nil
+}
+
+func (m *MockQuizRepository) SaveQuizSession(ctx context.Context, session *entities.QuizSession) (*entities.QuizSession, error) {
+	session.ID = fmt.Sprintf("session-%d", len(m.sessions)+1)
+	stored := *session
+	m.sessions = append(m.sessions, &stored)
+	return session, nil
+}
+
+func (m *MockQuizRepository) GetQuizSession(ctx context.Context, id string) (*entities.QuizSession, error) {
+	for _, s := range m.sessions {
+		if s.ID == id {
+			stored := *s
+			return &stored, nil
+		}
+	}
+	return nil, entities.ErrQuizSessionNotFound
+}
+
+func (m *MockQuizRepository) GetQuizSessions(ctx context.Context, userID, courseID, quizID string) ([]entities.QuizSession, error) {
+	var result []entities.QuizSession
+	for i := len(m.sessions) - 1; i >= 0; i-- {
+		s := m.sessions[i]
+		if s.UserID == userID && s.CourseID == courseID && s.QuizID == quizID {
+			result = append(result, *s)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockQuizRepository) CloseQuizSession(ctx context.Context, id string, submittedAt time.Time) error {
+	for _, s := range m.sessions {
+		if s.ID == id {
+			if s.SubmittedAt != nil {
+				return entities.ErrQuizSessionClosed
+			}
+			s.SubmittedAt = &submittedAt
+			return nil
+		}
+	}
+	return entities.ErrQuizSessionNotFound
+}
+
+func (m *MockQuizRepository) SaveQuizDraft(ctx context.Context, draft *entities.QuizDraft) (*entities.QuizDraft, error) {
+	draft.ID = fmt.Sprintf("draft-%d", len(m.drafts)+1)
+	stored := *draft
+	stored.Answers = slices.Clone(draft.Answers)
+	m.drafts = append(m.drafts, &stored)
+	return draft, nil
+}
+
+func (m *MockQuizRepository) GetQuizDraft(ctx context.Context, id string) (*entities.QuizDraft, error) {
+	for _, d := range m.drafts {
+		if d.ID == id {
+			stored := *d
+			stored.Answers = slices.Clone(d.Answers)
+			return &stored, nil
+		}
+	}
+	return nil, entities.ErrQuizDraftNotFound
+}
+
+func (m *MockQuizRepository) GetOpenQuizDrafts(ctx context.Context, userID string, now time.Time) ([]entities.QuizDraft, error) {
+	var result []entities.QuizDraft
+	for i := len(m.drafts) - 1; i >= 0; i-- {
+		d := m.drafts[i]
+		if d.UserID == userID && !d.IsExpired(now) {
+			stored := *d
+			stored.Answers = slices.Clone(d.Answers)
+			result = append(result, stored)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockQuizRepository) SaveQuizDraftAnswer(ctx context.Context, draft *entities.QuizDraft, answer entities.QuizAnswer) error {
+	for _, d := range m.drafts {
+		if d.ID == draft.ID {
+			d.SetAnswer(answer)
+			d.UpdatedAt = draft.UpdatedAt
+			d.ExpiresAt = draft.ExpiresAt
+			return nil
+		}
+	}
+	return entities.ErrQuizDraftNotFound
+}
+
+func (m *MockQuizRepository) DeleteQuizDraft(ctx context.Context, id string) error {
+	m.drafts = slices.DeleteFunc(m.drafts, func(d *entities.QuizDraft) bool { return d.ID == id })
+	return nil
+}
+
+func (m *MockQuizRepository) DeleteExpiredQuizDrafts(ctx context.Context, now time.Time) (int, error) {
+	before := len(m.drafts)
+	m.drafts = slices.DeleteFunc(m.drafts, func(d *entities.QuizDraft) bool { return d.IsExpired(now) })
+	return before - len(m.drafts), nil
+}
+
+func (m *MockQuizRepository) GetQuizInstance(ctx context.Context, id string) (*entities.QuizInstance, error) {
+	instance, ok := m.instances[id]
+	if !ok {
+		return nil, entities.ErrQuizInstanceNotFound
+	}
+	stored := *instance
+	return &stored, nil
+}
+
+func newQuizTestCourse() *entities.LibraryCourse {
+	return &entities.LibraryCourse{
+		ID:    "course-1",
+		Title: "Go Basics",
+		Lessons: []entities.Lesson{
+			{Title: "Chapter 1", Content: "Intro", Sublessons: []entities.Lesson{
+				{Title: "Section 1.1", Content: "Content", ExtendedQuiz: &entities.ExtendedQuiz{
+					Version: "1.0",
+					Questions: []entities.ExtendedQuizQuestion{
+						{ID: "q1", Type: entities.QuestionTypeMultipleChoice, Difficulty: 2, Options: []string{"a", "b"}, CorrectIndex: 1},
+						{ID: "q2", Type: entities.QuestionTypeMultipleChoice, Difficulty: 3, Options: []string{"a", "b"}, CorrectIndex: 0},
+					},
+				}},
+			}},
+		},
+	}
+}
+
+func TestQuizUseCase_SubmitAttempt_GradesServerSide(t *testing.T) {
+	quizRepo := NewMockQuizRepository()
+	useCase := NewQuizUseCase(NewMockLibraryCourseRepository(newQuizTestCourse()), &MockUserCourseRepository{}, quizRepo, services.NewQuizGrader(nil), services.NewQuizAssembler(), services.NewReviewScheduler(), nil)
+
+	attempt, err := useCase.SubmitAttempt(context.Background(), ports.SubmitQuizAttemptInput{
+		UserID:     "user-1",
+		CourseID:   "course-1",
+		LessonPath: []int{0, 0},
+		Answers: []entities.QuizAnswer{
+			{QuestionID: "q1", Answer: json.RawMessage(`1`)},
+			{QuestionID: "q2", Answer: json.RawMessage(`1`)},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SubmitAttempt failed: %v", err)
+	}
+
+	if attempt.Score != 2 || attempt.MaxScore != 5 {
+		t.Errorf("expected score 2/5, got %d/%d", attempt.Score, attempt.MaxScore)
+	}
+	if attempt.CorrectCount != 1 || attempt.TotalQuestions != 2 {
+		t.Errorf("expected 1 of 2 correct, got %d of %d", attempt.CorrectCount, attempt.TotalQuestions)
+	}
+	if attempt.QuizID != "lesson-00-sub-00" || attempt.QuizType != "subchapter" {
+		t.Errorf("expected quiz lesson-00-sub-00 (subchapter), got %s (%s)", attempt.QuizID, attempt.QuizType)
+	}
+	if len(quizRepo.responses) != 2 {
+		t.Fatalf("expected 2 stored responses, got %d", len(quizRepo.responses))
+	}
+	for _, r := range quizRepo.responses {
+		if r.AttemptID != attempt.ID {
+			t.Errorf("expected response to reference attempt %s, got %s", attempt.ID, r.AttemptID)
+		}
+	}
+}
+
+func TestQuizUseCase_SubmitAttempt_QueuesConfidentlyWrongAnswers(t *testing.T) {
+	quizRepo := NewMockQuizRepository()
+	useCase := NewQuizUseCase(NewMockLibraryCourseRepository(newQuizTestCourse()), &MockUserCourseRepository{}, quizRepo, services.NewQuizGrader(nil), services.NewQuizAssembler(), services.NewReviewScheduler(), nil)
+
+	_, err := useCase.SubmitAttempt(context.Background(), ports.SubmitQuizAttemptInput{
+		UserID:     "user-1",
+		CourseID:   "course-1",
+		LessonPath: []int{0, 0},
+		Answers: []entities.QuizAnswer{
+			{QuestionID: "q1", Answer: json.RawMessage(`0`), Confidence: entities.ConfidenceLow},
+			{QuestionID: "q2", Answer: json.RawMessage(`1`), Confidence: entities.ConfidenceHigh},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SubmitAttempt failed: %v", err)
+	}
+
+	if len(quizRepo.reviewItems) != 1 {
+		t.Fatalf("expected only the confidently wrong answer to be queued, got %d items", len(quizRepo.reviewItems))
+	}
+	item, ok := quizRepo.reviewItems[reviewItemKey("user-1", "course-1", "q2")]
+	if !ok {
+		t.Fatal("expected q2 to be queued for review")
+	}
+	if item.Priority != entities.ReviewPriorityHigh || item.QuizID != "lesson-00-sub-00" {
+		t.Errorf("expected a high priority item for lesson-00-sub-00, got %+v", item)
+	}
+}
+
+func TestQuizUseCase_SubmitAttempt_NoQuiz(t *testing.T) {
+	useCase := NewQuizUseCase(NewMockLibraryCourseRepository(newQuizTestCourse()), &MockUserCourseRepository{}, NewMockQuizRepository(), services.NewQuizGrader(nil), services.NewQuizAssembler(), services.NewReviewScheduler(), nil)
+
+	_, err := useCase.SubmitAttempt(context.Background(), ports.SubmitQuizAttemptInput{
+		UserID:     "user-1",
+		CourseID:   "course-1",
+		LessonPath: []int{0},
+	})
+	if err != entities.ErrQuizNotFound {
+		t.Errorf("expected ErrQuizNotFound, got %v", err)
+	}
+}
+
+func TestQuizUseCase_RevealQuestion(t *testing.T) {
+	course := newQuizTestCourse()
+	course.AuthorID = "author-1"
+	quizRepo := NewMockQuizRepository()
+	useCase := NewQuizUseCase(NewMockLibraryCourseRepository(course), &MockUserCourseRepository{}, quizRepo, services.NewQuizGrader(nil), services.NewQuizAssembler(), services.NewReviewScheduler(), nil)
+	ctx := context.Background()
+
+	if _, err := useCase.RevealQuestion(ctx, "user-1", "course-1", []int{0, 0}, "q1"); err != entities.ErrAnswerKeyHidden {
+		t.Fatalf("expected ErrAnswerKeyHidden before answering, got %v", err)
+	}
+
+	if _, err := useCase.RevealQuestion(ctx, "author-1", "course-1", []int{0, 0}, "q1"); err != nil {
+		t.Fatalf("expected author to see the answer key, got %v", err)
+	}
+
+	_, err := useCase.SubmitAttempt(ctx, ports.SubmitQuizAttemptInput{
+		UserID:     "user-1",
+		CourseID:   "course-1",
+		LessonPath: []int{0, 0},
+		Answers:    []entities.QuizAnswer{{QuestionID: "q1", Answer: json.RawMessage(`0`)}},
+	})
+	if err != nil {
+		t.Fatalf("SubmitAttempt failed: %v", err)
+	}
+
+	question, err := useCase.RevealQuestion(ctx, "user-1", "course-1", []int{0, 0}, "q1")
+	if err != nil {
+		t.Fatalf("expected answered question to be revealed, got %v", err)
+	}
+	if question.CorrectIndex != 1 {
+		t.Errorf("expected correct index 1, got %d", question.CorrectIndex)
+	}
+
+	if _, err := useCase.RevealQuestion(ctx, "user-1", "course-1", []int{0, 0}, "q2"); err != entities.ErrAnswerKeyHidden {
+		t.Errorf("expected unanswered question to stay hidden, got %v", err)
+	}
+
+	lessons, err := useCase.VisibleLessons(ctx, "user-1", course)
+	if err != nil {
+		t.Fatalf("VisibleLessons failed: %v", err)
+	}
+	questions := lessons[0].Sublessons[0].ExtendedQuiz.Questions
+	if questions[0].AnswerKeyHidden || !questions[1].AnswerKeyHidden {
+		t.Error("expected only the answered question to keep its answer key")
+	}
+	if course.Lessons[0].Sublessons[0].ExtendedQuiz.Questions[1].AnswerKeyHidden {
+		t.Error("expected redaction not to modify the stored course")
+	}
+}
+
+func TestQuizUseCase_LegacyQuiz(t *testing.T) {
+	// Legacy quizzes are graded and revealed as multiple choice questions
+	course := newQuizTestCourse()
+	course.Lessons[0].Sublessons[0].ExtendedQuiz = nil
+	course.Lessons[0].Sublessons[0].Quiz = &entities.Quiz{Questions: []entities.QuizQuestion{
+		{ID: "q1", Question: "Pick", Options: []string{"a", "b"}, CorrectIndex: 1, Explanation: "b"},
+		{ID: "q2", Question: "Pick", Options: []string{"a", "b"}, CorrectIndex: 0},
+	}}
+	useCase := NewQuizUseCase(NewMockLibraryCourseRepository(course), &MockUserCourseRepository{}, NewMockQuizRepository(), services.NewQuizGrader(nil), services.NewQuizAssembler(), services.NewReviewScheduler(), nil)
+	ctx := context.Background()
+
+	if _, err := useCase.RevealQuestion(ctx, "user-1", "course-1", []int{0, 0}, "q1"); err != entities.ErrAnswerKeyHidden {
+		t.Fatalf("expected ErrAnswerKeyHidden before answering, got %v", err)
+	}
+
+	attempt, err := useCase.SubmitAttempt(ctx, ports.SubmitQuizAttemptInput{
+		UserID:     "user-1",
+		CourseID:   "course-1",
+		LessonPath: []int{0, 0},
+		Answers:    []entities.QuizAnswer{{QuestionID: "q1", Answer: json.RawMessage(`1`)}},
+	})
+	if err != nil {
+		t.Fatalf("SubmitAttempt failed: %v", err)
+	}
+	if attempt.CorrectCount != 1 || attempt.TotalQuestions != 2 {
+		t.Errorf("expected 1 of 2 correct, got %d of %d", attempt.CorrectCount, attempt.TotalQuestions)
+	}
+
+	question, err := useCase.RevealQuestion(ctx, "user-1", "course-1", []int{0, 0}, "q1")
+	if err != nil {
+		t.Fatalf("expected answered question to be revealed, got %v", err)
+	}
+	if question.CorrectIndex != 1 || question.Explanation != "b" {
+		t.Errorf("expected the answer key of q1, got %+v", question)
+	}
+
+	lessons, err := useCase.VisibleLessons(ctx, "user-1", course)
+	if err != nil {
+		t.Fatalf("VisibleLessons failed: %v", err)
+	}
+	questions := lessons[0].Sublessons[0].Quiz.Questions
+	if questions[0].AnswerKeyHidden || !questions[1].AnswerKeyHidden {
+		t.Error("expected only the answered question to keep its answer key")
+	}
+}
+
+func newTestOutCourse() *entities.LibraryCourse {
+	return &entities.LibraryCourse{
+		ID:    "course-1",
+		Title: "Go Basics",
+		Lessons: []entities.Lesson{
+			{Title: "Chapter 1", Content: "Intro", ExtendedQuiz: &entities.ExtendedQuiz{
+				Version: "1.0",
+				Questions: []entities.ExtendedQuizQuestion{
+					{ID: "q1", Type: entities.QuestionTypeMultipleChoice, Difficulty: 2, Options: []string{"a", "b"}, CorrectIndex: 1},
+					{ID: "q2", Type: entities.QuestionTypeMultipleChoice, Difficulty: 3, Options: []string{"a", "b"}, CorrectIndex: 0},
+				},
+			}, Sublessons: []entities.Lesson{
+				{Title: "Section 1.1", Content: "Content"},
+				{Title: "Section 1.2", Content: "Content"},
+			}},
+			{Title: "Chapter 2", Content: "More"},
+		},
+	}
+}
+
+func TestQuizUseCase_TestOut(t *testing.T) {
+	// Answering only q2 scores 3 of 5 points (60%)
+	answers := []entities.QuizAnswer{
+		{QuestionID: "q1", Answer: json.RawMessage(`0`)},
+		{QuestionID: "q2", Answer: json.RawMessage(`0`)},
+	}
+
+	t.Run("below the default threshold", func(t *testing.T) {
+		userCourseRepo := &MockUserCourseRepository{}
+		useCase := NewQuizUseCase(NewMockLibraryCourseRepository(newTestOutCourse()), userCourseRepo, NewMockQuizRepository(), services.NewQuizGrader(nil), services.NewQuizAssembler(), services.NewReviewScheduler(), nil)
+
+		result, err := useCase.TestOut(context.Background(), ports.TestOutInput{UserID: "user-1", CourseID: "course-1", Chapter: 0, Answers: answers})
+		if err != nil {
+			t.Fatalf("TestOut failed: %v", err)
+		}
+		if result.Passed {
+			t.Error("expected 60% to fail the default threshold")
+		}
+		if result.Threshold != entities.DefaultQuizConfig().TestOutThreshold {
+			t.Errorf("expected default threshold, got %d", result.Threshold)
+		}
+		if len(userCourseRepo.userCourses) != 0 {
+			t.Errorf("expected no enrollment after a failed test-out, got %d", len(userCourseRepo.userCourses))
+		}
+	})
+
+	t.Run("meets a course override", func(t *testing.T) {
+		course := newTestOutCourse()
+		config := entities.DefaultQuizConfig()
+		config.TestOutThreshold = 60
+		course.QuizConfig = &config
+
+		userCourse := &entities.UserCourse{UserID: "user-1", LibraryCourseID: "course-1", CompletedLessons: []int{1}}
+		userCourseRepo := &MockUserCourseRepository{userCourses: []*entities.UserCourse{userCourse}}
+		useCase := NewQuizUseCase(NewMockLibraryCourseRepository(course), userCourseRepo, NewMockQuizRepository(), services.NewQuizGrader(nil), services.NewQuizAssembler(), services.NewReviewScheduler(), nil)
+
+		result, err := useCase.TestOut(context.Background(), ports.TestOutInput{UserID: "user-1", CourseID: "course-1", Chapter: 0, Answers: answers})
+		if err != nil {
+			t.Fatalf("TestOut failed: %v", err)
+		}
+		if !result.Passed {
+			t.Fatal("expected 60% to meet a threshold of 60")
+		}
+
+		want := []int{0, 2}
+		if len(result.CompletedLessons) != len(want) {
+			t.Fatalf("expected newly completed lessons %v, got %v", want, result.CompletedLessons)
+		}
+		for i, index := range want {
+			if result.CompletedLessons[i] != index {
+				t.Errorf("expected newly completed lessons %v, got %v", want, result.CompletedLessons)
+			}
+			if userCourse.CompletionReasons[index] != entities.CompletionReasonTestedOut {
+				t.Errorf("expected lesson %d to be tested out, got %q", index, userCourse.CompletionReasons[index])
+			}
+		}
+		if _, ok := userCourse.CompletionReasons[1]; ok {
+			t.Error("expected a lesson studied before the test-out to keep no reason")
+		}
+		if userCourse.Progress != 75 {
+			t.Errorf("expected progress 75, got %d", userCourse.Progress)
+		}
+	})
+
+	t.Run("enrolls the learner when needed", func(t *testing.T) {
+		course := newTestOutCourse()
+		config := entities.DefaultQuizConfig()
+		config.TestOutThreshold = 50
+		course.QuizConfig = &config
+
+		userCourseRepo := &MockUserCourseRepository{}
+		useCase := NewQuizUseCase(NewMockLibraryCourseRepository(course), userCourseRepo, NewMockQuizRepository(), services.NewQuizGrader(nil), services.NewQuizAssembler(), services.NewReviewScheduler(), nil)
+
+		result, err := useCase.TestOut(context.Background(), ports.TestOutInput{UserID: "user-1", CourseID: "course-1", Chapter: 0, Answers: answers})
+		if err != nil {
+			t.Fatalf("TestOut failed: %v", err)
+		}
+		if len(userCourseRepo.userCourses) != 1 {
+			t.Fatalf("expected the learner to be enrolled, got %d user courses", len(userCourseRepo.userCourses))
+		}
+		if len(result.UserCourse.CompletedLessons) != 3 {
+			t.Errorf("expected 3 completed lessons, got %v", result.UserCourse.CompletedLessons)
+		}
+	})
+
+	t.Run("unknown chapter", func(t *testing.T) {
+		useCase := NewQuizUseCase(NewMockLibraryCourseRepository(newTestOutCourse()), &MockUserCourseRepository{}, NewMockQuizRepository(), services.NewQuizGrader(nil), services.NewQuizAssembler(), services.NewReviewScheduler(), nil)
+
+		_, err := useCase.TestOut(context.Background(), ports.TestOutInput{UserID: "user-1", CourseID: "course-1", Chapter: 5, Answers: answers})
+		if err != entities.ErrInvalidLessonIndex {
+			t.Errorf("expected ErrInvalidLessonIndex, got %v", err)
+		}
+	})
+}
+
+func newGeneratedQuizTestCourse() *entities.LibraryCourse {
+	var questions []entities.ExtendedQuizQuestion
+	for i := 0; i < 8; i++ {
+		questions = append(questions, entities.ExtendedQuizQuestion{
+			ID:           fmt.Sprintf("q%d", i),
+			Type:         entities.QuestionTypeMultipleChoice,
+			Difficulty:   i%5 + 1,
+			Options:      []string{"right", "wrong 1", "wrong 2", "wrong 3"},
+			CorrectIndex: 0,
+		})
+	}
+
+	config := entities.DefaultQuizConfig()
+	config.QuestionsPerSubchapter = 4
+
+	return &entities.LibraryCourse{
+		ID:         "course-1",
+		Title:      "Go Basics",
+		QuizConfig: &config,
+		Lessons: []entities.Lesson{
+			{Title: "Chapter 1", Content: "Intro", Sublessons: []entities.Lesson{
+				{Title: "Section 1.1", Content: "Content", ExtendedQuiz: &entities.ExtendedQuiz{Questions: questions[:6]}},
+				{Title: "Section 1.2", Content: "Content", ExtendedQuiz: &entities.ExtendedQuiz{Questions: questions[6:]}},
+			}},
+		},
+	}
+}
+
+func TestQuizUseCase_GenerateQuiz(t *testing.T) {
+	quizRepo := NewMockQuizRepository()
+	useCase := NewQuizUseCase(NewMockLibraryCourseRepository(newGeneratedQuizTestCourse()), &MockUserCourseRepository{}, quizRepo, services.NewQuizGrader(nil), services.NewQuizAssembler(), services.NewReviewScheduler(), nil)
+	ctx := context.Background()
+
+	instance, err := useCase.GenerateQuiz(ctx, ports.GenerateQuizInput{UserID: "user-1", CourseID: "course-1", LessonPath: []int{0, 0}})
+	if err != nil {
+		t.Fatalf("GenerateQuiz failed: %v", err)
+	}
+
+	if len(instance.Questions) != 4 {
+		t.Fatalf("expected QuestionsPerSubchapter questions, got %d", len(instance.Questions))
+	}
+	if instance.QuizID != "lesson-00-sub-00" {
+		t.Errorf("expected quiz lesson-00-sub-00, got %s", instance.QuizID)
+	}
+	if _, ok := quizRepo.instances[instance.ID]; !ok {
+		t.Error("expected the instance to be stored")
+	}
+
+	// Answer by option text, since the options arrive shuffled and without an answer key
+	answers := make([]entities.QuizAnswer, len(instance.Questions))
+	for i, q := range instance.Questions {
+		if !q.AnswerKeyHidden {
+			t.Errorf("expected question %s to be redacted", q.ID)
+		}
+		for j, option := range q.Options {
+			if option == "right" {
+				answers[i] = entities.QuizAnswer{QuestionID: q.ID, Answer: json.RawMessage(fmt.Sprint(j))}
+			}
+		}
+	}
+
+	attempt, err := useCase.SubmitAttempt(ctx, ports.SubmitQuizAttemptInput{
+		UserID:     "user-1",
+		CourseID:   "course-1",
+		LessonPath: []int{0, 0},
+		InstanceID: instance.ID,
+		Answers:    answers,
+	})
+	if err != nil {
+		t.Fatalf("SubmitAttempt failed: %v", err)
+	}
+	if attempt.TotalQuestions != 4 || attempt.CorrectCount != 4 {
+		t.Errorf("expected 4 of 4 correct on the issued instance, got %d of %d", attempt.CorrectCount, attempt.TotalQuestions)
+	}
+	for _, r := range quizRepo.responses {
+		if string(r.UserAnswer) != "0" {
+			t.Errorf("expected answers stored in the original option order, got %s for %s", r.UserAnswer, r.QuestionID)
+		}
+	}
+
+	_, err = useCase.SubmitAttempt(ctx, ports.SubmitQuizAttemptInput{
+		UserID:     "user-2",
+		CourseID:   "course-1",
+		LessonPath: []int{0, 0},
+		InstanceID: instance.ID,
+		Answers:    answers,
+	})
+	if err != entities.ErrQuizInstanceNotFound {
+		t.Errorf("expected ErrQuizInstanceNotFound for another user, got %v", err)
+	}
+}
+
+func TestQuizUseCase_GenerateQuiz_IncludeSublessons(t *testing.T) {
+	useCase := NewQuizUseCase(NewMockLibraryCourseRepository(newGeneratedQuizTestCourse()), &MockUserCourseRepository{}, NewMockQuizRepository(), services.NewQuizGrader(nil), services.NewQuizAssembler(), services.NewReviewScheduler(), nil)
+	ctx := context.Background()
+
+	if _, err := useCase.GenerateQuiz(ctx, ports.GenerateQuizInput{UserID: "user-1", CourseID: "course-1", LessonPath: []int{0}}); err != entities.ErrQuizNotFound {
+		t.Errorf("expected ErrQuizNotFound for a chapter without its own questions, got %v", err)
+	}
+
+	instance, err := useCase.GenerateQuiz(ctx, ports.GenerateQuizInput{UserID: "user-1", CourseID: "course-1", LessonPath: []int{0}, IncludeSublessons: true})
+	if err != nil {
+		t.Fatalf("GenerateQuiz failed: %v", err)
+	}
+	// QuestionsPerChapter (10) exceeds the 8 questions in the sublessons, so all are drawn
+	if len(instance.Questions) != 8 {
+		t.Errorf("expected all 8 sublesson questions, got %d", len(instance.Questions))
+	}
+}
+
+func TestQuizUseCase_GenerateQuiz_CollidingSublessonQuestionIDs(t *testing.T) {
+	course := newGeneratedQuizTestCourse()
+	// Both sublessons number their questions from q0, with different answer keys
+	second := course.Lessons[0].Sublessons[1].ExtendedQuiz
+	for i := range second.Questions {
+		second.Questions[i].ID = fmt.Sprintf("q%d", i)
+		second.Questions[i].Options = []string{"wrong 1", "right", "wrong 2", "wrong 3"}
+		second.Questions[i].CorrectIndex = 1
+	}
+	quizRepo := NewMockQuizRepository()
+	useCase := NewQuizUseCase(NewMockLibraryCourseRepository(course), &MockUserCourseRepository{}, quizRepo, services.NewQuizGrader(nil), services.NewQuizAssembler(), services.NewReviewScheduler(), nil)
+	ctx := context.Background()
+
+	instance, err := useCase.GenerateQuiz(ctx, ports.GenerateQuizInput{UserID: "user-1", CourseID: "course-1", LessonPath: []int{0}, IncludeSublessons: true})
+	if err != nil {
+		t.Fatalf("GenerateQuiz failed: %v", err)
+	}
+	if len(instance.Questions) != 8 {
+		t.Fatalf("expected all 8 sublesson questions despite colliding IDs, got %d", len(instance.Questions))
+	}
+
+	answers := make([]entities.QuizAnswer, len(instance.Questions))
+	for i, q := range instance.Questions {
+		for j, option := range q.Options {
+			if option == "right" {
+				answers[i] = entities.QuizAnswer{QuestionID: q.ID, Answer: json.RawMessage(fmt.Sprint(j))}
+			}
+		}
+	}
+
+	attempt, err := useCase.SubmitAttempt(ctx, ports.SubmitQuizAttemptInput{
+		UserID:     "user-1",
+		CourseID:   "course-1",
+		LessonPath: []int{0},
+		InstanceID: instance.ID,
+		Answers:    answers,
+	})
+	if err != nil {
+		t.Fatalf("SubmitAttempt failed: %v", err)
+	}
+	if attempt.CorrectCount != 8 {
+		t.Errorf("expected each question graded against its own sublesson's key, got %d of 8 correct", attempt.CorrectCount)
+	}
+
+	reviews, err := useCase.ReviewAttempt(ctx, "user-1", attempt)
+	if err != nil {
+		t.Fatalf("ReviewAttempt failed: %v", err)
+	}
+	for _, review := range reviews {
+		if review.QuestionType == "" {
+			t.Errorf("expected response %s to be joined to its question", review.QuestionID)
+		}
+	}
+}
+
+func TestQuizUseCase_ItemAnalysis(t *testing.T) {
+	course := newQuizTestCourse()
+	course.AuthorID = "author-1"
+	quizRepo := NewMockQuizRepository()
+	useCase := NewQuizUseCase(NewMockLibraryCourseRepository(course), &MockUserCourseRepository{}, quizRepo, services.NewQuizGrader(nil), services.NewQuizAssembler(), services.NewReviewScheduler(), nil)
+	ctx := context.Background()
+
+	for _, answer := range []string{`1`, `1`, `0`} {
+		_, err := useCase.SubmitAttempt(ctx, ports.SubmitQuizAttemptInput{
+			UserID:     "user-1",
+			CourseID:   "course-1",
+			LessonPath: []int{0, 0},
+			Answers:    []entities.QuizAnswer{{QuestionID: "q1", Answer: json.RawMessage(answer)}},
+		})
+		if err != nil {
+			t.Fatalf("SubmitAttempt failed: %v", err)
+		}
+	}
+
+	if _, err := useCase.ItemAnalysis(ctx, "user-1", "course-1", "lesson-00-sub-00"); err != entities.ErrUnauthorized {
+		t.Errorf("expected ErrUnauthorized for a learner, got %v", err)
+	}
+
+	analysis, err := useCase.ItemAnalysis(ctx, "author-1", "course-1", "lesson-00-sub-00")
+	if err != nil {
+		t.Fatalf("ItemAnalysis failed: %v", err)
+	}
+	if analysis.Attempts != 3 || len(analysis.Items) != 2 {
+		t.Fatalf("expected 3 attempts over 2 questions, got %d over %d", analysis.Attempts, len(analysis.Items))
+	}
+
+	q1 := analysis.Items[0]
+	if q1.QuestionID != "q1" || q1.Options[1].Count != 2 || !q1.Options[1].IsKey {
+		t.Errorf("unexpected statistics for q1: %+v", q1)
+	}
+	if q2 := analysis.Items[1]; q2.Skipped != 3 || q2.DifficultyIndex != 0 {
+		t.Errorf("expected q2 to be skipped in every attempt, got %+v", q2)
+	}
+
+	if _, err := useCase.ItemAnalysis(ctx, "author-1", "course-1", "lesson-05"); err != entities.ErrInvalidLessonIndex {
+		t.Errorf("expected ErrInvalidLessonIndex for an unknown quiz, got %v", err)
+	}
+}
+
+func TestQuizUseCase_EditLessonQuiz(t *testing.T) {
+	course := newQuizTestCourse()
+	course.AuthorID = "author-1"
+	useCase := NewQuizUseCase(NewMockLibraryCourseRepository(course), &MockUserCourseRepository{}, NewMockQuizRepository(), services.NewQuizGrader(nil), services.NewQuizAssembler(), services.NewReviewScheduler(), nil)
+	ctx := context.Background()
+	target := ports.EditQuizInput{UserID: "author-1", CourseID: "course-1", LessonPath: []int{0, 0}}
+	question := entities.ExtendedQuizQuestion{ID: "q3", Type: entities.QuestionTypeShortAnswer, Question: "Keyword for a goroutine?", Difficulty: 1, AcceptedAnswers: []string{"go"}}
+
+	learner := target
+	learner.UserID = "user-1"
+	if _, err := useCase.AddQuizQuestion(ctx, learner, question, -1); err != entities.ErrUnauthorized {
+		t.Errorf("expected ErrUnauthorized for a learner, got %v", err)
+	}
+
+	invalid := question
+	invalid.AcceptedAnswers = nil
+	if _, err := useCase.AddQuizQuestion(ctx, target, invalid, -1); !errors.Is(err, entities.ErrInvalidQuestion) {
+		t.Errorf("expected ErrInvalidQuestion for a question without answers, got %v", err)
+	}
+
+	quiz, err := useCase.AddQuizQuestion(ctx, target, question, 0)
+	if err != nil {
+		t.Fatalf("AddQuizQuestion failed: %v", err)
+	}
+	if len(quiz.Questions) != 3 || quiz.Questions[0].ID != "q3" {
+		t.Fatalf("expected q3 to be inserted first, got %+v", quiz.Questions)
+	}
+	if _, err := useCase.AddQuizQuestion(ctx, target, question, -1); !errors.Is(err, entities.ErrInvalidQuestion) {
+		t.Errorf("expected ErrInvalidQuestion for a duplicate id, got %v", err)
+	}
+
+	question.Question = "Which keyword starts a goroutine?"
+	quiz, err = useCase.UpdateQuizQuestion(ctx, target, question)
+	if err != nil {
+		t.Fatalf("UpdateQuizQuestion failed: %v", err)
+	}
+	if quiz.Questions[0].Question != question.Question {
+		t.Errorf("expected the question text to be updated, got %q", quiz.Questions[0].Question)
+	}
+
+	if _, err := useCase.ReorderQuizQuestions(ctx, target, []string{"q1", "q2"}); !errors.Is(err, entities.ErrInvalidQuestion) {
+		t.Errorf("expected ErrInvalidQuestion when a question is left out, got %v", err)
+	}
+	quiz, err = useCase.ReorderQuizQuestions(ctx, target, []string{"q2", "q3", "q1"})
+	if err != nil {
+		t.Fatalf("ReorderQuizQuestions failed: %v", err)
+	}
+	if quiz.Questions[0].ID != "q2" || quiz.Questions[1].ID != "q3" || quiz.Questions[2].ID != "q1" {
+		t.Errorf("expected order q2, q3, q1, got %+v", quiz.Questions)
+	}
+
+	quiz, err = useCase.DeleteQuizQuestion(ctx, target, "q2")
+	if err != nil {
+		t.Fatalf("DeleteQuizQuestion failed: %v", err)
+	}
+	if len(quiz.Questions) != 2 {
+		t.Errorf("expected 2 questions after deleting, got %d", len(quiz.Questions))
+	}
+	if _, err := useCase.DeleteQuizQuestion(ctx, target, "q2"); err != entities.ErrQuestionNotFound {
+		t.Errorf("expected ErrQuestionNotFound, got %v", err)
+	}
+
+	stored, _ := course.LessonAt([]int{0, 0})
+	if len(stored.ExtendedQuiz.Questions) != 2 || stored.ExtendedQuiz.Questions[0].ID != "q3" {
+		t.Errorf("expected the edited quiz to be saved, got %+v", stored.ExtendedQuiz.Questions)
+	}
+}
+
+func TestQuizUseCase_EditLessonQuiz_FolderCourse(t *testing.T) {
+	course := newQuizTestCourse()
+	course.AuthorID = entities.FolderAuthorID
+	editors := entities.NewUserGroup([]string{"editor-1"})
+	useCase := NewQuizUseCase(NewMockLibraryCourseRepository(course), &MockUserCourseRepository{}, NewMockQuizRepository(), services.NewQuizGrader(nil), services.NewQuizAssembler(), services.NewReviewScheduler(), editors)
+	ctx := context.Background()
+	editor := ports.EditQuizInput{UserID: "editor-1", CourseID: "course-1", LessonPath: []int{0, 0}}
+	learner := ports.EditQuizInput{UserID: "user-1", CourseID: "course-1", LessonPath: []int{0, 0}}
+	answer := true
+	question := entities.ExtendedQuizQuestion{ID: "tf1", Type: entities.QuestionTypeTrueFalse, Question: "Slices are reference types", Difficulty: 2, CorrectAnswer: &answer}
+
+	// Folder courses have no author account, so signed-in learners are not editors
+	if _, err := useCase.AddQuizQuestion(ctx, learner, question, -1); !errors.Is(err, entities.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized for a learner adding a question, got %v", err)
+	}
+	if _, err := useCase.UpsertLessonQuiz(ctx, learner, entities.ExtendedQuiz{Questions: []entities.ExtendedQuizQuestion{question}}); !errors.Is(err, entities.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized for a learner replacing the quiz, got %v", err)
+	}
+	if _, err := useCase.DeleteQuizQuestion(ctx, learner, "q1"); !errors.Is(err, entities.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized for a learner deleting a question, got %v", err)
+	}
+	if stored, _ := course.LessonAt([]int{0, 0}); len(stored.ExtendedQuiz.Questions) != 2 {
+		t.Errorf("expected the quiz to be unchanged, got %+v", stored.ExtendedQuiz.Questions)
+	}
+
+	quiz, err := useCase.AddQuizQuestion(ctx, editor, question, -1)
+	if err != nil {
+		t.Fatalf("expected a configured editor to add the question, got %v", err)
+	}
+	if len(quiz.Questions) != 3 {
+		t.Errorf("expected 3 questions, got %+v", quiz.Questions)
+	}
+}
+
+func TestQuizUseCase_UpsertLessonQuiz(t *testing.T) {
+	course := newQuizTestCourse()
+	course.AuthorID = "author-1"
+	useCase := NewQuizUseCase(NewMockLibraryCourseRepository(course), &MockUserCourseRepository{}, NewMockQuizRepository(), services.NewQuizGrader(nil), services.NewQuizAssembler(), services.NewReviewScheduler(), nil)
+	ctx := context.Background()
+	target := ports.EditQuizInput{UserID: "author-1", CourseID: "course-1", LessonPath: []int{0}}
+	answer := true
+
+	quiz, err := useCase.UpsertLessonQuiz(ctx, target, entities.ExtendedQuiz{Questions: []entities.ExtendedQuizQuestion{
+		{ID: "tf1", Type: entities.QuestionTypeTrueFalse, Question: "Slices are reference types", Difficulty: 2, CorrectAnswer: &answer},
+	}})
+	if err != nil {
+		t.Fatalf("UpsertLessonQuiz failed: %v", err)
+	}
+	if quiz.Version != entities.ExtendedQuizVersion || len(quiz.Questions) != 1 {
+		t.Errorf("expected a version %s quiz with 1 question, got %+v", entities.ExtendedQuizVersion, quiz)
+	}
+	if course.Lessons[0].ExtendedQuiz == nil {
+		t.Error("expected the quiz to be saved on the chapter")
+	}
+
+	_, err = useCase.UpsertLessonQuiz(ctx, target, entities.ExtendedQuiz{Questions: []entities.ExtendedQuizQuestion{
+		{ID: "tf1", Type: entities.QuestionTypeTrueFalse, Question: "Slices are reference types", Difficulty: 2},
+	}})
+	if !errors.Is(err, entities.ErrInvalidQuestion) {
+		t.Errorf("expected ErrInvalidQuestion for a missing answer, got %v", err)
+	}
+}
+
+func TestQuizUseCase_BankQuestionsReadOnly(t *testing.T) {
+	course := newQuizTestCourse()
+	course.AuthorID = "author-1"
+	quiz := course.Lessons[0].Sublessons[0].ExtendedQuiz
+	quiz.BankRefs = []entities.QuestionBankRef{{Bank: "go-basics", Questions: []string{"b1"}}}
+	quiz.Questions = append(quiz.Questions, entities.ExtendedQuizQuestion{
+		ID: "go-basics.b1", BankID: "go-basics", Type: entities.QuestionTypeMultipleChoice, Difficulty: 2, Question: "Bank?", Options: []string{"a", "b"}, CorrectIndex: 0,
+	})
+	useCase := NewQuizUseCase(NewMockLibraryCourseRepository(course), &MockUserCourseRepository{}, NewMockQuizRepository(), services.NewQuizGrader(nil), services.NewQuizAssembler(), services.NewReviewScheduler(), nil)
+	ctx := context.Background()
+	target := ports.EditQuizInput{UserID: "author-1", CourseID: "course-1", LessonPath: []int{0, 0}}
+
+	if _, err := useCase.DeleteQuizQuestion(ctx, target, "go-basics.b1"); !errors.Is(err, entities.ErrBankQuestionReadOnly) {
+		t.Errorf("expected ErrBankQuestionReadOnly when deleting a bank question, got %v", err)
+	}
+	edited := quiz.Questions[2]
+	edited.Question = "Changed"
+	if _, err := useCase.UpdateQuizQuestion(ctx, target, edited); !errors.Is(err, entities.ErrBankQuestionReadOnly) {
+		t.Errorf("expected ErrBankQuestionReadOnly when updating a bank question, got %v", err)
+	}
+
+	reordered, err := useCase.ReorderQuizQuestions(ctx, target, []string{"go-basics.b1", "q2", "q1"})
+	if err != nil {
+		t.Fatalf("ReorderQuizQuestions failed: %v", err)
+	}
+	if ids := []string{reordered.Questions[0].ID, reordered.Questions[1].ID, reordered.Questions[2].ID}; !slices.Equal(ids, []string{"q2", "q1", "go-basics.b1"}) {
+		t.Errorf("expected own questions reordered ahead of the bank question, got %v", ids)
+	}
+
+	answer := true
+	replaced, err := useCase.UpsertLessonQuiz(ctx, target, entities.ExtendedQuiz{Questions: []entities.ExtendedQuizQuestion{
+		{ID: "tf1", Type: entities.QuestionTypeTrueFalse, Question: "Slices are reference types", Difficulty: 2, CorrectAnswer: &answer},
+	}})
+	if err != nil {
+		t.Fatalf("UpsertLessonQuiz failed: %v", err)
+	}
+	if len(replaced.Questions) != 2 || replaced.Questions[1].ID != "go-basics.b1" {
+		t.Errorf("expected the bank question kept after the new questions, got %+v", replaced.Questions)
+	}
+}
+
+func TestQuizUseCase_ImportQuizQuestions(t *testing.T) {
+	course := newQuizTestCourse()
+	course.AuthorID = "author-1"
+	useCase := NewQuizUseCase(NewMockLibraryCourseRepository(course), &MockUserCourseRepository{}, NewMockQuizRepository(), services.NewQuizGrader(nil), services.NewQuizAssembler(), services.NewReviewScheduler(), nil)
+	ctx := context.Background()
+	target := ports.EditQuizInput{UserID: "author-1", CourseID: "course-1", LessonPath: []int{0, 0}}
+	answer := false
+
+	result, err := useCase.ImportQuizQuestions(ctx, target, []entities.ExtendedQuizQuestion{
+		{ID: "q1", Type: entities.QuestionTypeTrueFalse, Question: "Maps are ordered", Difficulty: 1, CorrectAnswer: &answer},
+		{ID: "broken", Type: entities.QuestionTypeTrueFalse, Question: "No answer", Difficulty: 1},
+	})
+	if err != nil {
+		t.Fatalf("ImportQuizQuestions failed: %v", err)
+	}
+	if len(result.Quiz.Questions) != 3 || result.Quiz.Questions[2].ID != "q1-2" {
+		t.Errorf("expected the colliding ID to be renamed to q1-2, got %+v", result.Quiz.Questions)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0].Item != "broken" {
+		t.Errorf("expected the invalid question to be skipped, got %+v", result.Skipped)
+	}
+	if len(course.Lessons[0].Sublessons[0].ExtendedQuiz.Questions) != 3 {
+		t.Error("expected the imported question to be saved")
+	}
+
+	if _, err := useCase.ImportQuizQuestions(ctx, ports.EditQuizInput{UserID: "learner", CourseID: "course-1", LessonPath: []int{0, 0}}, nil); !errors.Is(err, entities.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized for a learner, got %v", err)
+	}
+}
+
+func TestQuizUseCase_GetLessonQuizForEditing(t *testing.T) {
+	course := newQuizTestCourse()
+	course.AuthorID = "author-1"
+	useCase := NewQuizUseCase(NewMockLibraryCourseRepository(course), &MockUserCourseRepository{}, NewMockQuizRepository(), services.NewQuizGrader(nil), services.NewQuizAssembler(), services.NewReviewScheduler(), nil)
+	ctx := context.Background()
+
+	quiz, err := useCase.GetLessonQuizForEditing(ctx, ports.EditQuizInput{UserID: "author-1", CourseID: "course-1", LessonPath: []int{0, 0}})
+	if err != nil {
+		t.Fatalf("GetLessonQuizForEditing failed: %v", err)
+	}
+	if len(quiz.Questions) != 2 || quiz.Questions[0].CorrectIndex != 1 {
+		t.Errorf("expected the quiz with its answer keys, got %+v", quiz)
+	}
+
+	if _, err := useCase.GetLessonQuizForEditing(ctx, ports.EditQuizInput{UserID: "author-1", CourseID: "course-1", LessonPath: []int{0}}); !errors.Is(err, entities.ErrQuizNotFound) {
+		t.Errorf("expected ErrQuizNotFound for a lesson without a quiz, got %v", err)
+	}
+}
+
+func TestQuizUseCase_GetLessonQuizForEditing_FolderCourse(t *testing.T) {
+	course := newQuizTestCourse()
+	course.AuthorID = entities.FolderAuthorID
+	editors := entities.NewUserGroup([]string{"editor-1"})
+	useCase := NewQuizUseCase(NewMockLibraryCourseRepository(course), &MockUserCourseRepository{}, NewMockQuizRepository(), services.NewQuizGrader(nil), services.NewQuizAssembler(), services.NewReviewScheduler(), editors)
+	ctx := context.Background()
+
+	// Exports carry every answer key, so learners may not read quizzes this way
+	if _, err := useCase.GetLessonQuizForEditing(ctx, ports.EditQuizInput{UserID: "user-1", CourseID: "course-1", LessonPath: []int{0, 0}}); !errors.Is(err, entities.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized for a learner, got %v", err)
+	}
+
+	quiz, err := useCase.GetLessonQuizForEditing(ctx, ports.EditQuizInput{UserID: "editor-1", CourseID: "course-1", LessonPath: []int{0, 0}})
+	if err != nil {
+		t.Fatalf("GetLessonQuizForEditing failed: %v", err)
+	}
+	if len(quiz.Questions) != 2 || quiz.Questions[0].CorrectIndex != 1 {
+		t.Errorf("expected the quiz with its answer keys, got %+v", quiz)
+	}
+}
+
+func TestQuizUseCase_ReviewAttempt(t *testing.T) {
+	course := newQuizTestCourse()
+	course.AuthorID = "author-1"
+	useCase := NewQuizUseCase(NewMockLibraryCourseRepository(course), &MockUserCourseRepository{}, NewMockQuizRepository(), services.NewQuizGrader(nil), services.NewQuizAssembler(), services.NewReviewScheduler(), nil)
+	ctx := context.Background()
+
+	submitted, err := useCase.SubmitAttempt(ctx, ports.SubmitQuizAttemptInput{
+		UserID:     "user-1",
+		CourseID:   "course-1",
+		LessonPath: []int{0, 0},
+		Answers:    []entities.QuizAnswer{{QuestionID: "q1", Answer: json.RawMessage(`0`)}},
+	})
+	if err != nil {
+		t.Fatalf("SubmitAttempt failed: %v", err)
+	}
+
+	attempt, err := useCase.GetAttempt(ctx, "user-1", submitted.ID)
+	if err != nil {
+		t.Fatalf("GetAttempt failed: %v", err)
+	}
+
+	reviews, err := useCase.ReviewAttempt(ctx, "user-1", attempt)
+	if err != nil {
+		t.Fatalf("ReviewAttempt failed: %v", err)
+	}
+	if len(reviews) != 2 {
+		t.Fatalf("expected 2 responses, got %d", len(reviews))
+	}
+	if reviews[0].QuestionID != "q1" || reviews[0].IsCorrect || reviews[0].Answer[0] != "a" || reviews[0].CorrectAnswer[0] != "b" {
+		t.Errorf("expected the wrong answer to be shown next to the correct one, got %+v", reviews[0])
+	}
+	if len(reviews[1].Answer) != 0 {
+		t.Errorf("expected the skipped question to have no answer, got %q", reviews[1].Answer)
+	}
+
+	if _, err := useCase.ReviewAttempt(ctx, "author-1", attempt); err != nil {
+		t.Errorf("expected the course author to review the attempt, got %v", err)
+	}
+	if _, err := useCase.GetAttempt(ctx, "user-2", submitted.ID); !errors.Is(err, entities.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized for another learner, got %v", err)
+	}
+	if _, err := useCase.ReviewAttempt(ctx, "user-2", attempt); !errors.Is(err, entities.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized for another learner, got %v", err)
+	}
+	if _, err := useCase.GetAttempt(ctx, "user-1", "missing"); !errors.Is(err, entities.ErrQuizAttemptNotFound) {
+		t.Errorf("expected ErrQuizAttemptNotFound, got %v", err)
+	}
+}
+
+func TestQuizUseCase_ExamSessions(t *testing.T) {
+	course := newQuizTestCourse()
+	course.Lessons[0].Sublessons[0].ExtendedQuiz.Exam = &entities.ExamConfig{MaxAttempts: 2, TimeLimitMinutes: 10, ScoringPolicy: entities.ScoringAverage}
+	quizRepo := NewMockQuizRepository()
+	useCase := NewQuizUseCase(NewMockLibraryCourseRepository(course), &MockUserCourseRepository{}, quizRepo, services.NewQuizGrader(nil), services.NewQuizAssembler(), services.NewReviewScheduler(), nil)
+	ctx := context.Background()
+	submit := func(sessionID string) (*entities.QuizAttempt, error) {
+		return useCase.SubmitAttempt(ctx, ports.SubmitQuizAttemptInput{
+			UserID:     "user-1",
+			CourseID:   "course-1",
+			LessonPath: []int{0, 0},
+			SessionID:  sessionID,
+			Answers:    []entities.QuizAnswer{{QuestionID: "q1", Answer: json.RawMessage(`1`)}},
+		})
+	}
+
+	if _, err := submit(""); !errors.Is(err, entities.ErrExamSessionRequired) {
+		t.Errorf("expected ErrExamSessionRequired without a session, got %v", err)
+	}
+
+	first, err := useCase.StartQuizSession(ctx, "user-1", "course-1", []int{0, 0})
+	if err != nil {
+		t.Fatalf("StartQuizSession failed: %v", err)
+	}
+	if first.ExpiresAt == nil || first.ExpiresAt.Sub(first.StartedAt) != 10*time.Minute {
+		t.Errorf("expected the session to expire after 10 minutes, got %+v", first)
+	}
+	again, err := useCase.StartQuizSession(ctx, "user-1", "course-1", []int{0, 0})
+	if err != nil || again.ID != first.ID {
+		t.Errorf("expected the open session to be returned, got %+v, %v", again, err)
+	}
+
+	attempt, err := submit(first.ID)
+	if err != nil {
+		t.Fatalf("SubmitAttempt failed: %v", err)
+	}
+	if _, err := submit(first.ID); !errors.Is(err, entities.ErrQuizSessionClosed) {
+		t.Errorf("expected ErrQuizSessionClosed for a second submission, got %v", err)
+	}
+
+	// The second session runs out of time before it is submitted
+	second, err := useCase.StartQuizSession(ctx, "user-1", "course-1", []int{0, 0})
+	if err != nil {
+		t.Fatalf("StartQuizSession failed: %v", err)
+	}
+	expired := time.Now().Add(-time.Minute)
+	quizRepo.sessions[1].ExpiresAt = &expired
+	if _, err := submit(second.ID); !errors.Is(err, entities.ErrExamTimeLimitExceeded) {
+		t.Errorf("expected ErrExamTimeLimitExceeded for a late submission, got %v", err)
+	}
+
+	if _, err := useCase.StartQuizSession(ctx, "user-1", "course-1", []int{0, 0}); !errors.Is(err, entities.ErrExamAttemptsExhausted) {
+		t.Errorf("expected ErrExamAttemptsExhausted after two sessions, got %v", err)
+	}
+
+	status, err := useCase.ExamStatus(ctx, "user-1", "course-1", []int{0, 0})
+	if err != nil {
+		t.Fatalf("ExamStatus failed: %v", err)
+	}
+	if status.AttemptsUsed != 2 || status.AttemptsRemaining == nil || *status.AttemptsRemaining != 0 || status.OpenSession != nil {
+		t.Errorf("expected both attempts to be used, got %+v", status)
+	}
+	if status.Score == nil || *status.Score != attempt.Percentage {
+		t.Errorf("expected the only submitted attempt to make up the score, got %v", status.Score)
+	}
+
+	if _, err := useCase.StartQuizSession(ctx, "user-1", "course-1", []int{0}); !errors.Is(err, entities.ErrNotAnExam) {
+		t.Errorf("expected ErrNotAnExam for an open practice quiz, got %v", err)
+	}
+}
+
+func TestQuizUseCase_ExamCooldown(t *testing.T) {
+	course := newQuizTestCourse()
+	course.QuizConfig = &entities.QuizConfig{Exam: &entities.ExamConfig{CooldownMinutes: 60}}
+	course.Lessons[0].ExtendedQuiz = course.Lessons[0].Sublessons[0].ExtendedQuiz
+	quizRepo := NewMockQuizRepository()
+	useCase := NewQuizUseCase(NewMockLibraryCourseRepository(course), &MockUserCourseRepository{}, quizRepo, services.NewQuizGrader(nil), services.NewQuizAssembler(), services.NewReviewScheduler(), nil)
+	ctx := context.Background()
+
+	session, err := useCase.StartQuizSession(ctx, "user-1", "course-1", []int{0})
+	if err != nil {
+		t.Fatalf("expected the course's exam settings to apply to the chapter quiz, got %v", err)
+	}
+	if session.ExpiresAt != nil {
+		t.Errorf("expected an untimed session, got %+v", session)
+	}
+
+	_, err = useCase.TestOut(ctx, ports.TestOutInput{UserID: "user-1", CourseID: "course-1", Chapter: 0, SessionID: session.ID})
+	if err != nil {
+		t.Fatalf("TestOut failed: %v", err)
+	}
+
+	_, err = useCase.StartQuizSession(ctx, "user-1", "course-1", []int{0})
+	if !errors.Is(err, entities.ErrExamCooldown) {
+		t.Errorf("expected ErrExamCooldown right after an attempt, got %v", err)
+	}
+	status, err := useCase.ExamStatus(ctx, "user-1", "course-1", []int{0})
+	if err != nil {
+		t.Fatalf("ExamStatus failed: %v", err)
+	}
+	if status.NextAttemptAt == nil || time.Until(*status.NextAttemptAt) < 59*time.Minute {
+		t.Errorf("expected the next attempt in an hour, got %v", status.NextAttemptAt)
+	}
+	if status.AttemptsRemaining != nil {
+		t.Errorf("expected unlimited attempts, got %d", *status.AttemptsRemaining)
+	}
+}
+
+func TestQuizUseCase_DraftAttempts(t *testing.T) {
+	quizRepo := NewMockQuizRepository()
+	useCase := NewQuizUseCase(NewMockLibraryCourseRepository(newQuizTestCourse()), &MockUserCourseRepository{}, quizRepo, services.NewQuizGrader(nil), services.NewQuizAssembler(), services.NewReviewScheduler(), nil)
+	ctx := context.Background()
+	start := ports.StartQuizAttemptInput{UserID: "user-1", CourseID: "course-1", LessonPath: []int{0, 0}}
+
+	draft, err := useCase.StartQuizAttempt(ctx, start)
+	if err != nil {
+		t.Fatalf("StartQuizAttempt failed: %v", err)
+	}
+	resumed, err := useCase.StartQuizAttempt(ctx, start)
+	if err != nil || resumed.ID != draft.ID {
+		t.Errorf("expected the open draft to be resumed, got %+v, %v", resumed, err)
+	}
+
+	if _, err := useCase.SaveQuizAnswer(ctx, "user-1", draft.ID, entities.QuizAnswer{QuestionID: "q1", Answer: json.RawMessage(`0`)}); err != nil {
+		t.Fatalf("SaveQuizAnswer failed: %v", err)
+	}
+	// Changing an answer replaces it
+	if _, err := useCase.SaveQuizAnswer(ctx, "user-1", draft.ID, entities.QuizAnswer{QuestionID: "q1", Answer: json.RawMessage(`1`)}); err != nil {
+		t.Fatalf("SaveQuizAnswer failed: %v", err)
+	}
+	if _, err := useCase.SaveQuizAnswer(ctx, "user-1", draft.ID, entities.QuizAnswer{QuestionID: "missing", Answer: json.RawMessage(`1`)}); !errors.Is(err, entities.ErrQuestionNotFound) {
+		t.Errorf("expected ErrQuestionNotFound for a question outside the quiz, got %v", err)
+	}
+	if _, err := useCase.SaveQuizAnswer(ctx, "user-2", draft.ID, entities.QuizAnswer{QuestionID: "q2", Answer: json.RawMessage(`0`)}); !errors.Is(err, entities.ErrQuizDraftNotFound) {
+		t.Errorf("expected ErrQuizDraftNotFound for another learner's draft, got %v", err)
+	}
+
+	open, err := useCase.OpenQuizAttempts(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("OpenQuizAttempts failed: %v", err)
+	}
+	if len(open) != 1 || len(open[0].Answers) != 1 || string(open[0].Answers[0].Answer) != "1" {
+		t.Errorf("expected one draft holding the latest answer, got %+v", open)
+	}
+
+	attempt, err := useCase.FinishQuizAttempt(ctx, "user-1", draft.ID)
+	if err != nil {
+		t.Fatalf("FinishQuizAttempt failed: %v", err)
+	}
+	if attempt.TotalQuestions != 2 || attempt.CorrectCount != 1 {
+		t.Errorf("expected 1 of 2 correct, got %d of %d", attempt.CorrectCount, attempt.TotalQuestions)
+	}
+	if _, err := useCase.FinishQuizAttempt(ctx, "user-1", draft.ID); !errors.Is(err, entities.ErrQuizDraftNotFound) {
+		t.Errorf("expected a finished draft to be gone, got %v", err)
+	}
+}
+
+func TestQuizUseCase_DraftExpiry(t *testing.T) {
+	quizRepo := NewMockQuizRepository()
+	useCase := NewQuizUseCase(NewMockLibraryCourseRepository(newQuizTestCourse()), &MockUserCourseRepository{}, quizRepo, services.NewQuizGrader(nil), services.NewQuizAssembler(), services.NewReviewScheduler(), nil)
+	ctx := context.Background()
+
+	draft, err := useCase.StartQuizAttempt(ctx, ports.StartQuizAttemptInput{UserID: "user-1", CourseID: "course-1", LessonPath: []int{0, 0}})
+	if err != nil {
+		t.Fatalf("StartQuizAttempt failed: %v", err)
+	}
+	quizRepo.drafts[0].ExpiresAt = time.Now().Add(-time.Minute)
+
+	if _, err := useCase.SaveQuizAnswer(ctx, "user-1", draft.ID, entities.QuizAnswer{QuestionID: "q1", Answer: json.RawMessage(`1`)}); !errors.Is(err, entities.ErrQuizDraftNotFound) {
+		t.Errorf("expected ErrQuizDraftNotFound for an expired draft, got %v", err)
+	}
+	if open, _ := useCase.OpenQuizAttempts(ctx, "user-1"); len(open) != 0 {
+		t.Errorf("expected expired drafts to be hidden, got %+v", open)
+	}
+
+	purged, err := useCase.PurgeExpiredDrafts(ctx)
+	if err != nil || purged != 1 || len(quizRepo.drafts) != 0 {
+		t.Errorf("expected the expired draft to be purged, got %d, %v", purged, err)
+	}
+}
+
+func TestQuizUseCase_ExamDraft(t *testing.T) {
+	course := newQuizTestCourse()
+	course.Lessons[0].Sublessons[0].ExtendedQuiz.Exam = &entities.ExamConfig{TimeLimitMinutes: 10}
+	quizRepo := NewMockQuizRepository()
+	useCase := NewQuizUseCase(NewMockLibraryCourseRepository(course), &MockUserCourseRepository{}, quizRepo, services.NewQuizGrader(nil), services.NewQuizAssembler(), services.NewReviewScheduler(), nil)
+	ctx := context.Background()
+	start := ports.StartQuizAttemptInput{UserID: "user-1", CourseID: "course-1", LessonPath: []int{0, 0}}
+
+	if _, err := useCase.StartQuizAttempt(ctx, start); !errors.Is(err, entities.ErrExamSessionRequired) {
+		t.Errorf("expected ErrExamSessionRequired without a session, got %v", err)
+	}
+
+	session, err := useCase.StartQuizSession(ctx, "user-1", "course-1", []int{0, 0})
+	if err != nil {
+		t.Fatalf("StartQuizSession failed: %v", err)
+	}
+	start.SessionID = session.ID
+	draft, err := useCase.StartQuizAttempt(ctx, start)
+	if err != nil {
+		t.Fatalf("StartQuizAttempt failed: %v", err)
+	}
+	if draft.Deadline == nil || !draft.ExpiresAt.Equal(session.ExpiresAt.Add(entities.ExamSubmissionGrace)) {
+		t.Errorf("expected the draft to expire with its session, got %+v", draft)
+	}
+
+	if _, err := useCase.FinishQuizAttempt(ctx, "user-1", draft.ID); err != nil {
+		t.Fatalf("FinishQuizAttempt failed: %v", err)
+	}
+	if stored, _ := quizRepo.GetQuizSession(ctx, session.ID); stored.SubmittedAt == nil {
+		t.Error("expected finishing the draft to submit the exam session")
+	}
+}
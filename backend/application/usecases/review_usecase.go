@@ -0,0 +1,230 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/project/backend/application/ports"
+	"github.com/project/backend/domain/entities"
+	"github.com/project/backend/domain/repositories"
+	"github.com/project/backend/domain/services"
+)
+
+// ReviewUseCase handles the spaced repetition review queue
+type ReviewUseCase struct {
+	courseRepo     repositories.LibraryCourseRepository
+	userCourseRepo repositories.UserCourseRepository
+	quizRepo       repositories.QuizRepository
+	grader         *services.QuizGrader
+	scheduler      *services.ReviewScheduler
+}
+
+// Ensure ReviewUseCase implements ReviewPort
+var _ ports.ReviewPort = (*ReviewUseCase)(nil)
+
+// enrollmentPageSize is how many enrollments are read at a time when collecting a user's courses
+const enrollmentPageSize = 100
+
+// NewReviewUseCase creates a new review use case
+func NewReviewUseCase(courseRepo repositories.LibraryCourseRepository, userCourseRepo repositories.UserCourseRepository, quizRepo repositories.QuizRepository, grader *services.QuizGrader, scheduler *services.ReviewScheduler) *ReviewUseCase {
+	return &ReviewUseCase{
+		courseRepo:     courseRepo,
+		userCourseRepo: userCourseRepo,
+		quizRepo:       quizRepo,
+		grader:         grader,
+		scheduler:      scheduler,
+	}
+}
+
+// QueueForReview adds a wrongly answered question to the review queue
+// Queueing a question that is already in the queue counts as another lapse
+func (uc *ReviewUseCase) QueueForReview(ctx context.Context, input ports.QueueForReviewInput) (*entities.ReviewQueueItem, error) {
+	if input.UserID == "" {
+		return nil, entities.ErrInvalidUserID
+	}
+
+	return queueForReview(ctx, uc.quizRepo, uc.scheduler, input)
+}
+
+// queueForReview schedules a missed question, as a new review item or as a lapse of a queued one
+func queueForReview(ctx context.Context, quizRepo repositories.QuizRepository, scheduler *services.ReviewScheduler, input ports.QueueForReviewInput) (*entities.ReviewQueueItem, error) {
+	now := time.Now()
+	item, err := quizRepo.GetReviewItem(ctx, input.UserID, input.CourseID, input.QuestionID)
+	switch {
+	case err == nil:
+		scheduler.Schedule(item, false, input.Confidence, now)
+	case errors.Is(err, entities.ErrReviewItemNotFound):
+		item = &entities.ReviewQueueItem{
+			UserID:     input.UserID,
+			CourseID:   input.CourseID,
+			QuizID:     input.QuizID,
+			QuestionID: input.QuestionID,
+			Concept:    input.Concept,
+		}
+		scheduler.NewItem(item, input.Confidence, now)
+	default:
+		return nil, err
+	}
+
+	if err := quizRepo.AddToReviewQueue(ctx, item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// RecordReviewOutcome grades the learner's answer to a queued question and reschedules it
+// Correct answers push the next review further out; wrong ones bring it closer
+func (uc *ReviewUseCase) RecordReviewOutcome(ctx context.Context, input ports.RecordReviewOutcomeInput) (*entities.ReviewQueueItem, error) {
+	if input.UserID == "" {
+		return nil, entities.ErrInvalidUserID
+	}
+
+	item, err := uc.quizRepo.GetReviewItem(ctx, input.UserID, input.CourseID, input.QuestionID)
+	if err != nil {
+		return nil, err
+	}
+
+	course, err := uc.courseRepo.GetByID(ctx, item.CourseID)
+	if err != nil {
+		return nil, err
+	}
+
+	question, _, err := findReviewQuestion(course, *item)
+	if err != nil {
+		return nil, err
+	}
+
+	grade, err := uc.grader.GradeQuestion(ctx, question, input.Answer)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	uc.scheduler.Schedule(item, grade.IsCorrect, input.Confidence, now)
+	item.LastReviewed = &now
+
+	if err := uc.quizRepo.AddToReviewQueue(ctx, item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// DailyReview collects due questions from all enrolled courses, interleaves them by concept
+// and resolves each to its question, stopping once the day's cap is reached
+func (uc *ReviewUseCase) DailyReview(ctx context.Context, userID string, dailyCap int) (*entities.DailyReview, error) {
+	if userID == "" {
+		return nil, entities.ErrInvalidUserID
+	}
+	if dailyCap <= 0 {
+		dailyCap = services.DefaultDailyReviewCap
+	}
+
+	courseIDs, err := uc.enrolledCourseIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	due, err := uc.quizRepo.GetDueReviewItems(ctx, userID, courseIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	reviewedToday, err := uc.quizRepo.CountReviewedSince(ctx, userID, startOfDay)
+	if err != nil {
+		return nil, err
+	}
+
+	review := &entities.DailyReview{
+		Items:         []entities.DailyReviewItem{},
+		TotalDue:      len(due),
+		ReviewedToday: reviewedToday,
+		DailyCap:      dailyCap,
+	}
+
+	courses := make(map[string]*entities.LibraryCourse)
+	for _, item := range uc.scheduler.Interleave(due) {
+		if len(review.Items)+reviewedToday >= dailyCap {
+			break
+		}
+
+		course, ok := courses[item.CourseID]
+		if !ok {
+			course, err = uc.courseRepo.GetByID(ctx, item.CourseID)
+			if err != nil && !errors.Is(err, entities.ErrCourseNotFound) {
+				return nil, err
+			}
+			courses[item.CourseID] = course
+		}
+		if course == nil {
+			continue
+		}
+
+		// Questions removed from the course since they were queued are skipped
+		reviewItem, err := resolveReviewItem(course, item)
+		if err != nil {
+			continue
+		}
+		review.Items = append(review.Items, *reviewItem)
+	}
+
+	return review, nil
+}
+
+// enrolledCourseIDs returns the IDs of every course the user is enrolled in, reading
+// enrollments a page at a time
+func (uc *ReviewUseCase) enrolledCourseIDs(ctx context.Context, userID string) ([]string, error) {
+	var courseIDs []string
+	for offset := 0; ; offset += enrollmentPageSize {
+		enrolled, total, err := uc.userCourseRepo.ListByUser(ctx, userID, enrollmentPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		for _, userCourse := range enrolled {
+			courseIDs = append(courseIDs, userCourse.LibraryCourseID)
+		}
+		if len(enrolled) == 0 || offset+len(enrolled) >= total {
+			return courseIDs, nil
+		}
+	}
+}
+
+// resolveReviewItem finds the question a review queue item refers to
+// The answer key is hidden; it is graded by RecordReviewOutcome
+func resolveReviewItem(course *entities.LibraryCourse, item entities.ReviewQueueItem) (*entities.DailyReviewItem, error) {
+	question, lessonPath, err := findReviewQuestion(course, item)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.DailyReviewItem{
+		Item:        item,
+		CourseTitle: course.Title,
+		LessonPath:  lessonPath,
+		Question:    question.Redacted(),
+	}, nil
+}
+
+// findReviewQuestion looks a queued question up in its quiz's lesson, through the lesson's
+// question pool so that sublesson questions queued from generated quizzes are found too
+func findReviewQuestion(course *entities.LibraryCourse, item entities.ReviewQueueItem) (*entities.ExtendedQuizQuestion, []int, error) {
+	lessonPath, err := entities.LessonPathForQuizID(item.QuizID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lesson, err := course.LessonAt(lessonPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	question, err := lesson.FindPoolQuestion(item.QuestionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return question, lessonPath, nil
+}
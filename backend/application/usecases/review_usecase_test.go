@@ -0,0 +1,365 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/project/backend/application/ports"
+	"github.com/project/backend/domain/entities"
+	"github.com/project/backend/domain/services"
+)
+
+// MockUserCourseRepository for testing
+type MockUserCourseRepository struct {
+	userCourses []*entities.UserCourse
+}
+
+func (m *MockUserCourseRepository) Create(ctx context.Context, userCourse *entities.UserCourse) (*entities.UserCourse, error) {
+	m.userCourses = append(m.userCourses, userCourse)
+	return userCourse, nil
+}
+
+func (m *MockUserCourseRepository) GetByID(ctx context.Context, id string) (*entities.UserCourse, error) {
+	for _, uc := range m.userCourses {
+		if uc.ID == id {
+			return uc, nil
+		}
+	}
+	return nil, entities.ErrCourseNotFound
+}
+
+func (m *MockUserCourseRepository) GetByUserAndCourse(ctx context.Context, userID, libraryCourseID string) (*entities.UserCourse, error) {
+	for _, uc := range m.userCourses {
+		if uc.UserID == userID && uc.LibraryCourseID == libraryCourseID {
+			return uc, nil
+		}
+	}
+	return nil, entities.ErrCourseNotFound
+}
+
+func (m *MockUserCourseRepository) Update(ctx context.Context, userCourse *entities.UserCourse) (*entities.UserCourse, error) {
+	return userCourse, nil
+}
+
+func (m *MockUserCourseRepository) Delete(ctx context.Context, id string) error {
+	return nil
+}
+
+func (m *MockUserCourseRepository) ListByUser(ctx context.Context, userID string, limit, offset int) ([]*entities.UserCourse, int, error) {
+	var result []*entities.UserCourse
+	for _, uc := range m.userCourses {
+		if uc.UserID == userID {
+			result = append(result, uc)
+		}
+	}
+	total := len(result)
+	if offset >= total {
+		return nil, total, nil
+	}
+	result = result[offset:]
+	if limit > 0 && limit < len(result) {
+		result = result[:limit]
+	}
+	return result, total, nil
+}
+
+func (m *MockUserCourseRepository) ListCompleted(ctx context.Context, userID string, limit, offset int) ([]*entities.UserCourse, int, error) {
+	return m.ListByUser(ctx, userID, limit, offset)
+}
+
+func (m *MockUserCourseRepository) ListInProgress(ctx context.Context, userID string, limit, offset int) ([]*entities.UserCourse, int, error) {
+	return m.ListByUser(ctx, userID, limit, offset)
+}
+
+func newReviewTestUseCase(quizRepo *MockQuizRepository, courses ...*entities.LibraryCourse) *ReviewUseCase {
+	if len(courses) == 0 {
+		courses = append(courses, newQuizTestCourse())
+	}
+	userCourseRepo := &MockUserCourseRepository{}
+	for _, course := range courses {
+		userCourseRepo.Create(context.Background(), &entities.UserCourse{UserID: "user-1", LibraryCourseID: course.ID})
+	}
+	return NewReviewUseCase(NewMockLibraryCourseRepository(courses...), userCourseRepo, quizRepo, services.NewQuizGrader(nil), services.NewReviewScheduler())
+}
+
+func TestReviewUseCase_QueueForReview(t *testing.T) {
+	quizRepo := NewMockQuizRepository()
+	useCase := newReviewTestUseCase(quizRepo)
+	ctx := context.Background()
+	input := ports.QueueForReviewInput{
+		UserID:     "user-1",
+		CourseID:   "course-1",
+		QuizID:     "lesson-00-sub-00",
+		QuestionID: "q1",
+		Concept:    "basics",
+	}
+
+	item, err := useCase.QueueForReview(ctx, input)
+	if err != nil {
+		t.Fatalf("QueueForReview failed: %v", err)
+	}
+	if item.WrongCount != 1 || item.Stability != services.InitialReviewStability {
+		t.Errorf("expected a fresh item, got wrong count %d and stability %v", item.WrongCount, item.Stability)
+	}
+
+	item, err = useCase.QueueForReview(ctx, input)
+	if err != nil {
+		t.Fatalf("QueueForReview failed: %v", err)
+	}
+	if item.WrongCount != 2 {
+		t.Errorf("expected queueing again to count as a lapse, got wrong count %d", item.WrongCount)
+	}
+	if len(quizRepo.reviewItems) != 1 {
+		t.Errorf("expected a single queued item, got %d", len(quizRepo.reviewItems))
+	}
+
+	input.Confidence = entities.ConfidenceHigh
+	item, err = useCase.QueueForReview(ctx, input)
+	if err != nil {
+		t.Fatalf("QueueForReview failed: %v", err)
+	}
+	if item.Priority != entities.ReviewPriorityHigh {
+		t.Errorf("expected a confidently wrong lapse to raise the priority, got %d", item.Priority)
+	}
+}
+
+func TestReviewUseCase_RecordReviewOutcome(t *testing.T) {
+	quizRepo := NewMockQuizRepository()
+	useCase := newReviewTestUseCase(quizRepo)
+	ctx := context.Background()
+
+	_, err := useCase.QueueForReview(ctx, ports.QueueForReviewInput{
+		UserID:     "user-1",
+		CourseID:   "course-1",
+		QuizID:     "lesson-00-sub-00",
+		QuestionID: "q1",
+	})
+	if err != nil {
+		t.Fatalf("QueueForReview failed: %v", err)
+	}
+
+	item, err := useCase.RecordReviewOutcome(ctx, ports.RecordReviewOutcomeInput{
+		UserID:     "user-1",
+		CourseID:   "course-1",
+		QuestionID: "q1",
+		Answer:     json.RawMessage(`1`),
+		Confidence: entities.ConfidenceHigh,
+	})
+	if err != nil {
+		t.Fatalf("RecordReviewOutcome failed: %v", err)
+	}
+	if item.Stability <= services.InitialReviewStability {
+		t.Errorf("expected a correct answer to lengthen the interval, got stability %v", item.Stability)
+	}
+	if item.NextReview.Before(time.Now().Add(48 * time.Hour)) {
+		t.Errorf("expected next review to move out, got %v", item.NextReview)
+	}
+	if _, err := quizRepo.GetReviewItem(ctx, "user-1", "course-1", "q1"); err != nil {
+		t.Errorf("expected the item to stay queued after a correct answer, got %v", err)
+	}
+
+	longest := item.Stability
+	item, err = useCase.RecordReviewOutcome(ctx, ports.RecordReviewOutcomeInput{
+		UserID:     "user-1",
+		CourseID:   "course-1",
+		QuestionID: "q1",
+		Answer:     json.RawMessage(`0`),
+	})
+	if err != nil {
+		t.Fatalf("RecordReviewOutcome failed: %v", err)
+	}
+	if item.Stability >= longest || item.WrongCount != 2 {
+		t.Errorf("expected a wrong answer to shorten the interval, got stability %v and wrong count %d", item.Stability, item.WrongCount)
+	}
+}
+
+func TestReviewUseCase_RecordReviewOutcome_NotQueued(t *testing.T) {
+	useCase := newReviewTestUseCase(NewMockQuizRepository())
+
+	_, err := useCase.RecordReviewOutcome(context.Background(), ports.RecordReviewOutcomeInput{
+		UserID:     "user-1",
+		CourseID:   "course-1",
+		QuestionID: "q1",
+		Answer:     json.RawMessage(`1`),
+	})
+	if !errors.Is(err, entities.ErrReviewItemNotFound) {
+		t.Errorf("expected ErrReviewItemNotFound, got %v", err)
+	}
+}
+
+func TestReviewUseCase_DailyReview(t *testing.T) {
+	quizRepo := NewMockQuizRepository()
+	other := newQuizTestCourse()
+	other.ID = "course-2"
+	other.Title = "Go Advanced"
+	useCase := newReviewTestUseCase(quizRepo, newQuizTestCourse(), other)
+	ctx := context.Background()
+
+	due := time.Now().Add(-time.Hour)
+	queued := []entities.ReviewQueueItem{
+		{UserID: "user-1", CourseID: "course-1", QuizID: "lesson-00-sub-00", QuestionID: "q1", Concept: "loops", NextReview: due.Add(-3 * time.Minute)},
+		{UserID: "user-1", CourseID: "course-1", QuizID: "lesson-00-sub-00", QuestionID: "q2", Concept: "loops", NextReview: due.Add(-2 * time.Minute)},
+		{UserID: "user-1", CourseID: "course-2", QuizID: "lesson-00-sub-00", QuestionID: "q1", Concept: "maps", NextReview: due.Add(-time.Minute)},
+		{UserID: "user-1", CourseID: "course-2", QuizID: "lesson-00-sub-00", QuestionID: "q2", Concept: "maps", NextReview: time.Now().Add(time.Hour)},
+		{UserID: "user-1", CourseID: "course-2", QuizID: "lesson-00-sub-00", QuestionID: "removed", Concept: "maps", NextReview: due},
+		{UserID: "user-1", CourseID: "course-3", QuizID: "lesson-00-sub-00", QuestionID: "q1", Concept: "other", NextReview: due},
+	}
+	for i := range queued {
+		quizRepo.AddToReviewQueue(ctx, &queued[i])
+	}
+
+	review, err := useCase.DailyReview(ctx, "user-1", 0)
+	if err != nil {
+		t.Fatalf("DailyReview failed: %v", err)
+	}
+
+	if review.DailyCap != services.DefaultDailyReviewCap {
+		t.Errorf("expected default cap %d, got %d", services.DefaultDailyReviewCap, review.DailyCap)
+	}
+	if review.TotalDue != 4 {
+		t.Errorf("expected 4 due items from enrolled courses, got %d", review.TotalDue)
+	}
+
+	want := []string{"course-1/q1", "course-2/q1", "course-1/q2"}
+	if len(review.Items) != len(want) {
+		t.Fatalf("expected %d resolved items, got %d", len(want), len(review.Items))
+	}
+	for i, w := range want {
+		item := review.Items[i]
+		if got := item.Item.CourseID + "/" + item.Item.QuestionID; got != w {
+			t.Errorf("expected %s at position %d, got %s", w, i, got)
+		}
+		if item.Question.ID != item.Item.QuestionID || !item.Question.AnswerKeyHidden {
+			t.Errorf("expected redacted question %s, got %+v", item.Item.QuestionID, item.Question)
+		}
+	}
+	if review.Items[1].CourseTitle != "Go Advanced" {
+		t.Errorf("expected course title Go Advanced, got %s", review.Items[1].CourseTitle)
+	}
+}
+
+func TestReviewUseCase_DailyReview_RespectsCap(t *testing.T) {
+	quizRepo := NewMockQuizRepository()
+	useCase := newReviewTestUseCase(quizRepo)
+	ctx := context.Background()
+
+	for _, id := range []string{"q1", "q2"} {
+		quizRepo.AddToReviewQueue(ctx, &entities.ReviewQueueItem{
+			UserID: "user-1", CourseID: "course-1", QuizID: "lesson-00-sub-00", QuestionID: id, NextReview: time.Now().Add(-time.Hour),
+		})
+	}
+
+	_, err := useCase.RecordReviewOutcome(ctx, ports.RecordReviewOutcomeInput{
+		UserID:     "user-1",
+		CourseID:   "course-1",
+		QuestionID: "q1",
+		Answer:     json.RawMessage(`1`),
+	})
+	if err != nil {
+		t.Fatalf("RecordReviewOutcome failed: %v", err)
+	}
+
+	review, err := useCase.DailyReview(ctx, "user-1", 1)
+	if err != nil {
+		t.Fatalf("DailyReview failed: %v", err)
+	}
+	if review.ReviewedToday != 1 {
+		t.Errorf("expected 1 reviewed today, got %d", review.ReviewedToday)
+	}
+	if len(review.Items) != 0 {
+		t.Errorf("expected the cap to be reached, got %d items", len(review.Items))
+	}
+}
+
+func TestReviewUseCase_DailyReview_ReadsEveryEnrollmentPage(t *testing.T) {
+	quizRepo := NewMockQuizRepository()
+	useCase := newReviewTestUseCase(quizRepo)
+	ctx := context.Background()
+
+	// Enrollments ahead of course-1 push it past the first page
+	userCourseRepo := useCase.userCourseRepo.(*MockUserCourseRepository)
+	var enrolled []*entities.UserCourse
+	for i := 0; i < enrollmentPageSize+5; i++ {
+		enrolled = append(enrolled, &entities.UserCourse{UserID: "user-1", LibraryCourseID: fmt.Sprintf("other-%d", i)})
+	}
+	userCourseRepo.userCourses = append(enrolled, userCourseRepo.userCourses...)
+
+	quizRepo.AddToReviewQueue(ctx, &entities.ReviewQueueItem{
+		UserID: "user-1", CourseID: "course-1", QuizID: "lesson-00-sub-00", QuestionID: "q1", NextReview: time.Now().Add(-time.Hour),
+	})
+
+	review, err := useCase.DailyReview(ctx, "user-1", 0)
+	if err != nil {
+		t.Fatalf("DailyReview failed: %v", err)
+	}
+	if review.TotalDue != 1 || len(review.Items) != 1 {
+		t.Errorf("expected the item from the course on the second page, got %d due and %d items", review.TotalDue, len(review.Items))
+	}
+}
+
+func TestReviewUseCase_SublessonQuestionsFromGeneratedQuizzes(t *testing.T) {
+	quizRepo := NewMockQuizRepository()
+	course := newGeneratedQuizTestCourse()
+	quizUseCase := NewQuizUseCase(NewMockLibraryCourseRepository(course), &MockUserCourseRepository{}, quizRepo, services.NewQuizGrader(nil), services.NewQuizAssembler(), services.NewReviewScheduler(), nil)
+	useCase := newReviewTestUseCase(quizRepo, course)
+	ctx := context.Background()
+
+	instance, err := quizUseCase.GenerateQuiz(ctx, ports.GenerateQuizInput{UserID: "user-1", CourseID: "course-1", LessonPath: []int{0}, IncludeSublessons: true})
+	if err != nil {
+		t.Fatalf("GenerateQuiz failed: %v", err)
+	}
+
+	// Confidently wrong answers queue the sublesson questions under the chapter's quiz
+	answers := make([]entities.QuizAnswer, len(instance.Questions))
+	for i, q := range instance.Questions {
+		for j, option := range q.Options {
+			if option == "wrong 1" {
+				answers[i] = entities.QuizAnswer{QuestionID: q.ID, Answer: json.RawMessage(fmt.Sprint(j)), Confidence: entities.ConfidenceHigh}
+			}
+		}
+	}
+	_, err = quizUseCase.SubmitAttempt(ctx, ports.SubmitQuizAttemptInput{
+		UserID:     "user-1",
+		CourseID:   "course-1",
+		LessonPath: []int{0},
+		InstanceID: instance.ID,
+		Answers:    answers,
+	})
+	if err != nil {
+		t.Fatalf("SubmitAttempt failed: %v", err)
+	}
+	item, ok := quizRepo.reviewItems[reviewItemKey("user-1", "course-1", "1/q6")]
+	if !ok || item.QuizID != "lesson-00" {
+		t.Fatalf("expected 1/q6 to be queued for lesson-00, got %+v", item)
+	}
+
+	// Queued items are due once the scheduler's first interval has passed
+	for key, queued := range quizRepo.reviewItems {
+		queued.NextReview = time.Now().Add(-time.Minute)
+		quizRepo.reviewItems[key] = queued
+	}
+	review, err := useCase.DailyReview(ctx, "user-1", 0)
+	if err != nil {
+		t.Fatalf("DailyReview failed: %v", err)
+	}
+	if len(review.Items) != len(instance.Questions) {
+		t.Errorf("expected all %d sublesson questions to be resolved, got %d", len(instance.Questions), len(review.Items))
+	}
+
+	reviewed, err := useCase.RecordReviewOutcome(ctx, ports.RecordReviewOutcomeInput{
+		UserID:     "user-1",
+		CourseID:   "course-1",
+		QuestionID: "1/q6",
+		Answer:     json.RawMessage(`0`),
+	})
+	if err != nil {
+		t.Fatalf("RecordReviewOutcome failed: %v", err)
+	}
+	if reviewed.LastReviewed == nil {
+		t.Error("expected the review to be recorded")
+	}
+}
@@ -1,9 +1,13 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler"
@@ -16,9 +20,12 @@ import (
 	"github.com/project/backend/adapters/folder"
 	"github.com/project/backend/adapters/graphql"
 	httpAdapter "github.com/project/backend/adapters/http"
+	"github.com/project/backend/adapters/sandbox"
 	"github.com/project/backend/adapters/storage"
+	"github.com/project/backend/application/ports"
 	"github.com/project/backend/application/usecases"
 	"github.com/project/backend/config"
+	"github.com/project/backend/domain/entities"
 	"github.com/project/backend/domain/repositories"
 	"github.com/project/backend/domain/services"
 )
@@ -33,6 +40,10 @@ func main() {
 	// Load configuration
 	cfg := config.Load()
 
+	// Background work such as the course watcher stops when the server shuts down
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Initialize database
 	database, err := db.NewSQLiteDB(cfg.DatabasePath)
 	if err != nil {
@@ -53,6 +64,7 @@ func main() {
 	bookmarkRepo := db.NewBookmarkRepository(database)
 	analyticsRepo := db.NewAnalyticsRepository(database)
 	attachmentRepo := db.NewAttachmentRepository(database)
+	quizRepo := db.NewQuizRepository(database)
 
 	// Initialize course repository (folder-based or database)
 	var libraryCourseRepo repositories.LibraryCourseRepository
@@ -61,6 +73,24 @@ func main() {
 		folderCourseRepo = folder.NewFolderCourseRepository(cfg.CoursesPath)
 		libraryCourseRepo = folderCourseRepo
 		slog.Info("Using folder-based course repository", "path", cfg.CoursesPath)
+		if err := folderCourseRepo.CheckCourseIDs(context.Background()); err != nil {
+			slog.Warn("Skipped course folders with duplicate course IDs", "error", err)
+		}
+
+		if cfg.WatchCourses {
+			changes, _ := folderCourseRepo.SubscribeCourseChanges()
+			go func() {
+				for change := range changes {
+					slog.Info("Course reloaded", "course", change.CourseID, "change", change.Kind)
+				}
+			}()
+			go func() {
+				// Without the watcher, courses are still reloaded when the cache expires
+				if err := folderCourseRepo.Watch(ctx, cfg.CourseWatchDebounce); err != nil {
+					slog.Warn("Course watcher stopped", "error", err)
+				}
+			}()
+		}
 	} else {
 		libraryCourseRepo = db.NewLibraryCourseRepository(database)
 		slog.Info("Using database course repository")
@@ -72,20 +102,68 @@ func main() {
 	// Initialize auth service
 	authService := services.NewAuthService(cfg.JWTSecret)
 
+	// Folder courses have no author account, so their editors are configured
+	editors := entities.NewUserGroup(cfg.EditorUserIDs)
+
 	// Initialize use cases
 	userUseCase := usecases.NewUserUseCase(userRepo)
 	authUseCase := usecases.NewAuthUseCase(userRepo, authService)
+	var codeRunner services.CodeRunner
+	if cfg.CodeExercisesEnabled {
+		codeRunner = sandbox.NewGoTestRunner(cfg.GoBinary, cfg.GoBuildCachePath, sandbox.Limits{
+			Timeout:     cfg.CodeExerciseTimeout,
+			CPUTime:     cfg.CodeExerciseCPUTime,
+			MemoryMB:    cfg.CodeExerciseMemoryMB,
+			Processes:   cfg.CodeExerciseProcesses,
+			Concurrency: cfg.CodeExerciseConcurrency,
+		})
+	}
+	quizGrader := services.NewQuizGrader(codeRunner)
+	reviewScheduler := services.NewReviewScheduler()
+	quizUseCase := usecases.NewQuizUseCase(libraryCourseRepo, userCourseRepo, quizRepo, quizGrader, services.NewQuizAssembler(), reviewScheduler, editors)
+	reviewUseCase := usecases.NewReviewUseCase(libraryCourseRepo, userCourseRepo, quizRepo, quizGrader, reviewScheduler)
+
+	// Question banks live next to the folder courses that reference them
+	var questionBankUseCase ports.QuestionBankPort
+	if folderCourseRepo != nil {
+		questionBankUseCase = usecases.NewQuestionBankUseCase(folderCourseRepo, quizRepo, editors)
+	}
+
+	// Expired quiz drafts are hidden as soon as they expire; this reclaims their storage
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			purged, err := quizUseCase.PurgeExpiredDrafts(ctx)
+			if err != nil {
+				slog.Warn("Failed to purge expired quiz drafts", "error", err)
+			} else if purged > 0 {
+				slog.Info("Purged expired quiz drafts", "count", purged)
+			}
+		}
+	}()
 
 	// Initialize GraphQL resolver
 	resolver := &graphql.Resolver{
-		UserUseCase:       userUseCase,
-		AuthUseCase:       authUseCase,
-		LibraryCourseRepo: libraryCourseRepo,
-		UserCourseRepo:    userCourseRepo,
-		BookmarkRepo:      bookmarkRepo,
-		AnalyticsRepo:     analyticsRepo,
-		AttachmentRepo:    attachmentRepo,
-		FolderCourseRepo:  folderCourseRepo,
+		UserUseCase:         userUseCase,
+		AuthUseCase:         authUseCase,
+		QuizUseCase:         quizUseCase,
+		ReviewUseCase:       reviewUseCase,
+		QuestionBankUseCase: questionBankUseCase,
+		LibraryCourseRepo:   libraryCourseRepo,
+		UserCourseRepo:      userCourseRepo,
+		BookmarkRepo:        bookmarkRepo,
+		AnalyticsRepo:       analyticsRepo,
+		AttachmentRepo:      attachmentRepo,
+		QuizRepo:            quizRepo,
+		FolderCourseRepo:    folderCourseRepo,
+		Editors:             editors,
+		Admins:              entities.NewUserGroup(cfg.AdminUserIDs),
 	}
 
 	// Initialize HTTP handlers
@@ -93,6 +171,7 @@ func main() {
 
 	// Create GraphQL server
 	srv := handler.NewDefaultServer(graphql.NewExecutableSchema(graphql.Config{Resolvers: resolver}))
+	srv.SetErrorPresenter(graphql.ErrorPresenter)
 
 	// Set up router
 	r := chi.NewRouter()
@@ -147,8 +226,22 @@ func main() {
 
 	// Start server
 	slog.Info("Starting server", "port", cfg.Port, "playground", cfg.EnablePlayground)
-	if err := server.ListenAndServe(); err != nil {
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
 		slog.Error("Server failed", "error", err)
 		os.Exit(1)
+	case <-ctx.Done():
+	}
+
+	slog.Info("Shutting down server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		slog.Error("Server shutdown failed", "error", err)
 	}
 }
@@ -0,0 +1,54 @@
+// Command courses checks the course folders
+//
+// Usage:
+//
+//	go run ./cmd/courses lint [-courses ./data/courses]
+//
+// lint reports every problem found as path:line:column: message and exits with status 1
+// if there are any, so it can run before committing course changes
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/project/backend/adapters/folder"
+	"github.com/project/backend/config"
+)
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] != "lint" {
+		fmt.Fprintln(os.Stderr, "usage: courses lint [-courses path]")
+		os.Exit(2)
+	}
+
+	cfg := config.Load()
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	coursesPath := flags.String("courses", cfg.CoursesPath, "course folders to check")
+	flags.Parse(os.Args[2:])
+
+	problems, err := folder.LintCourses(*coursesPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, problem := range problems {
+		problem.Path = relativePath(*coursesPath, problem.Path)
+		fmt.Println(problem)
+	}
+	if len(problems) > 0 {
+		fmt.Printf("%d problems found\n", len(problems))
+		os.Exit(1)
+	}
+}
+
+// relativePath shortens a path for display
+func relativePath(base, path string) string {
+	if rel, err := filepath.Rel(base, path); err == nil {
+		return rel
+	}
+	return path
+}
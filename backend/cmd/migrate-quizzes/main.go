@@ -0,0 +1,101 @@
+// Command migrate-quizzes converts legacy quiz.json files (capitalised keys) in the course
+// folders to the extended format and reports the quizzes it could not convert
+//
+// Usage:
+//
+//	go run ./cmd/migrate-quizzes [-courses ./data/courses] [-dry-run] [-report report.txt]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/project/backend/adapters/folder"
+	"github.com/project/backend/adapters/textdiff"
+	"github.com/project/backend/config"
+)
+
+func main() {
+	cfg := config.Load()
+
+	coursesPath := flag.String("courses", cfg.CoursesPath, "course folders to migrate")
+	dryRun := flag.Bool("dry-run", false, "print a diff of each conversion without writing")
+	reportPath := flag.String("report", "", "write the report to this file instead of stdout")
+	flag.Parse()
+
+	migrations, err := folder.MigrateLegacyQuizzes(*coursesPath, *dryRun)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *dryRun {
+		for _, m := range migrations {
+			if m.Migrated != nil {
+				name := relativePath(*coursesPath, m.Path)
+				fmt.Print(textdiff.Unified("a/"+name, "b/"+name, m.Original, m.Migrated))
+			}
+		}
+	}
+
+	report := io.Writer(os.Stdout)
+	if *reportPath != "" {
+		f, err := os.Create(*reportPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		report = f
+	}
+
+	failed := writeReport(report, *coursesPath, migrations, *dryRun)
+	if failed > 0 {
+		// Deferred calls do not run on os.Exit, so the report is closed explicitly
+		if f, ok := report.(*os.File); ok && f != os.Stdout {
+			f.Close()
+		}
+		os.Exit(1)
+	}
+}
+
+// writeReport lists the quizzes that were converted and, with the reasons, those that
+// were not; it returns the number not converted
+func writeReport(w io.Writer, coursesPath string, migrations []folder.QuizMigration, dryRun bool) int {
+	verb := "Converted"
+	if dryRun {
+		verb = "Would convert"
+	}
+
+	var converted, failed []folder.QuizMigration
+	for _, m := range migrations {
+		if m.Migrated != nil {
+			converted = append(converted, m)
+		} else {
+			failed = append(failed, m)
+		}
+	}
+
+	fmt.Fprintf(w, "%s %d legacy quizzes, %d could not be converted\n", verb, len(converted), len(failed))
+	for _, m := range converted {
+		fmt.Fprintf(w, "  %s\n", relativePath(coursesPath, m.Path))
+	}
+	for _, m := range failed {
+		fmt.Fprintf(w, "\nNot converted: %s\n", relativePath(coursesPath, m.Path))
+		for _, problem := range m.Problems {
+			fmt.Fprintf(w, "  - %s\n", problem)
+		}
+	}
+
+	return len(failed)
+}
+
+// relativePath shortens a quiz path for display
+func relativePath(base, path string) string {
+	if rel, err := filepath.Rel(base, path); err == nil {
+		return rel
+	}
+	return path
+}
@@ -18,6 +18,26 @@ type Config struct {
 	RequestTimeout   time.Duration
 	LogLevel         string
 	JWTSecret        string
+
+	// Folder courses have no author account; only these users may edit them
+	EditorUserIDs []string
+	// Users who may run administrative mutations, such as reloading every course
+	AdminUserIDs []string
+
+	// Folder courses are reloaded as their files change, once edits pause for the debounce
+	WatchCourses        bool
+	CourseWatchDebounce time.Duration
+
+	// Code exercises are graded by running go test on the server; they are off unless
+	// enabled, since they need Linux user namespaces
+	CodeExercisesEnabled    bool
+	GoBinary                string
+	GoBuildCachePath        string
+	CodeExerciseTimeout     time.Duration
+	CodeExerciseCPUTime     time.Duration
+	CodeExerciseMemoryMB    int
+	CodeExerciseProcesses   int
+	CodeExerciseConcurrency int
 }
 
 // Load reads configuration from environment variables with sensible defaults
@@ -32,6 +52,21 @@ func Load() *Config {
 		RequestTimeout:   getEnvDuration("REQUEST_TIMEOUT", 30*time.Second),
 		LogLevel:         getEnv("LOG_LEVEL", "info"),
 		JWTSecret:        getEnv("JWT_SECRET", "development-secret-change-in-production-32chars!"),
+
+		EditorUserIDs: getEnvSlice("EDITOR_USER_IDS", nil),
+		AdminUserIDs:  getEnvSlice("ADMIN_USER_IDS", nil),
+
+		WatchCourses:        getEnvBool("WATCH_COURSES", true),
+		CourseWatchDebounce: getEnvDuration("COURSE_WATCH_DEBOUNCE", 300*time.Millisecond),
+
+		CodeExercisesEnabled:    getEnvBool("CODE_EXERCISES_ENABLED", false),
+		GoBinary:                getEnv("GO_BINARY", "go"),
+		GoBuildCachePath:        getEnv("GO_BUILD_CACHE_PATH", "./data/go-build-cache"),
+		CodeExerciseTimeout:     getEnvDuration("CODE_EXERCISE_TIMEOUT", 20*time.Second),
+		CodeExerciseCPUTime:     getEnvDuration("CODE_EXERCISE_CPU_TIME", 5*time.Second),
+		CodeExerciseMemoryMB:    getEnvInt("CODE_EXERCISE_MEMORY_MB", 512),
+		CodeExerciseProcesses:   getEnvInt("CODE_EXERCISE_PROCESSES", 64),
+		CodeExerciseConcurrency: getEnvInt("CODE_EXERCISE_CONCURRENCY", 2),
 	}
 }
 
@@ -52,6 +87,16 @@ func getEnvBool(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
 func getEnvSlice(key string, defaultValue []string) []string {
 	if value := os.Getenv(key); value != "" {
 		return strings.Split(value, ",")
@@ -0,0 +1,84 @@
+package entities
+
+import "sort"
+
+// CalibrationReport compares how confident a learner said they were with how often they were right
+type CalibrationReport struct {
+	CourseID  string               `json:"courseId"` // Empty when the report spans all courses
+	Responses int                  `json:"responses"`
+	Levels    []ConfidenceAccuracy `json:"levels"` // Low, medium and high, skipping levels never reported
+	// ConfidentlyWrong counts answers given with high confidence that were wrong
+	ConfidentlyWrong         int      `json:"confidentlyWrong"`
+	ConfidentlyWrongConcepts []string `json:"confidentlyWrongConcepts"`
+}
+
+// ComputeCalibration breaks a learner's accuracy down by the confidence they reported
+// Answers without a confidence count towards Responses only
+func ComputeCalibration(courseID string, outcomes []ConceptOutcome) *CalibrationReport {
+	report := &CalibrationReport{
+		CourseID:                 courseID,
+		Responses:                len(outcomes),
+		Levels:                   []ConfidenceAccuracy{},
+		ConfidentlyWrongConcepts: ConfidentlyWrongConcepts(outcomes),
+	}
+
+	for _, level := range []ConfidenceLevel{ConfidenceLow, ConfidenceMedium, ConfidenceHigh} {
+		entry := ConfidenceAccuracy{Confidence: level}
+		for _, o := range outcomes {
+			if o.Confidence != level {
+				continue
+			}
+			entry.Responses++
+			if o.IsCorrect {
+				entry.CorrectCount++
+			}
+		}
+		if entry.Responses == 0 {
+			continue
+		}
+		entry.Accuracy = accuracy(entry.CorrectCount, entry.Responses)
+		report.Levels = append(report.Levels, entry)
+
+		if level == ConfidenceHigh {
+			report.ConfidentlyWrong = entry.Responses - entry.CorrectCount
+		}
+	}
+
+	return report
+}
+
+// ConfidentlyWrongConcepts lists concepts whose latest high-confidence answer was wrong,
+// which points at a misconception rather than a gap. Concepts with the most confidently
+// wrong answers come first
+func ConfidentlyWrongConcepts(outcomes []ConceptOutcome) []string {
+	sorted := make([]ConceptOutcome, len(outcomes))
+	copy(sorted, outcomes)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].AnsweredAt.Before(sorted[j].AnsweredAt) })
+
+	wrongCount := make(map[string]int)
+	latestWrong := make(map[string]bool)
+	for _, o := range sorted {
+		if o.Concept == "" || o.Confidence != ConfidenceHigh {
+			continue
+		}
+		latestWrong[o.Concept] = !o.IsCorrect
+		if !o.IsCorrect {
+			wrongCount[o.Concept]++
+		}
+	}
+
+	concepts := []string{}
+	for concept, wrong := range latestWrong {
+		if wrong {
+			concepts = append(concepts, concept)
+		}
+	}
+	sort.Slice(concepts, func(i, j int) bool {
+		if wrongCount[concepts[i]] != wrongCount[concepts[j]] {
+			return wrongCount[concepts[i]] > wrongCount[concepts[j]]
+		}
+		return concepts[i] < concepts[j]
+	})
+
+	return concepts
+}
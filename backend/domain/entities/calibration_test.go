@@ -0,0 +1,55 @@
+package entities
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeCalibration(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(day int) time.Time { return start.AddDate(0, 0, day) }
+
+	report := ComputeCalibration("course-1", []ConceptOutcome{
+		{Concept: "loops", IsCorrect: true, Confidence: ConfidenceHigh, AnsweredAt: at(0)},
+		{Concept: "loops", IsCorrect: false, Confidence: ConfidenceHigh, AnsweredAt: at(1)},
+		{Concept: "maps", IsCorrect: false, Confidence: ConfidenceHigh, AnsweredAt: at(0)},
+		{Concept: "maps", IsCorrect: true, Confidence: ConfidenceHigh, AnsweredAt: at(1)},
+		{Concept: "slices", IsCorrect: false, Confidence: ConfidenceLow, AnsweredAt: at(0)},
+		{Concept: "slices", IsCorrect: true, Confidence: ConfidenceLow, AnsweredAt: at(1)},
+		{Concept: "slices", IsCorrect: true, Confidence: "", AnsweredAt: at(2)},
+	})
+
+	if report.CourseID != "course-1" || report.Responses != 7 {
+		t.Errorf("expected 7 responses for course-1, got %d for %q", report.Responses, report.CourseID)
+	}
+	if len(report.Levels) != 2 {
+		t.Fatalf("expected low and high levels only, got %+v", report.Levels)
+	}
+	if low := report.Levels[0]; low.Confidence != ConfidenceLow || low.Responses != 2 || low.Accuracy != 50 {
+		t.Errorf("unexpected low confidence entry %+v", low)
+	}
+	if high := report.Levels[1]; high.Confidence != ConfidenceHigh || high.Responses != 4 || high.CorrectCount != 2 {
+		t.Errorf("unexpected high confidence entry %+v", high)
+	}
+	if report.ConfidentlyWrong != 2 {
+		t.Errorf("expected 2 confidently wrong answers, got %d", report.ConfidentlyWrong)
+	}
+	if len(report.ConfidentlyWrongConcepts) != 1 || report.ConfidentlyWrongConcepts[0] != "loops" {
+		t.Errorf("expected only loops to be confidently wrong, got %v", report.ConfidentlyWrongConcepts)
+	}
+}
+
+func TestConfidentlyWrongConcepts_OrderedByCount(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	concepts := ConfidentlyWrongConcepts([]ConceptOutcome{
+		{Concept: "maps", IsCorrect: false, Confidence: ConfidenceHigh, AnsweredAt: start},
+		{Concept: "channels", IsCorrect: false, Confidence: ConfidenceHigh, AnsweredAt: start},
+		{Concept: "channels", IsCorrect: false, Confidence: ConfidenceHigh, AnsweredAt: start.Add(time.Hour)},
+		{Concept: "loops", IsCorrect: false, Confidence: ConfidenceMedium, AnsweredAt: start},
+	})
+
+	if len(concepts) != 2 || concepts[0] != "channels" || concepts[1] != "maps" {
+		t.Errorf("expected [channels maps], got %v", concepts)
+	}
+}
@@ -0,0 +1,107 @@
+package entities
+
+import (
+	"sort"
+	"time"
+)
+
+// MinConceptAttempts is the number of answers needed before a concept is called weak or strong
+const MinConceptAttempts = 2
+
+// ConceptOutcome is a single graded answer to a question about a concept
+type ConceptOutcome struct {
+	Concept    string
+	IsCorrect  bool
+	Confidence ConfidenceLevel
+	AnsweredAt time.Time
+}
+
+// ConceptStrength summarizes how well a learner knows a concept
+type ConceptStrength struct {
+	Concept      string       `json:"concept"`
+	Attempts     int          `json:"attempts"`
+	CorrectCount int          `json:"correctCount"`
+	Accuracy     float64      `json:"accuracy"` // 0-100
+	Trend        float64      `json:"trend"`    // Accuracy of the newer half of answers minus the older half
+	Mastery      MasteryLevel `json:"mastery"`
+}
+
+// ComputeConceptStrengths aggregates graded answers per concept
+// Outcomes without a concept are ignored; the result is sorted by concept name
+func ComputeConceptStrengths(outcomes []ConceptOutcome) []ConceptStrength {
+	byConcept := make(map[string][]ConceptOutcome)
+	for _, o := range outcomes {
+		if o.Concept == "" {
+			continue
+		}
+		byConcept[o.Concept] = append(byConcept[o.Concept], o)
+	}
+
+	strengths := make([]ConceptStrength, 0, len(byConcept))
+	for concept, answers := range byConcept {
+		sort.SliceStable(answers, func(i, j int) bool {
+			return answers[i].AnsweredAt.Before(answers[j].AnsweredAt)
+		})
+
+		strength := ConceptStrength{
+			Concept:      concept,
+			Attempts:     len(answers),
+			CorrectCount: countCorrect(answers),
+		}
+		strength.Accuracy = accuracy(strength.CorrectCount, strength.Attempts)
+		strength.Mastery = GetMasteryLevel(strength.Accuracy)
+
+		if len(answers) >= 2 {
+			older, newer := answers[:len(answers)/2], answers[len(answers)/2:]
+			strength.Trend = accuracy(countCorrect(newer), len(newer)) - accuracy(countCorrect(older), len(older))
+		}
+
+		strengths = append(strengths, strength)
+	}
+
+	sort.Slice(strengths, func(i, j int) bool { return strengths[i].Concept < strengths[j].Concept })
+	return strengths
+}
+
+// WeakAndStrongConcepts splits concepts with enough answers into weak ones (developing or
+// below) and strong ones (expert), weakest and strongest first
+func WeakAndStrongConcepts(strengths []ConceptStrength) (weak, strong []string) {
+	ranked := make([]ConceptStrength, 0, len(strengths))
+	for _, s := range strengths {
+		if s.Attempts >= MinConceptAttempts {
+			ranked = append(ranked, s)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Accuracy < ranked[j].Accuracy })
+
+	weak, strong = []string{}, []string{}
+	for _, s := range ranked {
+		if s.Mastery == MasteryNovice || s.Mastery == MasteryDeveloping {
+			weak = append(weak, s.Concept)
+		}
+	}
+	for i := len(ranked) - 1; i >= 0; i-- {
+		if ranked[i].Mastery == MasteryExpert {
+			strong = append(strong, ranked[i].Concept)
+		}
+	}
+
+	return weak, strong
+}
+
+func countCorrect(outcomes []ConceptOutcome) int {
+	count := 0
+	for _, o := range outcomes {
+		if o.IsCorrect {
+			count++
+		}
+	}
+	return count
+}
+
+func accuracy(correct, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(correct) * 100 / float64(total)
+}
@@ -0,0 +1,63 @@
+package entities
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeConceptStrengths(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(day int) time.Time { return start.AddDate(0, 0, day) }
+
+	strengths := ComputeConceptStrengths([]ConceptOutcome{
+		{Concept: "maps", IsCorrect: true, AnsweredAt: at(3)},
+		{Concept: "loops", IsCorrect: false, AnsweredAt: at(0)},
+		{Concept: "loops", IsCorrect: false, AnsweredAt: at(1)},
+		{Concept: "loops", IsCorrect: true, AnsweredAt: at(2)},
+		{Concept: "loops", IsCorrect: true, AnsweredAt: at(3)},
+		{Concept: "", IsCorrect: true, AnsweredAt: at(3)},
+	})
+
+	if len(strengths) != 2 {
+		t.Fatalf("expected 2 concepts, got %d", len(strengths))
+	}
+
+	loops := strengths[0]
+	if loops.Concept != "loops" {
+		t.Fatalf("expected concepts sorted by name, got %s first", loops.Concept)
+	}
+	if loops.Attempts != 4 || loops.CorrectCount != 2 {
+		t.Errorf("expected 2 of 4 correct, got %d of %d", loops.CorrectCount, loops.Attempts)
+	}
+	if loops.Accuracy != 50 {
+		t.Errorf("expected accuracy 50, got %v", loops.Accuracy)
+	}
+	if loops.Trend != 100 {
+		t.Errorf("expected trend +100 from all wrong to all right, got %v", loops.Trend)
+	}
+	if loops.Mastery != MasteryDeveloping {
+		t.Errorf("expected developing mastery, got %s", loops.Mastery)
+	}
+
+	maps := strengths[1]
+	if maps.Trend != 0 || maps.Mastery != MasteryExpert {
+		t.Errorf("expected single answer to have no trend and expert mastery, got %+v", maps)
+	}
+}
+
+func TestWeakAndStrongConcepts(t *testing.T) {
+	weak, strong := WeakAndStrongConcepts([]ConceptStrength{
+		{Concept: "channels", Attempts: 4, Accuracy: 25, Mastery: MasteryNovice},
+		{Concept: "loops", Attempts: 4, Accuracy: 50, Mastery: MasteryDeveloping},
+		{Concept: "maps", Attempts: 3, Accuracy: 100, Mastery: MasteryExpert},
+		{Concept: "slices", Attempts: 5, Accuracy: 80, Mastery: MasteryProficient},
+		{Concept: "structs", Attempts: 1, Accuracy: 0, Mastery: MasteryNovice},
+	})
+
+	if len(weak) != 2 || weak[0] != "channels" || weak[1] != "loops" {
+		t.Errorf("expected weak concepts [channels loops], got %v", weak)
+	}
+	if len(strong) != 1 || strong[0] != "maps" {
+		t.Errorf("expected strong concepts [maps], got %v", strong)
+	}
+}
@@ -23,6 +23,9 @@ func ValidateDifficulty(d Difficulty) error {
 	}
 }
 
+// FolderAuthorID is the author of courses loaded from folders, which have no author account
+const FolderAuthorID = "folder-author"
+
 // QuizQuestion represents a single quiz question with multiple choice options
 type QuizQuestion struct {
 	ID           string
@@ -30,6 +33,16 @@ type QuizQuestion struct {
 	Options      []string
 	CorrectIndex int
 	Explanation  string
+	// AnswerKeyHidden is set on copies returned to learners
+	AnswerKeyHidden bool `json:"-"`
+}
+
+// Redacted returns a copy of the question with its answer key and explanation removed
+func (q QuizQuestion) Redacted() QuizQuestion {
+	q.CorrectIndex = 0
+	q.Explanation = ""
+	q.AnswerKeyHidden = true
+	return q
 }
 
 // Quiz represents a quiz attached to a lesson
@@ -44,6 +57,7 @@ type Lesson struct {
 	Content      string
 	Order        int
 	FolderIndex  int           // Index in alphabetically sorted folder list (used for save path)
+	Folder       string        // Folder the lesson was loaded from, identifying it when saved; empty for new lessons
 	Sublessons   []Lesson      // Nested subchapters/sublessons
 	Quiz         *Quiz         // Optional legacy quiz for this lesson
 	ExtendedQuiz *ExtendedQuiz // Optional extended quiz with multiple question types
@@ -80,6 +94,28 @@ func (l *Lesson) HasSublessons() bool {
 	return len(l.Sublessons) > 0
 }
 
+// EffectiveQuiz returns the quiz learners take in this lesson: the extended quiz, or the
+// legacy quiz converted to an extended one with multiple choice questions, or nil
+func (l *Lesson) EffectiveQuiz() *ExtendedQuiz {
+	if l.ExtendedQuiz != nil || l.Quiz == nil {
+		return l.ExtendedQuiz
+	}
+
+	quiz := &ExtendedQuiz{Version: ExtendedQuizVersion}
+	for _, q := range l.Quiz.Questions {
+		quiz.Questions = append(quiz.Questions, ExtendedQuizQuestion{
+			ID:           q.ID,
+			Type:         QuestionTypeMultipleChoice,
+			Difficulty:   DefaultDifficulty(QuestionTypeMultipleChoice),
+			Question:     q.Question,
+			Options:      q.Options,
+			CorrectIndex: q.CorrectIndex,
+			Explanation:  q.Explanation,
+		})
+	}
+	return quiz
+}
+
 // LibraryCourse represents a course in the shared library
 type LibraryCourse struct {
 	ID             string
@@ -91,6 +127,7 @@ type LibraryCourse struct {
 	Tags           []string
 	Difficulty     Difficulty
 	EstimatedHours int
+	QuizConfig     *QuizConfig // Optional per-course override of DefaultQuizConfig
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 }
@@ -141,6 +178,66 @@ func (c *LibraryCourse) TotalLessonCount() int {
 	return count
 }
 
+// EffectiveQuizConfig returns the course's quiz configuration, falling back to the defaults
+func (c *LibraryCourse) EffectiveQuizConfig() QuizConfig {
+	if c.QuizConfig != nil {
+		return *c.QuizConfig
+	}
+	return DefaultQuizConfig()
+}
+
+// ChapterLessonIndices returns the flat lesson indices covered by a chapter
+// Lessons are numbered depth-first, so a chapter and its sublessons form a contiguous range
+func (c *LibraryCourse) ChapterLessonIndices(chapter int) ([]int, error) {
+	if chapter < 0 || chapter >= len(c.Lessons) {
+		return nil, ErrInvalidLessonIndex
+	}
+
+	start := 0
+	for _, lesson := range c.Lessons[:chapter] {
+		start += lesson.TotalCount()
+	}
+
+	count := c.Lessons[chapter].TotalCount()
+	indices := make([]int, count)
+	for i := range indices {
+		indices[i] = start + i
+	}
+	return indices, nil
+}
+
+// LessonAt returns the lesson addressed by a lesson path
+// The path is [chapter] for a chapter or [chapter, sublesson] for a sublesson
+func (c *LibraryCourse) LessonAt(path []int) (*Lesson, error) {
+	if len(path) == 0 {
+		return nil, ErrInvalidLessonIndex
+	}
+
+	lessons := c.Lessons
+	var lesson *Lesson
+	for _, index := range path {
+		if index < 0 || index >= len(lessons) {
+			return nil, ErrInvalidLessonIndex
+		}
+		lesson = &lessons[index]
+		lessons = lesson.Sublessons
+	}
+
+	return lesson, nil
+}
+
+// CanEditContent reports whether a user may edit the course's lessons and quizzes
+// Folder courses have no author account, so only the configured editors may edit them
+func (c *LibraryCourse) CanEditContent(userID string, editors UserGroup) bool {
+	if userID == "" {
+		return false
+	}
+	if c.AuthorID == FolderAuthorID {
+		return editors.Contains(userID)
+	}
+	return c.AuthorID == userID
+}
+
 // RemoveLesson removes a lesson at the given index
 func (c *LibraryCourse) RemoveLesson(index int) error {
 	if index < 0 || index >= len(c.Lessons) {
@@ -155,6 +252,13 @@ func (c *LibraryCourse) RemoveLesson(index int) error {
 	return nil
 }
 
+// CompletionReason records why a lesson was marked complete
+type CompletionReason string
+
+const (
+	CompletionReasonTestedOut CompletionReason = "tested_out"
+)
+
 // UserCourse represents a user's personal copy of a course with progress tracking
 type UserCourse struct {
 	ID                 string
@@ -162,7 +266,8 @@ type UserCourse struct {
 	LibraryCourseID    string
 	Progress           int // 0-100 percentage
 	CurrentLessonIndex int
-	CompletedLessons   []int // Lesson indices that are completed
+	CompletedLessons   []int                    // Lesson indices that are completed
+	CompletionReasons  map[int]CompletionReason // Why a lesson was completed, when not simply studied
 	StartedAt          time.Time
 	UpdatedAt          time.Time
 	CompletedAt        *time.Time
@@ -184,6 +289,7 @@ func NewUserCourse(userID, libraryCourseID string) (*UserCourse, error) {
 		Progress:           0,
 		CurrentLessonIndex: 0,
 		CompletedLessons:   []int{},
+		CompletionReasons:  map[int]CompletionReason{},
 		StartedAt:          now,
 		UpdatedAt:          now,
 		CompletedAt:        nil,
@@ -245,12 +351,43 @@ func (uc *UserCourse) MarkLessonCompleted(lessonIndex int, totalLessons int) err
 	return nil
 }
 
+// IsLessonCompleted reports whether a lesson is in the completed list
+func (uc *UserCourse) IsLessonCompleted(lessonIndex int) bool {
+	for _, idx := range uc.CompletedLessons {
+		if idx == lessonIndex {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkLessonTestedOut marks a lesson as completed because the user passed a test-out quiz
+// Lessons the user had already completed keep their original reason
+func (uc *UserCourse) MarkLessonTestedOut(lessonIndex int, totalLessons int) error {
+	if uc.IsLessonCompleted(lessonIndex) {
+		return nil
+	}
+
+	if err := uc.MarkLessonCompleted(lessonIndex, totalLessons); err != nil {
+		return err
+	}
+
+	if uc.CompletionReasons == nil {
+		uc.CompletionReasons = map[int]CompletionReason{}
+	}
+	uc.CompletionReasons[lessonIndex] = CompletionReasonTestedOut
+
+	return nil
+}
+
 // MarkLessonIncomplete removes a lesson from completed list and updates progress
 func (uc *UserCourse) MarkLessonIncomplete(lessonIndex int, totalLessons int) error {
 	if lessonIndex < 0 || lessonIndex >= totalLessons {
 		return ErrInvalidLessonIndex
 	}
 
+	delete(uc.CompletionReasons, lessonIndex)
+
 	// Remove from completed lessons
 	newCompleted := []int{}
 	for _, idx := range uc.CompletedLessons {
@@ -0,0 +1,20 @@
+package entities
+
+import "time"
+
+// CourseChangeKind describes what happened to a course whose source changed
+type CourseChangeKind string
+
+const (
+	CourseAdded   CourseChangeKind = "added"
+	CourseUpdated CourseChangeKind = "updated"
+	CourseRemoved CourseChangeKind = "removed"
+)
+
+// CourseChange reports that a course was reloaded from its source, such as an edited
+// course folder
+type CourseChange struct {
+	CourseID  string
+	Kind      CourseChangeKind
+	ChangedAt time.Time
+}
@@ -349,3 +349,149 @@ func TestValidateDifficulty_Invalid(t *testing.T) {
 		t.Errorf("expected ErrInvalidDifficulty, got %v", err)
 	}
 }
+
+func TestLibraryCourse_LessonAt(t *testing.T) {
+	lessons := []Lesson{
+		{Title: "Chapter 1", Content: "Intro", Sublessons: []Lesson{
+			{Title: "Section 1.1", Content: "First"},
+			{Title: "Section 1.2", Content: "Second"},
+		}},
+		{Title: "Chapter 2", Content: "More"},
+	}
+	course, _ := NewLibraryCourse("Title", "Desc", lessons, "Author", "user-123", []string{}, DifficultyBeginner, 5)
+
+	chapter, err := course.LessonAt([]int{1})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if chapter.Title != "Chapter 2" {
+		t.Errorf("expected 'Chapter 2', got '%s'", chapter.Title)
+	}
+
+	sublesson, err := course.LessonAt([]int{0, 1})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if sublesson.Title != "Section 1.2" {
+		t.Errorf("expected 'Section 1.2', got '%s'", sublesson.Title)
+	}
+
+	for _, path := range [][]int{{}, {2}, {-1}, {0, 2}, {1, 0}} {
+		if _, err := course.LessonAt(path); err != ErrInvalidLessonIndex {
+			t.Errorf("path %v: expected ErrInvalidLessonIndex, got %v", path, err)
+		}
+	}
+}
+
+func TestLibraryCourse_ChapterLessonIndices(t *testing.T) {
+	course := &LibraryCourse{
+		Lessons: []Lesson{
+			{Title: "Chapter 1", Sublessons: []Lesson{{Title: "1.1"}, {Title: "1.2"}}},
+			{Title: "Chapter 2"},
+			{Title: "Chapter 3", Sublessons: []Lesson{{Title: "3.1"}}},
+		},
+	}
+
+	tests := []struct {
+		chapter int
+		want    []int
+	}{
+		{0, []int{0, 1, 2}},
+		{1, []int{3}},
+		{2, []int{4, 5}},
+	}
+
+	for _, tt := range tests {
+		got, err := course.ChapterLessonIndices(tt.chapter)
+		if err != nil {
+			t.Fatalf("chapter %d: unexpected error %v", tt.chapter, err)
+		}
+		if len(got) != len(tt.want) {
+			t.Fatalf("chapter %d: expected %v, got %v", tt.chapter, tt.want, got)
+		}
+		for i := range tt.want {
+			if got[i] != tt.want[i] {
+				t.Errorf("chapter %d: expected %v, got %v", tt.chapter, tt.want, got)
+			}
+		}
+	}
+
+	if _, err := course.ChapterLessonIndices(3); err != ErrInvalidLessonIndex {
+		t.Errorf("expected ErrInvalidLessonIndex, got %v", err)
+	}
+}
+
+func TestLibraryCourse_EffectiveQuizConfig(t *testing.T) {
+	course := &LibraryCourse{}
+	if got := course.EffectiveQuizConfig().TestOutThreshold; got != DefaultQuizConfig().TestOutThreshold {
+		t.Errorf("expected default threshold, got %d", got)
+	}
+
+	config := DefaultQuizConfig()
+	config.TestOutThreshold = 90
+	course.QuizConfig = &config
+	if got := course.EffectiveQuizConfig().TestOutThreshold; got != 90 {
+		t.Errorf("expected threshold 90, got %d", got)
+	}
+}
+
+func TestUserCourse_MarkLessonTestedOut(t *testing.T) {
+	uc, _ := NewUserCourse("user-123", "course-456")
+
+	if err := uc.MarkLessonCompleted(0, 4); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := uc.MarkLessonTestedOut(0, 4); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := uc.MarkLessonTestedOut(1, 4); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := uc.CompletionReasons[0]; ok {
+		t.Error("expected an already completed lesson to keep no reason")
+	}
+	if uc.CompletionReasons[1] != CompletionReasonTestedOut {
+		t.Errorf("expected lesson 1 to be tested out, got %q", uc.CompletionReasons[1])
+	}
+	if uc.Progress != 50 {
+		t.Errorf("expected progress 50, got %d", uc.Progress)
+	}
+
+	if err := uc.MarkLessonIncomplete(1, 4); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := uc.CompletionReasons[1]; ok {
+		t.Error("expected reason to be cleared when the lesson is marked incomplete")
+	}
+
+	if err := uc.MarkLessonTestedOut(4, 4); err != ErrInvalidLessonIndex {
+		t.Errorf("expected ErrInvalidLessonIndex, got %v", err)
+	}
+}
+
+func TestLibraryCourse_CanEditContent(t *testing.T) {
+	editors := NewUserGroup([]string{"editor-1"})
+	authored := &LibraryCourse{AuthorID: "author-1"}
+	folder := &LibraryCourse{AuthorID: FolderAuthorID}
+
+	tests := []struct {
+		name   string
+		course *LibraryCourse
+		userID string
+		want   bool
+	}{
+		{"author", authored, "author-1", true},
+		{"editor on an authored course", authored, "editor-1", false},
+		{"learner on an authored course", authored, "user-1", false},
+		{"editor on a folder course", folder, "editor-1", true},
+		{"learner on a folder course", folder, "user-1", false},
+		{"folder author ID", folder, FolderAuthorID, false},
+		{"anonymous", folder, "", false},
+	}
+	for _, tt := range tests {
+		if got := tt.course.CanEditContent(tt.userID, editors); got != tt.want {
+			t.Errorf("%s: CanEditContent(%q) = %v, want %v", tt.name, tt.userID, got, tt.want)
+		}
+	}
+}
@@ -36,3 +36,47 @@ var (
 	ErrInvalidFileType    = errors.New("invalid file type")
 	ErrFileTooLarge       = errors.New("file size exceeds maximum allowed")
 )
+
+// Domain errors - Quiz
+var (
+	ErrQuizNotFound          = errors.New("quiz not found")
+	ErrQuestionNotFound      = errors.New("question not found in quiz")
+	ErrInvalidAnswer         = errors.New("invalid answer for question")
+	ErrDuplicateAnswer       = errors.New("question answered more than once")
+	ErrUnsupportedQuestion   = errors.New("unsupported question type")
+	ErrAnswerKeyHidden       = errors.New("answer is revealed once the question has been answered")
+	ErrReviewItemNotFound    = errors.New("question is not in the review queue")
+	ErrInvalidQuizConfig     = errors.New("invalid quiz configuration")
+	ErrQuizInstanceNotFound  = errors.New("quiz instance not found")
+	ErrQuizAttemptNotFound   = errors.New("quiz attempt not found")
+	ErrCodeRunnerUnavailable = errors.New("code exercises cannot be graded right now")
+	ErrInvalidQuestion       = errors.New("invalid quiz question")
+)
+
+// Domain errors - Exams
+var (
+	ErrNotAnExam             = errors.New("quiz is not an exam")
+	ErrQuizSessionNotFound   = errors.New("quiz session not found")
+	ErrExamSessionRequired   = errors.New("exam answers must be submitted with the session they were started in")
+	ErrQuizSessionClosed     = errors.New("quiz session has already been submitted")
+	ErrExamTimeLimitExceeded = errors.New("exam time limit exceeded")
+	ErrExamAttemptsExhausted = errors.New("no exam attempts left")
+	ErrExamCooldown          = errors.New("next exam attempt is not allowed yet")
+)
+
+// Domain errors - Quiz drafts
+var (
+	ErrQuizDraftNotFound = errors.New("quiz draft not found")
+)
+
+// Domain errors - Question banks
+var (
+	ErrQuestionBankNotFound = errors.New("question bank not found")
+	ErrInvalidBankRef       = errors.New("invalid question bank reference")
+	ErrBankQuestionReadOnly = errors.New("bank questions can only be changed in their bank")
+)
+
+// Domain errors - Lesson revisions
+var (
+	ErrLessonRevisionNotFound = errors.New("lesson revision not found")
+)
@@ -0,0 +1,156 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+// ScoringPolicy decides which attempts make up an exam's score
+type ScoringPolicy string
+
+const (
+	ScoringBest    ScoringPolicy = "best"
+	ScoringLatest  ScoringPolicy = "latest"
+	ScoringAverage ScoringPolicy = "average"
+)
+
+// ExamSubmissionGrace is how long after its time limit a session still accepts a submission,
+// to allow for the request being in flight when the clock runs out
+const ExamSubmissionGrace = 30 * time.Second
+
+// ExamConfig turns a quiz into an exam: each attempt is taken in a timed session started
+// with StartQuizSession, and learners get a limited number of them
+// It is set per quiz in quiz.json ("exam") or for every chapter quiz in course.json
+// (quiz_config.exam); the quiz's own setting wins
+type ExamConfig struct {
+	MaxAttempts      int           `json:"maxAttempts,omitempty"`      // 0 allows unlimited attempts
+	CooldownMinutes  int           `json:"cooldownMinutes,omitempty"`  // Wait after one attempt ends before the next may start
+	TimeLimitMinutes int           `json:"timeLimitMinutes,omitempty"` // 0 leaves attempts untimed
+	ScoringPolicy    ScoringPolicy `json:"scoringPolicy,omitempty"`    // Defaults to best
+}
+
+// Validate checks that limits are not negative and the scoring policy is known
+func (c ExamConfig) Validate() error {
+	if c.MaxAttempts < 0 || c.CooldownMinutes < 0 || c.TimeLimitMinutes < 0 {
+		return fmt.Errorf("%w: exam limits cannot be negative", ErrInvalidQuizConfig)
+	}
+	switch c.ScoringPolicy {
+	case "", ScoringBest, ScoringLatest, ScoringAverage:
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown scoring policy %q", ErrInvalidQuizConfig, c.ScoringPolicy)
+	}
+}
+
+// Policy returns the scoring policy, defaulting to best
+func (c ExamConfig) Policy() ScoringPolicy {
+	if c.ScoringPolicy == "" {
+		return ScoringBest
+	}
+	return c.ScoringPolicy
+}
+
+// Cooldown returns the wait between attempts
+func (c ExamConfig) Cooldown() time.Duration {
+	return time.Duration(c.CooldownMinutes) * time.Minute
+}
+
+// TimeLimit returns how long an attempt may take, or 0 when untimed
+func (c ExamConfig) TimeLimit() time.Duration {
+	return time.Duration(c.TimeLimitMinutes) * time.Minute
+}
+
+// Score combines a learner's attempt percentages following the scoring policy
+// attempts must be ordered newest first; nil means there is no attempt yet
+func (c ExamConfig) Score(attempts []QuizAttempt) *float64 {
+	if len(attempts) == 0 {
+		return nil
+	}
+
+	var score float64
+	switch c.Policy() {
+	case ScoringLatest:
+		score = attempts[0].Percentage
+	case ScoringAverage:
+		for _, a := range attempts {
+			score += a.Percentage
+		}
+		score /= float64(len(attempts))
+	default:
+		for _, a := range attempts {
+			score = max(score, a.Percentage)
+		}
+	}
+	return &score
+}
+
+// ExamConfigFor returns the exam settings of the quiz at lessonPath, or nil if it is open practice
+func (c *LibraryCourse) ExamConfigFor(lessonPath []int) (*ExamConfig, error) {
+	lesson, err := c.LessonAt(lessonPath)
+	if err != nil {
+		return nil, err
+	}
+	if lesson.ExtendedQuiz != nil && lesson.ExtendedQuiz.Exam != nil {
+		return lesson.ExtendedQuiz.Exam, nil
+	}
+	if QuizTypeForLessonPath(lessonPath) == "chapter" {
+		return c.EffectiveQuizConfig().Exam, nil
+	}
+	return nil, nil
+}
+
+// QuizSession is one timed attempt at an exam, started before the questions are answered
+// Every session counts against the exam's attempt cap, whether it is submitted or not
+type QuizSession struct {
+	ID          string
+	UserID      string
+	CourseID    string
+	QuizID      string
+	StartedAt   time.Time
+	ExpiresAt   *time.Time // Nil for untimed exams
+	SubmittedAt *time.Time
+}
+
+// NewQuizSession starts a session for the quiz at lessonPath now
+func NewQuizSession(userID, courseID string, lessonPath []int, exam ExamConfig) *QuizSession {
+	session := &QuizSession{
+		UserID:    userID,
+		CourseID:  courseID,
+		QuizID:    QuizIDForLessonPath(lessonPath),
+		StartedAt: time.Now(),
+	}
+	if limit := exam.TimeLimit(); limit > 0 {
+		expiresAt := session.StartedAt.Add(limit)
+		session.ExpiresAt = &expiresAt
+	}
+	return session
+}
+
+// IsOpen reports whether the session can still be submitted at the given time
+func (s *QuizSession) IsOpen(now time.Time) bool {
+	if s.SubmittedAt != nil {
+		return false
+	}
+	return s.ExpiresAt == nil || !now.After(s.ExpiresAt.Add(ExamSubmissionGrace))
+}
+
+// EndedAt returns when the session was submitted or ran out of time, or nil while it is open
+func (s *QuizSession) EndedAt(now time.Time) *time.Time {
+	if s.SubmittedAt != nil {
+		return s.SubmittedAt
+	}
+	if !s.IsOpen(now) {
+		return s.ExpiresAt
+	}
+	return nil
+}
+
+// ExamStatus is where a learner stands on an exam
+type ExamStatus struct {
+	Config            ExamConfig
+	AttemptsUsed      int
+	AttemptsRemaining *int         // Nil when attempts are unlimited
+	NextAttemptAt     *time.Time   // Set while a cooldown is running
+	OpenSession       *QuizSession // The session in progress, if any
+	Score             *float64     // Submitted attempts combined by the scoring policy
+}
@@ -0,0 +1,87 @@
+package entities
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExamConfig_Score(t *testing.T) {
+	// Newest first
+	attempts := []QuizAttempt{{Percentage: 60}, {Percentage: 90}, {Percentage: 30}}
+
+	tests := []struct {
+		policy   ScoringPolicy
+		expected float64
+	}{
+		{"", 90},
+		{ScoringBest, 90},
+		{ScoringLatest, 60},
+		{ScoringAverage, 60},
+	}
+	for _, tt := range tests {
+		score := ExamConfig{ScoringPolicy: tt.policy}.Score(attempts)
+		if score == nil || *score != tt.expected {
+			t.Errorf("%q: expected %v, got %v", tt.policy, tt.expected, score)
+		}
+	}
+
+	if score := (ExamConfig{}).Score(nil); score != nil {
+		t.Errorf("expected no score without attempts, got %v", *score)
+	}
+}
+
+func TestExamConfig_Validate(t *testing.T) {
+	if err := (ExamConfig{MaxAttempts: 3, ScoringPolicy: ScoringLatest}).Validate(); err != nil {
+		t.Errorf("expected a valid config, got %v", err)
+	}
+	if err := (ExamConfig{TimeLimitMinutes: -1}).Validate(); !errors.Is(err, ErrInvalidQuizConfig) {
+		t.Errorf("expected ErrInvalidQuizConfig for a negative limit, got %v", err)
+	}
+	if err := (ExamConfig{ScoringPolicy: "worst"}).Validate(); !errors.Is(err, ErrInvalidQuizConfig) {
+		t.Errorf("expected ErrInvalidQuizConfig for an unknown policy, got %v", err)
+	}
+}
+
+func TestQuizSession_IsOpen(t *testing.T) {
+	now := time.Now()
+	expiresAt := now.Add(-ExamSubmissionGrace / 2)
+	session := &QuizSession{StartedAt: now.Add(-time.Hour), ExpiresAt: &expiresAt}
+
+	if !session.IsOpen(now) {
+		t.Error("expected a submission within the grace period to be accepted")
+	}
+	if session.IsOpen(now.Add(ExamSubmissionGrace)) {
+		t.Error("expected the session to close after the grace period")
+	}
+	if ended := session.EndedAt(now.Add(ExamSubmissionGrace)); ended == nil || !ended.Equal(expiresAt) {
+		t.Errorf("expected an expired session to end at its time limit, got %v", ended)
+	}
+
+	session.SubmittedAt = &now
+	if session.IsOpen(now) {
+		t.Error("expected a submitted session to be closed")
+	}
+}
+
+func TestLibraryCourse_ExamConfigFor(t *testing.T) {
+	quizExam := &ExamConfig{MaxAttempts: 1}
+	courseExam := &ExamConfig{MaxAttempts: 3}
+	course := &LibraryCourse{
+		QuizConfig: &QuizConfig{Exam: courseExam},
+		Lessons: []Lesson{
+			{Title: "Chapter 1", Sublessons: []Lesson{{Title: "Section 1.1"}}},
+			{Title: "Chapter 2", ExtendedQuiz: &ExtendedQuiz{Exam: quizExam}},
+		},
+	}
+
+	if exam, _ := course.ExamConfigFor([]int{0}); exam != courseExam {
+		t.Errorf("expected the course's exam settings for a chapter quiz, got %+v", exam)
+	}
+	if exam, _ := course.ExamConfigFor([]int{1}); exam != quizExam {
+		t.Errorf("expected the quiz's own exam settings to win, got %+v", exam)
+	}
+	if exam, _ := course.ExamConfigFor([]int{0, 0}); exam != nil {
+		t.Errorf("expected subchapter quizzes to stay open practice, got %+v", exam)
+	}
+}
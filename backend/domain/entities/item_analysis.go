@@ -0,0 +1,333 @@
+package entities
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+)
+
+// MinItemAnalysisResponses is the fewest responses a question needs before it is flagged
+const MinItemAnalysisResponses = 5
+
+// TooEasyDifficultyIndex is the percent correct at which a question stops telling learners apart
+const TooEasyDifficultyIndex = 95.0
+
+// ItemFlag marks a question whose statistics suggest the author should review it
+type ItemFlag string
+
+const (
+	// ItemFlagPossiblyMiskeyed: stronger learners do worse on it, or prefer a distractor to the key
+	ItemFlagPossiblyMiskeyed ItemFlag = "possibly_miskeyed"
+	// ItemFlagTooEasy: nearly everyone answers it correctly
+	ItemFlagTooEasy ItemFlag = "too_easy"
+)
+
+// ItemResponse is a graded response together with the overall score of its attempt
+type ItemResponse struct {
+	QuizResponse
+	CourseID          string
+	AttemptPercentage float64
+}
+
+// OptionFrequency is how often learners selected one option of a question
+type OptionFrequency struct {
+	Index int
+	Label string
+	IsKey bool
+	Count int
+	Rate  float64 // Percent of answered responses that selected the option
+}
+
+// ConfidenceAccuracy compares how sure learners said they were with how often they were right
+type ConfidenceAccuracy struct {
+	Confidence   ConfidenceLevel
+	Responses    int
+	CorrectCount int
+	Accuracy     float64 // Percent correct
+}
+
+// ItemStatistics summarises how one question performed across all attempts
+type ItemStatistics struct {
+	QuestionID      string
+	QuestionType    QuestionType
+	Concept         string
+	Question        string
+	Responses       int
+	Skipped         int      // Responses submitted without an answer
+	DifficultyIndex float64  // Percent of responses that were correct
+	Discrimination  float64  // Point-biserial correlation of correctness with the attempt score
+	MeanTimeSeconds *float64 // Nil when no response recorded a time
+	Options         []OptionFrequency
+	Confidence      []ConfidenceAccuracy
+	Flags           []ItemFlag
+}
+
+// QuizItemAnalysis is the item analysis report for one quiz
+type QuizItemAnalysis struct {
+	CourseID string
+	QuizID   string
+	Attempts int
+	Items    []ItemStatistics
+}
+
+// ComputeItemAnalysis computes per-question statistics for a quiz's responses
+// Questions are reported in the given order, including those nobody has answered yet
+func ComputeItemAnalysis(courseID, quizID string, questions []ExtendedQuizQuestion, responses []ItemResponse) *QuizItemAnalysis {
+	attempts := make(map[string]bool)
+	byQuestion := make(map[string][]ItemResponse)
+	for _, r := range responses {
+		attempts[r.AttemptID] = true
+		byQuestion[r.QuestionID] = append(byQuestion[r.QuestionID], r)
+	}
+
+	analysis := &QuizItemAnalysis{
+		CourseID: courseID,
+		QuizID:   quizID,
+		Attempts: len(attempts),
+		Items:    make([]ItemStatistics, 0, len(questions)),
+	}
+	for i := range questions {
+		analysis.Items = append(analysis.Items, computeItemStatistics(&questions[i], byQuestion[questions[i].ID]))
+	}
+
+	return analysis
+}
+
+func computeItemStatistics(q *ExtendedQuizQuestion, responses []ItemResponse) ItemStatistics {
+	stats := ItemStatistics{
+		QuestionID:   q.ID,
+		QuestionType: q.Type,
+		Concept:      q.Concept,
+		Question:     q.Question,
+		Responses:    len(responses),
+	}
+	if len(responses) == 0 {
+		return stats
+	}
+
+	correct := 0
+	timed, totalTime := 0, 0
+	for _, r := range responses {
+		if r.IsCorrect {
+			correct++
+		}
+		if isSkipped(r.UserAnswer) {
+			stats.Skipped++
+		}
+		if r.TimeTakenSec > 0 {
+			timed++
+			totalTime += r.TimeTakenSec
+		}
+	}
+
+	stats.DifficultyIndex = float64(correct) / float64(len(responses)) * 100
+	stats.Discrimination = pointBiserial(responses)
+	if timed > 0 {
+		mean := float64(totalTime) / float64(timed)
+		stats.MeanTimeSeconds = &mean
+	}
+	stats.Options = optionFrequencies(q, responses)
+	stats.Confidence = confidenceAccuracy(responses)
+
+	if len(responses) >= MinItemAnalysisResponses {
+		if stats.Discrimination < 0 || topScorersPreferDistractor(q, responses) {
+			stats.Flags = append(stats.Flags, ItemFlagPossiblyMiskeyed)
+		}
+		if stats.DifficultyIndex >= TooEasyDifficultyIndex {
+			stats.Flags = append(stats.Flags, ItemFlagTooEasy)
+		}
+	}
+
+	return stats
+}
+
+// pointBiserial correlates answering the question correctly with the attempt's percentage
+// It is zero when everyone, or no one, got the question right
+func pointBiserial(responses []ItemResponse) float64 {
+	n := float64(len(responses))
+	var sum, sumCorrect, sumWrong float64
+	var nCorrect float64
+	for _, r := range responses {
+		sum += r.AttemptPercentage
+		if r.IsCorrect {
+			nCorrect++
+			sumCorrect += r.AttemptPercentage
+		} else {
+			sumWrong += r.AttemptPercentage
+		}
+	}
+	nWrong := n - nCorrect
+	if nCorrect == 0 || nWrong == 0 {
+		return 0
+	}
+
+	mean := sum / n
+	var variance float64
+	for _, r := range responses {
+		variance += (r.AttemptPercentage - mean) * (r.AttemptPercentage - mean)
+	}
+	sd := math.Sqrt(variance / n)
+	if sd == 0 {
+		return 0
+	}
+
+	p := nCorrect / n
+	return (sumCorrect/nCorrect - sumWrong/nWrong) / sd * math.Sqrt(p*(1-p))
+}
+
+// optionFrequencies counts how often each option was selected, for question types with options
+func optionFrequencies(q *ExtendedQuizQuestion, responses []ItemResponse) []OptionFrequency {
+	labels, keys := optionKey(q)
+	if labels == nil {
+		return nil
+	}
+
+	counts := make([]int, len(labels))
+	answered := 0
+	for _, r := range responses {
+		selected, ok := selectedOptions(q, r.UserAnswer)
+		if !ok {
+			continue
+		}
+		answered++
+		for _, index := range selected {
+			if index >= 0 && index < len(counts) {
+				counts[index]++
+			}
+		}
+	}
+
+	frequencies := make([]OptionFrequency, len(labels))
+	for i, label := range labels {
+		frequencies[i] = OptionFrequency{Index: i, Label: label, IsKey: keys[i], Count: counts[i]}
+		if answered > 0 {
+			frequencies[i].Rate = float64(counts[i]) / float64(answered) * 100
+		}
+	}
+	return frequencies
+}
+
+// optionKey returns the option labels of a question and which of them are keyed correct
+// True/false questions are treated as two options, True then False
+func optionKey(q *ExtendedQuizQuestion) ([]string, []bool) {
+	switch q.Type {
+	case QuestionTypeMultipleChoice, QuestionTypeCodeAnalysis:
+		keys := make([]bool, len(q.Options))
+		if q.CorrectIndex >= 0 && q.CorrectIndex < len(keys) {
+			keys[q.CorrectIndex] = true
+		}
+		return q.Options, keys
+	case QuestionTypeMultipleSelect:
+		keys := make([]bool, len(q.Options))
+		for _, index := range q.CorrectIndices {
+			if index >= 0 && index < len(keys) {
+				keys[index] = true
+			}
+		}
+		return q.Options, keys
+	case QuestionTypeTrueFalse:
+		keys := make([]bool, 2)
+		if q.CorrectAnswer != nil {
+			if *q.CorrectAnswer {
+				keys[0] = true
+			} else {
+				keys[1] = true
+			}
+		}
+		return []string{"True", "False"}, keys
+	default:
+		return nil, nil
+	}
+}
+
+// selectedOptions decodes a stored answer into the option indices it selected
+func selectedOptions(q *ExtendedQuizQuestion, answer json.RawMessage) ([]int, bool) {
+	if isSkipped(answer) {
+		return nil, false
+	}
+
+	switch q.Type {
+	case QuestionTypeMultipleChoice, QuestionTypeCodeAnalysis:
+		var index int
+		if err := json.Unmarshal(answer, &index); err != nil {
+			return nil, false
+		}
+		return []int{index}, true
+	case QuestionTypeMultipleSelect:
+		var indices []int
+		if err := json.Unmarshal(answer, &indices); err != nil {
+			return nil, false
+		}
+		return indices, true
+	case QuestionTypeTrueFalse:
+		var value bool
+		if err := json.Unmarshal(answer, &value); err != nil {
+			return nil, false
+		}
+		if value {
+			return []int{0}, true
+		}
+		return []int{1}, true
+	default:
+		return nil, false
+	}
+}
+
+// topScorersPreferDistractor reports whether, among the better-scoring half of the
+// responses, some wrong option of a single-answer question was chosen more than the key
+func topScorersPreferDistractor(q *ExtendedQuizQuestion, responses []ItemResponse) bool {
+	if q.Type == QuestionTypeMultipleSelect {
+		return false
+	}
+	if labels, _ := optionKey(q); labels == nil {
+		return false
+	}
+
+	sorted := make([]ItemResponse, len(responses))
+	copy(sorted, responses)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].AttemptPercentage > sorted[j].AttemptPercentage
+	})
+	top := sorted[:(len(sorted)+1)/2]
+
+	frequencies := optionFrequencies(q, top)
+	keyCount := 0
+	for _, f := range frequencies {
+		if f.IsKey && f.Count > keyCount {
+			keyCount = f.Count
+		}
+	}
+	for _, f := range frequencies {
+		if !f.IsKey && f.Count > keyCount {
+			return true
+		}
+	}
+	return false
+}
+
+// confidenceAccuracy groups responses by the confidence the learner reported
+func confidenceAccuracy(responses []ItemResponse) []ConfidenceAccuracy {
+	var result []ConfidenceAccuracy
+	for _, level := range []ConfidenceLevel{ConfidenceLow, ConfidenceMedium, ConfidenceHigh} {
+		entry := ConfidenceAccuracy{Confidence: level}
+		for _, r := range responses {
+			if r.Confidence != level {
+				continue
+			}
+			entry.Responses++
+			if r.IsCorrect {
+				entry.CorrectCount++
+			}
+		}
+		if entry.Responses == 0 {
+			continue
+		}
+		entry.Accuracy = float64(entry.CorrectCount) / float64(entry.Responses) * 100
+		result = append(result, entry)
+	}
+	return result
+}
+
+func isSkipped(answer json.RawMessage) bool {
+	return len(answer) == 0 || string(answer) == "null"
+}
@@ -0,0 +1,139 @@
+package entities
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestComputeItemAnalysis(t *testing.T) {
+	questions := []ExtendedQuizQuestion{
+		{ID: "good", Type: QuestionTypeMultipleChoice, Options: []string{"a", "b", "c"}, CorrectIndex: 0},
+		{ID: "miskeyed", Type: QuestionTypeMultipleChoice, Options: []string{"a", "b", "c"}, CorrectIndex: 0},
+		{ID: "easy", Type: QuestionTypeMultipleChoice, Options: []string{"a", "b"}, CorrectIndex: 1},
+		{ID: "unanswered", Type: QuestionTypeOrdering},
+	}
+
+	// Six attempts from strongest to weakest
+	percentages := []float64{90, 80, 70, 40, 30, 20}
+	goodAnswers := []int{0, 0, 0, 1, 2, 1}
+	miskeyedAnswers := []int{1, 1, 1, 0, 0, 2}
+
+	var responses []ItemResponse
+	for i, pct := range percentages {
+		attemptID := fmt.Sprintf("attempt-%d", i)
+		add := func(questionID string, answer string, correct bool, confidence ConfidenceLevel, seconds int) {
+			responses = append(responses, ItemResponse{
+				QuizResponse: QuizResponse{
+					AttemptID:    attemptID,
+					QuestionID:   questionID,
+					UserAnswer:   json.RawMessage(answer),
+					IsCorrect:    correct,
+					Confidence:   confidence,
+					TimeTakenSec: seconds,
+				},
+				AttemptPercentage: pct,
+			})
+		}
+		add("good", fmt.Sprint(goodAnswers[i]), goodAnswers[i] == 0, ConfidenceHigh, 10*(i+1))
+		add("miskeyed", fmt.Sprint(miskeyedAnswers[i]), miskeyedAnswers[i] == 0, ConfidenceLow, 0)
+		add("easy", "1", true, "", 5)
+	}
+	responses[len(responses)-1].UserAnswer = json.RawMessage("null")
+	responses[len(responses)-1].IsCorrect = false
+
+	analysis := ComputeItemAnalysis("course-1", "lesson-00", questions, responses)
+
+	if analysis.Attempts != 6 {
+		t.Errorf("expected 6 attempts, got %d", analysis.Attempts)
+	}
+	if len(analysis.Items) != 4 {
+		t.Fatalf("expected 4 items, got %d", len(analysis.Items))
+	}
+
+	good := analysis.Items[0]
+	if good.DifficultyIndex != 50 {
+		t.Errorf("expected difficulty index 50, got %v", good.DifficultyIndex)
+	}
+	if good.Discrimination <= 0.5 {
+		t.Errorf("expected strong positive discrimination, got %v", good.Discrimination)
+	}
+	if good.MeanTimeSeconds == nil || *good.MeanTimeSeconds != 35 {
+		t.Errorf("expected mean time 35s, got %v", good.MeanTimeSeconds)
+	}
+	if good.Options[0].Count != 3 || !good.Options[0].IsKey || good.Options[1].Count != 2 || good.Options[2].Count != 1 {
+		t.Errorf("unexpected option frequencies %+v", good.Options)
+	}
+	if len(good.Confidence) != 1 || good.Confidence[0].Confidence != ConfidenceHigh || good.Confidence[0].Accuracy != 50 {
+		t.Errorf("unexpected confidence breakdown %+v", good.Confidence)
+	}
+	if len(good.Flags) != 0 {
+		t.Errorf("expected no flags for a good question, got %v", good.Flags)
+	}
+
+	miskeyed := analysis.Items[1]
+	if miskeyed.Discrimination >= 0 {
+		t.Errorf("expected negative discrimination, got %v", miskeyed.Discrimination)
+	}
+	if miskeyed.MeanTimeSeconds != nil {
+		t.Errorf("expected no mean time without timings, got %v", *miskeyed.MeanTimeSeconds)
+	}
+	if !hasFlag(miskeyed.Flags, ItemFlagPossiblyMiskeyed) {
+		t.Errorf("expected miskeyed flag, got %v", miskeyed.Flags)
+	}
+
+	easy := analysis.Items[2]
+	if easy.Skipped != 1 {
+		t.Errorf("expected 1 skipped response, got %d", easy.Skipped)
+	}
+	if math.Abs(easy.DifficultyIndex-500.0/6) > 1e-9 {
+		t.Errorf("expected difficulty index 83.3, got %v", easy.DifficultyIndex)
+	}
+	if easy.Options[1].Rate != 100 {
+		t.Errorf("expected skipped answers to be left out of option rates, got %v", easy.Options[1].Rate)
+	}
+
+	unanswered := analysis.Items[3]
+	if unanswered.Responses != 0 || unanswered.Options != nil || unanswered.Flags != nil {
+		t.Errorf("expected an empty entry for an unanswered question, got %+v", unanswered)
+	}
+}
+
+func TestComputeItemAnalysis_TooEasy(t *testing.T) {
+	trueAnswer := true
+	questions := []ExtendedQuizQuestion{{ID: "tf", Type: QuestionTypeTrueFalse, CorrectAnswer: &trueAnswer}}
+
+	var responses []ItemResponse
+	for i := 0; i < MinItemAnalysisResponses; i++ {
+		responses = append(responses, ItemResponse{
+			QuizResponse:      QuizResponse{AttemptID: fmt.Sprint(i), QuestionID: "tf", UserAnswer: json.RawMessage("true"), IsCorrect: true},
+			AttemptPercentage: float64(50 + i*10),
+		})
+	}
+
+	item := ComputeItemAnalysis("course-1", "lesson-00", questions, responses).Items[0]
+	if !hasFlag(item.Flags, ItemFlagTooEasy) {
+		t.Errorf("expected too easy flag, got %v", item.Flags)
+	}
+	if item.Discrimination != 0 {
+		t.Errorf("expected zero discrimination when everyone is correct, got %v", item.Discrimination)
+	}
+	if item.Options[0].Label != "True" || item.Options[0].Count != MinItemAnalysisResponses {
+		t.Errorf("unexpected option frequencies %+v", item.Options)
+	}
+
+	item = ComputeItemAnalysis("course-1", "lesson-00", questions, responses[:MinItemAnalysisResponses-1]).Items[0]
+	if len(item.Flags) != 0 {
+		t.Errorf("expected no flags below the minimum number of responses, got %v", item.Flags)
+	}
+}
+
+func hasFlag(flags []ItemFlag, flag ItemFlag) bool {
+	for _, f := range flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
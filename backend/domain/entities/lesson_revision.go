@@ -0,0 +1,14 @@
+package entities
+
+import "time"
+
+// LessonRevision is one saved version of a lesson's content
+// Revisions are numbered from 1 in the order they were saved and are never changed;
+// restoring an old revision saves its content as a new one
+type LessonRevision struct {
+	ID        int
+	AuthorID  string // Empty for the content a lesson had before its history was kept
+	Message   string
+	Content   string
+	CreatedAt time.Time
+}
@@ -0,0 +1,216 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"slices"
+	"sort"
+)
+
+// bankIDPattern is the question ID pattern without '.', which separates a bank's ID from
+// the IDs of its questions
+var bankIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// IsValidBankID reports whether id can be used as a question bank ID
+func IsValidBankID(id string) bool {
+	return bankIDPattern.MatchString(id)
+}
+
+// BankQuestionID returns the ID a bank question has in the quizzes that use it
+// Every course records answers under the same ID, so they can be analysed together
+func BankQuestionID(bankID, questionID string) string {
+	return bankID + "." + questionID
+}
+
+// QuestionBank is a named set of questions that lesson quizzes of any course can reference
+// Question IDs are unique within the bank and stay stable, since answers are recorded by them
+type QuestionBank struct {
+	ID          string
+	Title       string
+	Description string
+	AuthorID    string
+	Questions   []ExtendedQuizQuestion // IDs as in the bank, without the bank prefix
+}
+
+// CanEditContent reports whether a user may see the bank's answer keys and statistics
+// Banks loaded from folders have no author account, so only the configured editors may,
+// as with folder courses
+func (b *QuestionBank) CanEditContent(userID string, editors UserGroup) bool {
+	if userID == "" {
+		return false
+	}
+	if b.AuthorID == FolderAuthorID {
+		return editors.Contains(userID)
+	}
+	return b.AuthorID == userID
+}
+
+// Tags returns every tag used in the bank, sorted
+func (b *QuestionBank) Tags() []string {
+	tags := []string{}
+	for _, q := range b.Questions {
+		for _, tag := range q.Tags {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// QuestionBankRef pulls questions from a bank into a lesson quiz, either listed by ID or
+// drawn from those carrying every one of the tags
+type QuestionBankRef struct {
+	Bank      string   `json:"bank"`
+	Questions []string `json:"questions,omitempty"` // IDs within the bank
+	Tags      []string `json:"tags,omitempty"`      // Draw rule; no tags draws from the whole bank
+	Count     int      `json:"count,omitempty"`     // Questions to draw; 0 takes every match
+}
+
+// Validate checks that the reference names a bank and is either a list or a draw rule
+func (r QuestionBankRef) Validate() error {
+	if !IsValidBankID(r.Bank) {
+		return fmt.Errorf("%w: bank %q is not a valid bank ID", ErrInvalidBankRef, r.Bank)
+	}
+	if len(r.Questions) > 0 && (len(r.Tags) > 0 || r.Count != 0) {
+		return fmt.Errorf("%w: bank %s: list questions or give a draw rule, not both", ErrInvalidBankRef, r.Bank)
+	}
+	if r.Count < 0 {
+		return fmt.Errorf("%w: bank %s: count cannot be negative", ErrInvalidBankRef, r.Bank)
+	}
+	return nil
+}
+
+// Select returns the bank questions a reference picks, with their quiz IDs and bank set
+// Draws are made by ranking the matching questions on a hash of salt and their IDs, so a
+// quiz draws the same questions every time it is loaded, and adding questions to the bank
+// only swaps in those that rank higher; questions in exclude are never drawn
+func (b *QuestionBank) Select(ref QuestionBankRef, salt string, exclude map[string]bool) ([]ExtendedQuizQuestion, error) {
+	var selected []ExtendedQuizQuestion
+	if len(ref.Questions) > 0 {
+		for _, id := range ref.Questions {
+			index := slices.IndexFunc(b.Questions, func(q ExtendedQuizQuestion) bool { return q.ID == id })
+			if index < 0 {
+				return nil, fmt.Errorf("%w: %s has no question %s", ErrQuestionNotFound, b.ID, id)
+			}
+			selected = append(selected, b.Questions[index])
+		}
+	} else {
+		for _, q := range b.Questions {
+			if !exclude[BankQuestionID(b.ID, q.ID)] && hasAllTags(q.Tags, ref.Tags) {
+				selected = append(selected, q)
+			}
+		}
+		rank := func(q ExtendedQuizQuestion) uint64 {
+			h := fnv.New64a()
+			h.Write([]byte(salt + "/" + q.ID))
+			return h.Sum64()
+		}
+		sort.SliceStable(selected, func(i, j int) bool { return rank(selected[i]) < rank(selected[j]) })
+		if ref.Count > 0 && ref.Count < len(selected) {
+			selected = selected[:ref.Count]
+		}
+	}
+
+	questions := make([]ExtendedQuizQuestion, len(selected))
+	for i, q := range selected {
+		q.ID = BankQuestionID(b.ID, q.ID)
+		q.BankID = b.ID
+		questions[i] = q
+	}
+	return questions, nil
+}
+
+// AddBankQuestions appends the questions picked by the quiz's bank references to its own
+// questions, in reference order, skipping any question already in the quiz
+// References that cannot be resolved are skipped and reported together in the error
+func (q *ExtendedQuiz) AddBankQuestions(banks map[string]*QuestionBank, salt string) error {
+	taken := make(map[string]bool, len(q.Questions))
+	for _, question := range q.Questions {
+		taken[question.ID] = true
+	}
+
+	var errs []error
+	for _, ref := range q.BankRefs {
+		if err := ref.Validate(); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		bank, ok := banks[ref.Bank]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrQuestionBankNotFound, ref.Bank))
+			continue
+		}
+		questions, err := bank.Select(ref, salt, taken)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, question := range questions {
+			if !taken[question.ID] {
+				taken[question.ID] = true
+				q.Questions = append(q.Questions, question)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// OwnQuestions returns the questions written into the quiz itself, leaving out those
+// pulled from banks
+func (q *ExtendedQuiz) OwnQuestions() []ExtendedQuizQuestion {
+	own := make([]ExtendedQuizQuestion, 0, len(q.Questions))
+	for _, question := range q.Questions {
+		if question.BankID == "" {
+			own = append(own, question)
+		}
+	}
+	return own
+}
+
+func hasAllTags(tags, required []string) bool {
+	for _, tag := range required {
+		if !slices.Contains(tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// QuestionBankAnalysis is the item analysis of a bank's questions across every course
+// whose quizzes use them
+type QuestionBankAnalysis struct {
+	BankID   string
+	Courses  []string // Courses with recorded answers to the bank's questions
+	Attempts int
+	Items    []ItemStatistics // Question IDs are those used in quizzes, with the bank prefix
+}
+
+// ComputeQuestionBankAnalysis computes per-question statistics for a bank from responses
+// recorded in any course; every bank question is reported, answered or not
+func ComputeQuestionBankAnalysis(bank *QuestionBank, responses []ItemResponse) *QuestionBankAnalysis {
+	questions := make([]ExtendedQuizQuestion, len(bank.Questions))
+	for i, q := range bank.Questions {
+		q.ID = BankQuestionID(bank.ID, q.ID)
+		questions[i] = q
+	}
+
+	courses := []string{}
+	for _, r := range responses {
+		if !slices.Contains(courses, r.CourseID) {
+			courses = append(courses, r.CourseID)
+		}
+	}
+	sort.Strings(courses)
+
+	items := ComputeItemAnalysis("", "", questions, responses)
+	return &QuestionBankAnalysis{
+		BankID:   bank.ID,
+		Courses:  courses,
+		Attempts: items.Attempts,
+		Items:    items.Items,
+	}
+}
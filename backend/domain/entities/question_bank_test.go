@@ -0,0 +1,136 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func newTestBank() *QuestionBank {
+	bank := &QuestionBank{ID: "go-basics", AuthorID: FolderAuthorID}
+	for i := 1; i <= 6; i++ {
+		tags := []string{"slices"}
+		if i%2 == 0 {
+			tags = append(tags, "append")
+		}
+		bank.Questions = append(bank.Questions, ExtendedQuizQuestion{
+			ID:           fmt.Sprintf("q%d", i),
+			Type:         QuestionTypeMultipleChoice,
+			Difficulty:   2,
+			Question:     fmt.Sprintf("Question %d", i),
+			Options:      []string{"a", "b"},
+			CorrectIndex: 1,
+			Tags:         tags,
+		})
+	}
+	return bank
+}
+
+func TestQuestionBank_SelectByID(t *testing.T) {
+	bank := newTestBank()
+
+	questions, err := bank.Select(QuestionBankRef{Bank: bank.ID, Questions: []string{"q3", "q1"}}, "", nil)
+	if err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	if len(questions) != 2 || questions[0].ID != "go-basics.q3" || questions[1].ID != "go-basics.q1" {
+		t.Errorf("expected q3 and q1 in order with the bank prefix, got %+v", questions)
+	}
+	if questions[0].BankID != bank.ID || bank.Questions[2].ID != "q3" {
+		t.Errorf("expected copies marked with the bank, got %+v", questions[0])
+	}
+
+	if _, err := bank.Select(QuestionBankRef{Bank: bank.ID, Questions: []string{"missing"}}, "", nil); !errors.Is(err, ErrQuestionNotFound) {
+		t.Errorf("expected ErrQuestionNotFound for an unknown question, got %v", err)
+	}
+}
+
+func TestQuestionBank_Draw(t *testing.T) {
+	bank := newTestBank()
+	ref := QuestionBankRef{Bank: bank.ID, Tags: []string{"slices", "append"}, Count: 2}
+
+	first, err := bank.Select(ref, "course-a/lesson-00", nil)
+	if err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	if len(first) != 2 {
+		t.Fatalf("expected 2 questions, got %d", len(first))
+	}
+	for _, q := range first {
+		if !hasAllTags(q.Tags, ref.Tags) {
+			t.Errorf("expected only questions tagged %v, got %s %v", ref.Tags, q.ID, q.Tags)
+		}
+	}
+
+	again, _ := bank.Select(ref, "course-a/lesson-00", nil)
+	if again[0].ID != first[0].ID || again[1].ID != first[1].ID {
+		t.Errorf("expected the same draw for the same salt, got %s, %s", again[0].ID, again[1].ID)
+	}
+
+	all, _ := bank.Select(QuestionBankRef{Bank: bank.ID}, "salt", map[string]bool{"go-basics.q1": true})
+	if len(all) != 5 {
+		t.Errorf("expected every question but the excluded one, got %d", len(all))
+	}
+}
+
+func TestExtendedQuiz_AddBankQuestions(t *testing.T) {
+	bank := newTestBank()
+	quiz := &ExtendedQuiz{
+		Questions: []ExtendedQuizQuestion{{ID: "own", Type: QuestionTypeTrueFalse, Difficulty: 1, Question: "Own?"}},
+		BankRefs: []QuestionBankRef{
+			{Bank: bank.ID, Questions: []string{"q2"}},
+			{Bank: bank.ID, Tags: []string{"append"}},
+			{Bank: "missing", Questions: []string{"q1"}},
+		},
+	}
+
+	err := quiz.AddBankQuestions(map[string]*QuestionBank{bank.ID: bank}, "salt")
+	if !errors.Is(err, ErrQuestionBankNotFound) {
+		t.Errorf("expected the missing bank to be reported, got %v", err)
+	}
+	// own, q2, then the other two questions tagged append
+	if len(quiz.Questions) != 4 || quiz.Questions[1].ID != "go-basics.q2" {
+		t.Fatalf("expected 4 questions with q2 listed first, got %+v", quiz.Questions)
+	}
+	if own := quiz.OwnQuestions(); len(own) != 1 || own[0].ID != "own" {
+		t.Errorf("expected only the quiz's own question, got %+v", own)
+	}
+}
+
+func TestQuestionBankRef_Validate(t *testing.T) {
+	tests := []struct {
+		ref   QuestionBankRef
+		valid bool
+	}{
+		{QuestionBankRef{Bank: "go-basics", Questions: []string{"q1"}}, true},
+		{QuestionBankRef{Bank: "go-basics", Tags: []string{"slices"}, Count: 3}, true},
+		{QuestionBankRef{Bank: "go.basics"}, false},
+		{QuestionBankRef{Bank: "go-basics", Questions: []string{"q1"}, Count: 1}, false},
+		{QuestionBankRef{Bank: "go-basics", Count: -1}, false},
+	}
+	for _, tt := range tests {
+		err := tt.ref.Validate()
+		if tt.valid && err != nil {
+			t.Errorf("%+v: expected valid, got %v", tt.ref, err)
+		}
+		if !tt.valid && !errors.Is(err, ErrInvalidBankRef) {
+			t.Errorf("%+v: expected ErrInvalidBankRef, got %v", tt.ref, err)
+		}
+	}
+}
+
+func TestComputeQuestionBankAnalysis(t *testing.T) {
+	bank := newTestBank()
+	responses := []ItemResponse{
+		{QuizResponse: QuizResponse{AttemptID: "a1", QuestionID: "go-basics.q1", IsCorrect: true}, CourseID: "course-b", AttemptPercentage: 100},
+		{QuizResponse: QuizResponse{AttemptID: "a2", QuestionID: "go-basics.q1", IsCorrect: false}, CourseID: "course-a", AttemptPercentage: 0},
+	}
+
+	analysis := ComputeQuestionBankAnalysis(bank, responses)
+	if len(analysis.Courses) != 2 || analysis.Courses[0] != "course-a" || analysis.Attempts != 2 {
+		t.Errorf("expected 2 attempts from 2 courses, got %+v", analysis)
+	}
+	if len(analysis.Items) != 6 || analysis.Items[0].Responses != 2 || analysis.Items[0].DifficultyIndex != 50 {
+		t.Errorf("expected q1 answered twice, half correctly, got %+v", analysis.Items[0])
+	}
+}
@@ -2,6 +2,9 @@ package entities
 
 import (
 	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -15,6 +18,10 @@ const (
 	QuestionTypeCodeAnalysis   QuestionType = "code_analysis"
 	QuestionTypeMatching       QuestionType = "matching"
 	QuestionTypeOrdering       QuestionType = "ordering"
+	QuestionTypeFillBlank      QuestionType = "fill_blank"
+	QuestionTypeNumeric        QuestionType = "numeric"
+	QuestionTypeShortAnswer    QuestionType = "short_answer"
+	QuestionTypeCodeExercise   QuestionType = "code_exercise"
 )
 
 // ConfidenceLevel represents student confidence in their answer
@@ -83,6 +90,54 @@ type ExtendedQuizQuestion struct {
 	// For ordering
 	Items        []string `json:"items,omitempty"`
 	CorrectOrder []int    `json:"correctOrder,omitempty"`
+
+	// For fill_blank and short_answer: answers are compared after trimming and collapsing
+	// whitespace, and ignoring case unless CaseSensitive; AnswerPattern is an optional regular
+	// expression that a fill_blank answer may match in full instead
+	AcceptedAnswers []string `json:"acceptedAnswers,omitempty"`
+	CaseSensitive   bool     `json:"caseSensitive,omitempty"`
+	AnswerPattern   string   `json:"answerPattern,omitempty"`
+
+	// For short_answer: groups of interchangeable terms, e.g. [["func", "function"]]
+	Synonyms [][]string `json:"synonyms,omitempty"`
+
+	// For numeric: an answer is correct within the larger of the absolute tolerance and the
+	// relative tolerance (a fraction of CorrectValue); Unit, if set, may follow the number
+	CorrectValue      *float64 `json:"correctValue,omitempty"`
+	AbsoluteTolerance float64  `json:"absoluteTolerance,omitempty"`
+	RelativeTolerance float64  `json:"relativeTolerance,omitempty"`
+	Unit              string   `json:"unit,omitempty"`
+
+	// For code_exercise: the learner edits StarterCode and the submission is graded by
+	// running TestCode against it with go test; TestCode is never shown to learners
+	StarterCode string `json:"starterCode,omitempty"`
+	TestCode    string `json:"testCode,omitempty"`
+
+	// Tags group questions for bank draw rules
+	Tags []string `json:"tags,omitempty"`
+
+	// BankID is set on questions pulled into a quiz from a question bank
+	BankID string `json:"bankId,omitempty"`
+
+	// AnswerKeyHidden is set on copies returned to learners who have not answered yet
+	AnswerKeyHidden bool `json:"-"`
+}
+
+// Redacted returns a copy of the question with its answer key and explanation removed
+func (q ExtendedQuizQuestion) Redacted() ExtendedQuizQuestion {
+	q.CorrectIndex = 0
+	q.CorrectAnswer = nil
+	q.CorrectIndices = nil
+	q.CorrectPairs = nil
+	q.CorrectOrder = nil
+	q.AcceptedAnswers = nil
+	q.AnswerPattern = ""
+	q.Synonyms = nil
+	q.CorrectValue = nil
+	q.TestCode = ""
+	q.Explanation = ""
+	q.AnswerKeyHidden = true
+	return q
 }
 
 // ExtendedQuiz represents a quiz with extended question types
@@ -91,6 +146,120 @@ type ExtendedQuiz struct {
 	SubchapterID string                 `json:"subchapterId"`
 	LessonID     string                 `json:"lessonId"`
 	Questions    []ExtendedQuizQuestion `json:"questions"`
+	Exam         *ExamConfig            `json:"exam,omitempty"` // Set when the quiz is taken as an exam
+
+	// BankRefs pull shared questions into the quiz; the questions they pick follow the
+	// quiz's own in Questions
+	BankRefs []QuestionBankRef `json:"bankRefs,omitempty"`
+}
+
+// FindQuestion returns the question with the given ID
+func (q *ExtendedQuiz) FindQuestion(id string) (*ExtendedQuizQuestion, error) {
+	for i := range q.Questions {
+		if q.Questions[i].ID == id {
+			return &q.Questions[i], nil
+		}
+	}
+	return nil, ErrQuestionNotFound
+}
+
+// QuizIDForLessonPath returns the quiz ID used to record attempts for a lesson
+// e.g. [0] -> "lesson-00", [0, 1] -> "lesson-00-sub-01"
+func QuizIDForLessonPath(path []int) string {
+	if len(path) == 0 {
+		return ""
+	}
+	id := fmt.Sprintf("lesson-%02d", path[0])
+	for _, index := range path[1:] {
+		id += fmt.Sprintf("-sub-%02d", index)
+	}
+	return id
+}
+
+// LessonPathForQuizID is the inverse of QuizIDForLessonPath
+func LessonPathForQuizID(quizID string) ([]int, error) {
+	parts := strings.Split(quizID, "-sub-")
+	var chapter int
+	if _, err := fmt.Sscanf(parts[0], "lesson-%d", &chapter); err != nil {
+		return nil, ErrQuizNotFound
+	}
+
+	path := []int{chapter}
+	for _, part := range parts[1:] {
+		index, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, ErrQuizNotFound
+		}
+		path = append(path, index)
+	}
+
+	if QuizIDForLessonPath(path) != quizID {
+		return nil, ErrQuizNotFound
+	}
+	return path, nil
+}
+
+// QuizTypeForLessonPath returns "chapter" for a chapter quiz and "subchapter" otherwise
+func QuizTypeForLessonPath(path []int) string {
+	if len(path) == 1 {
+		return "chapter"
+	}
+	return "subchapter"
+}
+
+// RedactLessons returns copies of the lessons with every quiz answer key hidden,
+// except for questions the learner has already answered (quiz ID -> question IDs)
+func RedactLessons(lessons []Lesson, answered map[string][]string) []Lesson {
+	revealed := make(map[string]map[string]bool, len(answered))
+	for quizID, questionIDs := range answered {
+		revealed[quizID] = make(map[string]bool, len(questionIDs))
+		for _, id := range questionIDs {
+			revealed[quizID][id] = true
+		}
+	}
+	return redactLessons(lessons, nil, revealed)
+}
+
+func redactLessons(lessons []Lesson, parentPath []int, revealed map[string]map[string]bool) []Lesson {
+	if lessons == nil {
+		return nil
+	}
+
+	result := make([]Lesson, len(lessons))
+	for i, lesson := range lessons {
+		path := append(append([]int{}, parentPath...), i)
+		answered := revealed[QuizIDForLessonPath(path)]
+
+		if lesson.Quiz != nil {
+			quiz := &Quiz{Questions: make([]QuizQuestion, len(lesson.Quiz.Questions))}
+			for j, q := range lesson.Quiz.Questions {
+				if answered[q.ID] {
+					quiz.Questions[j] = q
+				} else {
+					quiz.Questions[j] = q.Redacted()
+				}
+			}
+			lesson.Quiz = quiz
+		}
+
+		if lesson.ExtendedQuiz != nil {
+			quiz := *lesson.ExtendedQuiz
+			quiz.Questions = make([]ExtendedQuizQuestion, len(lesson.ExtendedQuiz.Questions))
+			for j, q := range lesson.ExtendedQuiz.Questions {
+				if answered[q.ID] {
+					quiz.Questions[j] = q
+				} else {
+					quiz.Questions[j] = q.Redacted()
+				}
+			}
+			lesson.ExtendedQuiz = &quiz
+		}
+
+		lesson.Sublessons = redactLessons(lesson.Sublessons, path, revealed)
+		result[i] = lesson
+	}
+
+	return result
 }
 
 // QuizAttempt represents a user's attempt at a quiz
@@ -133,15 +302,32 @@ func NewQuizAttempt(userID, courseID, quizType, quizID string, score, maxScore,
 
 // QuizResponse represents a user's response to a single question
 type QuizResponse struct {
-	ID              string          `json:"id"`
-	AttemptID       string          `json:"attemptId"`
-	QuestionID      string          `json:"questionId"`
-	UserAnswer      json.RawMessage `json:"userAnswer"` // JSON to handle different answer types
-	IsCorrect       bool            `json:"isCorrect"`
-	PointsEarned    int             `json:"pointsEarned"`
-	PointsPossible  int             `json:"pointsPossible"`
-	Confidence      ConfidenceLevel `json:"confidence"`
-	TimeTakenSec    int             `json:"timeTakenSeconds"`
+	ID             string           `json:"id"`
+	AttemptID      string           `json:"attemptId"`
+	QuestionID     string           `json:"questionId"`
+	UserAnswer     json.RawMessage  `json:"userAnswer"` // JSON to handle different answer types
+	IsCorrect      bool             `json:"isCorrect"`
+	PointsEarned   int              `json:"pointsEarned"`
+	PointsPossible int              `json:"pointsPossible"`
+	Confidence     ConfidenceLevel  `json:"confidence"`
+	TimeTakenSec   int              `json:"timeTakenSeconds"`
+	Concept        string           `json:"concept"`               // Concept of the question, copied at grading time
+	TestResults    []CodeTestResult `json:"testResults,omitempty"` // Per-test outcomes of a code_exercise
+}
+
+// CodeTestResult is the outcome of one test run against a code_exercise submission
+type CodeTestResult struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Output string `json:"output,omitempty"` // Test log, or the compiler output when the build failed
+}
+
+// QuizAnswer represents a raw answer submitted by a learner, before grading
+type QuizAnswer struct {
+	QuestionID   string          `json:"questionId"`
+	Answer       json.RawMessage `json:"answer"`
+	Confidence   ConfidenceLevel `json:"confidence"`
+	TimeTakenSec int             `json:"timeTakenSeconds"`
 }
 
 // QuizStats represents statistics for a specific quiz
@@ -156,17 +342,18 @@ type QuizStats struct {
 
 // CourseQuizSummary represents quiz statistics for an entire course
 type CourseQuizSummary struct {
-	CourseID            string       `json:"courseId"`
-	CourseTitle         string       `json:"courseTitle"`
-	TotalQuizzes        int          `json:"totalQuizzes"`
-	CompletedQuizzes    int          `json:"completedQuizzes"`
-	AverageScore        float64      `json:"averageScore"`
-	OverallMastery      MasteryLevel `json:"overallMastery"`
-	SubchapterStats     []QuizStats  `json:"subchapterStats"`
-	ChapterStats        []QuizStats  `json:"chapterStats"`
-	WeakConcepts        []string     `json:"weakConcepts"`    // Concepts with low scores
-	StrongConcepts      []string     `json:"strongConcepts"`  // Concepts with high scores
-	ReviewQueueSize     int          `json:"reviewQueueSize"` // Questions to review (spaced repetition)
+	CourseID                 string       `json:"courseId"`
+	CourseTitle              string       `json:"courseTitle"`
+	TotalQuizzes             int          `json:"totalQuizzes"`
+	CompletedQuizzes         int          `json:"completedQuizzes"`
+	AverageScore             float64      `json:"averageScore"`
+	OverallMastery           MasteryLevel `json:"overallMastery"`
+	SubchapterStats          []QuizStats  `json:"subchapterStats"`
+	ChapterStats             []QuizStats  `json:"chapterStats"`
+	WeakConcepts             []string     `json:"weakConcepts"`             // Concepts with low scores
+	StrongConcepts           []string     `json:"strongConcepts"`           // Concepts with high scores
+	ConfidentlyWrongConcepts []string     `json:"confidentlyWrongConcepts"` // Concepts last answered wrongly with high confidence
+	ReviewQueueSize          int          `json:"reviewQueueSize"`          // Questions to review (spaced repetition)
 }
 
 // ScoreDataPoint represents a single data point for score history charts
@@ -197,6 +384,7 @@ type QuizConfig struct {
 	PassingScore           int                `json:"passingScore"`
 	TestOutThreshold       int                `json:"testOutThreshold"`
 	DifficultyDistribution map[string]float64 `json:"difficultyDistribution"`
+	Exam                   *ExamConfig        `json:"exam,omitempty"` // Exam settings for every chapter quiz
 }
 
 // DefaultQuizConfig returns the default quiz configuration
@@ -209,6 +397,10 @@ func DefaultQuizConfig() QuizConfig {
 			QuestionTypeCodeAnalysis,
 			QuestionTypeMatching,
 			QuestionTypeOrdering,
+			QuestionTypeFillBlank,
+			QuestionTypeNumeric,
+			QuestionTypeShortAnswer,
+			QuestionTypeCodeExercise,
 		},
 		QuestionsPerSubchapter: 6,
 		QuestionsPerChapter:    10,
@@ -222,16 +414,87 @@ func DefaultQuizConfig() QuizConfig {
 	}
 }
 
+// Validate checks that scores are percentages and counts are not negative
+func (c QuizConfig) Validate() error {
+	if c.PassingScore < 0 || c.PassingScore > 100 {
+		return ErrInvalidQuizConfig
+	}
+	if c.TestOutThreshold < 0 || c.TestOutThreshold > 100 {
+		return ErrInvalidQuizConfig
+	}
+	if c.QuestionsPerSubchapter < 0 || c.QuestionsPerChapter < 0 {
+		return ErrInvalidQuizConfig
+	}
+	for _, share := range c.DifficultyDistribution {
+		if share < 0 {
+			return ErrInvalidQuizConfig
+		}
+	}
+	if c.Exam != nil {
+		return c.Exam.Validate()
+	}
+	return nil
+}
+
+// Review priorities order due questions; higher priorities are reviewed first
+const (
+	// ReviewPriorityLow is for misses the learner already suspected, and for questions recalled since
+	ReviewPriorityLow = 0
+	// ReviewPriorityNormal is for misses without a confidence signal
+	ReviewPriorityNormal = 1
+	// ReviewPriorityHigh is for confidently wrong answers, which point at a misconception
+	ReviewPriorityHigh = 2
+)
+
+// ReviewPriorityForMiss returns the priority of a question answered wrongly with the given confidence
+func ReviewPriorityForMiss(confidence ConfidenceLevel) int {
+	switch confidence {
+	case ConfidenceHigh:
+		return ReviewPriorityHigh
+	case ConfidenceLow:
+		return ReviewPriorityLow
+	default:
+		return ReviewPriorityNormal
+	}
+}
+
 // ReviewQueueItem represents a question in the spaced repetition review queue
 type ReviewQueueItem struct {
-	ID           string    `json:"id"`
-	UserID       string    `json:"userId"`
-	CourseID     string    `json:"courseId"`
-	QuizID       string    `json:"quizId"`
-	QuestionID   string    `json:"questionId"`
-	Concept      string    `json:"concept"`
-	WrongCount   int       `json:"wrongCount"`
-	LastAttempt  time.Time `json:"lastAttempt"`
-	NextReview   time.Time `json:"nextReview"`
-	Stability    float64   `json:"stability"` // Spaced repetition stability score
+	ID           string     `json:"id"`
+	UserID       string     `json:"userId"`
+	CourseID     string     `json:"courseId"`
+	QuizID       string     `json:"quizId"`
+	QuestionID   string     `json:"questionId"`
+	Concept      string     `json:"concept"`
+	WrongCount   int        `json:"wrongCount"`
+	LastAttempt  time.Time  `json:"lastAttempt"`
+	NextReview   time.Time  `json:"nextReview"`
+	Stability    float64    `json:"stability"`              // Spaced repetition stability score
+	LastReviewed *time.Time `json:"lastReviewed,omitempty"` // Set when answered from the review queue
+	Priority     int        `json:"priority"`               // One of the ReviewPriority constants
+}
+
+// DailyReviewItem is a due review queue item together with the question it refers to
+type DailyReviewItem struct {
+	Item        ReviewQueueItem      `json:"item"`
+	CourseTitle string               `json:"courseTitle"`
+	LessonPath  []int                `json:"lessonPath"`
+	Question    ExtendedQuizQuestion `json:"question"`
+}
+
+// DailyReview is a single review session spanning all of a user's courses
+type DailyReview struct {
+	Items         []DailyReviewItem `json:"items"`
+	TotalDue      int               `json:"totalDue"`      // Due items across all courses, before the cap
+	ReviewedToday int               `json:"reviewedToday"` // Items already reviewed today
+	DailyCap      int               `json:"dailyCap"`
+}
+
+// TestOutResult is the outcome of a chapter test-out attempt
+type TestOutResult struct {
+	Attempt          *QuizAttempt `json:"attempt"`
+	Passed           bool         `json:"passed"`
+	Threshold        int          `json:"threshold"`
+	CompletedLessons []int        `json:"completedLessons"` // Lesson indices newly marked as tested out
+	UserCourse       *UserCourse  `json:"userCourse"`
 }
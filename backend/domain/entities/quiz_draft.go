@@ -0,0 +1,78 @@
+package entities
+
+import "time"
+
+// QuizDraftTTL is how long an unfinished attempt is kept after its last saved answer
+const QuizDraftTTL = 7 * 24 * time.Hour
+
+// QuizDraft is a quiz attempt in progress
+// Answers are saved one question at a time and graded together when the learner finishes,
+// so an attempt survives a closed browser and can be resumed on another device
+type QuizDraft struct {
+	ID         string
+	UserID     string
+	CourseID   string
+	QuizID     string
+	InstanceID string       // Generated quiz being answered, if any
+	SessionID  string       // Exam session the attempt is taken in, if any
+	Answers    []QuizAnswer // Saved answers, in the order their questions were first answered
+	StartedAt  time.Time
+	UpdatedAt  time.Time
+	ExpiresAt  time.Time
+	Deadline   *time.Time // When the exam session stops accepting answers; drafts never outlive it
+}
+
+// NewQuizDraft starts a draft attempt at the quiz at lessonPath now
+// deadline is nil unless the attempt belongs to a timed exam session
+func NewQuizDraft(userID, courseID string, lessonPath []int, instanceID, sessionID string, deadline *time.Time) (*QuizDraft, error) {
+	if userID == "" {
+		return nil, ErrInvalidUserID
+	}
+	if courseID == "" {
+		return nil, ErrInvalidCourseID
+	}
+
+	now := time.Now()
+	draft := &QuizDraft{
+		UserID:     userID,
+		CourseID:   courseID,
+		QuizID:     QuizIDForLessonPath(lessonPath),
+		InstanceID: instanceID,
+		SessionID:  sessionID,
+		Answers:    []QuizAnswer{},
+		StartedAt:  now,
+		Deadline:   deadline,
+	}
+	draft.Touch(now)
+	return draft, nil
+}
+
+// Touch records activity on the draft, pushing its expiry back by QuizDraftTTL
+func (d *QuizDraft) Touch(now time.Time) {
+	d.UpdatedAt = now
+	d.ExpiresAt = now.Add(QuizDraftTTL)
+	if d.Deadline != nil && d.Deadline.Before(d.ExpiresAt) {
+		d.ExpiresAt = *d.Deadline
+	}
+}
+
+// IsExpired reports whether the draft has gone stale and can no longer be resumed
+func (d *QuizDraft) IsExpired(now time.Time) bool {
+	return !now.Before(d.ExpiresAt)
+}
+
+// SetAnswer saves the answer to a question, replacing any earlier answer to it
+func (d *QuizDraft) SetAnswer(answer QuizAnswer) {
+	for i := range d.Answers {
+		if d.Answers[i].QuestionID == answer.QuestionID {
+			d.Answers[i] = answer
+			return
+		}
+	}
+	d.Answers = append(d.Answers, answer)
+}
+
+// LessonPath returns the path of the lesson whose quiz is being answered
+func (d *QuizDraft) LessonPath() ([]int, error) {
+	return LessonPathForQuizID(d.QuizID)
+}
@@ -0,0 +1,51 @@
+package entities
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestQuizDraft_SetAnswer(t *testing.T) {
+	draft, err := NewQuizDraft("user-1", "course-1", []int{0, 1}, "", "", nil)
+	if err != nil {
+		t.Fatalf("failed to create draft: %v", err)
+	}
+	if draft.QuizID != "lesson-00-sub-01" {
+		t.Errorf("expected quiz ID lesson-00-sub-01, got %s", draft.QuizID)
+	}
+
+	draft.SetAnswer(QuizAnswer{QuestionID: "q1", Answer: json.RawMessage("0")})
+	draft.SetAnswer(QuizAnswer{QuestionID: "q2", Answer: json.RawMessage("1")})
+	draft.SetAnswer(QuizAnswer{QuestionID: "q1", Answer: json.RawMessage("2")})
+
+	if len(draft.Answers) != 2 {
+		t.Fatalf("expected 2 answers, got %d", len(draft.Answers))
+	}
+	if draft.Answers[0].QuestionID != "q1" || string(draft.Answers[0].Answer) != "2" {
+		t.Errorf("expected q1 to be replaced in place, got %+v", draft.Answers[0])
+	}
+}
+
+func TestQuizDraft_Expiry(t *testing.T) {
+	draft, _ := NewQuizDraft("user-1", "course-1", []int{0}, "", "", nil)
+	if !draft.ExpiresAt.Equal(draft.UpdatedAt.Add(QuizDraftTTL)) {
+		t.Errorf("expected the draft to expire %v after its last update, got %v", QuizDraftTTL, draft.ExpiresAt)
+	}
+
+	later := draft.UpdatedAt.Add(24 * time.Hour)
+	draft.Touch(later)
+	if !draft.ExpiresAt.Equal(later.Add(QuizDraftTTL)) {
+		t.Errorf("expected saving to push the expiry back, got %v", draft.ExpiresAt)
+	}
+	if draft.IsExpired(later) || !draft.IsExpired(draft.ExpiresAt) {
+		t.Errorf("expected the draft to expire exactly at %v", draft.ExpiresAt)
+	}
+
+	deadline := time.Now().Add(time.Hour)
+	timed, _ := NewQuizDraft("user-1", "course-1", []int{0}, "", "session-1", &deadline)
+	timed.Touch(time.Now())
+	if !timed.ExpiresAt.Equal(deadline) {
+		t.Errorf("expected an exam draft to expire with its session at %v, got %v", deadline, timed.ExpiresAt)
+	}
+}
@@ -0,0 +1,14 @@
+package entities
+
+// QuizConversionIssue reports a question left out when converting a quiz to or from
+// another quiz format, such as GIFT or Moodle XML
+type QuizConversionIssue struct {
+	Item   string `json:"item"` // Question name or ID in the source
+	Reason string `json:"reason"`
+}
+
+// QuizImportResult is a lesson's quiz after importing questions into it
+type QuizImportResult struct {
+	Quiz    *ExtendedQuiz         `json:"quiz"`
+	Skipped []QuizConversionIssue `json:"skipped"`
+}
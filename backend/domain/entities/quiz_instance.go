@@ -0,0 +1,118 @@
+package entities
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QuizInstance is a quiz assembled for one learner from a lesson's question pool
+// Only the seed and the chosen question IDs are stored; the shuffled questions are
+// rebuilt from them so the server grades exactly what it issued
+type QuizInstance struct {
+	ID                string
+	UserID            string
+	CourseID          string
+	QuizID            string // Quiz ID of the lesson the pool was drawn from, e.g. "lesson-00"
+	IncludeSublessons bool   // Whether the pool included the lesson's sublesson quizzes
+	Seed              int64
+	QuestionIDs       []string // Chosen questions, in the order they are presented
+	CreatedAt         time.Time
+
+	// Questions holds the assembled questions when the instance has been built
+	Questions []ExtendedQuizQuestion
+}
+
+// NewQuizInstance creates a new quiz instance for a lesson
+func NewQuizInstance(userID, courseID string, lessonPath []int, includeSublessons bool, seed int64, questionIDs []string) (*QuizInstance, error) {
+	if userID == "" {
+		return nil, ErrInvalidUserID
+	}
+	if courseID == "" {
+		return nil, ErrInvalidCourseID
+	}
+	if len(questionIDs) == 0 {
+		return nil, ErrQuizNotFound
+	}
+
+	return &QuizInstance{
+		UserID:            userID,
+		CourseID:          courseID,
+		QuizID:            QuizIDForLessonPath(lessonPath),
+		IncludeSublessons: includeSublessons,
+		Seed:              seed,
+		QuestionIDs:       questionIDs,
+		CreatedAt:         time.Now(),
+	}, nil
+}
+
+// Question difficulty bands used as DifficultyDistribution keys
+const (
+	DifficultyBandEasy   = "easy"
+	DifficultyBandMedium = "medium"
+	DifficultyBandHard   = "hard"
+)
+
+// DifficultyBand maps a 1-5 question difficulty onto the easy/medium/hard bands
+func DifficultyBand(difficulty int) string {
+	switch {
+	case difficulty <= 2:
+		return DifficultyBandEasy
+	case difficulty == 3:
+		return DifficultyBandMedium
+	default:
+		return DifficultyBandHard
+	}
+}
+
+// QuestionPool returns the lesson's extended quiz questions and, optionally, those of
+// all its sublessons. Sublesson questions have their IDs namespaced by the sublesson's
+// path below the lesson (see PooledQuestionID), so questions sharing an ID in different
+// sublessons stay distinct.
+func (l *Lesson) QuestionPool(includeSublessons bool) []ExtendedQuizQuestion {
+	var pool []ExtendedQuizQuestion
+
+	var collect func(lesson *Lesson, subPath []int)
+	collect = func(lesson *Lesson, subPath []int) {
+		if quiz := lesson.EffectiveQuiz(); quiz != nil {
+			for _, q := range quiz.Questions {
+				q.ID = PooledQuestionID(subPath, q.ID)
+				pool = append(pool, q)
+			}
+		}
+		if includeSublessons {
+			for i := range lesson.Sublessons {
+				collect(&lesson.Sublessons[i], append(subPath[:len(subPath):len(subPath)], i))
+			}
+		}
+	}
+	collect(l, nil)
+
+	return pool
+}
+
+// PooledQuestionID returns the ID a question has in a lesson's question pool: the
+// lesson's own questions keep their ID, and a sublesson's are prefixed with its path
+// below the lesson, e.g. [1] -> "1/q1", [1, 0] -> "1.0/q1"
+func PooledQuestionID(subPath []int, questionID string) string {
+	if len(subPath) == 0 {
+		return questionID
+	}
+	parts := make([]string, len(subPath))
+	for i, index := range subPath {
+		parts[i] = strconv.Itoa(index)
+	}
+	return strings.Join(parts, ".") + "/" + questionID
+}
+
+// FindPoolQuestion finds a question in the lesson's question pool, sublessons included,
+// by its pooled ID
+func (l *Lesson) FindPoolQuestion(id string) (*ExtendedQuizQuestion, error) {
+	pool := l.QuestionPool(true)
+	for i := range pool {
+		if pool[i].ID == id {
+			return &pool[i], nil
+		}
+	}
+	return nil, ErrQuestionNotFound
+}
@@ -0,0 +1,48 @@
+package entities
+
+import "testing"
+
+func TestLesson_QuestionPool_NamespacesSublessonQuestions(t *testing.T) {
+	question := func(id string, difficulty int) ExtendedQuizQuestion {
+		return ExtendedQuizQuestion{ID: id, Type: QuestionTypeMultipleChoice, Difficulty: difficulty, Options: []string{"a", "b"}}
+	}
+	chapter := Lesson{
+		Title:        "Chapter 1",
+		ExtendedQuiz: &ExtendedQuiz{Questions: []ExtendedQuizQuestion{question("q1", 1)}},
+		Sublessons: []Lesson{
+			{Title: "Section 1.1", ExtendedQuiz: &ExtendedQuiz{Questions: []ExtendedQuizQuestion{question("q1", 2)}}},
+			{Title: "Section 1.2", ExtendedQuiz: &ExtendedQuiz{Questions: []ExtendedQuizQuestion{question("q1", 3)}}, Sublessons: []Lesson{
+				{Title: "Section 1.2.1", ExtendedQuiz: &ExtendedQuiz{Questions: []ExtendedQuizQuestion{question("q1", 4)}}},
+			}},
+		},
+	}
+
+	if pool := chapter.QuestionPool(false); len(pool) != 1 || pool[0].ID != "q1" {
+		t.Errorf("expected only the lesson's own question, got %+v", pool)
+	}
+
+	pool := chapter.QuestionPool(true)
+	want := []struct {
+		id         string
+		difficulty int
+	}{{"q1", 1}, {"0/q1", 2}, {"1/q1", 3}, {"1.0/q1", 4}}
+	if len(pool) != len(want) {
+		t.Fatalf("expected %d questions with colliding IDs kept apart, got %d", len(want), len(pool))
+	}
+	for i, w := range want {
+		if pool[i].ID != w.id || pool[i].Difficulty != w.difficulty {
+			t.Errorf("expected %s (difficulty %d) at position %d, got %s (difficulty %d)", w.id, w.difficulty, i, pool[i].ID, pool[i].Difficulty)
+		}
+	}
+	if chapter.Sublessons[0].ExtendedQuiz.Questions[0].ID != "q1" {
+		t.Error("expected the sublesson's own question ID to be left alone")
+	}
+
+	found, err := chapter.FindPoolQuestion("1.0/q1")
+	if err != nil || found.Difficulty != 4 {
+		t.Errorf("expected to find the nested sublesson's question, got %+v (%v)", found, err)
+	}
+	if _, err := chapter.FindPoolQuestion("2/q1"); err != ErrQuestionNotFound {
+		t.Errorf("expected ErrQuestionNotFound for an unknown sublesson, got %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+package entities
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ResponseReview is a graded response joined to the question it answered, so a learner can
+// see what they answered, what was expected and why
+// Question fields are empty when the question has since been removed from the course
+type ResponseReview struct {
+	QuizResponse
+	QuestionType  QuestionType
+	Question      string
+	Answer        []string // The learner's answer as shown to them: one entry per option, pair, item or blank
+	CorrectAnswer []string // Empty for code exercises, whose tests are hidden
+	Explanation   string
+}
+
+// NewResponseReview decodes a response's answer against its question
+// question may be nil when it is no longer part of the quiz; the answer is then kept as stored
+func NewResponseReview(response QuizResponse, question *ExtendedQuizQuestion) ResponseReview {
+	review := ResponseReview{QuizResponse: response, Answer: []string{}, CorrectAnswer: []string{}}
+	if question == nil {
+		if answered(response.UserAnswer) {
+			review.Answer = []string{string(response.UserAnswer)}
+		}
+		return review
+	}
+
+	review.QuestionType = question.Type
+	review.Question = question.Question
+	review.Explanation = question.Explanation
+	review.CorrectAnswer = correctAnswerText(question)
+	if answered(response.UserAnswer) {
+		text, err := answerText(question, response.UserAnswer)
+		if err != nil {
+			// Answers stored before the question changed may no longer decode
+			text = []string{string(response.UserAnswer)}
+		}
+		review.Answer = text
+	}
+
+	return review
+}
+
+// answered reports whether a stored answer holds anything; skipped questions are stored as null
+func answered(answer json.RawMessage) bool {
+	return len(answer) > 0 && string(answer) != "null"
+}
+
+// answerText decodes an answer in the format its question type is graded in
+func answerText(q *ExtendedQuizQuestion, answer json.RawMessage) ([]string, error) {
+	switch q.Type {
+	case QuestionTypeMultipleChoice, QuestionTypeCodeAnalysis:
+		var selected int
+		if err := json.Unmarshal(answer, &selected); err != nil {
+			return nil, err
+		}
+		return pick(q.Options, []int{selected})
+	case QuestionTypeTrueFalse:
+		var selected bool
+		if err := json.Unmarshal(answer, &selected); err != nil {
+			return nil, err
+		}
+		return []string{strconv.FormatBool(selected)}, nil
+	case QuestionTypeMultipleSelect, QuestionTypeOrdering:
+		var selected []int
+		if err := json.Unmarshal(answer, &selected); err != nil {
+			return nil, err
+		}
+		if q.Type == QuestionTypeOrdering {
+			return pick(q.Items, selected)
+		}
+		return pick(q.Options, selected)
+	case QuestionTypeMatching:
+		var pairs [][]int
+		if err := json.Unmarshal(answer, &pairs); err != nil {
+			return nil, err
+		}
+		return pairText(q, pairs)
+	case QuestionTypeNumeric:
+		var value float64
+		if err := json.Unmarshal(answer, &value); err == nil {
+			return []string{formatFloat(value)}, nil
+		}
+		fallthrough
+	case QuestionTypeFillBlank, QuestionTypeShortAnswer, QuestionTypeCodeExercise:
+		var text string
+		if err := json.Unmarshal(answer, &text); err != nil {
+			return nil, err
+		}
+		return []string{text}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedQuestion, q.Type)
+	}
+}
+
+// correctAnswerText describes the answer key of a question
+func correctAnswerText(q *ExtendedQuizQuestion) []string {
+	var text []string
+	switch q.Type {
+	case QuestionTypeMultipleChoice, QuestionTypeCodeAnalysis:
+		text, _ = pick(q.Options, []int{q.CorrectIndex})
+	case QuestionTypeTrueFalse:
+		if q.CorrectAnswer != nil {
+			text = []string{strconv.FormatBool(*q.CorrectAnswer)}
+		}
+	case QuestionTypeMultipleSelect:
+		text, _ = pick(q.Options, q.CorrectIndices)
+	case QuestionTypeOrdering:
+		text, _ = pick(q.Items, q.CorrectOrder)
+	case QuestionTypeMatching:
+		text, _ = pairText(q, q.CorrectPairs)
+	case QuestionTypeFillBlank, QuestionTypeShortAnswer:
+		text = q.AcceptedAnswers
+		if len(text) == 0 && q.AnswerPattern != "" {
+			text = []string{q.AnswerPattern}
+		}
+	case QuestionTypeNumeric:
+		if q.CorrectValue != nil {
+			value := formatFloat(*q.CorrectValue)
+			if tolerance := max(q.AbsoluteTolerance, q.RelativeTolerance*math.Abs(*q.CorrectValue)); tolerance > 0 {
+				value += " ± " + formatFloat(tolerance)
+			}
+			text = []string{strings.TrimSpace(value + " " + q.Unit)}
+		}
+	}
+	if text == nil {
+		return []string{}
+	}
+	return text
+}
+
+// pick returns the entries at the given indices
+func pick(entries []string, indices []int) ([]string, error) {
+	text := make([]string, 0, len(indices))
+	for _, i := range indices {
+		if i < 0 || i >= len(entries) {
+			return nil, fmt.Errorf("index %d out of range", i)
+		}
+		text = append(text, entries[i])
+	}
+	return text, nil
+}
+
+// pairText describes matching pairs as "left → right"
+func pairText(q *ExtendedQuizQuestion, pairs [][]int) ([]string, error) {
+	text := make([]string, 0, len(pairs))
+	for _, p := range pairs {
+		if len(p) != 2 || p[0] < 0 || p[0] >= len(q.LeftColumn) || p[1] < 0 || p[1] >= len(q.RightColumn) {
+			return nil, fmt.Errorf("pair %v out of range", p)
+		}
+		text = append(text, q.LeftColumn[p[0]]+" → "+q.RightColumn[p[1]])
+	}
+	return text, nil
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,119 @@
+package entities
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestNewResponseReview(t *testing.T) {
+	yes := true
+	value := 2.5
+	tests := []struct {
+		name          string
+		question      ExtendedQuizQuestion
+		answer        string
+		answerText    []string
+		correctAnswer []string
+	}{
+		{
+			name:          "multiple choice",
+			question:      ExtendedQuizQuestion{Type: QuestionTypeMultipleChoice, Options: []string{"a", "b"}, CorrectIndex: 1},
+			answer:        `0`,
+			answerText:    []string{"a"},
+			correctAnswer: []string{"b"},
+		},
+		{
+			name:          "true false",
+			question:      ExtendedQuizQuestion{Type: QuestionTypeTrueFalse, CorrectAnswer: &yes},
+			answer:        `false`,
+			answerText:    []string{"false"},
+			correctAnswer: []string{"true"},
+		},
+		{
+			name:          "multiple select",
+			question:      ExtendedQuizQuestion{Type: QuestionTypeMultipleSelect, Options: []string{"a", "b", "c"}, CorrectIndices: []int{0, 2}},
+			answer:        `[1, 2]`,
+			answerText:    []string{"b", "c"},
+			correctAnswer: []string{"a", "c"},
+		},
+		{
+			name:          "matching",
+			question:      ExtendedQuizQuestion{Type: QuestionTypeMatching, LeftColumn: []string{"x", "y"}, RightColumn: []string{"1", "2"}, CorrectPairs: [][]int{{0, 0}, {1, 1}}},
+			answer:        `[[0, 1], [1, 0]]`,
+			answerText:    []string{"x → 2", "y → 1"},
+			correctAnswer: []string{"x → 1", "y → 2"},
+		},
+		{
+			name:          "ordering",
+			question:      ExtendedQuizQuestion{Type: QuestionTypeOrdering, Items: []string{"first", "second"}, CorrectOrder: []int{0, 1}},
+			answer:        `[1, 0]`,
+			answerText:    []string{"second", "first"},
+			correctAnswer: []string{"first", "second"},
+		},
+		{
+			name:          "fill blank",
+			question:      ExtendedQuizQuestion{Type: QuestionTypeFillBlank, AcceptedAnswers: []string{"defer"}},
+			answer:        `"go"`,
+			answerText:    []string{"go"},
+			correctAnswer: []string{"defer"},
+		},
+		{
+			name:          "numeric",
+			question:      ExtendedQuizQuestion{Type: QuestionTypeNumeric, CorrectValue: &value, AbsoluteTolerance: 0.1, Unit: "s"},
+			answer:        `"3 s"`,
+			answerText:    []string{"3 s"},
+			correctAnswer: []string{"2.5 ± 0.1 s"},
+		},
+		{
+			name:          "code exercise",
+			question:      ExtendedQuizQuestion{Type: QuestionTypeCodeExercise, TestCode: "package x"},
+			answer:        `"package x"`,
+			answerText:    []string{"package x"},
+			correctAnswer: []string{},
+		},
+		{
+			name:          "skipped",
+			question:      ExtendedQuizQuestion{Type: QuestionTypeMultipleChoice, Options: []string{"a", "b"}},
+			answer:        `null`,
+			answerText:    []string{},
+			correctAnswer: []string{"a"},
+		},
+		{
+			name:          "answer no longer valid",
+			question:      ExtendedQuizQuestion{Type: QuestionTypeMultipleChoice, Options: []string{"a", "b"}},
+			answer:        `4`,
+			answerText:    []string{"4"},
+			correctAnswer: []string{"a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.question.Question = "Question text"
+			tt.question.Explanation = "Because"
+			review := NewResponseReview(QuizResponse{UserAnswer: json.RawMessage(tt.answer)}, &tt.question)
+
+			if !slices.Equal(review.Answer, tt.answerText) {
+				t.Errorf("expected answer %q, got %q", tt.answerText, review.Answer)
+			}
+			if !slices.Equal(review.CorrectAnswer, tt.correctAnswer) {
+				t.Errorf("expected correct answer %q, got %q", tt.correctAnswer, review.CorrectAnswer)
+			}
+			if review.Question != "Question text" || review.Explanation != "Because" || review.QuestionType != tt.question.Type {
+				t.Errorf("expected the question details to be copied, got %+v", review)
+			}
+		})
+	}
+}
+
+func TestNewResponseReview_RemovedQuestion(t *testing.T) {
+	review := NewResponseReview(QuizResponse{QuestionID: "gone", UserAnswer: json.RawMessage(`1`)}, nil)
+
+	if review.Question != "" || len(review.CorrectAnswer) != 0 {
+		t.Errorf("expected no question details, got %+v", review)
+	}
+	if !slices.Equal(review.Answer, []string{"1"}) {
+		t.Errorf("expected the stored answer, got %q", review.Answer)
+	}
+}
@@ -0,0 +1,28 @@
+package entities
+
+import (
+	"testing"
+)
+
+func TestLessonPathForQuizID(t *testing.T) {
+	for _, path := range [][]int{{0}, {3, 12}, {1, 2, 3}} {
+		got, err := LessonPathForQuizID(QuizIDForLessonPath(path))
+		if err != nil {
+			t.Fatalf("LessonPathForQuizID failed for %v: %v", path, err)
+		}
+		if len(got) != len(path) {
+			t.Fatalf("expected %v, got %v", path, got)
+		}
+		for i := range path {
+			if got[i] != path[i] {
+				t.Errorf("expected %v, got %v", path, got)
+			}
+		}
+	}
+
+	for _, id := range []string{"", "quiz-1", "lesson-xx", "lesson-00-sub-", "lesson-1"} {
+		if _, err := LessonPathForQuizID(id); err != ErrQuizNotFound {
+			t.Errorf("expected ErrQuizNotFound for %q, got %v", id, err)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package entities
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// ExtendedQuizVersion is the quiz.json format version written for authored quizzes
+const ExtendedQuizVersion = "1.0"
+
+// questionIDPattern keeps question IDs usable in quiz IDs, URLs and file names
+var questionIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// defaultDifficulty follows the difficulty guidelines for authored quizzes: recall questions
+// are easiest and questions that need analysis hardest
+var defaultDifficulty = map[QuestionType]int{
+	QuestionTypeTrueFalse:      1,
+	QuestionTypeMultipleChoice: 2,
+	QuestionTypeFillBlank:      2,
+	QuestionTypeShortAnswer:    2,
+	QuestionTypeNumeric:        2,
+	QuestionTypeMultipleSelect: 3,
+	QuestionTypeMatching:       3,
+	QuestionTypeOrdering:       4,
+	QuestionTypeCodeAnalysis:   4,
+	QuestionTypeCodeExercise:   4,
+}
+
+// DefaultDifficulty returns the difficulty given to questions of a type whose source has none,
+// such as legacy or imported quizzes
+func DefaultDifficulty(questionType QuestionType) int {
+	if difficulty, ok := defaultDifficulty[questionType]; ok {
+		return difficulty
+	}
+	return 2
+}
+
+// IsValidQuestionID reports whether id can be used as a question ID
+func IsValidQuestionID(id string) bool {
+	return questionIDPattern.MatchString(id)
+}
+
+// Validate checks that every question is complete, that question IDs are unique and that
+// the exam settings and bank references, if any, are valid
+func (q *ExtendedQuiz) Validate() error {
+	if q.Exam != nil {
+		if err := q.Exam.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, ref := range q.BankRefs {
+		if err := ref.Validate(); err != nil {
+			return err
+		}
+	}
+	seen := make(map[string]bool, len(q.Questions))
+	for i := range q.Questions {
+		question := &q.Questions[i]
+		if err := question.Validate(); err != nil {
+			return err
+		}
+		if seen[question.ID] {
+			return fmt.Errorf("%w %s: id is used by another question", ErrInvalidQuestion, question.ID)
+		}
+		seen[question.ID] = true
+	}
+	return nil
+}
+
+// Validate checks the fields shared by every question and those its type requires
+func (q *ExtendedQuizQuestion) Validate() error {
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w %s: %s", ErrInvalidQuestion, q.ID, fmt.Sprintf(format, args...))
+	}
+
+	if !IsValidQuestionID(q.ID) {
+		return fmt.Errorf("%w: id %q may only contain letters, digits, '.', '-' and '_'", ErrInvalidQuestion, q.ID)
+	}
+	if q.Question == "" {
+		return invalid("question text is required")
+	}
+	if q.Difficulty < 1 || q.Difficulty > 5 {
+		return invalid("difficulty must be between 1 and 5")
+	}
+
+	switch q.Type {
+	case QuestionTypeMultipleChoice, QuestionTypeCodeAnalysis:
+		if len(q.Options) < 2 {
+			return invalid("at least two options are required")
+		}
+		if q.CorrectIndex < 0 || q.CorrectIndex >= len(q.Options) {
+			return invalid("correctIndex %d is not an option", q.CorrectIndex)
+		}
+		if q.Type == QuestionTypeCodeAnalysis && q.CodeSnippet == "" {
+			return invalid("codeSnippet is required")
+		}
+	case QuestionTypeTrueFalse:
+		if q.CorrectAnswer == nil {
+			return invalid("correctAnswer is required")
+		}
+	case QuestionTypeMultipleSelect:
+		if len(q.Options) < 2 {
+			return invalid("at least two options are required")
+		}
+		if err := validateIndices(q.CorrectIndices, len(q.Options)); err != nil {
+			return invalid("correctIndices: %v", err)
+		}
+		if q.MinSelections < 0 || q.MaxSelections < 0 || q.MaxSelections > len(q.Options) {
+			return invalid("selection limits must be between 0 and the number of options")
+		}
+		if q.MaxSelections > 0 && q.MinSelections > q.MaxSelections {
+			return invalid("minSelections is more than maxSelections")
+		}
+	case QuestionTypeMatching:
+		if len(q.LeftColumn) < 2 || len(q.RightColumn) < 2 {
+			return invalid("both columns need at least two entries")
+		}
+		if len(q.CorrectPairs) != len(q.LeftColumn) {
+			return invalid("every left entry needs exactly one correct pair")
+		}
+		matched := make(map[int]bool, len(q.CorrectPairs))
+		matchedRight := make(map[int]bool, len(q.CorrectPairs))
+		for _, p := range q.CorrectPairs {
+			if len(p) != 2 || p[0] < 0 || p[0] >= len(q.LeftColumn) || p[1] < 0 || p[1] >= len(q.RightColumn) {
+				return invalid("pair %v is out of range", p)
+			}
+			if matched[p[0]] {
+				return invalid("left entry %d is paired twice", p[0])
+			}
+			// A right entry paired twice would leave another left entry without its match
+			if matchedRight[p[1]] {
+				return invalid("right entry %d is paired twice", p[1])
+			}
+			matched[p[0]] = true
+			matchedRight[p[1]] = true
+		}
+	case QuestionTypeOrdering:
+		if len(q.Items) < 2 {
+			return invalid("at least two items are required")
+		}
+		if len(q.CorrectOrder) != len(q.Items) {
+			return invalid("correctOrder must list every item once")
+		}
+		if err := validateIndices(q.CorrectOrder, len(q.Items)); err != nil {
+			return invalid("correctOrder: %v", err)
+		}
+	case QuestionTypeFillBlank:
+		if len(q.AcceptedAnswers) == 0 && q.AnswerPattern == "" {
+			return invalid("acceptedAnswers or answerPattern is required")
+		}
+		if q.AnswerPattern != "" {
+			if _, err := regexp.Compile(q.AnswerPattern); err != nil {
+				return invalid("answerPattern does not compile: %v", err)
+			}
+		}
+	case QuestionTypeShortAnswer:
+		if len(q.AcceptedAnswers) == 0 {
+			return invalid("acceptedAnswers is required")
+		}
+	case QuestionTypeNumeric:
+		if q.CorrectValue == nil {
+			return invalid("correctValue is required")
+		}
+		if q.AbsoluteTolerance < 0 || q.RelativeTolerance < 0 {
+			return invalid("tolerances cannot be negative")
+		}
+	case QuestionTypeCodeExercise:
+		if q.TestCode == "" {
+			return invalid("testCode is required")
+		}
+		if q.Language != "" && q.Language != "go" {
+			return invalid("only go code exercises are supported")
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedQuestion, q.Type)
+	}
+
+	return nil
+}
+
+// validateIndices checks that indices are distinct and within [0, n)
+func validateIndices(indices []int, n int) error {
+	seen := make(map[int]bool, len(indices))
+	for _, index := range indices {
+		if index < 0 || index >= n {
+			return fmt.Errorf("index %d is out of range", index)
+		}
+		if seen[index] {
+			return fmt.Errorf("index %d is listed twice", index)
+		}
+		seen[index] = true
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package entities
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExtendedQuizQuestion_Validate(t *testing.T) {
+	answer := false
+	value := 3.14
+	valid := []ExtendedQuizQuestion{
+		{ID: "mc1", Type: QuestionTypeMultipleChoice, Question: "?", Difficulty: 1, Options: []string{"a", "b"}, CorrectIndex: 1},
+		{ID: "tf1", Type: QuestionTypeTrueFalse, Question: "?", Difficulty: 2, CorrectAnswer: &answer},
+		{ID: "ms1", Type: QuestionTypeMultipleSelect, Question: "?", Difficulty: 3, Options: []string{"a", "b", "c"}, CorrectIndices: []int{0, 2}},
+		{ID: "m1", Type: QuestionTypeMatching, Question: "?", Difficulty: 3, LeftColumn: []string{"a", "b"}, RightColumn: []string{"x", "y"}, CorrectPairs: [][]int{{0, 1}, {1, 0}}},
+		{ID: "o1", Type: QuestionTypeOrdering, Question: "?", Difficulty: 4, Items: []string{"a", "b", "c"}, CorrectOrder: []int{2, 0, 1}},
+		{ID: "fb1", Type: QuestionTypeFillBlank, Question: "?", Difficulty: 1, AnswerPattern: `^go(lang)?$`},
+		{ID: "n1", Type: QuestionTypeNumeric, Question: "?", Difficulty: 2, CorrectValue: &value},
+		{ID: "ce1", Type: QuestionTypeCodeExercise, Question: "?", Difficulty: 5, TestCode: "package exercise"},
+	}
+	for _, q := range valid {
+		if err := q.Validate(); err != nil {
+			t.Errorf("expected %s to be valid, got %v", q.ID, err)
+		}
+	}
+
+	invalid := []ExtendedQuizQuestion{
+		{ID: "bad id", Type: QuestionTypeMultipleChoice, Question: "?", Difficulty: 1, Options: []string{"a", "b"}},
+		{ID: "mc1", Type: QuestionTypeMultipleChoice, Question: "?", Difficulty: 0, Options: []string{"a", "b"}},
+		{ID: "mc2", Type: QuestionTypeMultipleChoice, Question: "?", Difficulty: 1, Options: []string{"a", "b"}, CorrectIndex: 2},
+		{ID: "ca1", Type: QuestionTypeCodeAnalysis, Question: "?", Difficulty: 1, Options: []string{"a", "b"}},
+		{ID: "ms1", Type: QuestionTypeMultipleSelect, Question: "?", Difficulty: 1, Options: []string{"a", "b"}, CorrectIndices: []int{1, 1}},
+		{ID: "m1", Type: QuestionTypeMatching, Question: "?", Difficulty: 1, LeftColumn: []string{"a", "b"}, RightColumn: []string{"x", "y"}, CorrectPairs: [][]int{{0, 1}, {0, 0}}},
+		{ID: "m2", Type: QuestionTypeMatching, Question: "?", Difficulty: 1, LeftColumn: []string{"a", "b"}, RightColumn: []string{"x", "y", "z"}, CorrectPairs: [][]int{{0, 1}, {1, 1}}},
+		{ID: "o1", Type: QuestionTypeOrdering, Question: "?", Difficulty: 1, Items: []string{"a", "b"}, CorrectOrder: []int{0, 0}},
+		{ID: "fb1", Type: QuestionTypeFillBlank, Question: "?", Difficulty: 1, AnswerPattern: `(`},
+		{ID: "sa1", Type: QuestionTypeShortAnswer, Question: "?", Difficulty: 1},
+		{ID: "n1", Type: QuestionTypeNumeric, Question: "?", Difficulty: 1},
+		{ID: "ce1", Type: QuestionTypeCodeExercise, Question: "?", Difficulty: 1},
+	}
+	for _, q := range invalid {
+		if err := q.Validate(); !errors.Is(err, ErrInvalidQuestion) {
+			t.Errorf("expected ErrInvalidQuestion for %s, got %v", q.ID, err)
+		}
+	}
+
+	unknown := ExtendedQuizQuestion{ID: "x1", Type: "essay", Question: "?", Difficulty: 1}
+	if err := unknown.Validate(); !errors.Is(err, ErrUnsupportedQuestion) {
+		t.Errorf("expected ErrUnsupportedQuestion, got %v", err)
+	}
+}
+
+func TestExtendedQuiz_Validate_DuplicateIDs(t *testing.T) {
+	q := ExtendedQuizQuestion{ID: "q1", Type: QuestionTypeShortAnswer, Question: "?", Difficulty: 1, AcceptedAnswers: []string{"a"}}
+	quiz := ExtendedQuiz{Questions: []ExtendedQuizQuestion{q, q}}
+	if err := quiz.Validate(); !errors.Is(err, ErrInvalidQuestion) {
+		t.Errorf("expected ErrInvalidQuestion for duplicate ids, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package entities
 
 import (
+	"strings"
 	"time"
 )
 
@@ -47,3 +48,23 @@ func (u *User) Update(name, email string) error {
 	u.UpdatedAt = time.Now()
 	return nil
 }
+
+// UserGroup is a set of users given a role by the configuration, such as the editors of
+// content that has no author account
+type UserGroup map[string]bool
+
+// NewUserGroup returns the group of the given user IDs, ignoring blank entries
+func NewUserGroup(userIDs []string) UserGroup {
+	group := make(UserGroup, len(userIDs))
+	for _, id := range userIDs {
+		if id = strings.TrimSpace(id); id != "" {
+			group[id] = true
+		}
+	}
+	return group
+}
+
+// Contains reports whether a signed-in user is in the group
+func (g UserGroup) Contains(userID string) bool {
+	return userID != "" && g[userID]
+}
@@ -94,3 +94,17 @@ func TestUser_Update_PartialUpdate(t *testing.T) {
 		t.Errorf("expected email to remain 'test@example.com', got '%s'", user.Email)
 	}
 }
+
+func TestUserGroup_Contains(t *testing.T) {
+	group := NewUserGroup([]string{"editor-1", " editor-2 ", ""})
+
+	if !group.Contains("editor-1") || !group.Contains("editor-2") {
+		t.Errorf("expected both editors in the group, got %v", group)
+	}
+	if group.Contains("user-1") || group.Contains("") {
+		t.Error("expected other and anonymous users not to be in the group")
+	}
+	if UserGroup(nil).Contains("editor-1") {
+		t.Error("expected an empty group to contain no one")
+	}
+}
@@ -37,6 +37,17 @@ type LibraryCourseRepository interface {
 
 	// GetAllTags retrieves all unique tags
 	GetAllTags(ctx context.Context) ([]string, error)
+
+	// SaveLessonQuiz replaces the extended quiz of the lesson at lessonPath
+	SaveLessonQuiz(ctx context.Context, courseID string, lessonPath []int, quiz *entities.ExtendedQuiz) error
+}
+
+// CourseChangeSource is implemented by course repositories that reload courses as their
+// source changes
+type CourseChangeSource interface {
+	// SubscribeCourseChanges returns a channel receiving course changes and a function that
+	// ends the subscription and closes the channel
+	SubscribeCourseChanges() (<-chan entities.CourseChange, func())
 }
 
 // UserCourseRepository defines the interface for user course data access
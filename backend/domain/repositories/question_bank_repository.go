@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/project/backend/domain/entities"
+)
+
+// QuestionBankRepository defines the interface for question bank data access
+type QuestionBankRepository interface {
+	// ListQuestionBanks retrieves every question bank, ordered by ID
+	ListQuestionBanks(ctx context.Context) ([]*entities.QuestionBank, error)
+
+	// GetQuestionBank retrieves a question bank by ID
+	GetQuestionBank(ctx context.Context, id string) (*entities.QuestionBank, error)
+}
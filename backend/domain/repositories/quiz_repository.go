@@ -15,6 +15,14 @@ type QuizRepository interface {
 	// SaveResponse stores a quiz response
 	SaveResponse(ctx context.Context, response *entities.QuizResponse) (*entities.QuizResponse, error)
 
+	// SaveGradedAttempt stores a graded attempt and its responses, which are linked to it,
+	// atomically. With a sessionID the exam session is closed in the same transaction, and
+	// nothing is stored if it fails with ErrQuizSessionClosed
+	SaveGradedAttempt(ctx context.Context, sessionID string, submittedAt time.Time, attempt *entities.QuizAttempt, responses []entities.QuizResponse) (*entities.QuizAttempt, error)
+
+	// GetAttempt retrieves a single attempt by ID
+	GetAttempt(ctx context.Context, id string) (*entities.QuizAttempt, error)
+
 	// GetAttemptsByQuiz retrieves all attempts for a specific quiz
 	GetAttemptsByQuiz(ctx context.Context, userID, courseID, quizID string) ([]entities.QuizAttempt, error)
 
@@ -24,18 +32,83 @@ type QuizRepository interface {
 	// GetCourseQuizSummary retrieves quiz summary for entire course
 	GetCourseQuizSummary(ctx context.Context, userID, courseID string) (*entities.CourseQuizSummary, error)
 
+	// GetConceptStrengths retrieves per-concept performance for a user in a course
+	GetConceptStrengths(ctx context.Context, userID, courseID string) ([]entities.ConceptStrength, error)
+
+	// GetCalibration retrieves a user's accuracy by reported confidence, for one course or,
+	// with an empty courseID, across all courses
+	GetCalibration(ctx context.Context, userID, courseID string) (*entities.CalibrationReport, error)
+
 	// GetDashboardQuizStats retrieves aggregated quiz stats for the dashboard
 	GetDashboardQuizStats(ctx context.Context, userID string, fromDate, toDate *time.Time) (*entities.DashboardQuizStats, error)
 
-	// AddToReviewQueue adds a question to the spaced repetition review queue
+	// AddToReviewQueue adds a question to the spaced repetition review queue,
+	// or replaces its schedule if the question is already queued
 	AddToReviewQueue(ctx context.Context, item *entities.ReviewQueueItem) error
 
+	// GetDueReviewItems retrieves every question due for review across the given courses
+	GetDueReviewItems(ctx context.Context, userID string, courseIDs []string) ([]entities.ReviewQueueItem, error)
+
+	// CountReviewedSince counts the queued questions a user has reviewed since the given time
+	CountReviewedSince(ctx context.Context, userID string, since time.Time) (int, error)
+
+	// GetReviewItem retrieves a single queued question
+	GetReviewItem(ctx context.Context, userID, courseID, questionID string) (*entities.ReviewQueueItem, error)
+
 	// GetReviewQueue retrieves questions due for review
 	GetReviewQueue(ctx context.Context, userID, courseID string, limit int) ([]entities.ReviewQueueItem, error)
 
 	// RemoveFromReviewQueue removes a question from review queue
 	RemoveFromReviewQueue(ctx context.Context, userID, courseID, questionID string) error
 
-	// GetResponsesByAttempt retrieves all responses for an attempt
+	// GetResponsesByAttempt retrieves all responses for an attempt, in the order they were graded
 	GetResponsesByAttempt(ctx context.Context, attemptID string) ([]entities.QuizResponse, error)
+
+	// GetItemResponses retrieves all learners' responses to a quiz, for item analysis
+	GetItemResponses(ctx context.Context, courseID, quizID string) ([]entities.ItemResponse, error)
+
+	// GetQuestionResponses retrieves all learners' responses to the given questions in any
+	// course, for analysing shared bank questions
+	GetQuestionResponses(ctx context.Context, questionIDs []string) ([]entities.ItemResponse, error)
+
+	// GetAnsweredQuestions returns the question IDs a user has answered in a course, keyed by quiz ID
+	GetAnsweredQuestions(ctx context.Context, userID, courseID string) (map[string][]string, error)
+
+	// SaveQuizInstance stores a generated quiz so it can be graded exactly as issued
+	SaveQuizInstance(ctx context.Context, instance *entities.QuizInstance) (*entities.QuizInstance, error)
+
+	// GetQuizInstance retrieves a generated quiz by ID
+	GetQuizInstance(ctx context.Context, id string) (*entities.QuizInstance, error)
+
+	// SaveQuizSession stores a newly started exam session
+	SaveQuizSession(ctx context.Context, session *entities.QuizSession) (*entities.QuizSession, error)
+
+	// GetQuizSession retrieves an exam session by ID
+	GetQuizSession(ctx context.Context, id string) (*entities.QuizSession, error)
+
+	// GetQuizSessions retrieves a user's sessions for a quiz, newest first
+	GetQuizSessions(ctx context.Context, userID, courseID, quizID string) ([]entities.QuizSession, error)
+
+	// CloseQuizSession marks a session as submitted; it fails with ErrQuizSessionClosed if
+	// the session was already submitted, so each session is graded at most once
+	CloseQuizSession(ctx context.Context, id string, submittedAt time.Time) error
+
+	// SaveQuizDraft stores a newly started draft attempt
+	SaveQuizDraft(ctx context.Context, draft *entities.QuizDraft) (*entities.QuizDraft, error)
+
+	// GetQuizDraft retrieves a draft attempt with its saved answers
+	GetQuizDraft(ctx context.Context, id string) (*entities.QuizDraft, error)
+
+	// GetOpenQuizDrafts retrieves a user's unexpired drafts, most recently saved first
+	GetOpenQuizDrafts(ctx context.Context, userID string, now time.Time) ([]entities.QuizDraft, error)
+
+	// SaveQuizDraftAnswer stores an answer to one question of a draft, replacing any earlier
+	// answer to it, along with the draft's updated time and expiry
+	SaveQuizDraftAnswer(ctx context.Context, draft *entities.QuizDraft, answer entities.QuizAnswer) error
+
+	// DeleteQuizDraft removes a draft and its answers
+	DeleteQuizDraft(ctx context.Context, id string) error
+
+	// DeleteExpiredQuizDrafts removes drafts that expired before now and returns how many
+	DeleteExpiredQuizDrafts(ctx context.Context, now time.Time) (int, error)
 }
@@ -0,0 +1,263 @@
+package services
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"math"
+	"math/rand/v2"
+	"sort"
+
+	"github.com/project/backend/domain/entities"
+)
+
+// difficultyBands is the fixed order bands are filled in, so assembly is reproducible
+var difficultyBands = []string{
+	entities.DifficultyBandEasy,
+	entities.DifficultyBandMedium,
+	entities.DifficultyBandHard,
+}
+
+// QuizAssembler draws quizzes from a question pool and shuffles their options
+// Everything is derived from a seed, so an issued quiz can be rebuilt exactly for grading
+type QuizAssembler struct{}
+
+// NewQuizAssembler creates a new quiz assembler
+func NewQuizAssembler() *QuizAssembler {
+	return &QuizAssembler{}
+}
+
+// SelectQuestions picks count questions from the pool, matching the easy/medium/hard
+// distribution as closely as the pool allows, and returns their IDs in presentation order
+// A count of zero or more than the pool size selects the whole pool
+func (a *QuizAssembler) SelectQuestions(pool []entities.ExtendedQuizQuestion, count int, distribution map[string]float64, seed int64) []string {
+	rng := rand.New(rand.NewPCG(uint64(seed), 0))
+
+	if count <= 0 || count > len(pool) {
+		count = len(pool)
+	}
+
+	byBand := make(map[string][]string, len(difficultyBands))
+	for _, q := range pool {
+		band := entities.DifficultyBand(q.Difficulty)
+		byBand[band] = append(byBand[band], q.ID)
+	}
+	for _, band := range difficultyBands {
+		ids := byBand[band]
+		rng.Shuffle(len(ids), func(i, j int) { ids[i], ids[j] = ids[j], ids[i] })
+	}
+
+	quotas := bandQuotas(count, distribution)
+	selected := make([]string, 0, count)
+	var leftovers []string
+	for _, band := range difficultyBands {
+		ids := byBand[band]
+		quota := quotas[band]
+		if quota > len(ids) {
+			quota = len(ids)
+		}
+		selected = append(selected, ids[:quota]...)
+		leftovers = append(leftovers, ids[quota:]...)
+	}
+
+	// Fill any band the pool could not cover from the remaining questions
+	rng.Shuffle(len(leftovers), func(i, j int) { leftovers[i], leftovers[j] = leftovers[j], leftovers[i] })
+	selected = append(selected, leftovers[:count-len(selected)]...)
+
+	rng.Shuffle(len(selected), func(i, j int) { selected[i], selected[j] = selected[j], selected[i] })
+	return selected
+}
+
+// BuildQuestions returns the chosen questions in order with their options shuffled
+// and the answer keys remapped to match
+func (a *QuizAssembler) BuildQuestions(pool []entities.ExtendedQuizQuestion, questionIDs []string, seed int64) ([]entities.ExtendedQuizQuestion, error) {
+	byID := make(map[string]entities.ExtendedQuizQuestion, len(pool))
+	for _, q := range pool {
+		byID[q.ID] = q
+	}
+
+	questions := make([]entities.ExtendedQuizQuestion, 0, len(questionIDs))
+	for _, id := range questionIDs {
+		q, ok := byID[id]
+		if !ok {
+			return nil, entities.ErrQuestionNotFound
+		}
+		questions = append(questions, shuffleQuestion(q, seed))
+	}
+
+	return questions, nil
+}
+
+// bandQuotas splits count across the difficulty bands by largest remainder
+// Bands missing from the distribution get no quota; an empty distribution gives none at all
+func bandQuotas(count int, distribution map[string]float64) map[string]int {
+	quotas := make(map[string]int, len(difficultyBands))
+
+	total := 0.0
+	for _, band := range difficultyBands {
+		total += distribution[band]
+	}
+	if total <= 0 {
+		return quotas
+	}
+
+	assigned := 0
+	remainders := make([]float64, len(difficultyBands))
+	for i, band := range difficultyBands {
+		exact := float64(count) * distribution[band] / total
+		quotas[band] = int(math.Floor(exact))
+		remainders[i] = exact - math.Floor(exact)
+		assigned += quotas[band]
+	}
+
+	order := []int{0, 1, 2}
+	sort.SliceStable(order, func(i, j int) bool {
+		return remainders[order[i]] > remainders[order[j]]
+	})
+	for _, i := range order[:count-assigned] {
+		quotas[difficultyBands[i]]++
+	}
+
+	return quotas
+}
+
+// UnshuffleAnswer translates a raw answer given against a built question back to the
+// question's original option order, so stored answers line up across attempts
+// Answers that cannot be parsed are returned unchanged
+func (a *QuizAssembler) UnshuffleAnswer(q *entities.ExtendedQuizQuestion, seed int64, answer json.RawMessage) json.RawMessage {
+	order := shuffleOrder(q, seed)
+	if order == nil {
+		return answer
+	}
+	original := func(index int) int {
+		if index >= 0 && index < len(order) {
+			return order[index]
+		}
+		return index
+	}
+
+	var unshuffled any
+	switch q.Type {
+	case entities.QuestionTypeMultipleChoice, entities.QuestionTypeCodeAnalysis:
+		var selected *int
+		if err := json.Unmarshal(answer, &selected); err != nil || selected == nil {
+			return answer
+		}
+		unshuffled = original(*selected)
+	case entities.QuestionTypeMultipleSelect, entities.QuestionTypeOrdering:
+		var indices []int
+		if err := json.Unmarshal(answer, &indices); err != nil || indices == nil {
+			return answer
+		}
+		for i, index := range indices {
+			indices[i] = original(index)
+		}
+		unshuffled = indices
+	case entities.QuestionTypeMatching:
+		var pairs [][]int
+		if err := json.Unmarshal(answer, &pairs); err != nil || pairs == nil {
+			return answer
+		}
+		for _, p := range pairs {
+			if len(p) == 2 {
+				p[1] = original(p[1])
+			}
+		}
+		unshuffled = pairs
+	default:
+		return answer
+	}
+
+	data, err := json.Marshal(unshuffled)
+	if err != nil {
+		return answer
+	}
+	return data
+}
+
+// shuffleOrder returns the permutation applied to the question's shuffled list, where
+// order[newIndex] is the original index, or nil when the question type is not shuffled
+// Each question gets its own stream so its shuffle does not depend on which other
+// questions were drawn
+func shuffleOrder(q *entities.ExtendedQuizQuestion, seed int64) []int {
+	var n int
+	switch q.Type {
+	case entities.QuestionTypeMultipleChoice, entities.QuestionTypeCodeAnalysis, entities.QuestionTypeMultipleSelect:
+		n = len(q.Options)
+	case entities.QuestionTypeMatching:
+		n = len(q.RightColumn)
+	case entities.QuestionTypeOrdering:
+		n = len(q.Items)
+	default:
+		return nil
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(q.ID))
+	rng := rand.New(rand.NewPCG(uint64(seed), h.Sum64()))
+	return rng.Perm(n)
+}
+
+// shuffleQuestion permutes the question's options, matching column or items and
+// rewrites the answer key in terms of the new positions
+func shuffleQuestion(q entities.ExtendedQuizQuestion, seed int64) entities.ExtendedQuizQuestion {
+	order := shuffleOrder(&q, seed)
+	if order == nil {
+		return q
+	}
+
+	// position[originalIndex] is where that entry ends up
+	position := make([]int, len(order))
+	for newIndex, oldIndex := range order {
+		position[oldIndex] = newIndex
+	}
+
+	switch q.Type {
+	case entities.QuestionTypeMultipleChoice, entities.QuestionTypeCodeAnalysis:
+		q.Options = permute(q.Options, order)
+		if q.CorrectIndex >= 0 && q.CorrectIndex < len(position) {
+			q.CorrectIndex = position[q.CorrectIndex]
+		}
+	case entities.QuestionTypeMultipleSelect:
+		q.Options = permute(q.Options, order)
+		q.CorrectIndices = remapIndices(q.CorrectIndices, position)
+		sort.Ints(q.CorrectIndices)
+	case entities.QuestionTypeMatching:
+		q.RightColumn = permute(q.RightColumn, order)
+		pairs := make([][]int, len(q.CorrectPairs))
+		for i, p := range q.CorrectPairs {
+			pairs[i] = p
+			if len(p) == 2 && p[1] >= 0 && p[1] < len(position) {
+				pairs[i] = []int{p[0], position[p[1]]}
+			}
+		}
+		q.CorrectPairs = pairs
+	case entities.QuestionTypeOrdering:
+		q.Items = permute(q.Items, order)
+		q.CorrectOrder = remapIndices(q.CorrectOrder, position)
+	}
+	return q
+}
+
+// permute returns a copy of values rearranged so that entry i is values[order[i]]
+func permute(values []string, order []int) []string {
+	shuffled := make([]string, len(values))
+	for newIndex, oldIndex := range order {
+		shuffled[newIndex] = values[oldIndex]
+	}
+	return shuffled
+}
+
+// remapIndices translates original indices to their shuffled positions
+func remapIndices(indices []int, position []int) []int {
+	if indices == nil {
+		return nil
+	}
+	remapped := make([]int, len(indices))
+	for i, idx := range indices {
+		remapped[i] = idx
+		if idx >= 0 && idx < len(position) {
+			remapped[i] = position[idx]
+		}
+	}
+	return remapped
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/project/backend/domain/entities"
+)
+
+func newAssemblerTestPool() []entities.ExtendedQuizQuestion {
+	var pool []entities.ExtendedQuizQuestion
+	for i, difficulty := range []int{1, 1, 2, 2, 2, 3, 3, 3, 3, 3, 4, 4, 5, 5} {
+		pool = append(pool, entities.ExtendedQuizQuestion{
+			ID:           fmt.Sprintf("q%d", i),
+			Type:         entities.QuestionTypeMultipleChoice,
+			Difficulty:   difficulty,
+			Options:      []string{"a", "b", "c", "d"},
+			CorrectIndex: i % 4,
+		})
+	}
+	return pool
+}
+
+func TestQuizAssembler_SelectQuestions_MatchesDistribution(t *testing.T) {
+	assembler := NewQuizAssembler()
+	pool := newAssemblerTestPool()
+	distribution := map[string]float64{"easy": 0.2, "medium": 0.5, "hard": 0.3}
+
+	ids := assembler.SelectQuestions(pool, 10, distribution, 42)
+	if len(ids) != 10 {
+		t.Fatalf("expected 10 questions, got %d", len(ids))
+	}
+
+	difficulty := make(map[string]int)
+	for _, q := range pool {
+		difficulty[q.ID] = q.Difficulty
+	}
+	bands := make(map[string]int)
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		if seen[id] {
+			t.Errorf("question %s selected twice", id)
+		}
+		seen[id] = true
+		bands[entities.DifficultyBand(difficulty[id])]++
+	}
+
+	want := map[string]int{"easy": 2, "medium": 5, "hard": 3}
+	for band, count := range want {
+		if bands[band] != count {
+			t.Errorf("expected %d %s questions, got %d", count, band, bands[band])
+		}
+	}
+}
+
+func TestQuizAssembler_SelectQuestions_FillsShortBands(t *testing.T) {
+	assembler := NewQuizAssembler()
+	pool := newAssemblerTestPool()[:6] // five easy questions and one medium
+
+	ids := assembler.SelectQuestions(pool, 4, map[string]float64{"hard": 1}, 7)
+	if len(ids) != 4 {
+		t.Errorf("expected the missing hard questions to be filled, got %d questions", len(ids))
+	}
+
+	ids = assembler.SelectQuestions(pool, 0, nil, 7)
+	if len(ids) != len(pool) {
+		t.Errorf("expected the whole pool for a zero count, got %d questions", len(ids))
+	}
+}
+
+func TestQuizAssembler_SelectQuestions_Seeded(t *testing.T) {
+	assembler := NewQuizAssembler()
+	pool := newAssemblerTestPool()
+	distribution := entities.DefaultQuizConfig().DifficultyDistribution
+
+	first := assembler.SelectQuestions(pool, 6, distribution, 1)
+	again := assembler.SelectQuestions(pool, 6, distribution, 1)
+	for i := range first {
+		if first[i] != again[i] {
+			t.Fatalf("expected the same seed to select %v, got %v", first, again)
+		}
+	}
+
+	differs := false
+	for seed := int64(2); seed < 10 && !differs; seed++ {
+		other := assembler.SelectQuestions(pool, 6, distribution, seed)
+		for i := range first {
+			if first[i] != other[i] {
+				differs = true
+			}
+		}
+	}
+	if !differs {
+		t.Error("expected different seeds to produce different quizzes")
+	}
+}
+
+func TestQuizAssembler_BuildQuestions_RemapsAnswerKeys(t *testing.T) {
+	assembler := NewQuizAssembler()
+	grader := NewQuizGrader(nil)
+	trueAnswer := true
+
+	pool := []entities.ExtendedQuizQuestion{
+		{ID: "mc", Type: entities.QuestionTypeMultipleChoice, Options: []string{"a", "b", "c", "d"}, CorrectIndex: 2},
+		{ID: "tf", Type: entities.QuestionTypeTrueFalse, CorrectAnswer: &trueAnswer},
+		{ID: "ms", Type: entities.QuestionTypeMultipleSelect, Options: []string{"a", "b", "c", "d"}, CorrectIndices: []int{0, 3}},
+		{ID: "match", Type: entities.QuestionTypeMatching, LeftColumn: []string{"1", "2", "3"}, RightColumn: []string{"one", "two", "three"}, CorrectPairs: [][]int{{0, 0}, {1, 1}, {2, 2}}},
+		{ID: "order", Type: entities.QuestionTypeOrdering, Items: []string{"first", "second", "third", "fourth"}, CorrectOrder: []int{0, 1, 2, 3}},
+	}
+
+	questions, err := assembler.BuildQuestions(pool, []string{"order", "mc", "tf", "ms", "match"}, 99)
+	if err != nil {
+		t.Fatalf("BuildQuestions failed: %v", err)
+	}
+	if questions[0].ID != "order" || questions[1].ID != "mc" {
+		t.Errorf("expected questions in the requested order, got %s, %s", questions[0].ID, questions[1].ID)
+	}
+
+	// Answers chosen by content must still be graded correct after shuffling
+	for _, q := range questions {
+		var answer any
+		switch q.ID {
+		case "mc":
+			answer = indexOf(q.Options, "c")
+		case "tf":
+			answer = true
+		case "ms":
+			answer = []int{indexOf(q.Options, "a"), indexOf(q.Options, "d")}
+		case "match":
+			answer = [][]int{{0, indexOf(q.RightColumn, "one")}, {1, indexOf(q.RightColumn, "two")}, {2, indexOf(q.RightColumn, "three")}}
+		case "order":
+			answer = []int{indexOf(q.Items, "first"), indexOf(q.Items, "second"), indexOf(q.Items, "third"), indexOf(q.Items, "fourth")}
+		}
+		raw, _ := json.Marshal(answer)
+
+		grade, err := grader.GradeQuestion(context.Background(), &q, raw)
+		if err != nil {
+			t.Fatalf("%s: GradeQuestion failed: %v", q.ID, err)
+		}
+		if !grade.IsCorrect {
+			t.Errorf("%s: expected answer %s to be correct after shuffling", q.ID, raw)
+		}
+	}
+
+	if pool[0].Options[2] != "c" || pool[0].CorrectIndex != 2 {
+		t.Error("expected the pool to be left unchanged")
+	}
+
+	rebuilt, _ := assembler.BuildQuestions(pool, []string{"mc"}, 99)
+	if rebuilt[0].CorrectIndex != questions[1].CorrectIndex {
+		t.Error("expected the same seed to rebuild the same shuffle")
+	}
+
+	if _, err := assembler.BuildQuestions(pool, []string{"missing"}, 99); err != entities.ErrQuestionNotFound {
+		t.Errorf("expected ErrQuestionNotFound, got %v", err)
+	}
+}
+
+func TestQuizAssembler_UnshuffleAnswer(t *testing.T) {
+	assembler := NewQuizAssembler()
+	pool := []entities.ExtendedQuizQuestion{
+		{ID: "mc", Type: entities.QuestionTypeMultipleChoice, Options: []string{"a", "b", "c", "d"}},
+		{ID: "ms", Type: entities.QuestionTypeMultipleSelect, Options: []string{"a", "b", "c", "d"}},
+		{ID: "match", Type: entities.QuestionTypeMatching, LeftColumn: []string{"1", "2", "3"}, RightColumn: []string{"one", "two", "three"}},
+		{ID: "order", Type: entities.QuestionTypeOrdering, Items: []string{"first", "second", "third"}},
+	}
+
+	questions, err := assembler.BuildQuestions(pool, []string{"mc", "ms", "match", "order"}, 5)
+	if err != nil {
+		t.Fatalf("BuildQuestions failed: %v", err)
+	}
+
+	tests := []struct {
+		question *entities.ExtendedQuizQuestion
+		answer   any
+		want     string
+	}{
+		{&questions[0], indexOf(questions[0].Options, "c"), `2`},
+		{&questions[1], []int{indexOf(questions[1].Options, "d"), indexOf(questions[1].Options, "a")}, `[3,0]`},
+		{&questions[2], [][]int{{0, indexOf(questions[2].RightColumn, "three")}}, `[[0,2]]`},
+		{&questions[3], []int{indexOf(questions[3].Items, "second"), indexOf(questions[3].Items, "first"), indexOf(questions[3].Items, "third")}, `[1,0,2]`},
+	}
+
+	for _, tt := range tests {
+		raw, _ := json.Marshal(tt.answer)
+		got := assembler.UnshuffleAnswer(tt.question, 5, raw)
+		if string(got) != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.question.ID, tt.want, got)
+		}
+	}
+
+	if got := assembler.UnshuffleAnswer(&questions[0], 5, json.RawMessage(`null`)); string(got) != `null` {
+		t.Errorf("expected unparseable answers to be kept, got %s", got)
+	}
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
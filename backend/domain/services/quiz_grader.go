@@ -0,0 +1,528 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/project/backend/domain/entities"
+)
+
+// MaxCodeSubmissionBytes is the largest code_exercise submission the grader will run
+const MaxCodeSubmissionBytes = 64 * 1024
+
+// CodeRunner runs a code_exercise's hidden tests against a learner's submission
+type CodeRunner interface {
+	// RunTests reports the outcome of each test in the question's TestCode
+	// Code that fails to build or exceeds its limits is reported as failed tests;
+	// an error means the submission could not be run at all
+	RunTests(ctx context.Context, q *entities.ExtendedQuizQuestion, code string) ([]entities.CodeTestResult, error)
+}
+
+// QuizGrader grades learner answers against an extended quiz answer key
+type QuizGrader struct {
+	runner CodeRunner
+}
+
+// QuestionGrade is the graded result of a single question
+type QuestionGrade struct {
+	QuestionID     string
+	IsCorrect      bool
+	Credit         float64 // Fraction of the question answered correctly (0-1)
+	PointsEarned   int
+	PointsPossible int
+	TestResults    []entities.CodeTestResult
+}
+
+// QuizGrade is the graded result of a whole quiz
+type QuizGrade struct {
+	Score          int
+	MaxScore       int
+	TotalQuestions int
+	CorrectCount   int
+	Responses      []entities.QuizResponse
+}
+
+// NewQuizGrader creates a new quiz grader
+// The runner grades code exercises; without one they fail with ErrCodeRunnerUnavailable
+func NewQuizGrader(runner CodeRunner) *QuizGrader {
+	return &QuizGrader{runner: runner}
+}
+
+// GradeQuiz grades a set of answers against every question in the quiz
+// Questions without an answer are graded as wrong and recorded with a null answer
+func (g *QuizGrader) GradeQuiz(ctx context.Context, quiz *entities.ExtendedQuiz, answers []entities.QuizAnswer) (*QuizGrade, error) {
+	if quiz == nil || len(quiz.Questions) == 0 {
+		return nil, entities.ErrQuizNotFound
+	}
+
+	byQuestion := make(map[string]entities.QuizAnswer, len(answers))
+	for _, a := range answers {
+		if _, ok := byQuestion[a.QuestionID]; ok {
+			return nil, fmt.Errorf("%w: %s", entities.ErrDuplicateAnswer, a.QuestionID)
+		}
+		byQuestion[a.QuestionID] = a
+	}
+
+	known := make(map[string]bool, len(quiz.Questions))
+	for _, q := range quiz.Questions {
+		known[q.ID] = true
+	}
+	for _, a := range answers {
+		if !known[a.QuestionID] {
+			return nil, fmt.Errorf("%w: %s", entities.ErrQuestionNotFound, a.QuestionID)
+		}
+	}
+
+	result := &QuizGrade{
+		TotalQuestions: len(quiz.Questions),
+		Responses:      make([]entities.QuizResponse, 0, len(quiz.Questions)),
+	}
+
+	for i := range quiz.Questions {
+		q := &quiz.Questions[i]
+		answer, answered := byQuestion[q.ID]
+
+		var grade *QuestionGrade
+		if answered {
+			var err error
+			grade, err = g.GradeQuestion(ctx, q, answer.Answer)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			answer.Answer = json.RawMessage("null")
+			grade = &QuestionGrade{QuestionID: q.ID, PointsPossible: pointsPossible(q)}
+		}
+
+		result.Score += grade.PointsEarned
+		result.MaxScore += grade.PointsPossible
+		if grade.IsCorrect {
+			result.CorrectCount++
+		}
+
+		result.Responses = append(result.Responses, entities.QuizResponse{
+			QuestionID:     q.ID,
+			UserAnswer:     answer.Answer,
+			IsCorrect:      grade.IsCorrect,
+			PointsEarned:   grade.PointsEarned,
+			PointsPossible: grade.PointsPossible,
+			Confidence:     answer.Confidence,
+			TimeTakenSec:   answer.TimeTakenSec,
+			Concept:        q.Concept,
+			TestResults:    grade.TestResults,
+		})
+	}
+
+	return result, nil
+}
+
+// GradeQuestion grades a single raw JSON answer against a question
+//
+// Answer formats by question type:
+//   - multiple_choice, code_analysis: option index (2)
+//   - true_false: boolean (true)
+//   - multiple_select: option indices ([0, 2])
+//   - matching: [left, right] index pairs ([[0, 1], [1, 0]])
+//   - ordering: item indices in the chosen order ([2, 0, 1])
+//   - fill_blank, short_answer: the text typed in ("ports")
+//   - numeric: a number (42), or a string with an optional unit ("12.5 ms")
+//   - code_exercise: the submitted source file ("package shapes\n...")
+func (g *QuizGrader) GradeQuestion(ctx context.Context, q *entities.ExtendedQuizQuestion, answer json.RawMessage) (*QuestionGrade, error) {
+	var earned, total int
+	var results []entities.CodeTestResult
+	var err error
+
+	switch q.Type {
+	case entities.QuestionTypeMultipleChoice, entities.QuestionTypeCodeAnalysis:
+		earned, total, err = gradeSingleChoice(q, answer)
+	case entities.QuestionTypeTrueFalse:
+		earned, total, err = gradeTrueFalse(q, answer)
+	case entities.QuestionTypeMultipleSelect:
+		earned, total, err = gradeMultipleSelect(q, answer)
+	case entities.QuestionTypeMatching:
+		earned, total, err = gradeMatching(q, answer)
+	case entities.QuestionTypeOrdering:
+		earned, total, err = gradeOrdering(q, answer)
+	case entities.QuestionTypeFillBlank:
+		earned, total, err = gradeFillBlank(q, answer)
+	case entities.QuestionTypeShortAnswer:
+		earned, total, err = gradeShortAnswer(q, answer)
+	case entities.QuestionTypeNumeric:
+		earned, total, err = gradeNumeric(q, answer)
+	case entities.QuestionTypeCodeExercise:
+		if g.runner == nil {
+			return nil, entities.ErrCodeRunnerUnavailable
+		}
+		var code string
+		if code, err = parseCodeSubmission(answer); err == nil {
+			earned, total, results, err = g.gradeCodeExercise(ctx, q, code)
+			if err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", entities.ErrUnsupportedQuestion, q.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %v", entities.ErrInvalidAnswer, q.ID, err)
+	}
+
+	possible := pointsPossible(q)
+	grade := &QuestionGrade{
+		QuestionID:     q.ID,
+		PointsPossible: possible,
+		TestResults:    results,
+	}
+	if total > 0 {
+		grade.Credit = float64(earned) / float64(total)
+		grade.PointsEarned = possible * earned / total
+		grade.IsCorrect = earned == total
+	}
+
+	return grade, nil
+}
+
+// pointsPossible weights each question by its difficulty, matching the frontend scoring
+func pointsPossible(q *entities.ExtendedQuizQuestion) int {
+	if q.Difficulty < 1 {
+		return 1
+	}
+	return q.Difficulty
+}
+
+// gradeSingleChoice grades multiple_choice and code_analysis (all or nothing)
+func gradeSingleChoice(q *entities.ExtendedQuizQuestion, answer json.RawMessage) (int, int, error) {
+	var selected int
+	if err := json.Unmarshal(answer, &selected); err != nil {
+		return 0, 0, fmt.Errorf("expected an option index")
+	}
+	if selected < 0 || selected >= len(q.Options) {
+		return 0, 0, fmt.Errorf("option %d out of range", selected)
+	}
+	if selected == q.CorrectIndex {
+		return 1, 1, nil
+	}
+	return 0, 1, nil
+}
+
+// gradeTrueFalse grades true_false (all or nothing)
+func gradeTrueFalse(q *entities.ExtendedQuizQuestion, answer json.RawMessage) (int, int, error) {
+	var selected bool
+	if err := json.Unmarshal(answer, &selected); err != nil {
+		return 0, 0, fmt.Errorf("expected true or false")
+	}
+	if q.CorrectAnswer != nil && selected == *q.CorrectAnswer {
+		return 1, 1, nil
+	}
+	return 0, 1, nil
+}
+
+// gradeMultipleSelect awards one credit per correct option selected and removes one
+// per wrong option selected, floored at zero. Selecting fewer than MinSelections or
+// more than MaxSelections options earns nothing.
+func gradeMultipleSelect(q *entities.ExtendedQuizQuestion, answer json.RawMessage) (int, int, error) {
+	var selected []int
+	if err := json.Unmarshal(answer, &selected); err != nil {
+		return 0, 0, fmt.Errorf("expected a list of option indices")
+	}
+
+	seen := make(map[int]bool, len(selected))
+	for _, idx := range selected {
+		if idx < 0 || idx >= len(q.Options) {
+			return 0, 0, fmt.Errorf("option %d out of range", idx)
+		}
+		if seen[idx] {
+			return 0, 0, fmt.Errorf("option %d selected twice", idx)
+		}
+		seen[idx] = true
+	}
+
+	total := len(q.CorrectIndices)
+	if total == 0 {
+		// No correct options: only an empty selection is right
+		if len(selected) == 0 {
+			return 1, 1, nil
+		}
+		return 0, 1, nil
+	}
+
+	if q.MinSelections > 0 && len(selected) < q.MinSelections {
+		return 0, total, nil
+	}
+	if q.MaxSelections > 0 && len(selected) > q.MaxSelections {
+		return 0, total, nil
+	}
+
+	correct := make(map[int]bool, total)
+	for _, idx := range q.CorrectIndices {
+		correct[idx] = true
+	}
+
+	earned := 0
+	for _, idx := range selected {
+		if correct[idx] {
+			earned++
+		} else {
+			earned--
+		}
+	}
+	if earned < 0 {
+		earned = 0
+	}
+
+	return earned, total, nil
+}
+
+// gradeMatching awards one credit per left item matched to its correct right item
+func gradeMatching(q *entities.ExtendedQuizQuestion, answer json.RawMessage) (int, int, error) {
+	var pairs [][]int
+	if err := json.Unmarshal(answer, &pairs); err != nil {
+		return 0, 0, fmt.Errorf("expected a list of [left, right] pairs")
+	}
+
+	matched := make(map[int]int, len(pairs))
+	for _, p := range pairs {
+		if len(p) != 2 {
+			return 0, 0, fmt.Errorf("pair must have exactly two indices")
+		}
+		left, right := p[0], p[1]
+		if left < 0 || left >= len(q.LeftColumn) || right < 0 || right >= len(q.RightColumn) {
+			return 0, 0, fmt.Errorf("pair [%d, %d] out of range", left, right)
+		}
+		if _, ok := matched[left]; ok {
+			return 0, 0, fmt.Errorf("left item %d matched twice", left)
+		}
+		matched[left] = right
+	}
+
+	earned := 0
+	for _, p := range q.CorrectPairs {
+		if len(p) != 2 {
+			continue
+		}
+		if right, ok := matched[p[0]]; ok && right == p[1] {
+			earned++
+		}
+	}
+
+	return earned, len(q.CorrectPairs), nil
+}
+
+// gradeOrdering awards one credit per item placed in its correct position
+func gradeOrdering(q *entities.ExtendedQuizQuestion, answer json.RawMessage) (int, int, error) {
+	var order []int
+	if err := json.Unmarshal(answer, &order); err != nil {
+		return 0, 0, fmt.Errorf("expected a list of item indices")
+	}
+	if len(order) != len(q.CorrectOrder) {
+		return 0, 0, fmt.Errorf("expected %d items, got %d", len(q.CorrectOrder), len(order))
+	}
+
+	seen := make(map[int]bool, len(order))
+	for _, idx := range order {
+		if idx < 0 || idx >= len(q.Items) {
+			return 0, 0, fmt.Errorf("item %d out of range", idx)
+		}
+		if seen[idx] {
+			return 0, 0, fmt.Errorf("item %d placed twice", idx)
+		}
+		seen[idx] = true
+	}
+
+	earned := 0
+	for i, idx := range order {
+		if idx == q.CorrectOrder[i] {
+			earned++
+		}
+	}
+
+	return earned, len(q.CorrectOrder), nil
+}
+
+// gradeFillBlank grades fill_blank (all or nothing) against the accepted answers and,
+// if set, the answer pattern. A pattern that does not compile accepts nothing
+func gradeFillBlank(q *entities.ExtendedQuizQuestion, answer json.RawMessage) (int, int, error) {
+	var text string
+	if err := json.Unmarshal(answer, &text); err != nil {
+		return 0, 0, fmt.Errorf("expected the text of the blank")
+	}
+
+	given := normalizeBlank(text, q.CaseSensitive)
+	if given == "" {
+		return 0, 1, nil
+	}
+
+	for _, accepted := range q.AcceptedAnswers {
+		if given == normalizeBlank(accepted, q.CaseSensitive) {
+			return 1, 1, nil
+		}
+	}
+
+	if q.AnswerPattern != "" {
+		pattern := "^(?:" + q.AnswerPattern + ")$"
+		if !q.CaseSensitive {
+			pattern = "(?i)" + pattern
+		}
+		if re, err := regexp.Compile(pattern); err == nil && re.MatchString(strings.Join(strings.Fields(text), " ")) {
+			return 1, 1, nil
+		}
+	}
+
+	return 0, 1, nil
+}
+
+// gradeShortAnswer grades short_answer (all or nothing) against the accepted answers,
+// treating the terms of each synonym group as the same word and ignoring trailing punctuation
+func gradeShortAnswer(q *entities.ExtendedQuizQuestion, answer json.RawMessage) (int, int, error) {
+	var text string
+	if err := json.Unmarshal(answer, &text); err != nil {
+		return 0, 0, fmt.Errorf("expected the text of the answer")
+	}
+
+	given := normalizeShortAnswer(q, text)
+	if len(given) == 0 {
+		return 0, 1, nil
+	}
+
+	for _, accepted := range q.AcceptedAnswers {
+		if slices.Equal(given, normalizeShortAnswer(q, accepted)) {
+			return 1, 1, nil
+		}
+	}
+
+	return 0, 1, nil
+}
+
+// normalizeShortAnswer splits an answer into normalized words, with every synonym
+// replaced by the first term of its group
+func normalizeShortAnswer(q *entities.ExtendedQuizQuestion, text string) []string {
+	text = strings.TrimRight(strings.TrimSpace(text), ".,;:!?")
+	words := strings.Fields(normalizeBlank(text, q.CaseSensitive))
+
+	for _, group := range q.Synonyms {
+		if len(group) == 0 {
+			continue
+		}
+		canonical := strings.Fields(normalizeBlank(group[0], q.CaseSensitive))
+		for _, synonym := range group[1:] {
+			if term := strings.Fields(normalizeBlank(synonym, q.CaseSensitive)); len(term) > 0 {
+				words = replaceTerm(words, term, canonical)
+			}
+		}
+	}
+
+	return words
+}
+
+// replaceTerm replaces every whole-word occurrence of term in words with replacement
+func replaceTerm(words, term, replacement []string) []string {
+	result := make([]string, 0, len(words))
+	for i := 0; i < len(words); {
+		if i+len(term) <= len(words) && slices.Equal(words[i:i+len(term)], term) {
+			result = append(result, replacement...)
+			i += len(term)
+			continue
+		}
+		result = append(result, words[i])
+		i++
+	}
+	return result
+}
+
+// numericAnswer matches a number followed by an optional unit, e.g. "-1.5e3 ms"
+var numericAnswer = regexp.MustCompile(`^([-+]?(?:\d+\.?\d*|\.\d+)(?:[eE][-+]?\d+)?)\s*(.*)$`)
+
+// gradeNumeric grades numeric (all or nothing) within the question's tolerance
+// An answer giving a unit other than the question's is wrong
+func gradeNumeric(q *entities.ExtendedQuizQuestion, answer json.RawMessage) (int, int, error) {
+	value, unit, err := parseNumericAnswer(answer)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if q.CorrectValue == nil {
+		return 0, 1, nil
+	}
+	if unit != "" && !strings.EqualFold(unit, q.Unit) {
+		return 0, 1, nil
+	}
+
+	tolerance := math.Max(q.AbsoluteTolerance, q.RelativeTolerance*math.Abs(*q.CorrectValue))
+	if math.Abs(value-*q.CorrectValue) <= tolerance {
+		return 1, 1, nil
+	}
+	return 0, 1, nil
+}
+
+// parseNumericAnswer reads a JSON number, or a JSON string holding a number and optional unit
+func parseNumericAnswer(answer json.RawMessage) (float64, string, error) {
+	var value float64
+	if err := json.Unmarshal(answer, &value); err == nil {
+		return value, "", nil
+	}
+
+	var text string
+	if err := json.Unmarshal(answer, &text); err != nil {
+		return 0, "", fmt.Errorf("expected a number")
+	}
+	match := numericAnswer.FindStringSubmatch(strings.TrimSpace(text))
+	if match == nil {
+		return 0, "", fmt.Errorf("expected a number")
+	}
+	value, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("expected a number")
+	}
+	return value, match[2], nil
+}
+
+// parseCodeSubmission reads a code_exercise answer, the source file as a JSON string
+func parseCodeSubmission(answer json.RawMessage) (string, error) {
+	var code string
+	if err := json.Unmarshal(answer, &code); err != nil {
+		return "", fmt.Errorf("expected the source code as a string")
+	}
+	if len(code) > MaxCodeSubmissionBytes {
+		return "", fmt.Errorf("submission is larger than %d bytes", MaxCodeSubmissionBytes)
+	}
+	return code, nil
+}
+
+// gradeCodeExercise runs the question's hidden tests against the submission and awards
+// one credit per passing test. An empty submission is wrong without being run
+func (g *QuizGrader) gradeCodeExercise(ctx context.Context, q *entities.ExtendedQuizQuestion, code string) (int, int, []entities.CodeTestResult, error) {
+	if strings.TrimSpace(code) == "" {
+		return 0, 1, nil, nil
+	}
+
+	results, err := g.runner.RunTests(ctx, q, code)
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("%w: %v", entities.ErrCodeRunnerUnavailable, err)
+	}
+	if len(results) == 0 {
+		return 0, 1, results, nil
+	}
+
+	passed := 0
+	for _, r := range results {
+		if r.Passed {
+			passed++
+		}
+	}
+	return passed, len(results), results, nil
+}
+
+// normalizeBlank trims and collapses whitespace, and lowercases unless case matters
+func normalizeBlank(text string, caseSensitive bool) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if !caseSensitive {
+		text = strings.ToLower(text)
+	}
+	return text
+}
@@ -0,0 +1,353 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/project/backend/domain/entities"
+)
+
+func boolPtr(b bool) *bool { return &b }
+
+func testQuiz() *entities.ExtendedQuiz {
+	return &entities.ExtendedQuiz{
+		Version: "1.0",
+		Questions: []entities.ExtendedQuizQuestion{
+			{ID: "mc", Type: entities.QuestionTypeMultipleChoice, Difficulty: 2, Concept: "basics", Options: []string{"a", "b", "c"}, CorrectIndex: 1},
+			{ID: "tf", Type: entities.QuestionTypeTrueFalse, Difficulty: 1, CorrectAnswer: boolPtr(false)},
+			{ID: "ms", Type: entities.QuestionTypeMultipleSelect, Difficulty: 4, Options: []string{"a", "b", "c", "d"}, CorrectIndices: []int{0, 2}, MinSelections: 1, MaxSelections: 3},
+			{ID: "match", Type: entities.QuestionTypeMatching, Difficulty: 3, LeftColumn: []string{"x", "y", "z"}, RightColumn: []string{"1", "2", "3"}, CorrectPairs: [][]int{{0, 1}, {1, 2}, {2, 0}}},
+			{ID: "order", Type: entities.QuestionTypeOrdering, Difficulty: 4, Items: []string{"a", "b", "c", "d"}, CorrectOrder: []int{2, 0, 3, 1}},
+		},
+	}
+}
+
+func findQuestion(t *testing.T, quiz *entities.ExtendedQuiz, id string) *entities.ExtendedQuizQuestion {
+	t.Helper()
+	for i := range quiz.Questions {
+		if quiz.Questions[i].ID == id {
+			return &quiz.Questions[i]
+		}
+	}
+	t.Fatalf("question %s not found", id)
+	return nil
+}
+
+func TestGradeQuestion(t *testing.T) {
+	grader := NewQuizGrader(nil)
+	quiz := testQuiz()
+
+	tests := []struct {
+		name       string
+		questionID string
+		answer     string
+		wantPoints int
+		wantRight  bool
+	}{
+		{"multiple choice correct", "mc", `1`, 2, true},
+		{"multiple choice wrong", "mc", `0`, 0, false},
+		{"true false correct", "tf", `false`, 1, true},
+		{"true false wrong", "tf", `true`, 0, false},
+		{"multiple select all correct", "ms", `[2, 0]`, 4, true},
+		{"multiple select half correct", "ms", `[0]`, 2, false},
+		{"multiple select wrong cancels right", "ms", `[0, 1]`, 0, false},
+		{"multiple select over max", "ms", `[0, 1, 2, 3]`, 0, false},
+		{"multiple select under min", "ms", `[]`, 0, false},
+		{"matching all pairs", "match", `[[0,1],[1,2],[2,0]]`, 3, true},
+		{"matching one pair", "match", `[[0,1],[1,0],[2,2]]`, 1, false},
+		{"ordering correct", "order", `[2,0,3,1]`, 4, true},
+		{"ordering two in place", "order", `[2,0,1,3]`, 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grade, err := grader.GradeQuestion(context.Background(), findQuestion(t, quiz, tt.questionID), json.RawMessage(tt.answer))
+			if err != nil {
+				t.Fatalf("GradeQuestion failed: %v", err)
+			}
+			if grade.PointsEarned != tt.wantPoints {
+				t.Errorf("expected %d points, got %d", tt.wantPoints, grade.PointsEarned)
+			}
+			if grade.IsCorrect != tt.wantRight {
+				t.Errorf("expected isCorrect %v, got %v", tt.wantRight, grade.IsCorrect)
+			}
+		})
+	}
+}
+
+func TestGradeQuestion_InvalidAnswers(t *testing.T) {
+	grader := NewQuizGrader(nil)
+	quiz := testQuiz()
+
+	tests := []struct {
+		name       string
+		questionID string
+		answer     string
+	}{
+		{"wrong json type", "mc", `"b"`},
+		{"option out of range", "mc", `7`},
+		{"duplicate selection", "ms", `[0, 0]`},
+		{"left matched twice", "match", `[[0,1],[0,2]]`},
+		{"malformed pair", "match", `[[0]]`},
+		{"ordering missing items", "order", `[2,0]`},
+		{"ordering repeats item", "order", `[2,2,3,1]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := grader.GradeQuestion(context.Background(), findQuestion(t, quiz, tt.questionID), json.RawMessage(tt.answer))
+			if !errors.Is(err, entities.ErrInvalidAnswer) {
+				t.Errorf("expected ErrInvalidAnswer, got %v", err)
+			}
+		})
+	}
+}
+
+func TestGradeQuestion_FillBlank(t *testing.T) {
+	grader := NewQuizGrader(nil)
+	blank := &entities.ExtendedQuizQuestion{ID: "blank", Type: entities.QuestionTypeFillBlank, AcceptedAnswers: []string{"ports", "port interfaces"}}
+	exact := &entities.ExtendedQuizQuestion{ID: "exact", Type: entities.QuestionTypeFillBlank, AcceptedAnswers: []string{"HTTP"}, CaseSensitive: true}
+	pattern := &entities.ExtendedQuizQuestion{ID: "pattern", Type: entities.QuestionTypeFillBlank, AnswerPattern: `colou?r`}
+	broken := &entities.ExtendedQuizQuestion{ID: "broken", Type: entities.QuestionTypeFillBlank, AnswerPattern: `(`}
+
+	tests := []struct {
+		name      string
+		question  *entities.ExtendedQuizQuestion
+		answer    string
+		wantRight bool
+	}{
+		{"accepted answer", blank, `"ports"`, true},
+		{"case is ignored by default", blank, `"Ports"`, true},
+		{"whitespace is normalised", blank, `"  port   interfaces "`, true},
+		{"other text is wrong", blank, `"adapters"`, false},
+		{"empty answer is wrong", blank, `"  "`, false},
+		{"case sensitive match", exact, `"HTTP"`, true},
+		{"case sensitive mismatch", exact, `"http"`, false},
+		{"pattern match", pattern, `"Colour"`, true},
+		{"pattern must match in full", pattern, `"colours"`, false},
+		{"invalid pattern accepts nothing", broken, `"("`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grade, err := grader.GradeQuestion(context.Background(), tt.question, json.RawMessage(tt.answer))
+			if err != nil {
+				t.Fatalf("GradeQuestion failed: %v", err)
+			}
+			if grade.IsCorrect != tt.wantRight {
+				t.Errorf("expected isCorrect %v, got %v", tt.wantRight, grade.IsCorrect)
+			}
+		})
+	}
+
+	if _, err := grader.GradeQuestion(context.Background(), blank, json.RawMessage(`3`)); !errors.Is(err, entities.ErrInvalidAnswer) {
+		t.Errorf("expected ErrInvalidAnswer for a non-string answer, got %v", err)
+	}
+}
+
+func TestGradeQuestion_Numeric(t *testing.T) {
+	grader := NewQuizGrader(nil)
+	value := func(v float64) *float64 { return &v }
+	exact := &entities.ExtendedQuizQuestion{ID: "exact", Type: entities.QuestionTypeNumeric, CorrectValue: value(3)}
+	absolute := &entities.ExtendedQuizQuestion{ID: "absolute", Type: entities.QuestionTypeNumeric, CorrectValue: value(9.81), AbsoluteTolerance: 0.05, Unit: "m/s2"}
+	relative := &entities.ExtendedQuizQuestion{ID: "relative", Type: entities.QuestionTypeNumeric, CorrectValue: value(200), RelativeTolerance: 0.1, Unit: "ms"}
+
+	tests := []struct {
+		name      string
+		question  *entities.ExtendedQuizQuestion
+		answer    string
+		wantRight bool
+	}{
+		{"exact number", exact, `3`, true},
+		{"exact number as text", exact, `" 3 "`, true},
+		{"off by one", exact, `4`, false},
+		{"within absolute tolerance", absolute, `9.8`, true},
+		{"outside absolute tolerance", absolute, `9.7`, false},
+		{"with the unit", absolute, `"9.8 m/s2"`, true},
+		{"within relative tolerance", relative, `"215ms"`, true},
+		{"outside relative tolerance", relative, `"225 ms"`, false},
+		{"unit is case insensitive", relative, `"190 MS"`, true},
+		{"wrong unit", relative, `"200 s"`, false},
+		{"scientific notation", relative, `"2e2"`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grade, err := grader.GradeQuestion(context.Background(), tt.question, json.RawMessage(tt.answer))
+			if err != nil {
+				t.Fatalf("GradeQuestion failed: %v", err)
+			}
+			if grade.IsCorrect != tt.wantRight {
+				t.Errorf("expected isCorrect %v, got %v", tt.wantRight, grade.IsCorrect)
+			}
+		})
+	}
+
+	for _, answer := range []string{`"lots"`, `true`, `[3]`} {
+		if _, err := grader.GradeQuestion(context.Background(), exact, json.RawMessage(answer)); !errors.Is(err, entities.ErrInvalidAnswer) {
+			t.Errorf("expected ErrInvalidAnswer for %s, got %v", answer, err)
+		}
+	}
+}
+
+func TestGradeQuestion_ShortAnswer(t *testing.T) {
+	grader := NewQuizGrader(nil)
+	question := &entities.ExtendedQuizQuestion{
+		ID:              "short",
+		Type:            entities.QuestionTypeShortAnswer,
+		AcceptedAnswers: []string{"io.Reader", "reader interface"},
+		Synonyms:        [][]string{{"interface", "iface", "contract"}, {"io.Reader", "Reader"}},
+	}
+
+	tests := []struct {
+		name      string
+		answer    string
+		wantRight bool
+	}{
+		{"accepted answer", `"io.Reader"`, true},
+		{"case and whitespace ignored", `"  READER   Interface "`, true},
+		{"trailing punctuation ignored", `"io.Reader."`, true},
+		{"synonym of a whole answer", `"reader"`, true},
+		{"synonym inside an answer", `"reader contract"`, true},
+		{"synonyms only match whole words", `"readers interface"`, false},
+		{"wrong answer", `"io.Writer"`, false},
+		{"empty answer", `""`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grade, err := grader.GradeQuestion(context.Background(), question, json.RawMessage(tt.answer))
+			if err != nil {
+				t.Fatalf("GradeQuestion failed: %v", err)
+			}
+			if grade.IsCorrect != tt.wantRight {
+				t.Errorf("expected isCorrect %v, got %v", tt.wantRight, grade.IsCorrect)
+			}
+		})
+	}
+}
+
+// fakeCodeRunner passes the tests named in the submitted code
+type fakeCodeRunner struct {
+	tests []string
+	err   error
+}
+
+func (r *fakeCodeRunner) RunTests(_ context.Context, _ *entities.ExtendedQuizQuestion, code string) ([]entities.CodeTestResult, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	results := make([]entities.CodeTestResult, len(r.tests))
+	for i, name := range r.tests {
+		results[i] = entities.CodeTestResult{Name: name, Passed: strings.Contains(code, name)}
+	}
+	return results, nil
+}
+
+func TestGradeQuestion_CodeExercise(t *testing.T) {
+	question := &entities.ExtendedQuizQuestion{ID: "code", Type: entities.QuestionTypeCodeExercise, Difficulty: 4, TestCode: "package shapes"}
+	grader := NewQuizGrader(&fakeCodeRunner{tests: []string{"TestArea", "TestPerimeter"}})
+
+	grade, err := grader.GradeQuestion(context.Background(), question, json.RawMessage(`"// TestArea TestPerimeter"`))
+	if err != nil {
+		t.Fatalf("GradeQuestion failed: %v", err)
+	}
+	if !grade.IsCorrect || grade.PointsEarned != 4 || len(grade.TestResults) != 2 {
+		t.Errorf("expected full credit with both test results, got %+v", grade)
+	}
+
+	grade, _ = grader.GradeQuestion(context.Background(), question, json.RawMessage(`"// TestArea"`))
+	if grade.IsCorrect || grade.Credit != 0.5 || grade.PointsEarned != 2 {
+		t.Errorf("expected half credit for one passing test, got %+v", grade)
+	}
+
+	grade, _ = grader.GradeQuestion(context.Background(), question, json.RawMessage(`"  "`))
+	if grade.IsCorrect || grade.TestResults != nil {
+		t.Errorf("expected an empty submission to be wrong without running, got %+v", grade)
+	}
+
+	if _, err := grader.GradeQuestion(context.Background(), question, json.RawMessage(`42`)); !errors.Is(err, entities.ErrInvalidAnswer) {
+		t.Errorf("expected ErrInvalidAnswer for a non-string submission, got %v", err)
+	}
+
+	failing := NewQuizGrader(&fakeCodeRunner{err: errors.New("no network namespace")})
+	if _, err := failing.GradeQuestion(context.Background(), question, json.RawMessage(`"code"`)); !errors.Is(err, entities.ErrCodeRunnerUnavailable) {
+		t.Errorf("expected ErrCodeRunnerUnavailable when the runner fails, got %v", err)
+	}
+	if _, err := NewQuizGrader(nil).GradeQuestion(context.Background(), question, json.RawMessage(`"code"`)); !errors.Is(err, entities.ErrCodeRunnerUnavailable) {
+		t.Errorf("expected ErrCodeRunnerUnavailable without a runner, got %v", err)
+	}
+
+	quiz := &entities.ExtendedQuiz{Questions: []entities.ExtendedQuizQuestion{*question}}
+	result, err := grader.GradeQuiz(context.Background(), quiz, []entities.QuizAnswer{{QuestionID: "code", Answer: json.RawMessage(`"// TestArea"`)}})
+	if err != nil {
+		t.Fatalf("GradeQuiz failed: %v", err)
+	}
+	if len(result.Responses[0].TestResults) != 2 || !result.Responses[0].TestResults[0].Passed {
+		t.Errorf("expected test results on the graded response, got %+v", result.Responses[0])
+	}
+}
+
+func TestGradeQuiz(t *testing.T) {
+	grader := NewQuizGrader(nil)
+
+	grade, err := grader.GradeQuiz(context.Background(), testQuiz(), []entities.QuizAnswer{
+		{QuestionID: "mc", Answer: json.RawMessage(`1`), Confidence: entities.ConfidenceHigh, TimeTakenSec: 12},
+		{QuestionID: "ms", Answer: json.RawMessage(`[0]`)},
+		{QuestionID: "order", Answer: json.RawMessage(`[2,0,3,1]`)},
+	})
+	if err != nil {
+		t.Fatalf("GradeQuiz failed: %v", err)
+	}
+
+	if grade.TotalQuestions != 5 {
+		t.Errorf("expected 5 questions, got %d", grade.TotalQuestions)
+	}
+	if grade.MaxScore != 14 {
+		t.Errorf("expected max score 14, got %d", grade.MaxScore)
+	}
+	if grade.Score != 8 {
+		t.Errorf("expected score 8, got %d", grade.Score)
+	}
+	if grade.CorrectCount != 2 {
+		t.Errorf("expected 2 correct, got %d", grade.CorrectCount)
+	}
+	if len(grade.Responses) != 5 {
+		t.Fatalf("expected a response per question, got %d", len(grade.Responses))
+	}
+
+	first := grade.Responses[0]
+	if first.Confidence != entities.ConfidenceHigh || first.TimeTakenSec != 12 {
+		t.Error("expected confidence and time taken to be carried into the response")
+	}
+	if first.Concept != "basics" {
+		t.Errorf("expected the question's concept on the response, got %q", first.Concept)
+	}
+
+	unanswered := grade.Responses[1]
+	if unanswered.QuestionID != "tf" || string(unanswered.UserAnswer) != "null" || unanswered.IsCorrect {
+		t.Errorf("expected unanswered question to be recorded as wrong with a null answer, got %+v", unanswered)
+	}
+}
+
+func TestGradeQuiz_RejectsUnknownAndDuplicateQuestions(t *testing.T) {
+	grader := NewQuizGrader(nil)
+
+	_, err := grader.GradeQuiz(context.Background(), testQuiz(), []entities.QuizAnswer{
+		{QuestionID: "missing", Answer: json.RawMessage(`1`)},
+	})
+	if !errors.Is(err, entities.ErrQuestionNotFound) {
+		t.Errorf("expected ErrQuestionNotFound, got %v", err)
+	}
+
+	_, err = grader.GradeQuiz(context.Background(), testQuiz(), []entities.QuizAnswer{
+		{QuestionID: "mc", Answer: json.RawMessage(`1`)},
+		{QuestionID: "mc", Answer: json.RawMessage(`1`)},
+	})
+	if !errors.Is(err, entities.ErrDuplicateAnswer) {
+		t.Errorf("expected ErrDuplicateAnswer, got %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"time"
+
+	"github.com/project/backend/domain/entities"
+)
+
+const (
+	// InitialReviewStability is the interval, in days, given to a newly queued question
+	InitialReviewStability = 1.0
+	// MinReviewStability keeps failed questions in the next day's review
+	MinReviewStability = 1.0
+	// MaxReviewStability caps the interval so mastered questions still resurface yearly
+	MaxReviewStability = 365.0
+	// DefaultDailyReviewCap is how many questions a learner reviews per day by default
+	DefaultDailyReviewCap = 50
+)
+
+// ReviewScheduler computes spaced repetition intervals for review queue items
+// A ReviewQueueItem's Stability is its current interval in days: a successful recall
+// multiplies it by a growth factor that depends on the learner's confidence, a lapse
+// shrinks it, and the next review is due Stability days after the attempt
+type ReviewScheduler struct{}
+
+// NewReviewScheduler creates a new review scheduler
+func NewReviewScheduler() *ReviewScheduler {
+	return &ReviewScheduler{}
+}
+
+// NewItem prepares a question that was just answered wrongly for its first review
+// The confidence of the wrong answer sets the item's priority
+func (s *ReviewScheduler) NewItem(item *entities.ReviewQueueItem, confidence entities.ConfidenceLevel, now time.Time) {
+	item.WrongCount = 1
+	item.Priority = entities.ReviewPriorityForMiss(confidence)
+	item.Stability = InitialReviewStability
+	item.LastAttempt = now
+	item.NextReview = nextReview(now, item.Stability)
+}
+
+// Schedule updates the item after a review and sets its next review date
+func (s *ReviewScheduler) Schedule(item *entities.ReviewQueueItem, correct bool, confidence entities.ConfidenceLevel, now time.Time) {
+	stability := item.Stability
+	if stability <= 0 {
+		stability = InitialReviewStability
+	}
+
+	if correct {
+		stability *= recallGrowth(confidence)
+		item.Priority = entities.ReviewPriorityLow
+	} else {
+		item.WrongCount++
+		stability *= lapseFactor(confidence)
+		item.Priority = entities.ReviewPriorityForMiss(confidence)
+	}
+
+	item.Stability = clampStability(stability)
+	item.LastAttempt = now
+	item.NextReview = nextReview(now, item.Stability)
+}
+
+// Interleave reorders due items so consecutive questions come from different concepts
+// Concepts take turns in the order they first fall due, keeping each concept's own order
+func (s *ReviewScheduler) Interleave(items []entities.ReviewQueueItem) []entities.ReviewQueueItem {
+	var concepts []string
+	byConcept := make(map[string][]entities.ReviewQueueItem)
+	for _, item := range items {
+		if _, ok := byConcept[item.Concept]; !ok {
+			concepts = append(concepts, item.Concept)
+		}
+		byConcept[item.Concept] = append(byConcept[item.Concept], item)
+	}
+
+	result := make([]entities.ReviewQueueItem, 0, len(items))
+	for len(result) < len(items) {
+		for _, concept := range concepts {
+			if queue := byConcept[concept]; len(queue) > 0 {
+				result = append(result, queue[0])
+				byConcept[concept] = queue[1:]
+			}
+		}
+	}
+
+	return result
+}
+
+// recallGrowth is how much the interval grows after a correct answer
+// A correct answer given with low confidence is treated as a partial recall
+func recallGrowth(confidence entities.ConfidenceLevel) float64 {
+	switch confidence {
+	case entities.ConfidenceLow:
+		return 1.2
+	case entities.ConfidenceHigh:
+		return 2.5
+	default:
+		return 2.0
+	}
+}
+
+// lapseFactor is how much the interval shrinks after a wrong answer
+// A confident wrong answer points at a misconception, so it starts over
+func lapseFactor(confidence entities.ConfidenceLevel) float64 {
+	if confidence == entities.ConfidenceHigh {
+		return 0
+	}
+	return 0.5
+}
+
+func clampStability(stability float64) float64 {
+	if stability < MinReviewStability {
+		return MinReviewStability
+	}
+	if stability > MaxReviewStability {
+		return MaxReviewStability
+	}
+	return stability
+}
+
+func nextReview(now time.Time, stability float64) time.Time {
+	return now.Add(time.Duration(stability * float64(24*time.Hour)))
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/project/backend/domain/entities"
+)
+
+func TestReviewScheduler_NewItem(t *testing.T) {
+	scheduler := NewReviewScheduler()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	item := &entities.ReviewQueueItem{}
+	scheduler.NewItem(item, entities.ConfidenceHigh, now)
+
+	if item.Priority != entities.ReviewPriorityHigh {
+		t.Errorf("expected a confidently wrong answer to get high priority, got %d", item.Priority)
+	}
+	if item.WrongCount != 1 {
+		t.Errorf("expected wrong count 1, got %d", item.WrongCount)
+	}
+	if item.Stability != InitialReviewStability {
+		t.Errorf("expected stability %v, got %v", InitialReviewStability, item.Stability)
+	}
+	if !item.NextReview.Equal(now.Add(24 * time.Hour)) {
+		t.Errorf("expected next review in 24h, got %v", item.NextReview)
+	}
+}
+
+func TestReviewScheduler_Schedule(t *testing.T) {
+	scheduler := NewReviewScheduler()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		stability     float64
+		correct       bool
+		confidence    entities.ConfidenceLevel
+		wantStability float64
+		wantWrong     int
+		wantPriority  int
+	}{
+		{"correct with high confidence", 4, true, entities.ConfidenceHigh, 10, 1, entities.ReviewPriorityLow},
+		{"correct with medium confidence", 4, true, entities.ConfidenceMedium, 8, 1, entities.ReviewPriorityLow},
+		{"correct without confidence", 4, true, "", 8, 1, entities.ReviewPriorityLow},
+		{"correct with low confidence", 5, true, entities.ConfidenceLow, 6, 1, entities.ReviewPriorityLow},
+		{"wrong halves the interval", 8, false, entities.ConfidenceMedium, 4, 2, entities.ReviewPriorityNormal},
+		{"confident wrong answer starts over", 8, false, entities.ConfidenceHigh, MinReviewStability, 2, entities.ReviewPriorityHigh},
+		{"wrong never drops below the minimum", 1, false, entities.ConfidenceLow, MinReviewStability, 2, entities.ReviewPriorityLow},
+		{"interval is capped", 300, true, entities.ConfidenceHigh, MaxReviewStability, 1, entities.ReviewPriorityLow},
+		{"missing stability starts from the initial interval", 0, true, entities.ConfidenceMedium, 2, 1, entities.ReviewPriorityLow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := &entities.ReviewQueueItem{Stability: tt.stability, WrongCount: 1}
+			scheduler.Schedule(item, tt.correct, tt.confidence, now)
+
+			if item.Stability != tt.wantStability {
+				t.Errorf("expected stability %v, got %v", tt.wantStability, item.Stability)
+			}
+			if item.WrongCount != tt.wantWrong {
+				t.Errorf("expected wrong count %d, got %d", tt.wantWrong, item.WrongCount)
+			}
+			if item.Priority != tt.wantPriority {
+				t.Errorf("expected priority %d, got %d", tt.wantPriority, item.Priority)
+			}
+			want := now.Add(time.Duration(tt.wantStability * float64(24*time.Hour)))
+			if !item.NextReview.Equal(want) {
+				t.Errorf("expected next review %v, got %v", want, item.NextReview)
+			}
+			if !item.LastAttempt.Equal(now) {
+				t.Errorf("expected last attempt %v, got %v", now, item.LastAttempt)
+			}
+		})
+	}
+}
+
+func TestReviewScheduler_RepeatedRecallsLengthenInterval(t *testing.T) {
+	scheduler := NewReviewScheduler()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	item := &entities.ReviewQueueItem{}
+	scheduler.NewItem(item, entities.ConfidenceMedium, now)
+
+	previous := item.NextReview.Sub(now)
+	for i := 0; i < 3; i++ {
+		now = item.NextReview
+		scheduler.Schedule(item, true, entities.ConfidenceMedium, now)
+		interval := item.NextReview.Sub(now)
+		if interval <= previous {
+			t.Fatalf("expected interval to grow past %v, got %v", previous, interval)
+		}
+		previous = interval
+	}
+}
+
+func TestReviewScheduler_Interleave(t *testing.T) {
+	scheduler := NewReviewScheduler()
+
+	items := []entities.ReviewQueueItem{
+		{QuestionID: "a1", Concept: "a"},
+		{QuestionID: "a2", Concept: "a"},
+		{QuestionID: "a3", Concept: "a"},
+		{QuestionID: "b1", Concept: "b"},
+		{QuestionID: "c1", Concept: "c"},
+		{QuestionID: "b2", Concept: "b"},
+	}
+
+	got := scheduler.Interleave(items)
+
+	want := []string{"a1", "b1", "c1", "a2", "b2", "a3"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d items, got %d", len(want), len(got))
+	}
+	for i, id := range want {
+		if got[i].QuestionID != id {
+			t.Errorf("expected %s at position %d, got %s", id, i, got[i].QuestionID)
+		}
+	}
+}
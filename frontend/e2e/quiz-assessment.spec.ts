@@ -21,29 +21,21 @@ import { test, expect } from '@playwright/test';
  *     And I should see multiple choice options
  *     And I should see a progress indicator
  *
- *   Scenario: User answers a question correctly
+ *   Scenario: User answers a question
  *     Given I am taking a quiz
  *     And I am on a question
- *     When I select the correct answer
- *     And I click "Submit Answer"
- *     Then I should see feedback indicating I was correct
- *     And I should be able to proceed to the next question
- *
- *   Scenario: User answers a question incorrectly
- *     Given I am taking a quiz
- *     And I am on a question
- *     When I select an incorrect answer
- *     And I click "Submit Answer"
- *     Then I should see feedback indicating I was wrong
- *     And I should see the correct answer
+ *     When I select an answer
+ *     And I click "Next Question"
+ *     Then I should see the next question
  *
  *   Scenario: User completes a quiz
  *     Given I am taking a quiz
  *     And I answer all questions
- *     When I submit the final answer
- *     Then I should see my quiz results
+ *     When I click "Submit Quiz"
+ *     Then the server grades my answers
+ *     And I should see my quiz results
  *     And I should see my score as a percentage
- *     And I should see which questions I got right and wrong
+ *     And I should see which questions I got right and wrong, with the correct answers
  *
  *   Scenario: User retakes a quiz
  *     Given I have completed a quiz
@@ -67,6 +59,20 @@ async function navigateToHexCourse(page: any) {
   await expect(page.getByRole('heading', { level: 1, name: /hexagonal architecture/i }).first()).toBeVisible();
 }
 
+// Answers every question with its first option and submits the quiz
+async function answerAllQuestions(page: any) {
+  for (let i = 0; i < 10; i++) { // Safety limit of 10 questions
+    await page.getByTestId('quiz-option').first().click();
+
+    const submitQuizButton = page.getByRole('button', { name: /submit quiz/i });
+    if (await submitQuizButton.isVisible().catch(() => false)) {
+      await submitQuizButton.click();
+      return;
+    }
+    await page.getByRole('button', { name: /next question/i }).click();
+  }
+}
+
 test.describe('Quiz Assessment System', () => {
   test.beforeEach(async ({ page }) => {
     await loginAsTestUser(page);
@@ -108,21 +114,18 @@ test.describe('Quiz Assessment System', () => {
     await expect(page.getByTestId('quiz-progress')).toBeVisible();
   });
 
-  test('should show correct feedback when answering correctly', async ({ page }) => {
+  test('should show feedback for each answer after submitting the quiz', async ({ page }) => {
     await navigateToHexCourse(page);
 
     // Navigate to lesson and start quiz
     await page.locator('[role="button"]').filter({ hasText: /introduction/i }).first().click();
     await page.getByRole('button', { name: /take quiz/i }).click();
 
-    // Select the first option (we'll need to know the correct answer in real implementation)
-    await page.getByTestId('quiz-option').first().click();
-
-    // Submit answer
-    await page.getByRole('button', { name: /submit answer/i }).click();
+    // Answers are graded by the server once the whole quiz is submitted
+    await answerAllQuestions(page);
 
-    // Should see the feedback container (correct or incorrect)
-    await expect(page.getByTestId('quiz-feedback')).toBeVisible();
+    // Each answer is shown with its feedback (correct or incorrect)
+    await expect(page.getByTestId('quiz-feedback').first()).toBeVisible();
   });
 
   test('should show quiz results after completing all questions', async ({ page }) => {
@@ -135,34 +138,7 @@ test.describe('Quiz Assessment System', () => {
     // Wait for quiz container to be visible
     await expect(page.getByTestId('quiz-container')).toBeVisible();
 
-    // Answer all questions (loop through)
-    for (let i = 0; i < 10; i++) { // Safety limit of 10 questions
-      // Wait for submit button to be available
-      await expect(page.getByRole('button', { name: /submit answer/i })).toBeVisible();
-
-      // Select first option
-      await page.getByTestId('quiz-option').first().click();
-
-      // Submit answer
-      await page.getByRole('button', { name: /submit answer/i }).click();
-
-      // Wait for feedback
-      await expect(page.getByTestId('quiz-feedback')).toBeVisible();
-
-      // Check for "See Results" or "Next Question" button
-      const seeResultsButton = page.getByRole('button', { name: /see results/i });
-      const nextQuestionButton = page.getByRole('button', { name: /next question/i });
-
-      // Check if quiz is complete (See Results button)
-      if (await seeResultsButton.isVisible().catch(() => false)) {
-        await seeResultsButton.click();
-        break;
-      } else if (await nextQuestionButton.isVisible().catch(() => false)) {
-        await nextQuestionButton.click();
-      } else {
-        break; // No more questions
-      }
-    }
+    await answerAllQuestions(page);
 
     // Should see results
     await expect(page.getByTestId('quiz-results')).toBeVisible();
@@ -182,24 +158,7 @@ test.describe('Quiz Assessment System', () => {
     await expect(page.getByTestId('quiz-container')).toBeVisible();
 
     // Complete the quiz (answer all questions)
-    for (let i = 0; i < 10; i++) {
-      await expect(page.getByRole('button', { name: /submit answer/i })).toBeVisible();
-      await page.getByTestId('quiz-option').first().click();
-      await page.getByRole('button', { name: /submit answer/i }).click();
-      await expect(page.getByTestId('quiz-feedback')).toBeVisible();
-
-      const seeResultsButton = page.getByRole('button', { name: /see results/i });
-      const nextQuestionButton = page.getByRole('button', { name: /next question/i });
-
-      if (await seeResultsButton.isVisible().catch(() => false)) {
-        await seeResultsButton.click();
-        break;
-      } else if (await nextQuestionButton.isVisible().catch(() => false)) {
-        await nextQuestionButton.click();
-      } else {
-        break;
-      }
-    }
+    await answerAllQuestions(page);
 
     // Wait for results to be visible
     await expect(page.getByTestId('quiz-results')).toBeVisible();
@@ -223,24 +182,7 @@ test.describe('Quiz Assessment System', () => {
     await expect(page.getByTestId('quiz-container')).toBeVisible();
 
     // Complete the quiz
-    for (let i = 0; i < 10; i++) {
-      await expect(page.getByRole('button', { name: /submit answer/i })).toBeVisible();
-      await page.getByTestId('quiz-option').first().click();
-      await page.getByRole('button', { name: /submit answer/i }).click();
-      await expect(page.getByTestId('quiz-feedback')).toBeVisible();
-
-      const seeResultsButton = page.getByRole('button', { name: /see results/i });
-      const nextQuestionButton = page.getByRole('button', { name: /next question/i });
-
-      if (await seeResultsButton.isVisible().catch(() => false)) {
-        await seeResultsButton.click();
-        break;
-      } else if (await nextQuestionButton.isVisible().catch(() => false)) {
-        await nextQuestionButton.click();
-      } else {
-        break;
-      }
-    }
+    await answerAllQuestions(page);
 
     // Wait for results
     await expect(page.getByTestId('quiz-results')).toBeVisible();
@@ -1,89 +1,112 @@
 import { useState, useCallback } from 'react';
 import type { Quiz as QuizType } from '../types/course';
 import { Button, Card, CardHeader, CardTitle, CardContent, Progress } from '@repo/playbook';
+import { quizStatsService } from '../services/quizStatsService';
+import type { QuizAttempt, QuestionAnswerKey } from '../services/quizStatsService';
 
 interface QuizProps {
   quiz: QuizType;
-  onComplete?: (score: number, total: number) => void;
+  courseId: string;
+  lessonPath: number[];
+  onComplete?: (attempt: QuizAttempt) => void;
   onClose?: () => void;
 }
 
 interface QuizState {
   currentQuestionIndex: number;
   selectedAnswer: number | null;
-  isSubmitted: boolean;
-  answers: { questionId: string; selectedIndex: number; isCorrect: boolean }[];
-  isComplete: boolean;
+  answers: { questionId: string; selectedIndex: number }[];
+  isSubmitting: boolean;
+  error: string | null;
+  attempt: QuizAttempt | null;
+  answerKeys: Record<string, QuestionAnswerKey>;
 }
 
-export function Quiz({ quiz, onComplete, onClose }: QuizProps) {
-  const [state, setState] = useState<QuizState>({
-    currentQuestionIndex: 0,
-    selectedAnswer: null,
-    isSubmitted: false,
-    answers: [],
-    isComplete: false,
-  });
+const initialState: QuizState = {
+  currentQuestionIndex: 0,
+  selectedAnswer: null,
+  answers: [],
+  isSubmitting: false,
+  error: null,
+  attempt: null,
+  answerKeys: {},
+};
+
+// The answers are graded on the server when the quiz is submitted; each question's
+// answer key is then revealed for the review, as learners never receive it beforehand
+export function Quiz({ quiz, courseId, lessonPath, onComplete, onClose }: QuizProps) {
+  const [state, setState] = useState<QuizState>(initialState);
 
   const currentQuestion = quiz.questions[state.currentQuestionIndex];
   const totalQuestions = quiz.questions.length;
-  const progress = ((state.currentQuestionIndex + (state.isSubmitted ? 1 : 0)) / totalQuestions) * 100;
+  const isLastQuestion = state.currentQuestionIndex === totalQuestions - 1;
+  const progress = (state.currentQuestionIndex / totalQuestions) * 100;
 
   const handleSelectAnswer = useCallback((index: number) => {
-    if (!state.isSubmitted) {
+    if (!state.isSubmitting) {
       setState(prev => ({ ...prev, selectedAnswer: index }));
     }
-  }, [state.isSubmitted]);
+  }, [state.isSubmitting]);
+
+  const submitQuiz = useCallback(async (answers: QuizState['answers']) => {
+    setState(prev => ({ ...prev, isSubmitting: true, error: null }));
+    try {
+      const attempt = await quizStatsService.submitQuizAttempt({
+        courseId,
+        lessonPath,
+        responses: answers.map(a => ({
+          questionId: a.questionId,
+          userAnswer: JSON.stringify(a.selectedIndex),
+        })),
+      });
+      const answerKeys = await quizStatsService.revealQuizQuestions(
+        courseId,
+        lessonPath,
+        answers.map(a => a.questionId)
+      );
+
+      setState(prev => ({ ...prev, answers, isSubmitting: false, attempt, answerKeys }));
+      onComplete?.(attempt);
+    } catch (err) {
+      setState(prev => ({
+        ...prev,
+        isSubmitting: false,
+        error: err instanceof Error ? err.message : 'Failed to submit quiz',
+      }));
+    }
+  }, [courseId, lessonPath, onComplete]);
 
-  const handleSubmitAnswer = useCallback(() => {
+  const handleNextQuestion = useCallback(() => {
     if (state.selectedAnswer === null) return;
 
-    const isCorrect = state.selectedAnswer === currentQuestion.correctIndex;
-    const newAnswer = {
-      questionId: currentQuestion.id,
-      selectedIndex: state.selectedAnswer,
-      isCorrect,
-    };
+    const answers = [
+      ...state.answers,
+      { questionId: currentQuestion.id, selectedIndex: state.selectedAnswer },
+    ];
+    if (isLastQuestion) {
+      submitQuiz(answers);
+      return;
+    }
 
     setState(prev => ({
       ...prev,
-      isSubmitted: true,
-      answers: [...prev.answers, newAnswer],
+      currentQuestionIndex: prev.currentQuestionIndex + 1,
+      selectedAnswer: null,
+      answers,
     }));
-  }, [state.selectedAnswer, currentQuestion]);
-
-  const handleNextQuestion = useCallback(() => {
-    if (state.currentQuestionIndex < totalQuestions - 1) {
-      setState(prev => ({
-        ...prev,
-        currentQuestionIndex: prev.currentQuestionIndex + 1,
-        selectedAnswer: null,
-        isSubmitted: false,
-      }));
-    } else {
-      // Quiz complete
-      const correctAnswers = state.answers.filter(a => a.isCorrect).length +
-        (state.selectedAnswer === currentQuestion.correctIndex ? 1 : 0);
-      setState(prev => ({ ...prev, isComplete: true }));
-      onComplete?.(correctAnswers, totalQuestions);
-    }
-  }, [state.currentQuestionIndex, state.answers, state.selectedAnswer, currentQuestion, totalQuestions, onComplete]);
+  }, [state.selectedAnswer, state.answers, currentQuestion, isLastQuestion, submitQuiz]);
 
   const handleRetakeQuiz = useCallback(() => {
-    setState({
-      currentQuestionIndex: 0,
-      selectedAnswer: null,
-      isSubmitted: false,
-      answers: [],
-      isComplete: false,
-    });
+    setState(initialState);
   }, []);
 
-  // Calculate final score
-  const correctCount = state.answers.filter(a => a.isCorrect).length;
-  const scorePercentage = Math.round((correctCount / totalQuestions) * 100);
+  if (state.attempt) {
+    const correctByQuestion: Record<string, boolean> = {};
+    for (const response of state.attempt.responses ?? []) {
+      correctByQuestion[response.questionId] = response.isCorrect;
+    }
+    const scorePercentage = Math.round(state.attempt.percentage);
 
-  if (state.isComplete) {
     return (
       <div data-testid="quiz-results" className="space-y-6">
         <Card>
@@ -102,18 +125,21 @@ export function Quiz({ quiz, onComplete, onClose }: QuizProps) {
                 {scorePercentage}%
               </div>
               <p className="text-muted-foreground">
-                You got {correctCount} out of {totalQuestions} questions correct
+                You got {state.attempt.correctCount} out of {totalQuestions} questions correct
               </p>
             </div>
 
             <div className="space-y-4">
               <h4 className="font-medium">Review Your Answers:</h4>
-              {quiz.questions.map((question, index) => {
-                const answer = state.answers[index];
-                const isCorrect = answer?.isCorrect;
+              {quiz.questions.map((question) => {
+                const answer = state.answers.find(a => a.questionId === question.id);
+                const key = state.answerKeys[question.id];
+                const isCorrect = correctByQuestion[question.id];
+                const correctIndex = key?.correctIndex ?? null;
                 return (
                   <div
                     key={question.id}
+                    data-testid="quiz-feedback"
                     className={`p-4 rounded-lg border ${
                       isCorrect ? 'bg-green-50 border-green-200' : 'bg-red-50 border-red-200'
                     }`}
@@ -126,17 +152,17 @@ export function Quiz({ quiz, onComplete, onClose }: QuizProps) {
                         <p className="font-medium">{question.question}</p>
                         <p className="text-sm mt-1">
                           <span className="text-muted-foreground">Your answer: </span>
-                          {question.options[answer?.selectedIndex ?? 0]}
+                          {answer ? question.options[answer.selectedIndex] : ''}
                         </p>
-                        {!isCorrect && (
+                        {!isCorrect && correctIndex !== null && (
                           <p className="text-sm text-green-600 mt-1">
                             <span className="font-medium">Correct answer: </span>
-                            {question.options[question.correctIndex]}
+                            {question.options[correctIndex]}
                           </p>
                         )}
-                        {question.explanation && (
+                        {key?.explanation && (
                           <p className="text-sm text-muted-foreground mt-2 italic">
-                            {question.explanation}
+                            {key.explanation}
                           </p>
                         )}
                       </div>
@@ -184,39 +210,26 @@ export function Quiz({ quiz, onComplete, onClose }: QuizProps) {
           <div data-testid="quiz-options" className="space-y-3">
             {currentQuestion.options.map((option, index) => {
               const isSelected = state.selectedAnswer === index;
-              const isCorrect = index === currentQuestion.correctIndex;
-              const showCorrect = state.isSubmitted && isCorrect;
-              const showIncorrect = state.isSubmitted && isSelected && !isCorrect;
 
               return (
                 <button
                   key={index}
                   data-testid="quiz-option"
                   onClick={() => handleSelectAnswer(index)}
-                  disabled={state.isSubmitted}
+                  disabled={state.isSubmitting}
                   className={`w-full p-4 text-left rounded-lg border-2 transition-colors ${
-                    showCorrect
-                      ? 'border-green-500 bg-green-50'
-                      : showIncorrect
-                      ? 'border-red-500 bg-red-50'
-                      : isSelected
+                    isSelected
                       ? 'border-primary bg-primary/5'
                       : 'border-gray-200 hover:border-gray-300'
-                  } ${state.isSubmitted ? 'cursor-default' : 'cursor-pointer'}`}
+                  } ${state.isSubmitting ? 'cursor-default' : 'cursor-pointer'}`}
                 >
                   <div className="flex items-center gap-3">
                     <div className={`w-6 h-6 rounded-full border-2 flex items-center justify-center ${
-                      showCorrect
-                        ? 'border-green-500 bg-green-500 text-white'
-                        : showIncorrect
-                        ? 'border-red-500 bg-red-500 text-white'
-                        : isSelected
+                      isSelected
                         ? 'border-primary bg-primary text-white'
                         : 'border-gray-300'
                     }`}>
-                      {showCorrect && '✓'}
-                      {showIncorrect && '✗'}
-                      {!state.isSubmitted && isSelected && '●'}
+                      {isSelected && '●'}
                     </div>
                     <span>{option}</span>
                   </div>
@@ -225,27 +238,8 @@ export function Quiz({ quiz, onComplete, onClose }: QuizProps) {
             })}
           </div>
 
-          {state.isSubmitted && (
-            <div data-testid="quiz-feedback" className={`p-4 rounded-lg ${
-              state.selectedAnswer === currentQuestion.correctIndex
-                ? 'bg-green-50 border border-green-200'
-                : 'bg-red-50 border border-red-200'
-            }`}>
-              <p className={`font-medium ${
-                state.selectedAnswer === currentQuestion.correctIndex
-                  ? 'text-green-700'
-                  : 'text-red-700'
-              }`}>
-                {state.selectedAnswer === currentQuestion.correctIndex
-                  ? 'Correct!'
-                  : 'Incorrect'}
-              </p>
-              {currentQuestion.explanation && (
-                <p className="text-sm mt-2 text-muted-foreground">
-                  {currentQuestion.explanation}
-                </p>
-              )}
-            </div>
+          {state.error && (
+            <p className="text-sm text-red-600">{state.error}</p>
           )}
 
           <div className="flex justify-between pt-4">
@@ -255,20 +249,16 @@ export function Quiz({ quiz, onComplete, onClose }: QuizProps) {
               </Button>
             )}
             <div className="ml-auto">
-              {!state.isSubmitted ? (
-                <Button
-                  onClick={handleSubmitAnswer}
-                  disabled={state.selectedAnswer === null}
-                >
-                  Submit Answer
-                </Button>
-              ) : (
-                <Button onClick={handleNextQuestion}>
-                  {state.currentQuestionIndex < totalQuestions - 1
-                    ? 'Next Question'
-                    : 'See Results'}
-                </Button>
-              )}
+              <Button
+                onClick={handleNextQuestion}
+                disabled={state.selectedAnswer === null || state.isSubmitting}
+              >
+                {!isLastQuestion
+                  ? 'Next Question'
+                  : state.isSubmitting
+                  ? 'Submitting...'
+                  : 'Submit Quiz'}
+              </Button>
             </div>
           </div>
         </CardContent>
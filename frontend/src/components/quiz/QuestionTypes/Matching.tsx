@@ -107,7 +107,7 @@ export function Matching({
   };
 
   const isPairCorrect = (leftIndex: number, rightIndex: number): boolean => {
-    return question.correctPairs.some(
+    return (question.correctPairs ?? []).some(
       ([l, r]) => l === leftIndex && r === rightIndex
     );
   };
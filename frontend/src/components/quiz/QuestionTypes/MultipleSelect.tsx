@@ -48,7 +48,7 @@ export function MultipleSelect({
       <div className="space-y-2">
         {question.options.map((option, index) => {
           const isSelected = selected.includes(index);
-          const isCorrect = question.correctIndices.includes(index);
+          const isCorrect = (question.correctIndices ?? []).includes(index);
 
           let optionClass = 'border-slate-200 dark:border-slate-700 hover:border-blue-400';
 
@@ -55,7 +55,7 @@ export function Ordering({
   };
 
   const isCorrectPosition = (itemIndex: number, position: number): boolean => {
-    return question.correctOrder[position] === itemIndex;
+    return question.correctOrder?.[position] === itemIndex;
   };
 
   const isFullyCorrect = (): boolean => {
@@ -181,7 +181,7 @@ export function Ordering({
                 Correct order:
               </p>
               <ol className="list-decimal list-inside space-y-1">
-                {question.correctOrder.map((itemIndex, position) => (
+                {(question.correctOrder ?? []).map((itemIndex, position) => (
                   <li key={position} className="text-sm text-slate-600 dark:text-slate-400">
                     {question.items[itemIndex]}
                   </li>
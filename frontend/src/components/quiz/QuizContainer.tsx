@@ -1,14 +1,13 @@
-import { useState, useCallback, useMemo } from 'react';
+import { useState, useMemo } from 'react';
 import {
   Quiz,
   QuizQuestion,
   QuestionAnswer,
   MasteryLevel,
   ConfidenceLevel,
-  getMasteryLevel,
   getMasteryEmoji,
   getMasteryColor,
-  calculateScore,
+  withAnswerKey,
 } from './types';
 import {
   MultipleChoice,
@@ -18,66 +17,42 @@ import {
   Matching,
   Ordering,
 } from './QuestionTypes';
+import { quizStatsService } from '../../services/quizStatsService';
+import type { QuizAttempt, QuestionAnswerKey } from '../../services/quizStatsService';
 
 interface QuizContainerProps {
   quiz: Quiz;
-  onComplete: (score: number, totalQuestions: number, percentage: number, responses: QuestionResponse[]) => void;
+  courseId: string;
+  lessonPath: number[];
+  onComplete?: (attempt: QuizAttempt) => void;
   onAbandon?: () => void;
 }
 
 interface QuestionResponse {
   questionId: string;
   answer: QuestionAnswer;
-  isCorrect: boolean;
   confidence?: ConfidenceLevel;
   timeTaken: number;
 }
 
-type QuizState = 'start' | 'in-progress' | 'completed';
+type QuizState = 'start' | 'in-progress' | 'submitting' | 'completed';
 
-export function QuizContainer({ quiz, onComplete, onAbandon }: QuizContainerProps) {
+// The quiz is graded on the server when it is submitted; answer keys are then revealed
+// question by question for the results screen, as learners never receive them beforehand
+export function QuizContainer({ quiz, courseId, lessonPath, onComplete, onAbandon }: QuizContainerProps) {
   const [state, setState] = useState<QuizState>('start');
   const [currentIndex, setCurrentIndex] = useState(0);
   const [responses, setResponses] = useState<QuestionResponse[]>([]);
   const [currentAnswer, setCurrentAnswer] = useState<QuestionAnswer | null>(null);
-  const [showFeedback, setShowFeedback] = useState(false);
   const [questionStartTime, setQuestionStartTime] = useState<number>(Date.now());
   const [confidence, setConfidence] = useState<ConfidenceLevel | null>(null);
+  const [attempt, setAttempt] = useState<QuizAttempt | null>(null);
+  const [answerKeys, setAnswerKeys] = useState<Record<string, QuestionAnswerKey>>({});
+  const [error, setError] = useState<string | null>(null);
 
   const currentQuestion = quiz.questions[currentIndex];
   const totalQuestions = quiz.questions.length;
 
-  const isAnswerCorrect = useCallback((question: QuizQuestion, answer: QuestionAnswer): boolean => {
-    switch (question.type) {
-      case 'multiple_choice':
-      case 'code_analysis':
-        return answer === question.correctIndex;
-      case 'true_false':
-        return answer === question.correctAnswer;
-      case 'multiple_select': {
-        const selected = answer as number[];
-        const correct = question.correctIndices;
-        return (
-          selected.length === correct.length &&
-          selected.every((i) => correct.includes(i)) &&
-          correct.every((i) => selected.includes(i))
-        );
-      }
-      case 'matching': {
-        const pairs = answer as [number, number][];
-        return question.correctPairs.every(([l, r]) =>
-          pairs.some(([pl, pr]) => pl === l && pr === r)
-        );
-      }
-      case 'ordering': {
-        const order = answer as number[];
-        return order.every((item, idx) => item === question.correctOrder[idx]);
-      }
-      default:
-        return false;
-    }
-  }, []);
-
   const handleStartQuiz = () => {
     setState('in-progress');
     setQuestionStartTime(Date.now());
@@ -87,42 +62,55 @@ export function QuizContainer({ quiz, onComplete, onAbandon }: QuizContainerProp
     setCurrentAnswer(answer);
   };
 
-  const handleSubmitAnswer = () => {
-    if (currentAnswer === null) return;
+  const submitQuiz = async (finalResponses: QuestionResponse[]) => {
+    setState('submitting');
+    setError(null);
+    try {
+      const submitted = await quizStatsService.submitQuizAttempt({
+        courseId,
+        lessonPath,
+        responses: finalResponses.map((r) => ({
+          questionId: r.questionId,
+          userAnswer: JSON.stringify(r.answer),
+          confidence: r.confidence ? (r.confidence.toUpperCase() as 'LOW' | 'MEDIUM' | 'HIGH') : undefined,
+          timeTakenSeconds: r.timeTaken,
+        })),
+      });
+      const keys = await quizStatsService.revealQuizQuestions(
+        courseId,
+        lessonPath,
+        finalResponses.map((r) => r.questionId)
+      );
+
+      setResponses(finalResponses);
+      setAttempt(submitted);
+      setAnswerKeys(keys);
+      setState('completed');
+      onComplete?.(submitted);
+    } catch (err) {
+      setError(err instanceof Error ? err.message : 'Failed to submit quiz');
+      setState('in-progress');
+    }
+  };
 
-    const timeTaken = Math.round((Date.now() - questionStartTime) / 1000);
-    const isCorrect = isAnswerCorrect(currentQuestion, currentAnswer);
+  const handleNextQuestion = () => {
+    if (currentAnswer === null) return;
 
     const response: QuestionResponse = {
       questionId: currentQuestion.id,
       answer: currentAnswer,
-      isCorrect,
       confidence: confidence ?? undefined,
-      timeTaken,
+      timeTaken: Math.round((Date.now() - questionStartTime) / 1000),
     };
 
-    setResponses((prev) => [...prev, response]);
-    setShowFeedback(true);
-  };
-
-  const handleNextQuestion = () => {
     if (currentIndex < totalQuestions - 1) {
+      setResponses((prev) => [...prev, response]);
       setCurrentIndex((prev) => prev + 1);
       setCurrentAnswer(null);
-      setShowFeedback(false);
       setConfidence(null);
       setQuestionStartTime(Date.now());
     } else {
-      // Quiz complete
-      const finalResponses = [...responses];
-      const { score, maxScore } = calculateScore(quiz.questions, finalResponses.map((r, i) => ({
-        ...r,
-        difficulty: quiz.questions[i].difficulty,
-      })));
-      const percentage = Math.round((score / maxScore) * 100);
-
-      setState('completed');
-      onComplete(score, totalQuestions, percentage, finalResponses);
+      submitQuiz([...responses, response]);
     }
   };
 
@@ -132,46 +120,51 @@ export function QuizContainer({ quiz, onComplete, onAbandon }: QuizContainerProp
     }
   };
 
-  // Calculate final score for completed state
-  const finalScore = useMemo(() => {
-    if (state !== 'completed') return null;
-
-    const correctCount = responses.filter((r) => r.isCorrect).length;
-    const { score, maxScore } = calculateScore(
-      quiz.questions,
-      responses.map((r, i) => ({
-        ...r,
-        difficulty: quiz.questions[i].difficulty,
-      }))
-    );
-    const percentage = Math.round((score / maxScore) * 100);
-    const mastery = getMasteryLevel(percentage);
-
-    return { correctCount, score, maxScore, percentage, mastery };
-  }, [state, responses, quiz.questions]);
+  const handleRetake = () => {
+    setState('start');
+    setCurrentIndex(0);
+    setResponses([]);
+    setCurrentAnswer(null);
+    setConfidence(null);
+    setAttempt(null);
+    setAnswerKeys({});
+    setError(null);
+  };
 
-  const renderQuestion = () => {
+  // Whether each question was answered correctly, as graded by the server
+  const correctByQuestion = useMemo(() => {
+    const result: Record<string, boolean> = {};
+    for (const response of attempt?.responses ?? []) {
+      result[response.questionId] = response.isCorrect;
+    }
+    return result;
+  }, [attempt]);
+
+  const renderQuestion = (
+    question: QuizQuestion,
+    selectedAnswer: QuestionAnswer | null,
+    showFeedback: boolean
+  ) => {
     const props = {
-      question: currentQuestion,
       onAnswer: handleAnswer,
       showFeedback,
-      selectedAnswer: currentAnswer,
-      disabled: showFeedback,
+      selectedAnswer,
+      disabled: showFeedback || state === 'submitting',
     };
 
-    switch (currentQuestion.type) {
+    switch (question.type) {
       case 'multiple_choice':
-        return <MultipleChoice {...props} question={currentQuestion} />;
+        return <MultipleChoice {...props} question={question} />;
       case 'true_false':
-        return <TrueFalse {...props} question={currentQuestion} />;
+        return <TrueFalse {...props} question={question} />;
       case 'multiple_select':
-        return <MultipleSelect {...props} question={currentQuestion} />;
+        return <MultipleSelect {...props} question={question} />;
       case 'code_analysis':
-        return <CodeAnalysis {...props} question={currentQuestion} />;
+        return <CodeAnalysis {...props} question={question} />;
       case 'matching':
-        return <Matching {...props} question={currentQuestion} />;
+        return <Matching {...props} question={question} />;
       case 'ordering':
-        return <Ordering {...props} question={currentQuestion} />;
+        return <Ordering {...props} question={question} />;
       default:
         return <p>Unknown question type</p>;
     }
@@ -212,7 +205,7 @@ export function QuizContainer({ quiz, onComplete, onAbandon }: QuizContainerProp
               <svg className="w-5 h-5" fill="none" stroke="currentColor" viewBox="0 0 24 24">
                 <path strokeLinecap="round" strokeLinejoin="round" strokeWidth={2} d="M9 12l2 2 4-4m6 2a9 9 0 11-18 0 9 9 0 0118 0z" />
               </svg>
-              <span>Instant feedback</span>
+              <span>Answers revealed on submit</span>
             </div>
           </div>
 
@@ -227,8 +220,8 @@ export function QuizContainer({ quiz, onComplete, onAbandon }: QuizContainerProp
     );
   }
 
-  // In-progress screen
-  if (state === 'in-progress') {
+  // In-progress screen, kept while the answers are submitted
+  if (state === 'in-progress' || state === 'submitting') {
     const progressPercentage = ((currentIndex + 1) / totalQuestions) * 100;
 
     return (
@@ -271,61 +264,59 @@ export function QuizContainer({ quiz, onComplete, onAbandon }: QuizContainerProp
 
         {/* Question content */}
         <div className="p-6">
-          {renderQuestion()}
+          {renderQuestion(currentQuestion, currentAnswer, false)}
+          {error && (
+            <p className="mt-4 text-sm text-red-600 dark:text-red-400">{error}</p>
+          )}
         </div>
 
         {/* Footer with actions */}
         <div className="p-4 border-t border-slate-200 dark:border-slate-700 bg-slate-50 dark:bg-slate-800/50 rounded-b-xl">
-          {!showFeedback ? (
-            <div className="flex items-center justify-between">
-              {/* Confidence selector */}
-              <div className="flex items-center gap-2">
-                <span className="text-sm text-slate-500 dark:text-slate-400">Confidence:</span>
-                {(['low', 'medium', 'high'] as ConfidenceLevel[]).map((level) => (
-                  <button
-                    key={level}
-                    onClick={() => setConfidence(level)}
-                    className={`px-3 py-1 text-xs rounded-full transition-colors ${
-                      confidence === level
-                        ? 'bg-blue-600 text-white'
-                        : 'bg-slate-200 dark:bg-slate-700 text-slate-600 dark:text-slate-400 hover:bg-slate-300 dark:hover:bg-slate-600'
-                    }`}
-                  >
-                    {level.charAt(0).toUpperCase() + level.slice(1)}
-                  </button>
-                ))}
-              </div>
-
-              <button
-                onClick={handleSubmitAnswer}
-                disabled={currentAnswer === null}
-                className={`px-6 py-2 font-medium rounded-lg transition-colors ${
-                  currentAnswer === null
-                    ? 'bg-slate-200 dark:bg-slate-700 text-slate-400 cursor-not-allowed'
-                    : 'bg-blue-600 hover:bg-blue-700 text-white'
-                }`}
-              >
-                Check Answer
-              </button>
-            </div>
-          ) : (
-            <div className="flex justify-end">
-              <button
-                onClick={handleNextQuestion}
-                className="px-6 py-2 bg-blue-600 hover:bg-blue-700 text-white font-medium rounded-lg transition-colors"
-              >
-                {currentIndex < totalQuestions - 1 ? 'Next Question' : 'See Results'}
-              </button>
+          <div className="flex items-center justify-between">
+            {/* Confidence selector */}
+            <div className="flex items-center gap-2">
+              <span className="text-sm text-slate-500 dark:text-slate-400">Confidence:</span>
+              {(['low', 'medium', 'high'] as ConfidenceLevel[]).map((level) => (
+                <button
+                  key={level}
+                  onClick={() => setConfidence(level)}
+                  className={`px-3 py-1 text-xs rounded-full transition-colors ${
+                    confidence === level
+                      ? 'bg-blue-600 text-white'
+                      : 'bg-slate-200 dark:bg-slate-700 text-slate-600 dark:text-slate-400 hover:bg-slate-300 dark:hover:bg-slate-600'
+                  }`}
+                >
+                  {level.charAt(0).toUpperCase() + level.slice(1)}
+                </button>
+              ))}
             </div>
-          )}
+
+            <button
+              onClick={handleNextQuestion}
+              disabled={currentAnswer === null || state === 'submitting'}
+              className={`px-6 py-2 font-medium rounded-lg transition-colors ${
+                currentAnswer === null || state === 'submitting'
+                  ? 'bg-slate-200 dark:bg-slate-700 text-slate-400 cursor-not-allowed'
+                  : 'bg-blue-600 hover:bg-blue-700 text-white'
+              }`}
+            >
+              {currentIndex < totalQuestions - 1
+                ? 'Next Question'
+                : state === 'submitting'
+                ? 'Submitting...'
+                : 'Submit Quiz'}
+            </button>
+          </div>
         </div>
       </div>
     );
   }
 
   // Completed screen
-  if (state === 'completed' && finalScore) {
-    const { correctCount, percentage, mastery } = finalScore;
+  if (state === 'completed' && attempt) {
+    const { correctCount } = attempt;
+    const percentage = Math.round(attempt.percentage);
+    const mastery = attempt.masteryLevel.toLowerCase() as MasteryLevel;
 
     return (
       <div className="bg-white dark:bg-slate-900 rounded-xl border border-slate-200 dark:border-slate-700 p-6">
@@ -380,33 +371,51 @@ export function QuizContainer({ quiz, onComplete, onAbandon }: QuizContainerProp
               Question Breakdown
             </h3>
             <div className="flex flex-wrap justify-center gap-2">
-              {responses.map((response, index) => (
+              {quiz.questions.map((question, index) => {
+                const isCorrect = correctByQuestion[question.id];
+                return (
+                  <div
+                    key={question.id}
+                    className={`w-8 h-8 rounded-full flex items-center justify-center text-sm font-medium ${
+                      isCorrect
+                        ? 'bg-green-100 dark:bg-green-900/30 text-green-600 dark:text-green-400'
+                        : 'bg-red-100 dark:bg-red-900/30 text-red-600 dark:text-red-400'
+                    }`}
+                    title={`Question ${index + 1}: ${isCorrect ? 'Correct' : 'Incorrect'}`}
+                  >
+                    {index + 1}
+                  </div>
+                );
+              })}
+            </div>
+          </div>
+
+          {/* Each question with its revealed answer key */}
+          <div className="space-y-4 mb-6 text-left">
+            <h3 className="text-sm font-medium text-slate-700 dark:text-slate-300">
+              Review Your Answers
+            </h3>
+            {quiz.questions.map((question, index) => {
+              const key = answerKeys[question.id];
+              const response = responses.find((r) => r.questionId === question.id);
+              return (
                 <div
-                  key={index}
-                  className={`w-8 h-8 rounded-full flex items-center justify-center text-sm font-medium ${
-                    response.isCorrect
-                      ? 'bg-green-100 dark:bg-green-900/30 text-green-600 dark:text-green-400'
-                      : 'bg-red-100 dark:bg-red-900/30 text-red-600 dark:text-red-400'
-                  }`}
-                  title={`Question ${index + 1}: ${response.isCorrect ? 'Correct' : 'Incorrect'}`}
+                  key={question.id}
+                  className="p-4 rounded-lg border border-slate-200 dark:border-slate-700"
                 >
-                  {index + 1}
+                  <p className="text-xs text-slate-500 dark:text-slate-400 mb-2">
+                    Question {index + 1}: {correctByQuestion[question.id] ? 'Correct' : 'Incorrect'}
+                  </p>
+                  {renderQuestion(key ? withAnswerKey(question, key) : question, response?.answer ?? null, true)}
                 </div>
-              ))}
-            </div>
+              );
+            })}
           </div>
 
           {/* Actions */}
           <div className="flex justify-center gap-4">
             <button
-              onClick={() => {
-                setState('start');
-                setCurrentIndex(0);
-                setResponses([]);
-                setCurrentAnswer(null);
-                setShowFeedback(false);
-                setConfidence(null);
-              }}
+              onClick={handleRetake}
               className="px-6 py-2 bg-blue-600 hover:bg-blue-700 text-white font-medium rounded-lg transition-colors"
             >
               Retake Quiz
@@ -1,3 +1,5 @@
+import type { QuestionAnswerKey } from '../../services/quizStatsService';
+
 // Quiz question types
 export type QuestionType =
   | 'multiple_choice'
@@ -11,6 +13,8 @@ export type ConfidenceLevel = 'low' | 'medium' | 'high';
 
 export type MasteryLevel = 'novice' | 'developing' | 'proficient' | 'expert';
 
+// Answer keys are left out while the learner takes the quiz; the server grades the
+// attempt and the keys are filled in with withAnswerKey once they are revealed
 export interface BaseQuestion {
   id: string;
   type: QuestionType;
@@ -23,18 +27,18 @@ export interface BaseQuestion {
 export interface MultipleChoiceQuestion extends BaseQuestion {
   type: 'multiple_choice';
   options: string[];
-  correctIndex: number;
+  correctIndex?: number;
 }
 
 export interface TrueFalseQuestion extends BaseQuestion {
   type: 'true_false';
-  correctAnswer: boolean;
+  correctAnswer?: boolean;
 }
 
 export interface MultipleSelectQuestion extends BaseQuestion {
   type: 'multiple_select';
   options: string[];
-  correctIndices: number[];
+  correctIndices?: number[];
   minSelections?: number;
   maxSelections?: number;
 }
@@ -44,20 +48,20 @@ export interface CodeAnalysisQuestion extends BaseQuestion {
   codeSnippet: string;
   language: string;
   options: string[];
-  correctIndex: number;
+  correctIndex?: number;
 }
 
 export interface MatchingQuestion extends BaseQuestion {
   type: 'matching';
   leftColumn: string[];
   rightColumn: string[];
-  correctPairs: [number, number][];
+  correctPairs?: [number, number][];
 }
 
 export interface OrderingQuestion extends BaseQuestion {
   type: 'ordering';
   items: string[];
-  correctOrder: number[];
+  correctOrder?: number[];
 }
 
 export type QuizQuestion =
@@ -75,6 +79,24 @@ export interface Quiz {
   questions: QuizQuestion[];
 }
 
+// Returns the question with the answer key and explanation revealed by the server
+export const withAnswerKey = (question: QuizQuestion, key: QuestionAnswerKey): QuizQuestion => {
+  const explanation = key.explanation ?? '';
+  switch (question.type) {
+    case 'multiple_choice':
+    case 'code_analysis':
+      return { ...question, explanation, correctIndex: key.correctIndex ?? undefined };
+    case 'true_false':
+      return { ...question, explanation, correctAnswer: key.correctAnswer ?? undefined };
+    case 'multiple_select':
+      return { ...question, explanation, correctIndices: key.correctIndices ?? undefined };
+    case 'matching':
+      return { ...question, explanation, correctPairs: key.correctPairs ?? undefined };
+    case 'ordering':
+      return { ...question, explanation, correctOrder: key.correctOrder ?? undefined };
+  }
+};
+
 // Type alias for question answers
 export type QuestionAnswer = number | boolean | number[] | [number, number][];
 
@@ -26,28 +26,33 @@ interface FlatLesson {
   parentIndex: number | null;
   flatIndex: number;
   path: number[]; // Path to reach this lesson [parentIdx, subIdx, subSubIdx...]
+  quizPath: number[]; // Path into the lessons as loaded, which locates the lesson's quiz on the backend
 }
 
-function flattenLessons(lessons: Lesson[]): FlatLesson[] {
+// lessons may be sorted for display; loadedLessons are the course's lessons in the order
+// the backend counts them, as only top-level lessons are reordered
+function flattenLessons(lessons: Lesson[], loadedLessons: Lesson[]): FlatLesson[] {
   const result: FlatLesson[] = [];
 
-  function traverse(lessonList: Lesson[], depth: number, parentIndex: number | null, pathPrefix: number[]) {
+  function traverse(lessonList: Lesson[], depth: number, parentIndex: number | null, pathPrefix: number[], quizPathPrefix: number[]) {
     lessonList.forEach((lesson, idx) => {
       const path = [...pathPrefix, idx];
+      const quizPath = [...quizPathPrefix, depth === 0 ? loadedLessons.indexOf(lesson) : idx];
       result.push({
         lesson,
         depth,
         parentIndex,
         flatIndex: result.length,
         path,
+        quizPath,
       });
       if (lesson.sublessons && lesson.sublessons.length > 0) {
-        traverse(lesson.sublessons, depth + 1, result.length - 1, path);
+        traverse(lesson.sublessons, depth + 1, result.length - 1, path, quizPath);
       }
     });
   }
 
-  traverse(lessons, 0, null, []);
+  traverse(lessons, 0, null, [], []);
   return result;
 }
 
@@ -136,7 +141,7 @@ export function CourseDetailPage() {
 
   // Flatten lessons for navigation - memoize to prevent infinite loops
   const flatLessons = useMemo(() => {
-    return course ? flattenLessons([...course.lessons].sort((a, b) => a.order - b.order)) : [];
+    return course ? flattenLessons([...course.lessons].sort((a, b) => a.order - b.order), course.lessons) : [];
   }, [course]);
 
   // Toggle lesson expansion
@@ -497,9 +502,8 @@ export function CourseDetailPage() {
                   ) : (
                     <Quiz
                       quiz={currentLesson.quiz}
-                      onComplete={(score, total) => {
-                        console.log(`Quiz completed: ${score}/${total}`);
-                      }}
+                      courseId={course.id}
+                      lessonPath={currentFlatLesson.quizPath}
                       onClose={() => setShowQuiz(false)}
                     />
                   )}
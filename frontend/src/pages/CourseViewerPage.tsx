@@ -20,6 +20,8 @@ const difficultyColors: Record<Difficulty, string> = {
 };
 
 // Convert ExtendedQuiz from course types to the new Quiz format
+// Answer keys are left out: learners do not receive them until the quiz is submitted,
+// after which QuizContainer reveals them from the server
 function convertToQuizFormat(extendedQuiz: ExtendedQuizType): NewQuizType {
   return {
     version: extendedQuiz.version || '1.0',
@@ -31,20 +33,19 @@ function convertToQuizFormat(extendedQuiz: ExtendedQuizType): NewQuizType {
         difficulty: q.difficulty,
         concept: q.concept || '',
         question: q.question,
-        explanation: q.explanation || '',
+        explanation: '',
       };
 
       switch (q.type) {
         case 'multiple_choice':
-          return { ...base, type: 'multiple_choice', options: q.options, correctIndex: q.correctIndex };
+          return { ...base, type: 'multiple_choice', options: q.options };
         case 'true_false':
-          return { ...base, type: 'true_false', correctAnswer: q.correctAnswer };
+          return { ...base, type: 'true_false' };
         case 'multiple_select':
           return {
             ...base,
             type: 'multiple_select',
             options: q.options,
-            correctIndices: q.correctIndices,
             minSelections: q.minSelections,
             maxSelections: q.maxSelections,
           };
@@ -55,7 +56,6 @@ function convertToQuizFormat(extendedQuiz: ExtendedQuizType): NewQuizType {
             codeSnippet: q.codeSnippet,
             language: q.language || 'bash',
             options: q.options,
-            correctIndex: q.correctIndex,
           };
         case 'matching':
           return {
@@ -63,18 +63,16 @@ function convertToQuizFormat(extendedQuiz: ExtendedQuizType): NewQuizType {
             type: 'matching',
             leftColumn: q.leftColumn,
             rightColumn: q.rightColumn,
-            correctPairs: q.correctPairs,
           };
         case 'ordering':
           return {
             ...base,
             type: 'ordering',
             items: q.items,
-            correctOrder: q.correctOrder,
           };
         default:
           // Fallback to multiple choice for any unknown type
-          return { ...base, type: 'multiple_choice', options: [], correctIndex: 0 };
+          return { ...base, type: 'multiple_choice', options: [] };
       }
     }),
   };
@@ -87,15 +85,19 @@ interface FlatLesson {
   flatIndex: number;
   path: number[];
   folderPath: number[]; // Path using folderIndex values (for backend save operations)
+  quizPath: number[]; // Path into the lessons as loaded, which locates the lesson's quiz on the backend
 }
 
-function flattenLessons(lessons: Lesson[]): FlatLesson[] {
+// lessons may be sorted for display; loadedLessons are the course's lessons in the order
+// the backend counts them, as only top-level lessons are reordered
+function flattenLessons(lessons: Lesson[], loadedLessons: Lesson[]): FlatLesson[] {
   const result: FlatLesson[] = [];
 
-  function traverse(lessonList: Lesson[], depth: number, parentIndex: number | null, pathPrefix: number[], folderPathPrefix: number[]) {
+  function traverse(lessonList: Lesson[], depth: number, parentIndex: number | null, pathPrefix: number[], folderPathPrefix: number[], quizPathPrefix: number[]) {
     lessonList.forEach((lesson, idx) => {
       const path = [...pathPrefix, idx];
       const folderPath = [...folderPathPrefix, lesson.folderIndex];
+      const quizPath = [...quizPathPrefix, depth === 0 ? loadedLessons.indexOf(lesson) : idx];
       result.push({
         lesson,
         depth,
@@ -103,14 +105,15 @@ function flattenLessons(lessons: Lesson[]): FlatLesson[] {
         flatIndex: result.length,
         path,
         folderPath,
+        quizPath,
       });
       if (lesson.sublessons && lesson.sublessons.length > 0) {
-        traverse(lesson.sublessons, depth + 1, result.length - 1, path, folderPath);
+        traverse(lesson.sublessons, depth + 1, result.length - 1, path, folderPath, quizPath);
       }
     });
   }
 
-  traverse(lessons, 0, null, [], []);
+  traverse(lessons, 0, null, [], [], []);
   return result;
 }
 
@@ -239,7 +242,7 @@ export function CourseViewerPage() {
   const { preferences } = usePreferences();
 
   const flatLessons = useMemo(() => {
-    return course ? flattenLessons([...course.lessons].sort((a, b) => a.order - b.order)) : [];
+    return course ? flattenLessons([...course.lessons].sort((a, b) => a.order - b.order), course.lessons) : [];
   }, [course]);
 
   const toggleExpand = useCallback((flatIndex: number) => {
@@ -779,10 +782,8 @@ export function CourseViewerPage() {
                   <QuizContainer
                     key={`quiz-${selectedLesson}-${currentLesson.extendedQuiz.subchapterId}`}
                     quiz={convertToQuizFormat(currentLesson.extendedQuiz)}
-                    onComplete={(score, _total, percentage) => {
-                      console.log(`Quiz completed: ${score} points, ${percentage}%`);
-                      // TODO: Save to backend via GraphQL
-                    }}
+                    courseId={course.id}
+                    lessonPath={currentFlatLesson.quizPath}
                     onAbandon={() => {
                       // User chose to continue learning
                     }}
@@ -806,9 +807,8 @@ export function CourseViewerPage() {
                   ) : (
                     <Quiz
                       quiz={currentLesson.quiz}
-                      onComplete={(score, total) => {
-                        console.log(`Quiz completed: ${score}/${total}`);
-                      }}
+                      courseId={course.id}
+                      lessonPath={currentFlatLesson.quizPath}
                       onClose={() => setShowQuiz(false)}
                     />
                   )}
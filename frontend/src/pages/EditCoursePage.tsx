@@ -31,6 +31,7 @@ export function EditCoursePage() {
               title: l.title,
               content: l.content,
               order: l.order,
+              folder: l.folder,
             }))
           );
         } else {
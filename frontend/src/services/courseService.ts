@@ -61,6 +61,7 @@ const SUBLESSON_FIELDS = `
   content
   order
   folderIndex
+  folder
   hasSublessons
   ${QUIZ_FIELDS}
   ${EXTENDED_QUIZ_FIELDS}
@@ -71,6 +72,7 @@ const LESSON_FRAGMENT = `
   content
   order
   folderIndex
+  folder
   hasSublessons
   ${QUIZ_FIELDS}
   ${EXTENDED_QUIZ_FIELDS}
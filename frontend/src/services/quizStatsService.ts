@@ -15,6 +15,7 @@ export interface QuizAttempt {
   percentage: number;
   masteryLevel: MasteryLevel;
   completedAt: string;
+  responses?: { questionId: string; isCorrect: boolean }[];
 }
 
 export interface QuizStats {
@@ -146,31 +147,53 @@ const SUBMIT_QUIZ_ATTEMPT = `
   mutation SubmitQuizAttempt($input: SubmitQuizAttemptInput!) {
     submitQuizAttempt(input: $input) {
       ${QUIZ_ATTEMPT_FIELDS}
+      responses {
+        questionId
+        isCorrect
+      }
+    }
+  }
+`;
+
+const REVEAL_QUIZ_QUESTION = `
+  query RevealQuizQuestion($courseId: ID!, $lessonPath: [Int!]!, $questionId: ID!) {
+    revealQuizQuestion(courseId: $courseId, lessonPath: $lessonPath, questionId: $questionId) {
+      id
+      explanation
+      correctIndex
+      correctAnswer
+      correctIndices
+      correctPairs
+      correctOrder
     }
   }
 `;
 
+// Raw answers only - the server grades the attempt against the answer key
 export interface QuizResponseInput {
   questionId: string;
-  userAnswer: string;
-  isCorrect: boolean;
-  pointsEarned: number;
-  pointsPossible: number;
-  confidence?: number;
+  userAnswer: string; // JSON-encoded answer
+  confidence?: 'LOW' | 'MEDIUM' | 'HIGH';
   timeTakenSeconds?: number;
 }
 
 export interface SubmitQuizAttemptInput {
   courseId: string;
-  quizType: string;
-  quizId: string;
-  score: number;
-  maxScore: number;
-  totalQuestions: number;
-  correctCount: number;
+  lessonPath: number[];
   responses: QuizResponseInput[];
 }
 
+// Answer key of a question, revealed only once the learner has submitted an answer to it
+export interface QuestionAnswerKey {
+  id: string;
+  explanation: string | null;
+  correctIndex: number | null;
+  correctAnswer: boolean | null;
+  correctIndices: number[] | null;
+  correctPairs: [number, number][] | null;
+  correctOrder: number[] | null;
+}
+
 export const quizStatsService = {
   async getDashboardQuizStats(fromDate?: string, toDate?: string): Promise<DashboardQuizStats> {
     const data = await graphqlClient.request<{ dashboardQuizStats: DashboardQuizStats }>(
@@ -203,4 +226,20 @@ export const quizStatsService = {
     );
     return data.submitQuizAttempt;
   },
+
+  async revealQuizQuestion(courseId: string, lessonPath: number[], questionId: string): Promise<QuestionAnswerKey> {
+    const data = await graphqlClient.request<{ revealQuizQuestion: QuestionAnswerKey }>(
+      REVEAL_QUIZ_QUESTION,
+      { courseId, lessonPath, questionId }
+    );
+    return data.revealQuizQuestion;
+  },
+
+  // Reveals the answer key of every question of a submitted attempt, keyed by question ID
+  async revealQuizQuestions(courseId: string, lessonPath: number[], questionIds: string[]): Promise<Record<string, QuestionAnswerKey>> {
+    const keys = await Promise.all(
+      questionIds.map((id) => quizStatsService.revealQuizQuestion(courseId, lessonPath, id))
+    );
+    return Object.fromEntries(keys.map((key) => [key.id, key]));
+  },
 };
@@ -10,39 +10,41 @@ export type QuestionType =
   | 'ordering';
 
 // Legacy quiz question (simple multiple choice)
+// Answer keys and explanations are null for learners until they have answered the question
 export interface QuizQuestion {
   id: string;
   question: string;
   options: string[];
-  correctIndex: number;
-  explanation?: string;
+  correctIndex: number | null;
+  explanation?: string | null;
 }
 
 // Extended question types for the new quiz system
+// As with legacy questions, answer keys and explanations are null until answered
 export interface BaseQuizQuestion {
   id: string;
   type: QuestionType;
   question: string;
   difficulty: number; // 1-5
   concept?: string;
-  explanation?: string;
+  explanation?: string | null;
 }
 
 export interface MultipleChoiceQuestion extends BaseQuizQuestion {
   type: 'multiple_choice';
   options: string[];
-  correctIndex: number;
+  correctIndex: number | null;
 }
 
 export interface TrueFalseQuestion extends BaseQuizQuestion {
   type: 'true_false';
-  correctAnswer: boolean;
+  correctAnswer: boolean | null;
 }
 
 export interface MultipleSelectQuestion extends BaseQuizQuestion {
   type: 'multiple_select';
   options: string[];
-  correctIndices: number[];
+  correctIndices: number[] | null;
   minSelections?: number;
   maxSelections?: number;
 }
@@ -52,20 +54,20 @@ export interface CodeAnalysisQuestion extends BaseQuizQuestion {
   codeSnippet: string;
   language?: string;
   options: string[];
-  correctIndex: number;
+  correctIndex: number | null;
 }
 
 export interface MatchingQuestion extends BaseQuizQuestion {
   type: 'matching';
   leftColumn: string[];
   rightColumn: string[];
-  correctPairs: [number, number][];
+  correctPairs: [number, number][] | null;
 }
 
 export interface OrderingQuestion extends BaseQuizQuestion {
   type: 'ordering';
   items: string[];
-  correctOrder: number[];
+  correctOrder: number[] | null;
 }
 
 export type ExtendedQuizQuestion =
@@ -94,6 +96,7 @@ export interface Lesson {
   content: string;
   order: number;
   folderIndex: number;
+  folder: string;
   sublessons?: Lesson[];
   hasSublessons: boolean;
   quiz?: Quiz;
@@ -163,6 +166,7 @@ export interface LessonInput {
   title: string;
   content: string;
   order: number;
+  folder?: string;
   sublessons?: LessonInput[];
 }
 